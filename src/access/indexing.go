@@ -0,0 +1,16 @@
+package access
+
+import "github.com/alex-1900/wishlist/src/model"
+
+// CanIndexProfile reports whether search engines may index pages showing the user's profile.
+// Users may opt out of indexing in their profile settings.
+func CanIndexProfile(owner *model.User) bool {
+	return !owner.NoIndex
+}
+
+// CanIndexWishlist reports whether search engines may index pages showing the wishlist: public
+// wishlists of owners who did not opt out of indexing. Wishlists open to some users only or
+// reached through a share link are never indexed.
+func CanIndexWishlist(wishlist *model.Wishlist, owner *model.User) bool {
+	return wishlist.Visibility == model.VisibilityPublic && CanIndexProfile(owner)
+}
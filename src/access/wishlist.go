@@ -0,0 +1,116 @@
+// Package access decides which users may read which resources.
+// Handlers call it instead of checking visibility rules themselves so every
+// read endpoint enforces the same rules.
+package access
+
+import (
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/repository"
+)
+
+// CanViewWishlist reports whether the viewer may read the wishlist through its ID.
+// viewerID is 0 for anonymous visitors. Its creator and members always may. Besides the
+// visibility level, a wishlist shared to a group is always visible to the members of that
+// group, unless a block stands between the owner and the viewer.
+func CanViewWishlist(repo repository.Repository, wishlist *model.Wishlist, viewerID int) (bool, error) {
+	role, err := WishlistRole(repo, wishlist, viewerID)
+	if err != nil || role != "" {
+		return role != "", err
+	}
+
+	if viewerID != 0 {
+		blocked, err := repo.Block().IsBlockedEither(wishlist.UserID, viewerID)
+		if err != nil || blocked {
+			return false, err
+		}
+	}
+
+	if wishlist.Visibility == model.VisibilityPublic {
+		return true, nil
+	}
+
+	if viewerID == 0 {
+		return false, nil
+	}
+
+	if wishlist.Visibility == model.VisibilityFriends {
+		friends, err := AreFriends(repo, wishlist.UserID, viewerID)
+		if err != nil {
+			return false, err
+		}
+		if friends {
+			return true, nil
+		}
+	}
+
+	return repo.Group().SharesWishlistWith(wishlist.ID, viewerID)
+}
+
+// WishlistRole returns the role of the user on the wishlist: owner for its creator, the role of
+// their accepted invitation for members, and empty for anyone else, including anonymous visitors
+func WishlistRole(repo repository.Repository, wishlist *model.Wishlist, userID int) (model.WishlistRole, error) {
+	if userID == 0 {
+		return "", nil
+	}
+	if wishlist.IsOwnedBy(userID) {
+		return model.WishlistRoleOwner, nil
+	}
+	return repo.WishlistMember().GetRole(wishlist.ID, userID)
+}
+
+// CanViewThroughLink reports whether the wishlist may be read through its share link.
+// Links only work while the wishlist is link-only or public, so making a wishlist
+// private or friends-only disables its link without revoking it.
+func CanViewThroughLink(wishlist *model.Wishlist) bool {
+	return wishlist.ShareToken != nil &&
+		(wishlist.Visibility == model.VisibilityLinkOnly || wishlist.Visibility == model.VisibilityPublic)
+}
+
+// VisibleLevels returns the visibility levels of the owner's wishlists the viewer may browse.
+// Link-only wishlists are never listed; they are reached through their share link.
+// Callers check CanBrowseUser first, since group-shared wishlists are listed whatever the level.
+func VisibleLevels(repo repository.Repository, ownerID, viewerID int) ([]model.WishlistVisibility, error) {
+	if viewerID != 0 && viewerID == ownerID {
+		return []model.WishlistVisibility{
+			model.VisibilityPrivate, model.VisibilityFriends, model.VisibilityLinkOnly, model.VisibilityPublic,
+		}, nil
+	}
+
+	levels := []model.WishlistVisibility{model.VisibilityPublic}
+	if viewerID == 0 {
+		return levels, nil
+	}
+
+	friends, err := AreFriends(repo, ownerID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if friends {
+		levels = append(levels, model.VisibilityFriends)
+	}
+
+	return levels, nil
+}
+
+// CanBrowseUser reports whether the viewer may see the user's profile and wishlists at all,
+// which they may not when either blocked the other
+func CanBrowseUser(repo repository.Repository, userID, viewerID int) (bool, error) {
+	if viewerID == 0 || viewerID == userID {
+		return true, nil
+	}
+
+	blocked, err := repo.Block().IsBlockedEither(userID, viewerID)
+	if err != nil {
+		return false, err
+	}
+	return !blocked, nil
+}
+
+// AreFriends reports whether two users follow each other
+func AreFriends(repo repository.Repository, userID, otherID int) (bool, error) {
+	following, err := repo.Follow().IsFollowing(userID, otherID)
+	if err != nil || !following {
+		return false, err
+	}
+	return repo.Follow().IsFollowing(otherID, userID)
+}
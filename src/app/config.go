@@ -1,7 +1,22 @@
 package app
 
+import (
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/mailer"
+	"github.com/alex-1900/wishlist/src/ratelimit"
+	"github.com/alex-1900/wishlist/src/repository"
+	"github.com/alex-1900/wishlist/src/storage"
+)
+
 var config = AppConfig{
 	AppName: "WishlistSNS",
+	Server: ServerConfig{
+		Address:         ":8080",
+		ReadTimeout:     15,
+		WriteTimeout:    30,
+		IdleTimeout:     60,
+		ShutdownTimeout: 20,
+	},
 	Database: DatabaseConfig{
 		Host:     "host.docker.internal",
 		Port:     "5432",
@@ -9,7 +24,147 @@ var config = AppConfig{
 		Password: "postgres",
 		DBName:   "wishlist_dev",
 		SSLMode:  "disable",
+
+		MaxOpenConns:    25,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 30, // 30 minutes
+	},
+	Environments: map[string]DatabaseConfig{
+		"development": {
+			Host:     "host.docker.internal",
+			Port:     "5432",
+			User:     "postgres",
+			Password: "postgres",
+			DBName:   "wishlist_dev",
+			SSLMode:  "disable",
+		},
+	},
+	AutoMigrate: true,
+	Migration: MigrationConfig{
+		StatementTimeout: 60, // 1 minute
+		LockTimeout:      5,
+	},
+	Query: repository.QueryConfig{
+		Timeout:        10, // 10 seconds
+		MaxRetries:     3,
+		RetryBaseDelay: 50,   // 50 milliseconds
+		RetryMaxDelay:  1000, // 1 second
 	},
 	JWTSecret:     "your-super-secret-jwt-key-change-in-production",
 	JWTExpiration: 24, // 24 hours
+	JWTIssuer:     "wishlist",
+	JWTAudience:   "wishlist-api",
+	JWTLeeway:     30, // 30 seconds
+
+	RefreshTokenExpiration: 720, // 30 days
+	Session: SessionConfig{
+		Sliding:        false,
+		AccessTokenTTL: 15,  // 15 minutes
+		IdleTimeout:    168, // 7 days
+		MaxLifetime:    720, // 30 days
+	},
+	OIDC: OIDCConfig{
+		Issuer:         "http://localhost:8080",
+		SigningKeyPath: "",
+		ClientIDs:      []string{"wishlist-web", "wishlist-mobile"},
+		IDTokenTTL:     60, // 1 hour
+	},
+	OAuth: OAuthConfig{
+		RedirectBaseURL: "http://localhost:8080",
+	},
+
+	EmailVerification: EmailVerificationConfig{
+		CodeExpiration: 10, // 10 minutes
+		MaxAttempts:    5,
+		ResendCooldown: 60, // 1 minute
+		RequireOnLogin: false,
+	},
+	LoginLockout: LoginLockoutConfig{
+		MaxFailures: 5,
+		Window:      15, // 15 minutes
+		Duration:    15, // 15 minutes
+
+		IPMaxFailures: 20,
+		IPWindow:      15, // 15 minutes
+		IPDuration:    15, // 15 minutes
+	},
+	Scraper: ScraperConfig{
+		Timeout:   5,
+		MaxBodyKB: 512,
+		CacheTTL:  60, // 1 hour
+		CacheSize: 1000,
+		UserAgent: "WishlistBot/1.0 (+link preview)",
+	},
+	Storage: StorageConfig{
+		Driver:      "local",
+		MaxUploadKB: 5120, // 5 MB
+		Local: storage.LocalConfig{
+			Root:      "uploads",
+			URLPrefix: "/uploads",
+		},
+	},
+	Mailer: MailerConfig{
+		Driver: "log",
+		From:   "no-reply@wishlist.local",
+		SMTP: mailer.SMTPConfig{
+			Host: "localhost",
+			Port: "587",
+		},
+	},
+	PublicAPI: PublicAPIConfig{
+		CacheMaxAge: 60, // 1 minute
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Window:  60, // 1 minute
+			Default: ratelimit.Limit{Soft: 240, Hard: 300},
+		},
+	},
+	Sitemap: SitemapConfig{
+		Enabled:         false,
+		BaseURL:         "http://localhost:8080",
+		ProfileURL:      "http://localhost:3000/users/{username}",
+		WishlistURL:     "http://localhost:3000/wishlists/{id}",
+		RefreshInterval: 60, // 1 hour
+		URLsPerPage:     10000,
+	},
+	Robots: RobotsConfig{
+		DisallowAll: false,
+		Disallow:    []string{"/admin/", "/oauth/", "/shared/"},
+	},
+	Reminders: RemindersConfig{
+		Enabled:       true,
+		DaysBefore:    7,
+		CheckInterval: 60, // 1 hour
+	},
+	CDN: CDNConfig{
+		Policies: map[string]cdn.Policy{},
+		Provider: "",
+	},
+	PriceTracking: PriceTrackingConfig{
+		Enabled:       true,
+		CheckInterval: 10, // 10 minutes
+		RecheckAfter:  24, // 1 day
+		BatchSize:     50,
+		RetentionDays: 365,
+	},
+	Currency: CurrencyConfig{
+		Provider: "ecb",
+		CacheTTL: 720, // 12 hours, the reference rates change once a day
+	},
+	Archival: ArchivalConfig{
+		Enabled:          true,
+		ArchiveAfterDays: 30,
+		CheckInterval:    60, // 1 hour
+	},
+	Imports: ImportsConfig{
+		Enabled:      true,
+		PollInterval: 5,
+		MaxRows:      500,
+		MaxFileKB:    1024,
+	},
+	Demo: DemoConfig{
+		Enabled:       false,
+		ResetInterval: 60, // 1 hour
+		Password:      "demo-password",
+	},
 }
@@ -4,7 +4,18 @@ import (
 	"database/sql"
 	"sync"
 
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/auth/oauth"
+	"github.com/alex-1900/wishlist/src/bruteforce"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/imaging"
+	"github.com/alex-1900/wishlist/src/mailer"
+	"github.com/alex-1900/wishlist/src/ratelimit"
+	"github.com/alex-1900/wishlist/src/realtime"
 	"github.com/alex-1900/wishlist/src/repository"
+	"github.com/alex-1900/wishlist/src/scraper"
+	"github.com/alex-1900/wishlist/src/storage"
 	"github.com/gin-gonic/gin"
 )
 
@@ -41,6 +52,66 @@ func GetRepository() *repository.RepositoryManager {
 	return GetInstance().Repository
 }
 
+// GetJWTManager returns the JWT manager from the App instance
+func GetJWTManager() *auth.JWTManager {
+	return GetInstance().JWTManager
+}
+
+// GetMailer returns the email delivery service from the App instance
+func GetMailer() mailer.Mailer {
+	return GetInstance().Mailer
+}
+
+// GetScraper returns the link metadata scraper from the App instance
+func GetScraper() *scraper.Scraper {
+	return GetInstance().Scraper
+}
+
+// GetStorage returns the upload storage backend from the App instance
+func GetStorage() storage.Storage {
+	return GetInstance().Storage
+}
+
+// GetImages returns the processor generating image size variants from the App instance
+func GetImages() *imaging.Processor {
+	return GetInstance().Images
+}
+
+// GetRealtimeHub returns the hub of open WebSocket connections from the App instance
+func GetRealtimeHub() *realtime.Hub {
+	return GetInstance().Realtime
+}
+
+// GetCDN returns the caching policies of public routes and the CDN purger from the App instance
+func GetCDN() *cdn.CDN {
+	return GetInstance().CDN
+}
+
+// GetCurrency returns the converter to display currencies from the App instance
+func GetCurrency() *currency.Converter {
+	return GetInstance().Currency
+}
+
+// GetRateLimiter returns the request limiter of public API keys from the App instance
+func GetRateLimiter() *ratelimit.Limiter {
+	return GetInstance().RateLimit
+}
+
+// GetBruteForceMonitor returns the failed login monitor from the App instance
+func GetBruteForceMonitor() *bruteforce.Monitor {
+	return GetInstance().BruteForce
+}
+
+// GetIDTokenSigner returns the OpenID Connect ID token signer from the App instance
+func GetIDTokenSigner() *auth.IDTokenSigner {
+	return GetInstance().IDTokens
+}
+
+// GetOAuthProviders returns the configured external identity providers from the App instance
+func GetOAuthProviders() oauth.Providers {
+	return GetInstance().OAuth
+}
+
 // ResetApp resets the singleton instance (mainly for testing)
 func ResetApp() {
 	appOnce = sync.Once{}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/loadgen"
+	"github.com/alex-1900/wishlist/src/repository"
+)
+
+// LoadGenPassword is the password of every generated account
+const LoadGenPassword = "loadgen-password"
+
+// RunLoadGenCommand runs the loadgen CLI subcommand, generating a capacity test dataset:
+//
+//	loadgen [--users N] [--wishlists N] [--items N] [--follows N] [--batch N] [--seed N] [--env NAME]
+//	loadgen --clean [--env NAME]   delete every generated account and what it owns
+//
+// --wishlists, --items and --follows are averages per user and per wishlist. The schema must be
+// migrated first; --env NAME targets a database from Config.Environments instead of Config.Database.
+func RunLoadGenCommand(args []string) error {
+	opts := loadgen.DefaultOptions()
+	var env string
+	var clean bool
+
+	flags := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.IntVar(&opts.Users, "users", opts.Users, "number of users")
+	flags.IntVar(&opts.WishlistsPerUser, "wishlists", opts.WishlistsPerUser, "average wishlists per user")
+	flags.IntVar(&opts.ItemsPerWishlist, "items", opts.ItemsPerWishlist, "average items per wishlist")
+	flags.IntVar(&opts.FollowsPerUser, "follows", opts.FollowsPerUser, "average users followed per user")
+	flags.IntVar(&opts.BatchSize, "batch", opts.BatchSize, "rows per insert")
+	flags.Int64Var(&opts.Seed, "seed", opts.Seed, "random seed")
+	flags.StringVar(&env, "env", "", "environment from Config.Environments")
+	flags.BoolVar(&clean, "clean", false, "delete the generated accounts")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w; usage: loadgen [--users N] [--wishlists N] [--items N] [--follows N] [--batch N] [--seed N] [--clean] [--env NAME]", err)
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %s", flags.Arg(0))
+	}
+
+	dbConfig := config.Database
+	if env != "" {
+		var ok bool
+		if dbConfig, ok = config.Environments[env]; !ok {
+			return fmt.Errorf("unknown environment %q", env)
+		}
+	}
+
+	db, err := buildDatabaseConnection(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	repo := repository.NewLoadGenRepository(repository.NewDB(db, config.Query))
+	fmt.Printf("Environment: %s (%s@%s:%s/%s)\n", environmentName(env), dbConfig.User, dbConfig.Host, dbConfig.Port, dbConfig.DBName)
+
+	if clean {
+		deleted, err := repo.DeleteUsers(loadgen.EmailDomain)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %d generated accounts\n", deleted)
+		return nil
+	}
+
+	// One hash for every account keeps bcrypt out of the run time
+	passwordHash, err := auth.HashPassword(LoadGenPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash the password of generated accounts: %w", err)
+	}
+
+	summary, err := loadgen.Run(repo, opts, passwordHash)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Generated %s\n", summary)
+	fmt.Printf("Accounts are load%d_<n>@%s with password %q\n", opts.Seed, loadgen.EmailDomain, LoadGenPassword)
+	return nil
+}
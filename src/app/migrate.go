@@ -0,0 +1,198 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/database"
+)
+
+// collectMigrations gathers the migrations of every registered module
+func collectMigrations() []database.Migration {
+	var migrations []database.Migration
+	for _, m := range Modules() {
+		migrations = append(migrations, m.Migrations()...)
+	}
+	return migrations
+}
+
+// buildMigrator creates a migrator for every registered module's migrations
+func buildMigrator(db *sql.DB) (*database.Migrator, error) {
+	migrator, err := database.NewMigrator(db, collectMigrations())
+	if err != nil {
+		return nil, err
+	}
+	return migrator.UseSettings(database.MigrationSettings{
+		StatementTimeout: time.Duration(config.Migration.StatementTimeout) * time.Second,
+		LockTimeout:      time.Duration(config.Migration.LockTimeout) * time.Second,
+	}), nil
+}
+
+// migrateUp applies every pending migration
+func migrateUp(db *sql.DB) error {
+	migrator, err := buildMigrator(db)
+	if err != nil {
+		return err
+	}
+	return migrator.Up()
+}
+
+// RunMigrationCommand runs the migrate CLI subcommand:
+//
+//	migrate up [--dry-run]            apply every pending migration
+//	migrate down [steps] [--dry-run]  roll back the last applied migrations (default 1)
+//	migrate status                    list applied and pending migrations
+//
+// --dry-run prints the SQL that would run without changing the database, and
+// --env NAME targets a database from Config.Environments instead of Config.Database.
+func RunMigrationCommand(args []string) error {
+	command, positional, dryRun, env, err := parseMigrationArgs(args)
+	if err != nil {
+		return err
+	}
+
+	dbConfig := config.Database
+	if env != "" {
+		var ok bool
+		if dbConfig, ok = config.Environments[env]; !ok {
+			return fmt.Errorf("unknown environment %q", env)
+		}
+	}
+
+	db, err := buildDatabaseConnection(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := buildMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		if dryRun {
+			plan, err := migrator.PlanUp()
+			if err != nil {
+				return err
+			}
+			return printMigrationPlan(plan)
+		}
+		return migrator.Up()
+	case "down":
+		steps := 1
+		if len(positional) > 0 {
+			if steps, err = strconv.Atoi(positional[0]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps: %s", positional[0])
+			}
+		}
+		if dryRun {
+			plan, err := migrator.PlanDown(steps)
+			if err != nil {
+				return err
+			}
+			return printMigrationPlan(plan)
+		}
+		return migrator.Down(steps)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Environment: %s (%s@%s:%s/%s)\n\n", environmentName(env), dbConfig.User, dbConfig.Host, dbConfig.Port, dbConfig.DBName)
+
+		pending := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", ""
+			if status.Applied {
+				state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05")
+			} else {
+				pending++
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Migration.Version, status.Migration.Name, state, appliedAt)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Printf("\n%d applied, %d pending\n", len(statuses)-pending, pending)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q", command)
+	}
+}
+
+// parseMigrationArgs splits the migrate arguments into the command, its positional
+// arguments and the --dry-run and --env flags, which may appear anywhere
+func parseMigrationArgs(args []string) (command string, positional []string, dryRun bool, env string, err error) {
+	const usage = "usage: migrate up | down [steps] | status [--dry-run] [--env NAME]"
+
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--env":
+			if i+1 >= len(args) {
+				return "", nil, false, "", fmt.Errorf("--env needs an environment name")
+			}
+			i++
+			env = args[i]
+		case strings.HasPrefix(arg, "--env="):
+			env = strings.TrimPrefix(arg, "--env=")
+		case strings.HasPrefix(arg, "-"):
+			return "", nil, false, "", fmt.Errorf("unknown flag %s; %s", arg, usage)
+		case command == "":
+			command = arg
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	if command == "" {
+		return "", nil, false, "", fmt.Errorf(usage)
+	}
+	if dryRun && command == "status" {
+		return "", nil, false, "", fmt.Errorf("--dry-run only applies to up and down")
+	}
+	return command, positional, dryRun, env, nil
+}
+
+// printMigrationPlan writes the SQL of planned migrations as a reviewable script
+func printMigrationPlan(plan []database.PlannedMigration) error {
+	if len(plan) == 0 {
+		fmt.Println("-- Nothing to do")
+		return nil
+	}
+
+	for _, planned := range plan {
+		fmt.Printf("-- %d_%s (%s)\n", planned.Migration.Version, planned.Migration.Name, planned.Direction)
+		for _, statement := range planned.Statements {
+			fmt.Printf("%s;\n", strings.TrimSpace(statement))
+		}
+		if len(planned.Backfill) > 0 {
+			fmt.Println("-- backfill, outside the transaction: each statement commits on its own and repeats")
+			fmt.Println("-- until it updates fewer rows than its batch size; only the first batch is shown")
+			for _, statement := range planned.Backfill {
+				fmt.Printf("%s;\n", strings.TrimSpace(statement))
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Printf("-- %d migration(s), dry run: nothing was changed\n", len(plan))
+	return nil
+}
+
+// environmentName labels the database selected for a migrate command
+func environmentName(env string) string {
+	if env == "" {
+		return "default"
+	}
+	return env
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/alex-1900/wishlist/src/database"
+	"github.com/gin-gonic/gin"
+)
+
+// Module is a self-contained business area that plugs into the App.
+// Modules register themselves with RegisterModule (usually from an init function)
+// and the App drives their migrations, routes and lifecycle hooks.
+type Module interface {
+	// Name returns the unique module name
+	Name() string
+	// RegisterRoutes registers the module's HTTP routes
+	RegisterRoutes(router *gin.Engine)
+	// Migrations returns the schema changes owned by the module
+	Migrations() []database.Migration
+	// Start is called once the App is built, before the server starts serving
+	Start(app *App) error
+	// Stop is called when the App shuts down
+	Stop(app *App) error
+}
+
+var (
+	modules   []Module
+	modulesMu sync.Mutex
+)
+
+// RegisterModule adds a module to the App's module registry
+func RegisterModule(m Module) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	for _, existing := range modules {
+		if existing.Name() == m.Name() {
+			panic(fmt.Sprintf("module %q is already registered", m.Name()))
+		}
+	}
+	modules = append(modules, m)
+}
+
+// Modules returns the registered modules in registration order
+func Modules() []Module {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	result := make([]Module, len(modules))
+	copy(result, modules)
+	return result
+}
+
+// RegisterRoutes registers the routes of every registered module
+func (a *App) RegisterRoutes() {
+	for _, m := range Modules() {
+		m.RegisterRoutes(a.GinEngine)
+	}
+}
+
+// Start runs the Start hook of every registered module
+func (a *App) Start() error {
+	for _, m := range Modules() {
+		if err := m.Start(a); err != nil {
+			return fmt.Errorf("failed to start module %s: %w", m.Name(), err)
+		}
+		log.Printf("Module %s started", m.Name())
+	}
+	return nil
+}
+
+// Stop runs the Stop hook of every registered module in reverse order
+func (a *App) Stop() {
+	registered := Modules()
+	for i := len(registered) - 1; i >= 0; i-- {
+		if err := registered[i].Stop(a); err != nil {
+			log.Printf("Error stopping module %s: %v", registered[i].Name(), err)
+		}
+	}
+}
@@ -1,12 +1,27 @@
 package app
 
 import (
+	"crypto/rsa"
 	"database/sql"
 	"fmt"
 	"log"
+	"time"
 
-	"github.com/alex-1900/wishlist/src/database"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/auth/oauth"
+	"github.com/alex-1900/wishlist/src/bruteforce"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/demo"
+	"github.com/alex-1900/wishlist/src/imaging"
+	"github.com/alex-1900/wishlist/src/mailer"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/ratelimit"
+	"github.com/alex-1900/wishlist/src/realtime"
 	"github.com/alex-1900/wishlist/src/repository"
+	"github.com/alex-1900/wishlist/src/scraper"
+	"github.com/alex-1900/wishlist/src/storage"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 )
@@ -22,20 +37,188 @@ func buildApp() *App {
 	}
 	app.DB = db
 
-	// Initialize database schema
-	if err := database.InitializeSchema(db); err != nil {
-		log.Fatalf("Failed to initialize database schema: %v", err)
+	// Apply pending migrations of all registered modules
+	if app.Config.AutoMigrate {
+		if err := migrateUp(db); err != nil {
+			log.Fatalf("Failed to initialize database schema: %v", err)
+		}
 	}
 
 	// Initialize repository manager
-	app.Repository = repository.NewRepositoryManager(db)
+	app.Repository = repository.NewRepositoryManager(repository.NewDB(db, app.Config.Query))
 
-	app.GinEngine = buildGinEngine()
+	// Initialize JWT manager backed by the token denylist
+	app.JWTManager = buildJWTManager(app.Config, app.Repository)
+
+	// Initialize OpenID Connect ID token signing
+	app.IDTokens = buildIDTokenSigner(app.Config.OIDC)
+
+	// Initialize sign-in with external identity providers
+	app.OAuth = buildOAuthProviders(app.Config.OAuth)
+
+	// Initialize failed login throttling and alerting
+	app.BruteForce = buildBruteForceMonitor(app.Config.LoginLockout)
+
+	// Initialize email delivery
+	app.Mailer = buildMailer(app.Config.Mailer)
+
+	// Initialize link metadata scraping
+	app.Scraper = buildScraper(app.Config.Scraper)
+
+	// Initialize file storage for uploads
+	app.Storage = buildStorage(app.Config.Storage)
+	model.SetMediaURLResolver(app.Storage.URL)
+	app.Images = imaging.NewProcessor(app.Storage)
+
+	// Initialize push delivery to open WebSocket connections
+	app.Realtime = realtime.NewHub()
+
+	// Initialize the caching headers of public routes and CDN purging
+	app.CDN = buildCDN(app.Config.CDN)
+
+	// Initialize exchange rates for display currencies
+	app.Currency = buildCurrency(app.Config.Currency)
+
+	// Initialize the request limits of public API keys
+	app.RateLimit = buildRateLimiter(app.Config.PublicAPI.RateLimit)
+
+	app.GinEngine = buildGinEngine(app.Config.ErrorDocsURL)
+
+	// Confine the writes of demo deployments to the sandbox accounts
+	if app.Config.Demo.Enabled {
+		app.GinEngine.Use(demo.Guard(app.JWTManager, app.Repository.User()))
+	}
+
+	// Serve locally stored uploads
+	if local, ok := app.Storage.(*storage.LocalStorage); ok {
+		app.GinEngine.Static(local.URLPrefix(), local.Root())
+	}
 	return app
 }
 
-func buildGinEngine() *gin.Engine {
-	return gin.Default()
+func buildJWTManager(config AppConfig, repo *repository.RepositoryManager) *auth.JWTManager {
+	duration := time.Duration(config.JWTExpiration) * time.Hour
+	if config.Session.Sliding {
+		duration = time.Duration(config.Session.AccessTokenTTL) * time.Minute
+	}
+
+	return auth.NewJWTManager(config.JWTSecret, duration).
+		UseDenylist(repo.RevokedToken()).
+		UseIssuer(config.JWTIssuer, config.JWTAudience).
+		UseLeeway(time.Duration(config.JWTLeeway) * time.Second)
+}
+
+func buildIDTokenSigner(oidcConfig OIDCConfig) *auth.IDTokenSigner {
+	var key *rsa.PrivateKey
+	var err error
+	if oidcConfig.SigningKeyPath != "" {
+		key, err = auth.LoadRSAKey(oidcConfig.SigningKeyPath)
+	} else {
+		log.Println("No OIDC signing key configured, generating one; ID tokens will not verify across restarts or instances")
+		key, err = auth.GenerateRSAKey()
+	}
+	if err != nil {
+		log.Fatalf("Failed to set up OIDC signing key: %v", err)
+	}
+
+	return auth.NewIDTokenSigner(oidcConfig.Issuer, key, time.Duration(oidcConfig.IDTokenTTL)*time.Minute)
+}
+
+func buildOAuthProviders(oauthConfig OAuthConfig) oauth.Providers {
+	providers, err := oauth.NewProviders(oauthConfig.RedirectBaseURL, oauthConfig.Google, oauthConfig.GitHub, oauthConfig.Apple)
+	if err != nil {
+		log.Fatalf("Failed to set up OAuth providers: %v", err)
+	}
+	return providers
+}
+
+func buildBruteForceMonitor(lockoutConfig LoginLockoutConfig) *bruteforce.Monitor {
+	return bruteforce.NewMonitor(bruteforce.Config{
+		MaxFailures: lockoutConfig.IPMaxFailures,
+		Window:      time.Duration(lockoutConfig.IPWindow) * time.Minute,
+		Duration:    time.Duration(lockoutConfig.IPDuration) * time.Minute,
+	}, bruteforce.NewAlerter(lockoutConfig.AlertWebhookURL))
+}
+
+func buildMailer(mailerConfig MailerConfig) mailer.Mailer {
+	switch mailerConfig.Driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(mailerConfig.SMTP, mailerConfig.From)
+	case "sendgrid":
+		return mailer.NewSendGridMailer(mailerConfig.SendGrid.APIKey, mailerConfig.From)
+	case "ses":
+		return mailer.NewSESMailer(mailerConfig.SES, mailerConfig.From)
+	case "log", "":
+		return mailer.NewLogMailer()
+	default:
+		log.Fatalf("Unknown mailer driver: %s", mailerConfig.Driver)
+		return nil
+	}
+}
+
+func buildScraper(scraperConfig ScraperConfig) *scraper.Scraper {
+	return scraper.New(scraper.Config{
+		Timeout:      time.Duration(scraperConfig.Timeout) * time.Second,
+		MaxBodyBytes: int64(scraperConfig.MaxBodyKB) * 1024,
+		CacheTTL:     time.Duration(scraperConfig.CacheTTL) * time.Minute,
+		CacheSize:    scraperConfig.CacheSize,
+		UserAgent:    scraperConfig.UserAgent,
+	})
+}
+
+func buildStorage(storageConfig StorageConfig) storage.Storage {
+	switch storageConfig.Driver {
+	case "s3":
+		return storage.NewS3Storage(storageConfig.S3)
+	case "local", "":
+		return storage.NewLocalStorage(storageConfig.Local)
+	default:
+		log.Fatalf("Unknown storage driver: %s", storageConfig.Driver)
+		return nil
+	}
+}
+
+func buildCDN(cdnConfig CDNConfig) *cdn.CDN {
+	switch cdnConfig.Provider {
+	case "fastly":
+		return cdn.New(cdnConfig.Policies, cdn.NewFastlyPurger(cdnConfig.Fastly))
+	case "cloudflare":
+		return cdn.New(cdnConfig.Policies, cdn.NewCloudflarePurger(cdnConfig.Cloudflare))
+	case "":
+		return cdn.New(cdnConfig.Policies, nil)
+	default:
+		log.Fatalf("Unknown CDN provider: %s", cdnConfig.Provider)
+		return nil
+	}
+}
+
+func buildCurrency(currencyConfig CurrencyConfig) *currency.Converter {
+	ttl := time.Duration(currencyConfig.CacheTTL) * time.Minute
+	switch currencyConfig.Provider {
+	case "ecb", "":
+		return currency.NewConverter(currency.NewECBProvider(currencyConfig.ECBURL), ttl)
+	case "static":
+		return currency.NewConverter(currency.NewStaticProvider(currencyConfig.Base, currencyConfig.Rates), ttl)
+	default:
+		log.Fatalf("Unknown currency provider: %s", currencyConfig.Provider)
+		return nil
+	}
+}
+
+func buildRateLimiter(rateLimitConfig RateLimitConfig) *ratelimit.Limiter {
+	limiterConfig := ratelimit.Config{Window: time.Duration(rateLimitConfig.Window) * time.Second}
+	if rateLimitConfig.Enabled {
+		limiterConfig.Default = rateLimitConfig.Default
+		limiterConfig.Routes = rateLimitConfig.Routes
+		limiterConfig.Keys = rateLimitConfig.Keys
+	}
+	return ratelimit.NewLimiter(limiterConfig, bruteforce.NewAlerter(rateLimitConfig.AlertWebhookURL))
+}
+
+func buildGinEngine(errorDocsURL string) *gin.Engine {
+	engine := gin.Default()
+	engine.Use(apperror.Middleware(errorDocsURL))
+	return engine
 }
 
 func buildDatabaseConnection(dbConfig DatabaseConfig) (*sql.DB, error) {
@@ -47,6 +230,16 @@ func buildDatabaseConnection(dbConfig DatabaseConfig) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
+	if dbConfig.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(dbConfig.MaxOpenConns)
+	}
+	if dbConfig.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(dbConfig.MaxIdleConns)
+	}
+	if dbConfig.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(time.Duration(dbConfig.ConnMaxLifetime) * time.Minute)
+	}
+
 	// Test the connection
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Serve runs the HTTP server until it fails or the process receives SIGINT or SIGTERM,
+// in which case in-flight requests are given ShutdownTimeout seconds to finish
+func (a *App) Serve() error {
+	serverConfig := a.Config.Server
+	server := &http.Server{
+		Addr:         serverConfig.Address,
+		Handler:      a.GinEngine,
+		ReadTimeout:  time.Duration(serverConfig.ReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(serverConfig.WriteTimeout) * time.Second,
+		IdleTimeout:  time.Duration(serverConfig.IdleTimeout) * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Listening on %s", server.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("server stopped unexpectedly: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down, draining in-flight requests")
+
+	// WebSocket connections are hijacked, so Shutdown does not wait for or close them
+	a.Realtime.Close()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(serverConfig.ShutdownTimeout)*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down server gracefully: %w", err)
+	}
+	return nil
+}
+
+// Close waits for pending image variants and releases the database connection pool
+func (a *App) Close() {
+	if a.Images != nil {
+		a.Images.Wait()
+	}
+	if a.DB == nil {
+		return
+	}
+	if err := a.DB.Close(); err != nil {
+		log.Printf("Error closing database connection: %v", err)
+		return
+	}
+	log.Println("Database connection closed")
+}
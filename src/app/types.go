@@ -3,7 +3,18 @@ package app
 import (
 	"database/sql"
 
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/auth/oauth"
+	"github.com/alex-1900/wishlist/src/bruteforce"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/imaging"
+	"github.com/alex-1900/wishlist/src/mailer"
+	"github.com/alex-1900/wishlist/src/ratelimit"
+	"github.com/alex-1900/wishlist/src/realtime"
 	"github.com/alex-1900/wishlist/src/repository"
+	"github.com/alex-1900/wishlist/src/scraper"
+	"github.com/alex-1900/wishlist/src/storage"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 )
@@ -15,13 +26,151 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	MaxOpenConns    int // 0 leaves the pool unbounded
+	MaxIdleConns    int // 0 keeps the database/sql default of 2
+	ConnMaxLifetime int // in minutes, 0 reuses connections forever
+}
+
+type ServerConfig struct {
+	Address         string // host:port to listen on
+	ReadTimeout     int    // in seconds
+	WriteTimeout    int    // in seconds
+	IdleTimeout     int    // in seconds
+	ShutdownTimeout int    // in seconds, how long in-flight requests may drain
+}
+
+type MigrationConfig struct {
+	StatementTimeout int // in seconds, 0 leaves the server default
+	LockTimeout      int // in seconds, 0 leaves the server default
 }
 
 type AppConfig struct {
 	AppName       string
+	Server        ServerConfig
 	Database      DatabaseConfig
+	Environments  map[string]DatabaseConfig // databases the migrate CLI can target with --env
+	AutoMigrate   bool                      // apply pending migrations on startup
+	Migration     MigrationConfig
+	Query         repository.QueryConfig // timeout and retries of repository queries
 	JWTSecret     string
-	JWTExpiration int // in hours
+	JWTExpiration int    // in hours
+	JWTIssuer     string // iss claim minted and required; empty disables the check
+	JWTAudience   string // aud claim minted and required; empty disables the check
+	JWTLeeway     int    // clock skew tolerated when checking token times, in seconds
+
+	RefreshTokenExpiration int // in hours
+	Session                SessionConfig
+	OIDC                   OIDCConfig
+	OAuth                  OAuthConfig
+
+	AdminUserIDs []int // users allowed to call the /admin endpoints
+
+	ErrorDocsURL string // error reference that error responses link to, one page per code under it; empty links only codes with their own docs
+
+	EmailVerification EmailVerificationConfig
+	LoginLockout      LoginLockoutConfig
+	Mailer            MailerConfig
+	Scraper           ScraperConfig
+	Storage           StorageConfig
+	PublicAPI         PublicAPIConfig
+	Sitemap           SitemapConfig
+	Robots            RobotsConfig
+	Reminders         RemindersConfig
+	CDN               CDNConfig
+	PriceTracking     PriceTrackingConfig
+	Currency          CurrencyConfig
+	Archival          ArchivalConfig
+	Imports           ImportsConfig
+	Demo              DemoConfig
+}
+
+// SessionConfig enables sliding sessions: access tokens last minutes, and each refresh extends
+// the refresh token by IdleTimeout up to MaxLifetime after login
+type SessionConfig struct {
+	Sliding        bool
+	AccessTokenTTL int // in minutes, replaces JWTExpiration when sliding
+	IdleTimeout    int // in hours, refresh token lifetime after its last use
+	MaxLifetime    int // in hours since login, after which the user must log in again
+}
+
+// OIDCConfig configures the OpenID Connect endpoints used by first-party apps
+type OIDCConfig struct {
+	Issuer         string   // public base URL of the API, published as the ID token issuer
+	SigningKeyPath string   // PEM RSA key signing ID tokens; empty generates one per process (development only)
+	ClientIDs      []string // client IDs allowed to request tokens, used as the ID token audience
+	IDTokenTTL     int      // in minutes
+}
+
+// OAuthConfig configures sign-in with external identity providers; providers without
+// a client ID are disabled
+type OAuthConfig struct {
+	RedirectBaseURL string // public base URL of the API that provider callbacks return to
+	Google          oauth.Config
+	GitHub          oauth.Config
+	Apple           oauth.AppleConfig
+}
+
+type MailerConfig struct {
+	Driver   string // log, smtp, sendgrid or ses
+	From     string
+	SMTP     mailer.SMTPConfig
+	SendGrid SendGridConfig
+	SES      mailer.SESConfig
+}
+
+type SendGridConfig struct {
+	APIKey string
+}
+
+type EmailVerificationConfig struct {
+	CodeExpiration int  // in minutes
+	MaxAttempts    int  // failed attempts allowed per code
+	ResendCooldown int  // in seconds
+	RequireOnLogin bool // reject logins from unverified email addresses
+}
+
+type LoginLockoutConfig struct {
+	MaxFailures int // failed logins before the account is locked, 0 disables lockout
+	Window      int // in minutes, failures older than this are forgotten
+	Duration    int // in minutes
+
+	IPMaxFailures int // failed logins from one IP before it is throttled, 0 disables throttling
+	IPWindow      int // in minutes
+	IPDuration    int // in minutes
+
+	AlertWebhookURL string // Slack-compatible incoming webhook for lockout alerts, empty only logs
+}
+
+type ScraperConfig struct {
+	Timeout   int // in seconds
+	MaxBodyKB int // HTML read per page, in kilobytes
+	CacheTTL  int // in minutes, 0 disables caching
+	CacheSize int // pages kept in the cache
+	UserAgent string
+}
+
+type PublicAPIConfig struct {
+	CacheMaxAge int // in seconds, how long clients and shared caches may keep responses
+	RateLimit   RateLimitConfig
+}
+
+// RateLimitConfig limits the requests each API key may make per window. Past Soft requests
+// responses carry a warning and an alert is raised; past Hard requests are refused.
+type RateLimitConfig struct {
+	Enabled         bool
+	Window          int                        // in seconds
+	Default         ratelimit.Limit            // applies to routes and keys without their own limit
+	Routes          map[string]ratelimit.Limit // by route, as in "/api/v1/wishlists/:id"
+	Keys            map[int]ratelimit.Limit    // by API key ID, taking precedence over Routes
+	AlertWebhookURL string                     // Slack-compatible incoming webhook, empty only logs
+}
+
+type StorageConfig struct {
+	Driver      string // local or s3
+	MaxUploadKB int    // largest accepted image, in kilobytes
+	Local       storage.LocalConfig
+	S3          storage.S3Config
 }
 
 type App struct {
@@ -29,4 +178,90 @@ type App struct {
 	GinEngine  *gin.Engine
 	DB         *sql.DB
 	Repository *repository.RepositoryManager
+	JWTManager *auth.JWTManager
+	Mailer     mailer.Mailer
+	Scraper    *scraper.Scraper
+	Storage    storage.Storage
+	Images     *imaging.Processor
+	Realtime   *realtime.Hub
+	BruteForce *bruteforce.Monitor
+	IDTokens   *auth.IDTokenSigner
+	OAuth      oauth.Providers
+	CDN        *cdn.CDN
+	Currency   *currency.Converter
+	RateLimit  *ratelimit.Limiter
+}
+
+// SitemapConfig configures the sitemap of public profiles and wishlists served at /sitemap.xml
+type SitemapConfig struct {
+	Enabled         bool   // serve the sitemap and refresh it in the background
+	BaseURL         string // public base URL of the API, where the sitemap pages are served
+	ProfileURL      string // URL of a profile page of the web app, {username} is replaced
+	WishlistURL     string // URL of a wishlist page of the web app, {id} is replaced by its public ID
+	RefreshInterval int    // in minutes
+	URLsPerPage     int    // at most 50000, the limit of the sitemap protocol
+}
+
+// RobotsConfig configures the crawl policy served at /robots.txt
+type RobotsConfig struct {
+	DisallowAll bool     // keep every crawler out, e.g. on staging deployments
+	Disallow    []string // path prefixes crawlers must not fetch
+}
+
+// RemindersConfig configures the job notifying friends of upcoming birthdays and important dates
+type RemindersConfig struct {
+	Enabled       bool
+	DaysBefore    int // how many days ahead friends are reminded
+	CheckInterval int // in minutes
+}
+
+// CDNConfig configures the caching headers of the public routes and the purging of the copies a
+// CDN keeps when wishlists and profiles change
+type CDNConfig struct {
+	Policies   map[string]cdn.Policy // by route group: cdn.GroupPublicAPI, GroupShared or GroupSEO
+	Provider   string                // fastly or cloudflare to purge changes, empty to let copies expire
+	Fastly     cdn.FastlyConfig
+	Cloudflare cdn.CloudflareConfig
+}
+
+// PriceTrackingConfig configures the job re-reading the prices of wish items from their URLs
+type PriceTrackingConfig struct {
+	Enabled       bool
+	CheckInterval int // in minutes
+	RecheckAfter  int // in hours, how long a price is trusted before it is read again
+	BatchSize     int // items read per run
+	RetentionDays int // how long the price history is kept
+}
+
+// ImportsConfig configures the imports of wish items from CSV files and Amazon wish lists, run
+// in the background by the wishlist module
+type ImportsConfig struct {
+	Enabled      bool
+	PollInterval int // in seconds, how often waiting imports are looked for
+	MaxRows      int // rows of a file or items of an Amazon wish list imported at most
+	MaxFileKB    int // size of uploaded CSV files
+}
+
+// ArchivalConfig configures the job archiving wishlists once their event is over
+type ArchivalConfig struct {
+	Enabled          bool
+	ArchiveAfterDays int // days after the event date a wishlist stays active, for late thank-yous
+	CheckInterval    int // in minutes
+}
+
+// CurrencyConfig configures the exchange rates used to show prices in a viewer's display currency
+type CurrencyConfig struct {
+	Provider string             // ecb or static
+	ECBURL   string             // reference rates document, currency.ECBURL when empty
+	Base     string             // currency the static rates are against
+	Rates    map[string]float64 // static rates: units of each currency one unit of Base buys
+	CacheTTL int                // in minutes, how long fetched rates are used
+}
+
+// DemoConfig configures demo deployments, where visitors try the app with shared sandbox
+// accounts. Use it on a database of its own: the sandbox accounts are the only ones able to write.
+type DemoConfig struct {
+	Enabled       bool   // seed the sandbox, reset it on schedule and refuse writes outside it
+	ResetInterval int    // in minutes
+	Password      string // password of every sandbox account, published by GET /site-config
 }
@@ -0,0 +1,135 @@
+// Package apperror defines the typed errors handlers report and the Gin middleware
+// that turns them into consistent JSON responses with stable error codes.
+//
+// Handlers report a failure with ctx.Error(apperror.NotFound("Wishlist not found"))
+// and return; Middleware writes the response. Internal errors are logged with their
+// cause but only their message reaches the client.
+package apperror
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error is an error with an HTTP status and a machine-readable code
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details interface{}
+	Extra   map[string]interface{}
+	Err     error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying cause
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithCode replaces the generic code with a more specific one from the catalog
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+// WithDetails attaches details that are safe to show to the client
+func (e *Error) WithDetails(details interface{}) *Error {
+	e.Details = details
+	return e
+}
+
+// With adds an extra top-level field to the response, such as retry_after
+func (e *Error) With(key string, value interface{}) *Error {
+	if e.Extra == nil {
+		e.Extra = make(map[string]interface{})
+	}
+	e.Extra[key] = value
+	return e
+}
+
+// New creates an error with the given status, code and message
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// InvalidRequest reports a request body or query that could not be parsed
+func InvalidRequest(err error) *Error {
+	return New(http.StatusBadRequest, CodeInvalidRequest, "Invalid request format").WithDetails(err.Error())
+}
+
+// Validation reports a request that was parsed but failed validation
+func Validation(err error) *Error {
+	return New(http.StatusBadRequest, CodeValidationFailed, "Validation failed").WithDetails(err.Error())
+}
+
+// BadRequest reports any other malformed request
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// Unauthorized reports a missing or unusable authentication
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// Forbidden reports an authenticated user lacking permission
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, CodeForbidden, message)
+}
+
+// NotFound reports a missing resource, or one the user may not know about
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+// Conflict reports a request clashing with the current state of a resource
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, CodeConflict, message)
+}
+
+// Locked reports a resource that is temporarily locked
+func Locked(message string) *Error {
+	return New(http.StatusLocked, CodeLocked, message)
+}
+
+// TooManyRequests reports a client that must slow down
+func TooManyRequests(message string) *Error {
+	return New(http.StatusTooManyRequests, CodeTooManyRequests, message)
+}
+
+// PayloadTooLarge reports a request body or upload over the size limit
+func PayloadTooLarge(message string) *Error {
+	return New(http.StatusRequestEntityTooLarge, CodePayloadTooLarge, message)
+}
+
+// UnsupportedMediaType reports an upload in a format the endpoint does not accept
+func UnsupportedMediaType(message string) *Error {
+	return New(http.StatusUnsupportedMediaType, CodeUnsupportedMedia, message)
+}
+
+// Upstream reports a failure of an external service; the cause is logged, not shown
+func Upstream(message string, err error) *Error {
+	e := New(http.StatusBadGateway, CodeUpstreamFailed, message)
+	e.Err = err
+	return e
+}
+
+// Unavailable reports a resource that is not ready yet or temporarily unavailable
+func Unavailable(message string) *Error {
+	return New(http.StatusServiceUnavailable, CodeUnavailable, message)
+}
+
+// Internal reports an unexpected failure; the cause is logged, not shown
+func Internal(message string, err error) *Error {
+	e := New(http.StatusInternalServerError, CodeInternal, message)
+	e.Err = err
+	return e
+}
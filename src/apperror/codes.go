@@ -0,0 +1,71 @@
+package apperror
+
+// Error code catalog. Codes are part of the API contract: clients match on them,
+// so existing values must never change meaning.
+const (
+	// Generic codes, one per error kind
+	CodeInvalidRequest   = "invalid_request"
+	CodeValidationFailed = "validation_failed"
+	CodeBadRequest       = "bad_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeLocked           = "locked"
+	CodeTooManyRequests  = "too_many_requests"
+	CodePayloadTooLarge  = "payload_too_large"
+	CodeUnsupportedMedia = "unsupported_media_type"
+	CodeUpstreamFailed   = "upstream_failed"
+	CodeUnavailable      = "service_unavailable"
+	CodeInternal         = "internal_error"
+
+	// Authentication
+	CodeTokenMissing       = "token_missing"
+	CodeTokenInvalid       = "token_invalid"
+	CodeTokenRevoked       = "token_revoked"
+	CodeTokenExpired       = "token_expired"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeAccountLocked      = "account_locked"
+	CodeLoginThrottled     = "login_throttled"
+	CodeEmailNotVerified   = "email_not_verified"
+	CodeRefreshTokenReused = "refresh_token_reused"
+	CodeSessionExpired     = "session_expired"
+	CodeAPIKeyMissing      = "api_key_missing"
+	CodeAPIKeyInvalid      = "api_key_invalid"
+	CodeRateLimited        = "rate_limited"
+	CodeDemoReadOnly       = "demo_read_only"
+
+	// OAuth 2.0 token endpoint (RFC 6749 error codes)
+	CodeInvalidClient        = "invalid_client"
+	CodeUnsupportedGrantType = "unsupported_grant_type"
+
+	// Accounts
+	CodeUsernameTaken   = "username_taken"
+	CodeEmailTaken      = "email_taken"
+	CodePasswordTooWeak = "password_too_weak"
+
+	CodeImportantDateLimit = "important_date_limit_reached"
+
+	// Registration
+	CodeRegistrationClosed    = "registration_closed"
+	CodeEmailDomainNotAllowed = "email_domain_not_allowed"
+	CodeEmailDomainDenied     = "email_domain_denied"
+
+	// Verification codes
+	CodeVerificationCodeInvalid = "verification_code_invalid"
+	CodeVerificationCodeExpired = "verification_code_expired"
+
+	// Wishlists
+	CodeItemFullyReserved    = "item_fully_reserved"
+	CodeNoUnclaimedItem      = "no_unclaimed_item"
+	CodePollClosed           = "poll_closed"
+	CodeItemNotPriced        = "item_not_priced"
+	CodePledgeTooLarge       = "pledge_too_large"
+	CodeWishlistArchived     = "wishlist_archived"
+	CodeReservationPurchased = "reservation_purchased"
+	CodeItemFullyReceived    = "item_fully_received"
+	CodeImportInProgress     = "import_in_progress"
+
+	// Social
+	CodeUserBlocked = "user_blocked"
+)
@@ -0,0 +1,72 @@
+package apperror
+
+import (
+	"errors"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/pii"
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware writes the last error a handler reported with ctx.Error as a JSON response:
+//
+//	{"error": "Wishlist not found", "code": "not_found"}
+//
+// Codes with a registered remediation also get its "hint", and a "docs_url" from the
+// remediation or the error reference at docsBaseURL. Errors that are not *Error are treated
+// as internal errors. Server errors are logged with their details and extra fields passed
+// through pii.Redact.
+func Middleware(docsBaseURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		var appErr *Error
+		if !errors.As(err, &appErr) {
+			appErr = Internal("Internal server error", err)
+		}
+
+		if appErr.Status >= 500 {
+			logServerError(c, appErr)
+		}
+
+		body := gin.H{
+			"error": appErr.Message,
+			"code":  appErr.Code,
+		}
+		if appErr.Details != nil {
+			body["details"] = appErr.Details
+		}
+		remediation, _ := Lookup(appErr.Code)
+		if remediation.Hint != "" {
+			body["hint"] = remediation.Hint
+		}
+		if url := docsURL(appErr.Code, remediation, docsBaseURL); url != "" {
+			body["docs_url"] = url
+		}
+		for key, value := range appErr.Extra {
+			body[key] = value
+		}
+
+		c.AbortWithStatusJSON(appErr.Status, body)
+	}
+}
+
+// logServerError logs a server error with its details and extra fields, personal data redacted
+func logServerError(c *gin.Context, appErr *Error) {
+	message := "%s %s: %v"
+	args := []interface{}{c.Request.Method, c.Request.URL.Path, appErr}
+	if appErr.Details != nil {
+		message += " details=%v"
+		args = append(args, pii.Redact(appErr.Details))
+	}
+	if appErr.Extra != nil {
+		message += " extra=%v"
+		args = append(args, pii.Redact(appErr.Extra))
+	}
+	log.Printf(message, args...)
+}
@@ -0,0 +1,96 @@
+package apperror
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// taggedUser stands in for a model holding personal data
+type taggedUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email" pii:"true"`
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantLog    []string
+		notInLog   []string
+	}{
+		{
+			name:       "client errors are not logged",
+			err:        NotFound("Wishlist not found"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   CodeNotFound,
+		},
+		{
+			name:       "server error details are logged with personal data redacted",
+			err:        Internal("Failed to update user", errors.New("boom")).WithDetails(taggedUser{Username: "alice", Email: "alice@example.com"}),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+			wantLog:    []string{"GET /test: Failed to update user: boom", "username:alice", "email:[REDACTED]"},
+			notInLog:   []string{"alice@example.com"},
+		},
+		{
+			name:       "errors that are not *Error are internal",
+			err:        errors.New("raw failure"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+			wantLog:    []string{"Internal server error: raw failure"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged bytes.Buffer
+			log.SetOutput(&logged)
+			defer log.SetOutput(os.Stderr)
+
+			engine := gin.New()
+			engine.Use(Middleware(""))
+			engine.GET("/test", func(c *gin.Context) { c.Error(tt.err) })
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body["code"] != tt.wantCode {
+				t.Errorf("code = %v, want %s", body["code"], tt.wantCode)
+			}
+
+			if len(tt.wantLog) == 0 && logged.Len() > 0 {
+				t.Errorf("logged %q, want nothing", logged.String())
+			}
+			for _, want := range tt.wantLog {
+				if !strings.Contains(logged.String(), want) {
+					t.Errorf("log %q does not contain %q", logged.String(), want)
+				}
+			}
+			for _, unwanted := range tt.notInLog {
+				if strings.Contains(logged.String(), unwanted) {
+					t.Errorf("log %q contains %q", logged.String(), unwanted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package apperror
+
+import (
+	"strings"
+	"sync"
+)
+
+// Remediation tells clients what to do about an error code: Hint is a sentence that can be
+// shown to users, Docs points to the documentation of the code, either a URL or the path of
+// an API endpoint describing the rule that was broken, such as /password-policy
+type Remediation struct {
+	Hint string
+	Docs string
+}
+
+// registry maps error codes to their remediation. It holds the codes of the catalog; modules
+// add the remediation of their own codes with Register.
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Remediation{
+		CodeInvalidRequest:     {Hint: "Check that the request body is valid JSON and the query parameters have the expected types."},
+		CodeValidationFailed:   {Hint: "Correct the fields named in details and send the request again."},
+		CodeTokenMissing:       {Hint: "Send the access token in the Authorization header as \"Bearer <token>\"."},
+		CodeTokenInvalid:       {Hint: "Sign in again to get a new access token."},
+		CodeTokenRevoked:       {Hint: "Sign in again to get a new access token."},
+		CodeTokenExpired:       {Hint: "Exchange the refresh token at /refresh-auth-token for a new access token.", Docs: "/refresh-auth-token"},
+		CodeInvalidCredentials: {Hint: "Check the email address and password, or reset the password."},
+		CodeAccountLocked:      {Hint: "Wait for retry_after seconds before signing in again, or reset the password."},
+		CodeLoginThrottled:     {Hint: "Wait for retry_after seconds before trying to sign in again."},
+		CodeEmailNotVerified:   {Hint: "Confirm the email address with the code sent by /send-verification-code.", Docs: "/send-verification-code"},
+		CodeRefreshTokenReused: {Hint: "The session was revoked for safety; sign in again."},
+		CodeSessionExpired:     {Hint: "The session reached its maximum lifetime; sign in again."},
+		CodeAPIKeyMissing:      {Hint: "Send the API key in the X-API-Key header."},
+		CodeAPIKeyInvalid:      {Hint: "Check the API key, it may have been revoked."},
+		CodeRateLimited:        {Hint: "Wait for retry_after seconds before sending more requests."},
+		CodeTooManyRequests:    {Hint: "Slow down and try again later."},
+		CodeDemoReadOnly:       {Hint: "Demo deployments only accept changes to the sandbox accounts listed by /site-config.", Docs: "/site-config"},
+
+		CodeUsernameTaken: {Hint: "Choose another username."},
+		CodeEmailTaken:    {Hint: "Sign in with this email address, or use another one."},
+
+		CodeRegistrationClosed:    {Hint: "Sign-ups are closed on this site; /site-config tells whether they are open.", Docs: "/site-config"},
+		CodeEmailDomainNotAllowed: {Hint: "Register with an email address of a domain allowed on this site."},
+		CodeEmailDomainDenied:     {Hint: "Register with an email address of another domain."},
+
+		CodeVerificationCodeInvalid: {Hint: "Check the code from the email, or ask for a new one."},
+		CodeVerificationCodeExpired: {Hint: "Ask for a new code with /send-verification-code.", Docs: "/send-verification-code"},
+
+		CodeItemFullyReserved:    {Hint: "Every unit of this item is already reserved; pick another item."},
+		CodeNoUnclaimedItem:      {Hint: "Every item of this wishlist is reserved or received."},
+		CodePollClosed:           {Hint: "The poll no longer accepts votes."},
+		CodeItemNotPriced:        {Hint: "Give the item a price before collecting contributions for it."},
+		CodePledgeTooLarge:       {Hint: "Pledge at most the amount still needed for the item."},
+		CodeWishlistArchived:     {Hint: "Unarchive the wishlist before changing it."},
+		CodeReservationPurchased: {Hint: "The item was already marked purchased, so the reservation can no longer change."},
+		CodeItemFullyReceived:    {Hint: "Every unit of this item was already received."},
+		CodeImportInProgress:     {Hint: "Wait for the running import to finish; its status tells how far it is."},
+
+		CodeUserBlocked: {Hint: "This user blocked you or was blocked by you."},
+
+		CodePayloadTooLarge:  {Hint: "Send a smaller request body or file."},
+		CodeUnsupportedMedia: {Hint: "Send the file in one of the formats the endpoint accepts."},
+		CodeUpstreamFailed:   {Hint: "An external service failed; try again later."},
+		CodeUnavailable:      {Hint: "Try again in a moment."},
+	}
+)
+
+// Register sets the remediation of a code, replacing the one of the catalog. Modules call it
+// from init for the codes they own.
+func Register(code string, remediation Remediation) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[code] = remediation
+}
+
+// Lookup returns the remediation of a code, if one is registered
+func Lookup(code string) (Remediation, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	remediation, ok := registry[code]
+	return remediation, ok
+}
+
+// docsURL returns the documentation link of a code: its registered Docs, or the page of the
+// code in the error reference at docsBaseURL. Empty when neither exists.
+func docsURL(code string, remediation Remediation, docsBaseURL string) string {
+	if remediation.Docs != "" {
+		return remediation.Docs
+	}
+	if docsBaseURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(docsBaseURL, "/") + "/" + code
+}
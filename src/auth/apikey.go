@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// API key format constants. Keys start with APIKeyPrefix so they are easy to spot in leaked
+// code, and the first APIKeyDisplayLength characters are stored in clear to tell keys apart.
+const (
+	APIKeyPrefix        = "wl_"
+	APIKeyDisplayLength = 11
+	apiKeyBytes         = 32
+)
+
+// APIKeyHeader is the request header carrying an API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyStore authenticates API keys by the hash of their raw value
+type APIKeyStore interface {
+	// Authenticate returns the ID of the active key with the hash and whether there is one
+	Authenticate(keyHash string) (int, bool, error)
+}
+
+// GenerateAPIKey creates a new random API key.
+// It returns the raw key to hand to the integrator once, its display prefix and the hash to store.
+func GenerateAPIKey() (string, string, string, error) {
+	buf := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	raw := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return raw, raw[:APIKeyDisplayLength], HashAPIKey(raw), nil
+}
+
+// HashAPIKey returns the SHA-256 hex digest used to store an API key
+func HashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyMiddleware only lets requests carrying an active API key in the X-API-Key header through
+func APIKeyMiddleware(store APIKeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader(APIKeyHeader)
+		if raw == "" {
+			c.Error(apperror.Unauthorized("X-API-Key header is required").WithCode(apperror.CodeAPIKeyMissing))
+			c.Abort()
+			return
+		}
+
+		keyID, ok, err := store.Authenticate(HashAPIKey(raw))
+		if err != nil {
+			c.Error(apperror.Internal("Failed to check API key", err))
+			c.Abort()
+			return
+		}
+		if !ok {
+			c.Error(apperror.Unauthorized("Invalid API key").WithCode(apperror.CodeAPIKeyInvalid))
+			c.Abort()
+			return
+		}
+
+		c.Set("api_key_id", keyID)
+		c.Next()
+	}
+}
+
+// GetAPIKeyID retrieves the ID of the API key that authenticated the request
+func GetAPIKeyID(c *gin.Context) (int, bool) {
+	value, exists := c.Get("api_key_id")
+	if !exists {
+		return 0, false
+	}
+	id, ok := value.(int)
+	return id, ok
+}
@@ -1,12 +1,22 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+var (
+	// ErrTokenRevoked is returned when validating a token that is on the denylist
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrTokenExpired is returned when validating a token past its expiry; clients should refresh
+	ErrTokenExpired = errors.New("token has expired")
+)
+
 // Claims represents the JWT claims structure
 type Claims struct {
 	UserID   int    `json:"user_id"`
@@ -15,10 +25,21 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// TokenDenylist reports whether a token, identified by its JTI, has been revoked, or belongs to a
+// user who was deleted or whose sessions were ended after it was issued
+type TokenDenylist interface {
+	IsRevoked(jti string, userID int, issuedAt time.Time) (bool, error)
+}
+
 // JWTManager manages JWT token generation and validation
 type JWTManager struct {
 	secretKey string
 	duration  time.Duration
+	denylist  TokenDenylist
+
+	issuer   string
+	audience string
+	leeway   time.Duration
 }
 
 // NewJWTManager creates a new JWT manager
@@ -29,18 +50,54 @@ func NewJWTManager(secretKey string, duration time.Duration) *JWTManager {
 	}
 }
 
+// UseDenylist makes token validation reject tokens revoked in the given denylist
+func (j *JWTManager) UseDenylist(denylist TokenDenylist) *JWTManager {
+	j.denylist = denylist
+	return j
+}
+
+// UseIssuer makes the manager mint tokens with the given iss and aud claims and reject
+// tokens carrying other values, so tokens from other deployments or services are refused.
+// An empty issuer or audience is neither set nor checked.
+func (j *JWTManager) UseIssuer(issuer, audience string) *JWTManager {
+	j.issuer = issuer
+	j.audience = audience
+	return j
+}
+
+// UseLeeway tolerates clock skew of up to leeway between servers when checking exp, nbf and iat
+func (j *JWTManager) UseLeeway(leeway time.Duration) *JWTManager {
+	j.leeway = leeway
+	return j
+}
+
+// Duration returns the lifetime of tokens issued by the manager
+func (j *JWTManager) Duration() time.Duration {
+	return j.duration
+}
+
 // GenerateToken generates a new JWT token for a user
 func (j *JWTManager) GenerateToken(userID int, username, email string) (string, error) {
+	jti, err := generateTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	claims := &Claims{
 		UserID:   userID,
 		Username: username,
 		Email:    email,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    j.issuer,
 		},
 	}
+	if j.audience != "" {
+		claims.Audience = jwt.ClaimStrings{j.audience}
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(j.secretKey))
@@ -54,8 +111,11 @@ func (j *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return []byte(j.secretKey), nil
-	})
+	}, j.parserOptions()...)
 
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrTokenExpired
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -65,22 +125,47 @@ func (j *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, fmt.Errorf("invalid token")
 	}
 
+	// Check the denylist for tokens revoked before their expiry. A token without iat predates
+	// any end of the user's sessions.
+	if j.denylist != nil {
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		revoked, err := j.denylist.IsRevoked(claims.ID, claims.UserID, issuedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+
 	return claims, nil
 }
 
+// parserOptions returns the claim checks applied when validating a token
+func (j *JWTManager) parserOptions() []jwt.ParserOption {
+	opts := []jwt.ParserOption{jwt.WithIssuedAt(), jwt.WithLeeway(j.leeway)}
+	if j.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(j.issuer))
+	}
+	if j.audience != "" {
+		opts = append(opts, jwt.WithAudience(j.audience))
+	}
+	return opts
+}
+
 // RefreshToken generates a new token with extended expiration
 func (j *JWTManager) RefreshToken(claims *Claims) (string, error) {
-	newClaims := &Claims{
-		UserID:   claims.UserID,
-		Username: claims.Username,
-		Email:    claims.Email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.duration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
-	}
+	return j.GenerateToken(claims.UserID, claims.Username, claims.Email)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims)
-	return token.SignedString([]byte(j.secretKey))
+// generateTokenID generates a random JTI for a token
+func generateTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
 }
@@ -1,9 +1,11 @@
 package auth
 
 import (
-	"net/http"
+	"errors"
+	"slices"
 	"strings"
 
+	"github.com/alex-1900/wishlist/src/apperror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,36 +15,114 @@ func AuthMiddleware(jwtManager *JWTManager) gin.HandlerFunc {
 		// Get the Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+			c.Error(apperror.Unauthorized("Authorization header is required").WithCode(apperror.CodeTokenMissing))
 			c.Abort()
 			return
 		}
 
-		// Check if the header has the Bearer prefix
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
+		if authenticate(c, jwtManager, authHeader) {
+			c.Next()
+		}
+	}
+}
+
+// OptionalAuthMiddleware authenticates the request when it carries a token and lets
+// anonymous requests through. A token that is present but invalid is still rejected.
+func OptionalAuthMiddleware(jwtManager *JWTManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
 			return
 		}
 
-		// Validate the token
-		claims, err := jwtManager.ValidateToken(parts[1])
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		if authenticate(c, jwtManager, authHeader) {
+			c.Next()
+		}
+	}
+}
+
+// WebSocketAuthMiddleware authenticates like AuthMiddleware but also accepts the access token
+// in the access_token query parameter, since browsers cannot set headers on a WebSocket handshake
+func WebSocketAuthMiddleware(jwtManager *JWTManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			if token := c.Query("access_token"); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
+		if authHeader == "" {
+			c.Error(apperror.Unauthorized("Authorization header or access_token is required").WithCode(apperror.CodeTokenMissing))
 			c.Abort()
 			return
 		}
 
-		// Set user claims in the context
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
+		if authenticate(c, jwtManager, authHeader) {
+			c.Next()
+		}
+	}
+}
+
+// AdminMiddleware only lets the given users through. It must run after AuthMiddleware.
+func AdminMiddleware(adminUserIDs []int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+		if userID == 0 || !slices.Contains(adminUserIDs, userID) {
+			c.Error(apperror.Forbidden("Administrator access required"))
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
 }
 
+// Authenticate validates the bearer token of the request like AuthMiddleware, for middleware that
+// must know the user before the route's own authentication runs. It aborts the request and
+// returns false if the token is unusable.
+func Authenticate(c *gin.Context, jwtManager *JWTManager) bool {
+	return authenticate(c, jwtManager, c.GetHeader("Authorization"))
+}
+
+// authenticate validates the bearer token and stores its claims in the context.
+// It aborts the request and returns false if the token is unusable.
+func authenticate(c *gin.Context, jwtManager *JWTManager, authHeader string) bool {
+	// Check if the header has the Bearer prefix
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		c.Error(apperror.Unauthorized("Invalid authorization header format").WithCode(apperror.CodeTokenInvalid))
+		c.Abort()
+		return false
+	}
+
+	// Validate the token
+	claims, err := jwtManager.ValidateToken(parts[1])
+	if errors.Is(err, ErrTokenRevoked) {
+		c.Error(apperror.Unauthorized("Token has been revoked").WithCode(apperror.CodeTokenRevoked))
+		c.Abort()
+		return false
+	}
+	if errors.Is(err, ErrTokenExpired) {
+		c.Error(apperror.Unauthorized("Token has expired").WithCode(apperror.CodeTokenExpired))
+		c.Abort()
+		return false
+	}
+	if err != nil {
+		c.Error(apperror.Unauthorized("Invalid token").WithCode(apperror.CodeTokenInvalid))
+		c.Abort()
+		return false
+	}
+
+	// Set user claims in the context
+	c.Set("claims", claims)
+	c.Set("user_id", claims.UserID)
+	c.Set("username", claims.Username)
+	c.Set("email", claims.Email)
+
+	return true
+}
+
 // GetUserID retrieves user ID from the context
 func GetUserID(c *gin.Context) (int, bool) {
 	userID, exists := c.Get("user_id")
@@ -72,3 +152,13 @@ func GetEmail(c *gin.Context) (string, bool) {
 	e, ok := email.(string)
 	return e, ok
 }
+
+// GetClaims retrieves the validated token claims from the context
+func GetClaims(c *gin.Context) (*Claims, bool) {
+	value, exists := c.Get("claims")
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*Claims)
+	return claims, ok
+}
@@ -0,0 +1,91 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// appleIssuer is the issuer of Apple's ID tokens and the audience of client secrets
+const appleIssuer = "https://appleid.apple.com"
+
+// AppleConfig configures Sign in with Apple. Apple has no static client secret; each token
+// request is signed with the private key created in the Apple developer account.
+type AppleConfig struct {
+	ClientID       string   // Services ID used by web sign-in; empty disables Apple
+	BundleIDs      []string // iOS app bundle IDs, accepted as the audience of native ID tokens
+	TeamID         string
+	KeyID          string
+	PrivateKeyPath string // .p8 key file; without it only native ID token sign-in works
+}
+
+// newApple returns the Apple provider. Apple posts the callback as a form and only shares
+// the user's email through the ID token.
+func newApple(config AppleConfig, client *http.Client) (*Provider, error) {
+	p := &Provider{
+		name:       "apple",
+		config:     Config{ClientID: config.ClientID},
+		authURL:    appleIssuer + "/auth/authorize",
+		authParams: url.Values{"response_mode": {"form_post"}},
+		tokenURL:   appleIssuer + "/auth/token",
+		scopes:     []string{"name", "email"},
+		identity:   appleIdentity,
+		idTokens: &idTokenVerifier{
+			issuer:    appleIssuer,
+			audiences: append([]string{config.ClientID}, config.BundleIDs...),
+			jwksURL:   appleIssuer + "/auth/keys",
+			client:    client,
+		},
+	}
+
+	if config.ClientID == "" || config.PrivateKeyPath == "" {
+		p.clientSecret = func() (string, error) {
+			return "", errors.New("no Apple private key configured")
+		}
+		return p, nil
+	}
+
+	pemBytes, err := os.ReadFile(config.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Apple private key: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(pemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Apple private key: %w", err)
+	}
+
+	p.clientSecret = func() (string, error) {
+		return appleClientSecret(config, key)
+	}
+	return p, nil
+}
+
+// appleClientSecret signs the short-lived ES256 JWT Apple expects as the client secret
+func appleClientSecret(config AppleConfig, key *ecdsa.PrivateKey) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    config.TeamID,
+		Subject:   config.ClientID,
+		Audience:  jwt.ClaimStrings{appleIssuer},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+	})
+	token.Header["kid"] = config.KeyID
+
+	return token.SignedString(key)
+}
+
+// appleIdentity reads the Apple account from the ID token of the token response
+func appleIdentity(ctx context.Context, p *Provider, token *Token) (*Identity, error) {
+	if token.IDToken == "" {
+		return nil, errors.New("apple returned no ID token")
+	}
+	return p.idTokens.verify(ctx, token.IDToken, "")
+}
@@ -0,0 +1,51 @@
+package oauth
+
+import (
+	"context"
+	"strconv"
+)
+
+// newGitHub returns the GitHub provider
+func newGitHub(config Config) *Provider {
+	return &Provider{
+		name:     "github",
+		config:   config,
+		authURL:  "https://github.com/login/oauth/authorize",
+		tokenURL: "https://github.com/login/oauth/access_token",
+		scopes:   []string{"read:user", "user:email"},
+		identity: githubIdentity,
+	}
+}
+
+// githubIdentity reads the GitHub account and its primary email address. The public profile
+// email may be empty or unverified, so the address comes from the emails endpoint.
+func githubIdentity(ctx context.Context, p *Provider, token *Token) (*Identity, error) {
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+	}
+	if err := p.getJSON(ctx, "https://api.github.com/user", token.AccessToken, &user); err != nil {
+		return nil, err
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.getJSON(ctx, "https://api.github.com/user/emails", token.AccessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{Login: user.Login}
+	if user.ID != 0 {
+		identity.Subject = strconv.FormatInt(user.ID, 10)
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+		}
+	}
+	return identity, nil
+}
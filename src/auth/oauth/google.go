@@ -0,0 +1,35 @@
+package oauth
+
+import "context"
+
+// newGoogle returns the Google provider, which identifies users through OpenID Connect userinfo
+func newGoogle(config Config) *Provider {
+	return &Provider{
+		name:     "google",
+		config:   config,
+		authURL:  "https://accounts.google.com/o/oauth2/v2/auth",
+		tokenURL: "https://oauth2.googleapis.com/token",
+		scopes:   []string{"openid", "email", "profile"},
+		identity: googleIdentity,
+	}
+}
+
+// googleIdentity reads the Google account from the userinfo endpoint
+func googleIdentity(ctx context.Context, p *Provider, token *Token) (*Identity, error) {
+	var info struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := p.getJSON(ctx, "https://openidconnect.googleapis.com/v1/userinfo", token.AccessToken, &info); err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		Subject:       info.Sub,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Login:         info.Name,
+	}, nil
+}
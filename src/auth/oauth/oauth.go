@@ -0,0 +1,265 @@
+// Package oauth signs users in with external identity providers through the OAuth 2.0
+// authorization code flow with PKCE. Each provider turns the access token it grants into
+// an Identity; linking identities to local users is left to the caller.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxResponseBytes bounds the provider responses read into memory
+const maxResponseBytes = 1 << 20
+
+// ErrUnknownProvider is returned for providers that are not configured
+var ErrUnknownProvider = errors.New("unknown OAuth provider")
+
+// Config holds the client credentials registered with a provider.
+// A provider without a client ID is disabled.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// Identity is the account a user holds with a provider
+type Identity struct {
+	Provider string
+	// Subject is the provider's stable, unique ID of the account
+	Subject       string
+	Email         string
+	EmailVerified bool
+	// Login is the provider's username or name, a starting point for a local username
+	Login string
+}
+
+// Token holds the tokens a provider grants for an authorization code
+type Token struct {
+	AccessToken string
+	// IDToken is the OpenID Connect ID token, for providers that issue one
+	IDToken string
+}
+
+// Provider is an OAuth 2.0 identity provider
+type Provider struct {
+	name        string
+	config      Config
+	authURL     string
+	authParams  url.Values
+	tokenURL    string
+	scopes      []string
+	redirectURL string
+	client      *http.Client
+	identity    func(ctx context.Context, p *Provider, token *Token) (*Identity, error)
+
+	// clientSecret replaces the configured secret for providers that expect a signed one
+	clientSecret func() (string, error)
+	// idTokens verifies ID tokens obtained by native apps, for providers that support it
+	idTokens *idTokenVerifier
+}
+
+// Name returns the provider's name as used in URLs
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL returns the provider URL the user is sent to, carrying the state that protects
+// the callback against forgery and the PKCE challenge derived from verifier
+func (p *Provider) AuthCodeURL(state, verifier string) string {
+	challenge := sha256.Sum256([]byte(verifier))
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.redirectURL},
+		"scope":                 {strings.Join(p.scopes, " ")},
+		"state":                 {state},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	for name, values := range p.authParams {
+		query[name] = values
+	}
+	return p.authURL + "?" + query.Encode()
+}
+
+// Exchange trades the authorization code from the callback for the provider's tokens
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	secret := p.config.ClientSecret
+	if p.clientSecret != nil {
+		var err error
+		if secret, err = p.clientSecret(); err != nil {
+			return nil, fmt.Errorf("failed to sign %s client secret: %w", p.name, err)
+		}
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"client_id":     {p.config.ClientID},
+		"client_secret": {secret},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s token request: %w", p.name, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// GitHub answers form encoded unless asked for JSON
+	req.Header.Set("Accept", "application/json")
+
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := p.do(req, &token); err != nil {
+		return nil, err
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("%s rejected the authorization code: %s", p.name, strings.TrimSpace(token.Error+" "+token.ErrorDescription))
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("%s returned no access token", p.name)
+	}
+
+	return &Token{AccessToken: token.AccessToken, IDToken: token.IDToken}, nil
+}
+
+// Identity returns the account the tokens were granted for
+func (p *Provider) Identity(ctx context.Context, token *Token) (*Identity, error) {
+	identity, err := p.identity(ctx, p, token)
+	if err != nil {
+		return nil, err
+	}
+	return p.complete(identity)
+}
+
+// SupportsIDTokens reports whether VerifyIDToken accepts the provider's ID tokens
+func (p *Provider) SupportsIDTokens() bool {
+	return p.idTokens != nil
+}
+
+// VerifyIDToken returns the account of an ID token a native app obtained from the provider,
+// such as Sign in with Apple on iOS. The token must carry the SHA-256 hash of nonce, so that a
+// captured token cannot be replayed.
+func (p *Provider) VerifyIDToken(ctx context.Context, idToken, nonce string) (*Identity, error) {
+	if p.idTokens == nil {
+		return nil, fmt.Errorf("%s does not support ID token sign-in", p.name)
+	}
+	if nonce == "" {
+		return nil, fmt.Errorf("invalid ID token: nonce is required")
+	}
+
+	identity, err := p.idTokens.verify(ctx, idToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return p.complete(identity)
+}
+
+// complete checks and normalizes an identity read from the provider
+func (p *Provider) complete(identity *Identity) (*Identity, error) {
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%s returned no account ID", p.name)
+	}
+
+	identity.Provider = p.name
+	identity.Email = strings.TrimSpace(identity.Email)
+	return identity, nil
+}
+
+// getJSON calls a provider API with the access token and decodes the JSON response into v
+func (p *Provider) getJSON(ctx context.Context, endpoint, accessToken string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build %s request: %w", p.name, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	return p.do(req, v)
+}
+
+// do sends a request to the provider and decodes its JSON response into v
+func (p *Provider) do(req *http.Request, v interface{}) error {
+	return doJSON(p.client, p.name, req, v)
+}
+
+// doJSON sends a request and decodes its JSON response, failing on non-2xx statuses
+// other than the OAuth error responses of token endpoints. name identifies the remote in errors.
+func doJSON(client *http.Client, name string, req *http.Request, v interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", name, err)
+	}
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("%s responded with status %d", name, resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", name, err)
+	}
+	return nil
+}
+
+// Providers holds the configured identity providers by name
+type Providers map[string]*Provider
+
+// NewProviders sets up the providers that have a client ID. Their callbacks are
+// redirectBaseURL + "/oauth/<name>/callback", which must be registered with each provider.
+func NewProviders(redirectBaseURL string, google, github Config, apple AppleConfig) (Providers, error) {
+	providers := make(Providers)
+	client := &http.Client{Timeout: 10 * time.Second}
+	base := strings.TrimRight(redirectBaseURL, "/")
+
+	appleProvider, err := newApple(apple, client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range []*Provider{newGoogle(google), newGitHub(github), appleProvider} {
+		if p.config.ClientID == "" {
+			continue
+		}
+		p.client = client
+		p.redirectURL = base + "/oauth/" + p.name + "/callback"
+		providers[p.name] = p
+	}
+	return providers, nil
+}
+
+// Get returns a configured provider
+func (ps Providers) Get(name string) (*Provider, error) {
+	p, ok := ps[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return p, nil
+}
+
+// NewState returns a random value for the state parameter or a PKCE verifier
+func NewState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate OAuth state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
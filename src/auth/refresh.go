@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// refreshTokenBytes is the amount of random data in a refresh token
+const refreshTokenBytes = 32
+
+// GenerateRefreshToken creates a new random refresh token.
+// It returns the raw token to hand to the client and the hash to store.
+func GenerateRefreshToken() (string, string, error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+	return raw, HashRefreshToken(raw), nil
+}
+
+// HashRefreshToken returns the SHA-256 hex digest used to store a refresh token
+func HashRefreshToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
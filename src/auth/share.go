@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// shareTokenBytes is the amount of random data in a share link token
+const shareTokenBytes = 24
+
+// GenerateShareToken creates a new unguessable token for a share link
+func GenerateShareToken() (string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,93 @@
+package bruteforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/pii"
+)
+
+// webhookTimeout bounds a single webhook delivery
+const webhookTimeout = 5 * time.Second
+
+// Alerter reports security events to the log and, when configured, to a Slack-compatible
+// incoming webhook
+type Alerter struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewAlerter creates a new Alerter; an empty webhookURL only logs
+func NewAlerter(webhookURL string) *Alerter {
+	return &Alerter{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Alert logs the event and posts it to the webhook in the background
+func (a *Alerter) Alert(event string, fields map[string]interface{}) {
+	text := formatAlert(event, fields)
+	log.Printf("Security alert: %s", text)
+
+	if a.webhookURL == "" {
+		return
+	}
+	go func() {
+		if err := a.post(text); err != nil {
+			log.Printf("Failed to deliver security alert webhook: %v", err)
+		}
+	}()
+}
+
+// post sends the alert as a Slack message payload
+func (a *Alerter) post(text string) error {
+	body, err := json.Marshal(map[string]string{"text": "Security alert: " + text})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// formatAlert renders an event and its fields as "event key=value ..." in a stable order,
+// with personal data in the field values redacted
+func formatAlert(event string, fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(event)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, pii.Redact(fields[key]))
+	}
+	return b.String()
+}
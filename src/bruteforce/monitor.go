@@ -0,0 +1,140 @@
+// Package bruteforce throttles IP addresses with repeated failed logins and raises alerts
+// when an IP is throttled or an account gets locked.
+package bruteforce
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxTracked bounds the number of IP addresses kept in memory; expired entries are dropped first
+const maxTracked = 10000
+
+// Config configures the Monitor
+type Config struct {
+	MaxFailures int           // failed logins from one IP before it is throttled, 0 disables throttling
+	Window      time.Duration // failures older than this are forgotten
+	Duration    time.Duration // how long a throttled IP is refused
+}
+
+// ThrottledIP describes an IP address currently refused for too many failed logins
+type ThrottledIP struct {
+	IP           string    `json:"ip"`
+	Failures     int       `json:"failures"`
+	BlockedUntil time.Time `json:"blocked_until"`
+}
+
+type ipState struct {
+	failures     int
+	windowStart  time.Time
+	blockedUntil time.Time
+}
+
+// Monitor counts failed logins per IP address in memory. Counts are per instance and are
+// lost on restart; account lockouts are the durable protection and live in the database.
+type Monitor struct {
+	config  Config
+	alerter *Alerter
+
+	mu  sync.Mutex
+	ips map[string]*ipState
+}
+
+// NewMonitor creates a new Monitor sending its alerts through alerter
+func NewMonitor(config Config, alerter *Alerter) *Monitor {
+	return &Monitor{
+		config:  config,
+		alerter: alerter,
+		ips:     make(map[string]*ipState),
+	}
+}
+
+// RetryAfter returns how long the IP is still throttled, 0 when it may log in
+func (m *Monitor) RetryAfter(ip string) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.ips[ip]
+	if !ok {
+		return 0
+	}
+	now := time.Now()
+	if !now.Before(state.blockedUntil) {
+		return 0
+	}
+	return state.blockedUntil.Sub(now).Truncate(time.Second) + time.Second
+}
+
+// RecordFailure counts a failed login from the IP, throttling it and raising an alert when
+// it reaches the threshold within the window
+func (m *Monitor) RecordFailure(ip string) {
+	if m.config.MaxFailures <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	now := time.Now()
+	state, ok := m.ips[ip]
+	if !ok {
+		if len(m.ips) >= maxTracked {
+			m.prune(now)
+		}
+		state = &ipState{windowStart: now}
+		m.ips[ip] = state
+	}
+	if now.Sub(state.windowStart) > m.config.Window {
+		state.failures = 0
+		state.windowStart = now
+	}
+	state.failures++
+
+	throttled := state.failures == m.config.MaxFailures
+	if throttled {
+		state.blockedUntil = now.Add(m.config.Duration)
+	}
+	failures, blockedUntil := state.failures, state.blockedUntil
+	m.mu.Unlock()
+
+	if throttled {
+		m.alerter.Alert("ip_throttled", map[string]interface{}{
+			"ip":            ip,
+			"failures":      failures,
+			"blocked_until": blockedUntil.UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+// AccountLocked raises an alert for an account locked after repeated failed logins
+func (m *Monitor) AccountLocked(userID int, ip string, lockedUntil time.Time) {
+	m.alerter.Alert("account_locked", map[string]interface{}{
+		"user_id":      userID,
+		"ip":           ip,
+		"locked_until": lockedUntil.UTC().Format(time.RFC3339),
+	})
+}
+
+// Throttled lists the IP addresses currently throttled, the most recently blocked first
+func (m *Monitor) Throttled() []ThrottledIP {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	throttled := []ThrottledIP{}
+	for ip, state := range m.ips {
+		if now.Before(state.blockedUntil) {
+			throttled = append(throttled, ThrottledIP{IP: ip, Failures: state.failures, BlockedUntil: state.blockedUntil.UTC()})
+		}
+	}
+	sort.Slice(throttled, func(i, j int) bool { return throttled[i].BlockedUntil.After(throttled[j].BlockedUntil) })
+	return throttled
+}
+
+// prune drops IPs that are neither throttled nor within their failure window
+func (m *Monitor) prune(now time.Time) {
+	for ip, state := range m.ips {
+		if !now.Before(state.blockedUntil) && now.Sub(state.windowStart) > m.config.Window {
+			delete(m.ips, ip)
+		}
+	}
+}
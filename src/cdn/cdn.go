@@ -0,0 +1,181 @@
+// Package cdn sets the caching headers a CDN follows on public routes and purges the copies it
+// keeps when the content behind them changes.
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Route groups a caching policy can be configured for
+const (
+	GroupPublicAPI = "public_api" // /api/v1
+	GroupShared    = "shared"     // /shared/:token
+	GroupSEO       = "seo"        // /robots.txt and the sitemap
+)
+
+// purgeTimeout bounds a purge request to the provider
+const purgeTimeout = 10 * time.Second
+
+// keysContextKey is the gin context key holding the surrogate keys of the response
+const keysContextKey = "cdn_keys"
+
+// Policy is how long browsers and the CDN may keep the responses of a route group
+type Policy struct {
+	MaxAge               int // in seconds, for browsers
+	SharedMaxAge         int // in seconds, for the CDN; 0 leaves it to MaxAge
+	StaleWhileRevalidate int // in seconds the CDN may serve a stale copy while fetching a new one
+	StaleIfError         int // in seconds the CDN may serve a stale copy while the API fails
+}
+
+// cacheControl returns the Cache-Control header, which Cloudflare also reads s-maxage from
+func (p Policy) cacheControl() string {
+	value := fmt.Sprintf("public, max-age=%d", p.MaxAge)
+	if p.SharedMaxAge > 0 {
+		value += fmt.Sprintf(", s-maxage=%d", p.SharedMaxAge)
+	}
+	return value
+}
+
+// surrogateControl returns the Surrogate-Control header, which Fastly reads and strips, empty
+// when the CDN keeps responses as long as browsers
+func (p Policy) surrogateControl() string {
+	if p.SharedMaxAge <= 0 {
+		return ""
+	}
+	value := fmt.Sprintf("max-age=%d", p.SharedMaxAge)
+	if p.StaleWhileRevalidate > 0 {
+		value += fmt.Sprintf(", stale-while-revalidate=%d", p.StaleWhileRevalidate)
+	}
+	if p.StaleIfError > 0 {
+		value += fmt.Sprintf(", stale-if-error=%d", p.StaleIfError)
+	}
+	return value
+}
+
+// UserKey is the surrogate key of the responses showing a user's profile or public wishlists
+func UserKey(publicID string) string {
+	return "user-" + publicID
+}
+
+// WishlistKey is the surrogate key of the responses showing a wishlist
+func WishlistKey(publicID string) string {
+	return "wishlist-" + publicID
+}
+
+// CDN applies the caching policies of route groups and purges cached responses through the provider
+type CDN struct {
+	policies map[string]Policy
+	purger   Purger
+}
+
+// New creates a CDN; a nil purger leaves cached responses to expire
+func New(policies map[string]Policy, purger Purger) *CDN {
+	return &CDN{
+		policies: policies,
+		purger:   purger,
+	}
+}
+
+// Purging reports whether changes are purged from the CDN, so callers can skip looking up keys
+func (c *CDN) Purging() bool {
+	return c.purger != nil
+}
+
+// Purge asks the provider to drop the responses tagged with any of the keys, in the background.
+// Failures are logged; the copies then expire on their own.
+func (c *CDN) Purge(keys ...string) {
+	if c.purger == nil || len(keys) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), purgeTimeout)
+		defer cancel()
+
+		if err := c.purger.Purge(ctx, keys); err != nil {
+			log.Printf("Failed to purge %s from the CDN: %v", strings.Join(keys, ", "), err)
+		}
+	}()
+}
+
+// Middleware applies the policy of a route group to its successful responses, if one is
+// configured, and writes the surrogate keys handlers added with Tag. Error responses are never
+// cached. vary lists the request headers responses depend on, such as an API key.
+func (c *CDN) Middleware(group string, vary ...string) gin.HandlerFunc {
+	var policy *Policy
+	if p, ok := c.policies[group]; ok {
+		policy = &p
+	}
+
+	return func(ctx *gin.Context) {
+		ctx.Writer = &policyWriter{ResponseWriter: ctx.Writer, ctx: ctx, policy: policy, vary: vary}
+		ctx.Next()
+	}
+}
+
+// Tag adds surrogate keys to the response, so that purging any of them drops it from the CDN.
+// Keys are only written on routes behind Middleware.
+func Tag(ctx *gin.Context, keys ...string) {
+	ctx.Set(keysContextKey, append(ctx.GetStringSlice(keysContextKey), keys...))
+}
+
+// policyWriter sets the caching headers once the handler picks the status, before anything is sent
+type policyWriter struct {
+	gin.ResponseWriter
+	ctx     *gin.Context
+	policy  *Policy
+	vary    []string
+	applied bool
+}
+
+func (w *policyWriter) WriteHeader(code int) {
+	w.apply(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *policyWriter) Write(data []byte) (int, error) {
+	w.apply(w.Status())
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *policyWriter) WriteString(s string) (int, error) {
+	w.apply(w.Status())
+	return w.ResponseWriter.WriteString(s)
+}
+
+// apply sets the headers of the response with the given status, the first time it is called
+func (w *policyWriter) apply(code int) {
+	if w.applied {
+		return
+	}
+	w.applied = true
+
+	header := w.Header()
+	for _, name := range w.vary {
+		header.Add("Vary", name)
+	}
+
+	if code >= 400 {
+		header.Set("Cache-Control", "no-store")
+		header.Del("Surrogate-Control")
+		return
+	}
+
+	if w.policy != nil {
+		header.Set("Cache-Control", w.policy.cacheControl())
+		if surrogate := w.policy.surrogateControl(); surrogate != "" {
+			header.Set("Surrogate-Control", surrogate)
+		}
+	}
+
+	// Fastly reads space separated Surrogate-Key, Cloudflare comma separated Cache-Tag; both strip them
+	if keys := w.ctx.GetStringSlice(keysContextKey); len(keys) > 0 {
+		header.Set("Surrogate-Key", strings.Join(keys, " "))
+		header.Set("Cache-Tag", strings.Join(keys, ","))
+	}
+}
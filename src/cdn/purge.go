@@ -0,0 +1,114 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Purger drops the cached responses tagged with any of the surrogate keys
+type Purger interface {
+	Purge(ctx context.Context, keys []string) error
+}
+
+// FastlyConfig holds the settings for purging a Fastly service
+type FastlyConfig struct {
+	ServiceID string
+	APIToken  string // needs the purge_select scope
+	SoftPurge bool   // mark copies stale instead of removing them, so stale-if-error still applies
+}
+
+// fastlyEndpoint is the Fastly purge by surrogate key API, %s being the service ID
+const fastlyEndpoint = "https://api.fastly.com/service/%s/purge"
+
+// FastlyPurger purges through the Fastly API
+type FastlyPurger struct {
+	config FastlyConfig
+	client *http.Client
+}
+
+// NewFastlyPurger creates a new FastlyPurger
+func NewFastlyPurger(config FastlyConfig) *FastlyPurger {
+	return &FastlyPurger{
+		config: config,
+		client: &http.Client{},
+	}
+}
+
+// Purge purges the keys from the Fastly service
+func (p *FastlyPurger) Purge(ctx context.Context, keys []string) error {
+	endpoint := fmt.Sprintf(fastlyEndpoint, url.PathEscape(p.config.ServiceID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build Fastly purge request: %w", err)
+	}
+	req.Header.Set("Fastly-Key", p.config.APIToken)
+	req.Header.Set("Surrogate-Key", strings.Join(keys, " "))
+	req.Header.Set("Accept", "application/json")
+	if p.config.SoftPurge {
+		req.Header.Set("Fastly-Soft-Purge", "1")
+	}
+
+	return send(p.client, req, "Fastly")
+}
+
+// CloudflareConfig holds the settings for purging a Cloudflare zone
+type CloudflareConfig struct {
+	ZoneID   string
+	APIToken string // needs the Cache Purge permission
+}
+
+// cloudflareEndpoint is the Cloudflare purge cache API, %s being the zone ID
+const cloudflareEndpoint = "https://api.cloudflare.com/client/v4/zones/%s/purge_cache"
+
+// CloudflarePurger purges through the Cloudflare API
+type CloudflarePurger struct {
+	config CloudflareConfig
+	client *http.Client
+}
+
+// NewCloudflarePurger creates a new CloudflarePurger
+func NewCloudflarePurger(config CloudflareConfig) *CloudflarePurger {
+	return &CloudflarePurger{
+		config: config,
+		client: &http.Client{},
+	}
+}
+
+// Purge purges the keys, which Cloudflare calls cache tags, from the zone
+func (p *CloudflarePurger) Purge(ctx context.Context, keys []string) error {
+	body, err := json.Marshal(map[string][]string{"tags": keys})
+	if err != nil {
+		return fmt.Errorf("failed to encode Cloudflare purge payload: %w", err)
+	}
+
+	endpoint := fmt.Sprintf(cloudflareEndpoint, url.PathEscape(p.config.ZoneID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build Cloudflare purge request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.config.APIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	return send(p.client, req, "Cloudflare")
+}
+
+// send performs a purge request, treating any status other than 2xx as a failure
+func send(client *http.Client, req *http.Request, provider string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned status %d: %s", provider, resp.StatusCode, detail)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Package currency handles amounts of money stored in the minor unit of their ISO 4217 currency,
+// and converts them between currencies with exchange rates from a pluggable provider.
+package currency
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// codeRegex matches the shape of ISO 4217 codes
+var codeRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// exponents lists the currencies whose minor unit is not a hundredth of the major one, as of
+// ISO 4217; every other currency has two decimals
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// IsCode reports whether code is shaped like an ISO 4217 code, three upper-case letters
+func IsCode(code string) bool {
+	return codeRegex.MatchString(code)
+}
+
+// Exponent returns the number of decimals of a currency's minor unit
+func Exponent(code string) int {
+	if exponent, ok := exponents[code]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// scale returns the number of minor units in one major unit of a currency
+func scale(code string) float64 {
+	return math.Pow10(Exponent(code))
+}
+
+// ToMinor converts an amount in major units, as entered by users, to minor units, rounding to
+// the nearest one
+func ToMinor(major float64, code string) int64 {
+	return int64(math.Round(major * scale(code)))
+}
+
+// FromMinor converts an amount in minor units to major units
+func FromMinor(minor int64, code string) float64 {
+	return float64(minor) / scale(code)
+}
+
+// Amount is an amount of money in the minor unit of its currency
+type Amount struct {
+	Minor    int64
+	Currency string
+}
+
+// Major returns the amount in major units
+func (a Amount) Major() float64 {
+	return FromMinor(a.Minor, a.Currency)
+}
+
+// String formats the amount with the decimals of its currency, as in "19.99 EUR" or "1500 JPY"
+func (a Amount) String() string {
+	return fmt.Sprintf("%.*f %s", Exponent(a.Currency), a.Major(), a.Currency)
+}
+
+// SQLScale returns an SQL expression giving the number of minor units in one major unit of the
+// currency in column, to compare stored minor units with amounts given in major units
+func SQLScale(column string) string {
+	byExponent := map[int][]string{}
+	for code, exponent := range exponents {
+		byExponent[exponent] = append(byExponent[exponent], "'"+code+"'")
+	}
+
+	var expression strings.Builder
+	expression.WriteString("CASE")
+	for _, exponent := range []int{0, 3, 4} {
+		codes := byExponent[exponent]
+		sort.Strings(codes)
+		fmt.Fprintf(&expression, " WHEN %s IN (%s) THEN %d", column, strings.Join(codes, ", "), int(math.Pow10(exponent)))
+	}
+	expression.WriteString(" ELSE 100 END")
+	return expression.String()
+}
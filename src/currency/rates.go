@@ -0,0 +1,191 @@
+package currency
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownRate is returned when there is no exchange rate for one of the currencies
+	ErrUnknownRate = errors.New("no exchange rate for currency")
+	// ErrRatesUnavailable is returned while the provider has not delivered any rates yet
+	ErrRatesUnavailable = errors.New("exchange rates are unavailable")
+)
+
+// Rates are exchange rates against a base currency: Values holds how many units of each
+// currency one unit of Base buys
+type Rates struct {
+	Base   string
+	Values map[string]float64
+}
+
+// rate returns the units of code one unit of the base buys
+func (r *Rates) rate(code string) (float64, bool) {
+	if code == r.Base {
+		return 1, true
+	}
+	value, ok := r.Values[code]
+	return value, ok && value > 0
+}
+
+// Provider fetches the latest exchange rates
+type Provider interface {
+	Rates(ctx context.Context) (*Rates, error)
+}
+
+// StaticProvider serves fixed exchange rates, for deployments without access to a rates service
+type StaticProvider struct {
+	rates *Rates
+}
+
+// NewStaticProvider creates a StaticProvider with rates against base
+func NewStaticProvider(base string, values map[string]float64) *StaticProvider {
+	return &StaticProvider{rates: &Rates{Base: base, Values: values}}
+}
+
+// Rates returns the configured rates
+func (p *StaticProvider) Rates(ctx context.Context) (*Rates, error) {
+	return p.rates, nil
+}
+
+// ECBURL is the daily euro foreign exchange reference rates published by the European Central Bank
+const ECBURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ECBProvider fetches the daily euro reference rates of the European Central Bank. They need no
+// account and are updated once per working day.
+type ECBProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewECBProvider creates an ECBProvider reading the rates at url, ECBURL when empty
+func NewECBProvider(url string) *ECBProvider {
+	if url == "" {
+		url = ECBURL
+	}
+	return &ECBProvider{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// ecbEnvelope is the part of the ECB rates document holding the rates
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// Rates fetches the latest reference rates, against the euro
+func (p *ECBProvider) Rates(ctx context.Context) (*Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rates returned status %d", resp.StatusCode)
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rates: %w", err)
+	}
+
+	rates := &Rates{Base: "EUR", Values: make(map[string]float64)}
+	for _, rate := range envelope.Cube.Cube.Rates {
+		rates.Values[rate.Currency] = rate.Rate
+	}
+	if len(rates.Values) == 0 {
+		return nil, errors.New("exchange rates document lists no rates")
+	}
+	return rates, nil
+}
+
+// retryAfter is how long a Converter waits before asking a failing provider again
+const retryAfter = time.Minute
+
+// Converter converts amounts between currencies, keeping the rates of its provider for a while.
+// When the provider fails, the last rates keep being used until it answers again.
+type Converter struct {
+	provider Provider
+	ttl      time.Duration
+
+	mu        sync.Mutex
+	rates     *Rates
+	expiresAt time.Time
+}
+
+// NewConverter creates a Converter fetching rates from provider at most once per ttl
+func NewConverter(provider Provider, ttl time.Duration) *Converter {
+	return &Converter{provider: provider, ttl: ttl}
+}
+
+// Convert converts an amount to the currency to, rounding to its minor unit
+func (c *Converter) Convert(ctx context.Context, amount Amount, to string) (Amount, error) {
+	if amount.Currency == to {
+		return amount, nil
+	}
+
+	rates, err := c.load(ctx)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	from, ok := rates.rate(amount.Currency)
+	if !ok {
+		return Amount{}, fmt.Errorf("%w %s", ErrUnknownRate, amount.Currency)
+	}
+	target, ok := rates.rate(to)
+	if !ok {
+		return Amount{}, fmt.Errorf("%w %s", ErrUnknownRate, to)
+	}
+
+	return Amount{Minor: ToMinor(amount.Major()/from*target, to), Currency: to}, nil
+}
+
+// load returns the cached rates, fetching them again once they expired. Concurrent callers
+// wait for a single fetch.
+func (c *Converter) load(ctx context.Context) (*Rates, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(c.expiresAt) {
+		if c.rates == nil {
+			return nil, ErrRatesUnavailable
+		}
+		return c.rates, nil
+	}
+
+	// The rates are shared, so a request giving up must not cancel their fetch
+	rates, err := c.provider.Rates(context.WithoutCancel(ctx))
+	if err != nil {
+		// Give the provider a moment before trying again, serving the last rates meanwhile
+		c.expiresAt = now.Add(retryAfter)
+		if c.rates == nil {
+			log.Printf("Failed to load exchange rates: %v", err)
+			return nil, ErrRatesUnavailable
+		}
+		log.Printf("Failed to refresh exchange rates, using the previous ones: %v", err)
+		return c.rates, nil
+	}
+
+	c.rates = rates
+	c.expiresAt = now.Add(c.ttl)
+	return rates, nil
+}
@@ -0,0 +1,23 @@
+package database
+
+// CreateAPIKeysTable creates the api_keys table of integrations allowed to use the public API.
+// Keys outlive the admin who issued them.
+func CreateAPIKeysTable(tx Execer) error {
+	return execAll(tx, "create api keys table",
+		`CREATE TABLE IF NOT EXISTS api_keys (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			prefix VARCHAR(16) NOT NULL,
+			key_hash VARCHAR(64) UNIQUE NOT NULL,
+			created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			last_used_at TIMESTAMP WITH TIME ZONE,
+			revoked_at TIMESTAMP WITH TIME ZONE
+		)`,
+	)
+}
+
+// DropAPIKeysTable drops the api_keys table
+func DropAPIKeysTable(tx Execer) error {
+	return execAll(tx, "drop api keys table", `DROP TABLE IF EXISTS api_keys`)
+}
@@ -0,0 +1,299 @@
+package database
+
+// CreateRefreshTokensTable creates the refresh_tokens table
+func CreateRefreshTokensTable(tx Execer) error {
+	return execAll(tx, "create refresh_tokens table",
+		`CREATE TABLE IF NOT EXISTS refresh_tokens (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			token_hash CHAR(64) UNIQUE NOT NULL,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			revoked_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id)`,
+	)
+}
+
+// DropRefreshTokensTable drops the refresh_tokens table
+func DropRefreshTokensTable(tx Execer) error {
+	return execAll(tx, "drop refresh_tokens table", `DROP TABLE IF EXISTS refresh_tokens`)
+}
+
+// CreateRevokedTokensTable creates the revoked_tokens table used as the access token denylist
+func CreateRevokedTokensTable(tx Execer) error {
+	return execAll(tx, "create revoked_tokens table",
+		`CREATE TABLE IF NOT EXISTS revoked_tokens (
+			jti VARCHAR(64) PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			revoked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at)`,
+	)
+}
+
+// DropRevokedTokensTable drops the revoked_tokens table
+func DropRevokedTokensTable(tx Execer) error {
+	return execAll(tx, "drop revoked_tokens table", `DROP TABLE IF EXISTS revoked_tokens`)
+}
+
+// CreateVerificationCodesTable creates the verification_codes table and the users.email_verified_at column
+func CreateVerificationCodesTable(tx Execer) error {
+	return execAll(tx, "create verification_codes table",
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE`,
+		`CREATE TABLE IF NOT EXISTS verification_codes (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			email VARCHAR(100) NOT NULL,
+			purpose VARCHAR(20) NOT NULL,
+			code_hash CHAR(64) NOT NULL,
+			attempts INTEGER DEFAULT 0 NOT NULL,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			consumed_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_verification_codes_user_purpose ON verification_codes(user_id, purpose, created_at DESC)`,
+	)
+}
+
+// DropVerificationCodesTable drops the verification_codes table and the users.email_verified_at column
+func DropVerificationCodesTable(tx Execer) error {
+	return execAll(tx, "drop verification_codes table",
+		`DROP TABLE IF EXISTS verification_codes`,
+		`ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at`,
+	)
+}
+
+// CreateLoginAttemptsTable creates the login_attempts table used for account lockout
+func CreateLoginAttemptsTable(tx Execer) error {
+	return execAll(tx, "create login_attempts table",
+		`CREATE TABLE IF NOT EXISTS login_attempts (
+			user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			failures INTEGER DEFAULT 0 NOT NULL,
+			last_failed_at TIMESTAMP WITH TIME ZONE,
+			locked_until TIMESTAMP WITH TIME ZONE
+		)`,
+	)
+}
+
+// DropLoginAttemptsTable drops the login_attempts table
+func DropLoginAttemptsTable(tx Execer) error {
+	return execAll(tx, "drop login_attempts table", `DROP TABLE IF EXISTS login_attempts`)
+}
+
+// AddUserAvatar adds the avatar storage key column to users
+func AddUserAvatar(tx Execer) error {
+	return execAll(tx, "add user avatar column",
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_key VARCHAR(255) DEFAULT '' NOT NULL`,
+	)
+}
+
+// DropUserAvatar removes the avatar storage key column from users
+func DropUserAvatar(tx Execer) error {
+	return execAll(tx, "drop user avatar column",
+		`ALTER TABLE users DROP COLUMN IF EXISTS avatar_key`,
+	)
+}
+
+// AddRefreshTokenSessionStart records when the session a refresh token belongs to began,
+// carried over on rotation so sliding sessions can be capped. Existing tokens start their
+// session at creation; the column stays nullable while older versions still insert without it.
+func AddRefreshTokenSessionStart(tx Execer) error {
+	return execAll(tx, "add refresh token session start column",
+		`ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_started_at TIMESTAMP WITH TIME ZONE`,
+	)
+}
+
+// BackfillRefreshTokenSessionStart starts the session of existing refresh tokens at their creation
+func BackfillRefreshTokenSessionStart(db Execer) error {
+	return backfillInBatches(db, "backfill refresh token session start",
+		`UPDATE refresh_tokens SET session_started_at = created_at
+		WHERE id IN (SELECT id FROM refresh_tokens WHERE session_started_at IS NULL LIMIT $1)`,
+		1000,
+	)
+}
+
+// DropRefreshTokenSessionStart removes the session start column from refresh_tokens
+func DropRefreshTokenSessionStart(tx Execer) error {
+	return execAll(tx, "drop refresh token session start column",
+		`ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_started_at`,
+	)
+}
+
+// CreateSiteSettingsTable creates the single-row site_settings table and its default row
+func CreateSiteSettingsTable(tx Execer) error {
+	return execAll(tx, "create site_settings table",
+		`CREATE TABLE IF NOT EXISTS site_settings (
+			id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+			registration_open BOOLEAN DEFAULT TRUE NOT NULL,
+			allowed_email_domains TEXT[] DEFAULT '{}' NOT NULL,
+			updated_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+			updated_at TIMESTAMP WITH TIME ZONE
+		)`,
+		`INSERT INTO site_settings (id) VALUES (TRUE) ON CONFLICT (id) DO NOTHING`,
+	)
+}
+
+// DropSiteSettingsTable drops the site_settings table
+func DropSiteSettingsTable(tx Execer) error {
+	return execAll(tx, "drop site_settings table", `DROP TABLE IF EXISTS site_settings`)
+}
+
+// AddDeniedEmailDomains adds the email domain denylist to site_settings
+func AddDeniedEmailDomains(tx Execer) error {
+	return execAll(tx, "add denied email domains column",
+		`ALTER TABLE site_settings ADD COLUMN IF NOT EXISTS denied_email_domains TEXT[] DEFAULT '{}' NOT NULL`,
+	)
+}
+
+// DropDeniedEmailDomains removes the email domain denylist from site_settings
+func DropDeniedEmailDomains(tx Execer) error {
+	return execAll(tx, "drop denied email domains column",
+		`ALTER TABLE site_settings DROP COLUMN IF EXISTS denied_email_domains`,
+	)
+}
+
+// CreateIdentitiesTable creates the identities table linking external provider accounts to users
+func CreateIdentitiesTable(tx Execer) error {
+	return execAll(tx, "create identities table",
+		`CREATE TABLE IF NOT EXISTS identities (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			provider VARCHAR(32) NOT NULL,
+			subject VARCHAR(255) NOT NULL,
+			email VARCHAR(100) DEFAULT '' NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (provider, subject)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_identities_user_id ON identities(user_id)`,
+	)
+}
+
+// DropIdentitiesTable drops the identities table
+func DropIdentitiesTable(tx Execer) error {
+	return execAll(tx, "drop identities table", `DROP TABLE IF EXISTS identities`)
+}
+
+// AddUserDeletedAt adds the soft delete timestamp to users. The partial index keeps purges
+// cheap without growing with the live users.
+func AddUserDeletedAt(tx Execer) error {
+	return execAll(tx, "add user deleted_at column",
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE`,
+		`CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL`,
+	)
+}
+
+// DropUserDeletedAt removes the soft delete timestamp from users
+func DropUserDeletedAt(tx Execer) error {
+	return execAll(tx, "drop user deleted_at column",
+		`DROP INDEX IF EXISTS idx_users_deleted_at`,
+		`ALTER TABLE users DROP COLUMN IF EXISTS deleted_at`,
+	)
+}
+
+// AddUserPublicID adds the public identifier exposed instead of the user ID
+func AddUserPublicID(tx Execer) error {
+	return addPublicID(tx, "users")
+}
+
+// BackfillUserPublicID gives existing users a public identifier
+func BackfillUserPublicID(db Execer) error {
+	return backfillPublicID(db, "users")
+}
+
+// DropUserPublicID removes the public identifier from users
+func DropUserPublicID(tx Execer) error {
+	return dropPublicID(tx, "users")
+}
+
+// AddUserNoIndex adds the opt-out of search engine indexing to users
+func AddUserNoIndex(tx Execer) error {
+	return execAll(tx, "add user noindex column",
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS noindex BOOLEAN DEFAULT FALSE NOT NULL`,
+	)
+}
+
+// DropUserNoIndex removes the opt-out of search engine indexing from users
+func DropUserNoIndex(tx Execer) error {
+	return execAll(tx, "drop user noindex column", `ALTER TABLE users DROP COLUMN IF EXISTS noindex`)
+}
+
+// AddUserBirthday adds the birthday to users
+func AddUserBirthday(tx Execer) error {
+	return execAll(tx, "add user birthday column", `ALTER TABLE users ADD COLUMN IF NOT EXISTS birthday DATE`)
+}
+
+// DropUserBirthday removes the birthday from users
+func DropUserBirthday(tx Execer) error {
+	return execAll(tx, "drop user birthday column", `ALTER TABLE users DROP COLUMN IF EXISTS birthday`)
+}
+
+// CreateImportantDatesTable creates the important_dates table of yearly dates users want their
+// friends reminded of
+func CreateImportantDatesTable(tx Execer) error {
+	return execAll(tx, "create important dates table",
+		`CREATE TABLE IF NOT EXISTS important_dates (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			label VARCHAR(100) NOT NULL,
+			month SMALLINT NOT NULL CHECK (month BETWEEN 1 AND 12),
+			day SMALLINT NOT NULL CHECK (day BETWEEN 1 AND 31),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_important_dates_user_id ON important_dates(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_important_dates_month_day ON important_dates(month, day)`,
+	)
+}
+
+// DropImportantDatesTable drops the important_dates table
+func DropImportantDatesTable(tx Execer) error {
+	return execAll(tx, "drop important dates table", `DROP TABLE IF EXISTS important_dates`)
+}
+
+// AddUserAvatarAlt adds the alternative text of avatars, empty for avatars uploaded before it
+// was required
+func AddUserAvatarAlt(tx Execer) error {
+	return execAll(tx, "add user avatar alt column",
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_alt VARCHAR(250) DEFAULT '' NOT NULL`,
+	)
+}
+
+// DropUserAvatarAlt removes the alternative text of avatars
+func DropUserAvatarAlt(tx Execer) error {
+	return execAll(tx, "drop user avatar alt column",
+		`ALTER TABLE users DROP COLUMN IF EXISTS avatar_alt`,
+	)
+}
+
+// AddUserSandbox flags the accounts of the demo sandbox, which are deleted and seeded again on
+// every reset
+func AddUserSandbox(tx Execer) error {
+	return execAll(tx, "add user sandbox column",
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS sandbox BOOLEAN DEFAULT FALSE NOT NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_users_sandbox ON users(id) WHERE sandbox`,
+	)
+}
+
+// DropUserSandbox removes the demo sandbox flag from users
+func DropUserSandbox(tx Execer) error {
+	return execAll(tx, "drop user sandbox column",
+		`DROP INDEX IF EXISTS idx_users_sandbox`,
+		`ALTER TABLE users DROP COLUMN IF EXISTS sandbox`,
+	)
+}
+
+// AddUserSessionsEnd adds the time before which the user's access tokens are no longer accepted,
+// null until the sessions of the user are ended
+func AddUserSessionsEnd(tx Execer) error {
+	return execAll(tx, "add user sessions revoked at column",
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS sessions_revoked_at TIMESTAMP WITH TIME ZONE`,
+	)
+}
+
+// DropUserSessionsEnd removes the end of the user's sessions
+func DropUserSessionsEnd(tx Execer) error {
+	return execAll(tx, "drop user sessions revoked at column",
+		`ALTER TABLE users DROP COLUMN IF EXISTS sessions_revoked_at`,
+	)
+}
@@ -0,0 +1,98 @@
+package database
+
+// CreateGroupTables creates the groups, group_members and group_wishlists tables
+func CreateGroupTables(tx Execer) error {
+	return execAll(tx, "create group tables",
+		`CREATE TABLE IF NOT EXISTS groups (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			kind VARCHAR(20) NOT NULL CHECK (kind IN ('family', 'friends')),
+			owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS group_members (
+			group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'member')),
+			joined_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (group_id, user_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_group_members_user_id ON group_members(user_id)`,
+		`CREATE TABLE IF NOT EXISTS group_wishlists (
+			group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+			wishlist_id INTEGER NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+			shared_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			shared_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (group_id, wishlist_id)
+		)`,
+	)
+}
+
+// DropGroupTables drops the group tables
+func DropGroupTables(tx Execer) error {
+	return execAll(tx, "drop group tables",
+		`DROP TABLE IF EXISTS group_wishlists`,
+		`DROP TABLE IF EXISTS group_members`,
+		`DROP TABLE IF EXISTS groups`,
+	)
+}
+
+// CreateGroupNotesTable creates the group_notes table
+func CreateGroupNotesTable(tx Execer) error {
+	return execAll(tx, "create group_notes table",
+		`CREATE TABLE IF NOT EXISTS group_notes (
+			group_id INTEGER NOT NULL,
+			wishlist_id INTEGER NOT NULL,
+			content TEXT DEFAULT '' NOT NULL,
+			updated_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (group_id, wishlist_id),
+			FOREIGN KEY (group_id, wishlist_id) REFERENCES group_wishlists(group_id, wishlist_id) ON DELETE CASCADE
+		)`,
+	)
+}
+
+// DropGroupNotesTable drops the group_notes table
+func DropGroupNotesTable(tx Execer) error {
+	return execAll(tx, "drop group_notes table", `DROP TABLE IF EXISTS group_notes`)
+}
+
+// CreateGroupPollTables creates the group_polls, group_poll_options and group_poll_votes tables
+func CreateGroupPollTables(tx Execer) error {
+	return execAll(tx, "create group poll tables",
+		`CREATE TABLE IF NOT EXISTS group_polls (
+			id SERIAL PRIMARY KEY,
+			group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+			wishlist_id INTEGER REFERENCES wishlists(id) ON DELETE CASCADE,
+			created_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			question VARCHAR(200) NOT NULL,
+			closes_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_group_polls_group_id ON group_polls(group_id)`,
+		`CREATE TABLE IF NOT EXISTS group_poll_options (
+			id SERIAL PRIMARY KEY,
+			poll_id INTEGER NOT NULL REFERENCES group_polls(id) ON DELETE CASCADE,
+			label VARCHAR(100) NOT NULL,
+			position INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_group_poll_options_poll_id ON group_poll_options(poll_id)`,
+		`CREATE TABLE IF NOT EXISTS group_poll_votes (
+			poll_id INTEGER NOT NULL REFERENCES group_polls(id) ON DELETE CASCADE,
+			option_id INTEGER NOT NULL REFERENCES group_poll_options(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			voted_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (poll_id, user_id)
+		)`,
+	)
+}
+
+// DropGroupPollTables drops the group poll tables
+func DropGroupPollTables(tx Execer) error {
+	return execAll(tx, "drop group poll tables",
+		`DROP TABLE IF EXISTS group_poll_votes`,
+		`DROP TABLE IF EXISTS group_poll_options`,
+		`DROP TABLE IF EXISTS group_polls`,
+	)
+}
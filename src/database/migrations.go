@@ -8,61 +8,110 @@ import (
 	_ "github.com/lib/pq"
 )
 
-// InitializeSchema creates the users table for the application
-func InitializeSchema(db *sql.DB) error {
-	// Create users table
-	usersTable := `
-	CREATE TABLE IF NOT EXISTS users (
-		id SERIAL PRIMARY KEY,
-		username VARCHAR(50) UNIQUE NOT NULL,
-		email VARCHAR(100) UNIQUE NOT NULL,
-		gender VARCHAR(10) DEFAULT 'unknown' NOT NULL,
-		password_hash VARCHAR(255) NOT NULL,
-		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-	)`
-
-	if _, err := db.Exec(usersTable); err != nil {
-		return fmt.Errorf("failed to create users table: %w", err)
-	}
+// Migration versions are global and must be unique across modules.
+// Pick the next free number when adding a migration:
+//
+//	1-9    account (users, tokens, verification, site settings)
+//	10-19  wishlist (wishlists, items, reactions, subscriptions)
+//	20-29  group
+//	30-39  social (follows, notifications, blocks, date reminders)
+//	40-49  search (full-text search vectors)
+//	50-59  account, continued (external identities, soft delete, public IDs, indexing opt-out,
+//	       birthdays and important dates, avatar alt text, demo sandbox)
+//	60-69  api (public API keys)
+//	70-79  wishlist, continued (public IDs, translations, price history, image alt text,
+//	       prices in minor units, contributions, members, events, gift history,
+//	       item categories and tags)
+//	80-89  wishlist, continued (imports)
+//
+// Old and new application versions run against the same schema during a deploy, so keep
+// migrations backwards compatible: add columns as nullable or with a default, backfill large
+// tables with backfillInBatches from the migration's Backfill step, which commits each batch,
+// and drop or rename only once no running version uses the column.
+// Each migration runs under the configured statement_timeout and lock_timeout, and the migrator
+// holds an advisory lock so only one instance applies migrations at a time.
+const (
+	VersionCreateUsers                 = 1
+	VersionCreateRefreshTokens         = 2
+	VersionCreateRevokedTokens         = 3
+	VersionCreateVerificationCodes     = 4
+	VersionCreateLoginAttempts         = 5
+	VersionAddUserAvatar               = 6
+	VersionAddRefreshTokenSessionStart = 7
+	VersionCreateSiteSettings          = 8
+	VersionAddDeniedEmailDomains       = 9
 
-	// Add gender column to existing table if it doesn't exist (for backward compatibility)
-	addGenderColumn := `
-	DO $$
-	BEGIN
-		IF NOT EXISTS (
-			SELECT 1 FROM information_schema.columns
-			WHERE table_name = 'users' AND column_name = 'gender'
-		) THEN
-			ALTER TABLE users ADD COLUMN gender VARCHAR(10) DEFAULT 'unknown' NOT NULL;
-		END IF;
-	END
-	$$;`
-
-	if _, err := db.Exec(addGenderColumn); err != nil {
-		return fmt.Errorf("failed to add gender column: %w", err)
-	}
+	VersionCreateWishlists            = 10
+	VersionCreateWishItems            = 11
+	VersionAddWishItemGiftPreferences = 12
+	VersionCreateReservations         = 13
+	VersionAddWishlistVisibility      = 14
+	VersionAddWishlistShareToken      = 15
+	VersionAddWishItemImage           = 16
+	VersionCreateReactions            = 17
+	VersionCreateSubscriptions        = 18
+	VersionAddWishItemPriceIndex      = 19
 
-	// Add gender constraint after ensuring column exists
-	genderConstraint := `
-	DO $$
-	BEGIN
-		IF NOT EXISTS (
-			SELECT 1 FROM information_schema.table_constraints
-			WHERE table_name = 'users' AND constraint_name = 'check_gender'
-		) THEN
-			ALTER TABLE users ADD CONSTRAINT check_gender
-			CHECK (gender IN ('male', 'female', 'unknown'));
-		END IF;
-	END
-	$$;`
-
-	if _, err := db.Exec(genderConstraint); err != nil {
-		return fmt.Errorf("failed to add gender constraint: %w", err)
-	}
+	VersionCreateGroups     = 20
+	VersionCreateGroupNotes = 21
+	VersionCreateGroupPolls = 22
 
-	log.Println("Users table created successfully with gender field")
-	return nil
+	VersionCreateFollows       = 30
+	VersionCreateNotifications = 31
+	VersionCreateBlocks        = 32
+	VersionCreateDateReminders = 33
+
+	VersionAddSearchVectors = 40
+
+	VersionCreateIdentities     = 50
+	VersionAddUserDeletedAt     = 51
+	VersionAddUserPublicID      = 52
+	VersionAddUserNoIndex       = 53
+	VersionAddUserBirthday      = 54
+	VersionCreateImportantDates = 55
+	VersionAddUserAvatarAlt     = 56
+	VersionAddUserSandbox       = 57
+	VersionAddUserSessionsEnd   = 58
+
+	VersionCreateAPIKeys = 60
+
+	VersionAddWishlistPublicID     = 70
+	VersionAddWishlistTranslations = 71
+	VersionCreatePriceHistory      = 72
+	VersionAddWishItemImageAlt     = 73
+	VersionConvertPricesToMinor    = 74
+	VersionCreateContributions     = 75
+	VersionCreateWishlistMembers   = 76
+	VersionAddWishlistEvents       = 77
+	VersionCreateGiftHistory       = 78
+	VersionAddWishItemTags         = 79
+
+	VersionCreateWishlistImports      = 80
+	VersionAddWishlistImportOneActive = 81
+)
+
+// CreateUsersTable creates the users table with the gender constraint
+func CreateUsersTable(tx Execer) error {
+	return execAll(tx, "create users table",
+		`CREATE TABLE IF NOT EXISTS users (
+			id SERIAL PRIMARY KEY,
+			username VARCHAR(50) UNIQUE NOT NULL,
+			email VARCHAR(100) UNIQUE NOT NULL,
+			gender VARCHAR(10) DEFAULT 'unknown' NOT NULL,
+			password_hash VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		// Databases created before the gender field existed
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS gender VARCHAR(10) DEFAULT 'unknown' NOT NULL`,
+		`ALTER TABLE users DROP CONSTRAINT IF EXISTS check_gender`,
+		`ALTER TABLE users ADD CONSTRAINT check_gender CHECK (gender IN ('male', 'female', 'unknown'))`,
+	)
+}
+
+// DropUsersTable drops the users table
+func DropUsersTable(tx Execer) error {
+	return execAll(tx, "drop users table", `DROP TABLE IF EXISTS users`)
 }
 
 // CheckConnection verifies the database connection is working
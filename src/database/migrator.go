@@ -0,0 +1,474 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// Migration is a versioned schema change.
+// Versions are global across modules and applied in ascending order; each migration runs in its own transaction.
+// Backfill, when set, runs after Up commits and outside any transaction, so each statement it
+// runs commits on its own; the migration is recorded once it finishes. A failed backfill leaves
+// the migration pending, so Up must be safe to run again.
+type Migration struct {
+	Version  int
+	Name     string
+	Up       func(tx Execer) error
+	Backfill func(db Execer) error
+	Down     func(tx Execer) error
+}
+
+// Execer runs SQL statements. Migrations receive their transaction when they are applied
+// and a recorder when the migrator only plans them.
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// PlannedMigration is a migration that would be applied or rolled back, with its SQL
+type PlannedMigration struct {
+	Migration  Migration
+	Direction  string // "up" or "down"
+	Statements []string
+	Backfill   []string // run outside the transaction after Statements
+}
+
+// statementRecorder collects the statements of a migration instead of running them.
+// It reports no affected rows, so backfillInBatches records a single batch of each backfill.
+type statementRecorder struct {
+	statements []string
+}
+
+// Exec records the statement, preceded by a comment with its arguments
+func (r *statementRecorder) Exec(query string, args ...interface{}) (sql.Result, error) {
+	for i, arg := range args {
+		query = fmt.Sprintf("-- $%d = %v\n", i+1, arg) + query
+	}
+	r.statements = append(r.statements, query)
+	return driver.RowsAffected(0), nil
+}
+
+// batchExecer runs each statement of a backfill in its own transaction with the migration
+// timeouts, committing it before the next one starts
+type batchExecer struct {
+	migrator *Migrator
+}
+
+// Exec runs the statement and commits it
+func (b batchExecer) Exec(query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := b.migrator.inTransaction(func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(query, args...)
+		return err
+	})
+	return result, err
+}
+
+// MigrationStatus describes whether a migration has been applied
+type MigrationStatus struct {
+	Migration Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// migrationLockKey identifies the advisory lock that serializes migration runs across instances
+const migrationLockKey = 7391046
+
+// MigrationSettings bounds how long a migration may block the tables it touches
+type MigrationSettings struct {
+	StatementTimeout time.Duration // per statement, 0 leaves the server default
+	LockTimeout      time.Duration // waiting for a table lock, 0 leaves the server default
+}
+
+// Migrator applies and rolls back versioned migrations, tracking them in the schema_migrations table
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	settings   MigrationSettings
+}
+
+// NewMigrator creates a new Migrator.
+// It returns an error if two migrations share a version or a migration has no Up step.
+func NewMigrator(db *sql.DB, migrations []Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migration %s has invalid version %d", m.Name, m.Version)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %d_%s has no Up step", m.Version, m.Name)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", sorted[i-1].Name, m.Name, m.Version)
+		}
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+	}, nil
+}
+
+// UseSettings applies statement and lock timeouts to every migration transaction
+func (m *Migrator) UseSettings(settings MigrationSettings) *Migrator {
+	m.settings = settings
+	return m
+}
+
+// withLock runs fn while holding the migration advisory lock, so instances starting at the same
+// time apply migrations one after another instead of racing on the same versions
+func (m *Migrator) withLock(fn func() error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reserve connection for migration lock: %w", err)
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			log.Printf("Error releasing migration lock connection: %v", closeErr)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			log.Printf("Error releasing migration lock: %v", err)
+		}
+	}()
+
+	return fn()
+}
+
+// ensureTable creates the schema_migrations tracking table
+func (m *Migrator) ensureTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	if _, err := m.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// applied returns the applied versions with their timestamps
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations row: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// Status lists every known migration with whether it has been applied
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Up applies every pending migration in version order
+func (m *Migrator) Up() error {
+	return m.withLock(m.up)
+}
+
+// up applies every pending migration; the caller holds the migration lock
+func (m *Migrator) up() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.apply(migration)
+		if err != nil {
+			return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		log.Printf("Applied migration %d_%s", migration.Version, migration.Name)
+		count++
+	}
+
+	if count == 0 {
+		log.Println("Database schema is up to date")
+	}
+	return nil
+}
+
+// apply runs the Up step of a migration and records it. A migration with a backfill commits Up
+// first, runs the backfill outside the transaction and records the migration afterwards.
+func (m *Migrator) apply(migration Migration) error {
+	recordApplied := func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+			migration.Version, migration.Name, time.Now().UTC(),
+		)
+		return err
+	}
+
+	if migration.Backfill == nil {
+		return m.inTransaction(func(tx *sql.Tx) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return recordApplied(tx)
+		})
+	}
+
+	if err := m.inTransaction(func(tx *sql.Tx) error { return migration.Up(tx) }); err != nil {
+		return err
+	}
+	if err := migration.Backfill(batchExecer{migrator: m}); err != nil {
+		return err
+	}
+	return m.inTransaction(recordApplied)
+}
+
+// Down rolls back the most recently applied migrations, up to the given number of steps
+func (m *Migrator) Down(steps int) error {
+	return m.withLock(func() error { return m.down(steps) })
+}
+
+// down rolls back applied migrations; the caller holds the migration lock
+func (m *Migrator) down(steps int) error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return fmt.Errorf("migration %d_%s cannot be rolled back", migration.Version, migration.Name)
+		}
+
+		err := m.inTransaction(func(tx *sql.Tx) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		log.Printf("Rolled back migration %d_%s", migration.Version, migration.Name)
+		steps--
+	}
+
+	return nil
+}
+
+// applySettings sets the timeouts for the rest of the transaction
+func (m *Migrator) applySettings(tx *sql.Tx) error {
+	if m.settings.StatementTimeout > 0 {
+		if _, err := tx.Exec(fmt.Sprintf(`SET LOCAL statement_timeout = %d`, m.settings.StatementTimeout.Milliseconds())); err != nil {
+			return fmt.Errorf("failed to set statement_timeout: %w", err)
+		}
+	}
+	if m.settings.LockTimeout > 0 {
+		if _, err := tx.Exec(fmt.Sprintf(`SET LOCAL lock_timeout = %d`, m.settings.LockTimeout.Milliseconds())); err != nil {
+			return fmt.Errorf("failed to set lock_timeout: %w", err)
+		}
+	}
+	return nil
+}
+
+// PlanUp returns the SQL of every pending migration without running it
+func (m *Migrator) PlanUp() ([]PlannedMigration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var plan []PlannedMigration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		planned, err := record(migration, "up", migration.Up)
+		if err != nil {
+			return nil, err
+		}
+		if migration.Backfill != nil {
+			backfill, err := record(migration, "up", migration.Backfill)
+			if err != nil {
+				return nil, err
+			}
+			planned.Backfill = backfill.Statements
+		}
+		plan = append(plan, planned)
+	}
+	return plan, nil
+}
+
+// PlanDown returns the SQL that rolling back the given number of steps would run, without running it
+func (m *Migrator) PlanDown(steps int) ([]PlannedMigration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var plan []PlannedMigration
+	for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return nil, fmt.Errorf("migration %d_%s cannot be rolled back", migration.Version, migration.Name)
+		}
+		planned, err := record(migration, "down", migration.Down)
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, planned)
+		steps--
+	}
+	return plan, nil
+}
+
+// record runs a migration step against a recorder and returns its statements
+func record(migration Migration, direction string, step func(tx Execer) error) (PlannedMigration, error) {
+	recorder := &statementRecorder{}
+	if err := step(recorder); err != nil {
+		return PlannedMigration{}, fmt.Errorf("failed to plan migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return PlannedMigration{Migration: migration, Direction: direction, Statements: recorder.statements}, nil
+}
+
+// inTransaction runs fn inside a transaction with the migration timeouts, committing on success
+func (m *Migrator) inTransaction(fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	err = m.applySettings(tx)
+	if err == nil {
+		err = fn(tx)
+	}
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.Printf("Error rolling back migration transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// execAll runs each statement in order, wrapping the first failure with context
+func execAll(tx Execer, context string, statements ...string) error {
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to %s: %w", context, err)
+		}
+	}
+	return nil
+}
+
+// backfillInBatches runs an UPDATE repeatedly until it touches fewer rows than batchSize.
+// The statement must use $1 as its batch size, e.g.
+//
+//	UPDATE t SET c = 'x' WHERE id IN (SELECT id FROM t WHERE c IS NULL LIMIT $1)
+//
+// Call it from a migration's Backfill step, where each batch commits before the next one, so
+// row locks are held briefly and each statement stays within statement_timeout while old and
+// new application versions run side by side.
+func backfillInBatches(tx Execer, context, statement string, batchSize int) error {
+	for {
+		result, err := tx.Exec(statement, batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to %s: %w", context, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to %s: %w", context, err)
+		}
+		if affected < int64(batchSize) {
+			return nil
+		}
+	}
+}
+
+// addPublicID adds a public_id column to table holding a random UUID, for use in URLs and
+// responses where sequential IDs would let clients enumerate rows. The default is set before
+// backfillPublicID runs so rows inserted meanwhile get one too.
+func addPublicID(tx Execer, table string) error {
+	return execAll(tx, "add "+table+" public_id column",
+		`ALTER TABLE `+table+` ADD COLUMN IF NOT EXISTS public_id UUID`,
+		`ALTER TABLE `+table+` ALTER COLUMN public_id SET DEFAULT gen_random_uuid()`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_`+table+`_public_id ON `+table+`(public_id)`,
+	)
+}
+
+// backfillPublicID gives the existing rows of table the public_id added by addPublicID
+func backfillPublicID(db Execer, table string) error {
+	return backfillInBatches(db, "backfill "+table+" public_id",
+		`UPDATE `+table+` SET public_id = gen_random_uuid()
+		WHERE id IN (SELECT id FROM `+table+` WHERE public_id IS NULL LIMIT $1)`,
+		1000,
+	)
+}
+
+// dropPublicID removes the public_id column added by addPublicID
+func dropPublicID(tx Execer, table string) error {
+	return execAll(tx, "drop "+table+" public_id column",
+		`DROP INDEX IF EXISTS idx_`+table+`_public_id`,
+		`ALTER TABLE `+table+` DROP COLUMN IF EXISTS public_id`,
+	)
+}
@@ -0,0 +1,59 @@
+package database
+
+// searchVectorTables lists the tables indexed for full-text search and the text column feeding each
+var searchVectorTables = []struct {
+	table  string
+	column string
+}{
+	{"users", "username"},
+	{"wishlists", "title"},
+	{"wish_items", "title"},
+}
+
+// AddSearchVectors adds a search_vector tsvector column with a GIN index to each searched table.
+// A trigger keeps the column current, so older application versions that do not know about it
+// still write searchable rows. The 'simple' configuration is used because titles come in any
+// language and usernames should not be stemmed.
+func AddSearchVectors(tx Execer) error {
+	for _, t := range searchVectorTables {
+		if err := execAll(tx, "add "+t.table+" search vector",
+			`ALTER TABLE `+t.table+` ADD COLUMN IF NOT EXISTS search_vector TSVECTOR`,
+			`DROP TRIGGER IF EXISTS trg_`+t.table+`_search_vector ON `+t.table,
+			`CREATE TRIGGER trg_`+t.table+`_search_vector
+				BEFORE INSERT OR UPDATE OF `+t.column+` ON `+t.table+`
+				FOR EACH ROW EXECUTE FUNCTION tsvector_update_trigger(search_vector, 'pg_catalog.simple', `+t.column+`)`,
+			`CREATE INDEX IF NOT EXISTS idx_`+t.table+`_search_vector ON `+t.table+` USING GIN (search_vector)`,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillSearchVectors fills the search vectors of rows written before the triggers existed
+func BackfillSearchVectors(db Execer) error {
+	for _, t := range searchVectorTables {
+		if err := backfillInBatches(db, "backfill "+t.table+" search vector",
+			`UPDATE `+t.table+` SET search_vector = to_tsvector('simple', `+t.column+`)
+			WHERE id IN (SELECT id FROM `+t.table+` WHERE search_vector IS NULL LIMIT $1)`,
+			1000,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropSearchVectors removes the search columns, their triggers and indexes
+func DropSearchVectors(tx Execer) error {
+	for _, t := range searchVectorTables {
+		if err := execAll(tx, "drop "+t.table+" search vector",
+			`DROP TRIGGER IF EXISTS trg_`+t.table+`_search_vector ON `+t.table,
+			`DROP INDEX IF EXISTS idx_`+t.table+`_search_vector`,
+			`ALTER TABLE `+t.table+` DROP COLUMN IF EXISTS search_vector`,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
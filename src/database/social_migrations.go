@@ -0,0 +1,82 @@
+package database
+
+// CreateFollowsTable creates the follows table
+func CreateFollowsTable(tx Execer) error {
+	return execAll(tx, "create follows table",
+		`CREATE TABLE IF NOT EXISTS follows (
+			follower_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			followee_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (follower_id, followee_id),
+			CHECK (follower_id <> followee_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_follows_followee_id ON follows(followee_id)`,
+	)
+}
+
+// DropFollowsTable drops the follows table
+func DropFollowsTable(tx Execer) error {
+	return execAll(tx, "drop follows table", `DROP TABLE IF EXISTS follows`)
+}
+
+// CreateNotificationsTable creates the notifications table
+func CreateNotificationsTable(tx Execer) error {
+	return execAll(tx, "create notifications table",
+		`CREATE TABLE IF NOT EXISTS notifications (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			type VARCHAR(32) NOT NULL,
+			actor_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			wishlist_id INTEGER REFERENCES wishlists(id) ON DELETE CASCADE,
+			message TEXT NOT NULL,
+			read_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_user_id_created_at ON notifications(user_id, created_at DESC)`,
+	)
+}
+
+// DropNotificationsTable drops the notifications table
+func DropNotificationsTable(tx Execer) error {
+	return execAll(tx, "drop notifications table", `DROP TABLE IF EXISTS notifications`)
+}
+
+// CreateBlocksTable creates the blocks table holding blocked and muted users
+func CreateBlocksTable(tx Execer) error {
+	return execAll(tx, "create blocks table",
+		`CREATE TABLE IF NOT EXISTS blocks (
+			blocker_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			blocked_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			kind VARCHAR(16) NOT NULL CHECK (kind IN ('block', 'mute')),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (blocker_id, blocked_id),
+			CHECK (blocker_id <> blocked_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_blocks_blocked_id ON blocks(blocked_id)`,
+	)
+}
+
+// DropBlocksTable drops the blocks table
+func DropBlocksTable(tx Execer) error {
+	return execAll(tx, "drop blocks table", `DROP TABLE IF EXISTS blocks`)
+}
+
+// CreateDateRemindersTable creates the date_reminders table recording the birthday and important
+// date reminders already sent, so each occasion is announced once even with several instances
+func CreateDateRemindersTable(tx Execer) error {
+	return execAll(tx, "create date reminders table",
+		`CREATE TABLE IF NOT EXISTS date_reminders (
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			occasion VARCHAR(50) NOT NULL,
+			occurs_on DATE NOT NULL,
+			sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, occasion, occurs_on)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_date_reminders_occurs_on ON date_reminders(occurs_on)`,
+	)
+}
+
+// DropDateRemindersTable drops the date_reminders table
+func DropDateRemindersTable(tx Execer) error {
+	return execAll(tx, "drop date reminders table", `DROP TABLE IF EXISTS date_reminders`)
+}
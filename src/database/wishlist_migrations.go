@@ -0,0 +1,523 @@
+package database
+
+import "github.com/alex-1900/wishlist/src/currency"
+
+// CreateWishlistsTable creates the wishlists table
+func CreateWishlistsTable(tx Execer) error {
+	return execAll(tx, "create wishlists table",
+		`CREATE TABLE IF NOT EXISTS wishlists (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			title VARCHAR(100) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_wishlists_user_id ON wishlists(user_id)`,
+	)
+}
+
+// DropWishlistsTable drops the wishlists table
+func DropWishlistsTable(tx Execer) error {
+	return execAll(tx, "drop wishlists table", `DROP TABLE IF EXISTS wishlists`)
+}
+
+// CreateWishItemsTable creates the wish_items table
+func CreateWishItemsTable(tx Execer) error {
+	return execAll(tx, "create wish_items table",
+		`CREATE TABLE IF NOT EXISTS wish_items (
+			id SERIAL PRIMARY KEY,
+			wishlist_id INTEGER NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+			title VARCHAR(200) NOT NULL,
+			description TEXT DEFAULT '' NOT NULL,
+			url VARCHAR(2048) DEFAULT '' NOT NULL,
+			price NUMERIC(12, 2) DEFAULT 0 NOT NULL CHECK (price >= 0),
+			currency CHAR(3) DEFAULT 'USD' NOT NULL,
+			priority SMALLINT DEFAULT 3 NOT NULL CHECK (priority BETWEEN 1 AND 5),
+			quantity INTEGER DEFAULT 1 NOT NULL CHECK (quantity >= 1),
+			position INTEGER DEFAULT 0 NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_wish_items_wishlist_position ON wish_items(wishlist_id, position)`,
+	)
+}
+
+// DropWishItemsTable drops the wish_items table
+func DropWishItemsTable(tx Execer) error {
+	return execAll(tx, "drop wish_items table", `DROP TABLE IF EXISTS wish_items`)
+}
+
+// AddWishItemGiftPreferences adds the giver-facing preference columns to wish_items
+func AddWishItemGiftPreferences(tx Execer) error {
+	return execAll(tx, "add wish item gift preference columns",
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS preferred_variant VARCHAR(200) DEFAULT '' NOT NULL`,
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS ship_to VARCHAR(10) DEFAULT '' NOT NULL`,
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS gift_wrap BOOLEAN DEFAULT FALSE NOT NULL`,
+	)
+}
+
+// DropWishItemGiftPreferences removes the giver-facing preference columns from wish_items
+func DropWishItemGiftPreferences(tx Execer) error {
+	return execAll(tx, "drop wish item gift preference columns",
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS preferred_variant`,
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS ship_to`,
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS gift_wrap`,
+	)
+}
+
+// CreateReservationsTable creates the reservations table
+func CreateReservationsTable(tx Execer) error {
+	return execAll(tx, "create reservations table",
+		`CREATE TABLE IF NOT EXISTS reservations (
+			id SERIAL PRIMARY KEY,
+			item_id INTEGER NOT NULL REFERENCES wish_items(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			quantity INTEGER DEFAULT 1 NOT NULL CHECK (quantity >= 1),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (item_id, user_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_reservations_user_id ON reservations(user_id)`,
+	)
+}
+
+// DropReservationsTable drops the reservations table
+func DropReservationsTable(tx Execer) error {
+	return execAll(tx, "drop reservations table", `DROP TABLE IF EXISTS reservations`)
+}
+
+// AddWishlistVisibility adds the visibility column to wishlists; existing wishlists stay private
+func AddWishlistVisibility(tx Execer) error {
+	return execAll(tx, "add wishlist visibility column",
+		`ALTER TABLE wishlists ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) DEFAULT 'private' NOT NULL
+			CHECK (visibility IN ('private', 'friends', 'link-only', 'public'))`,
+	)
+}
+
+// DropWishlistVisibility removes the visibility column from wishlists
+func DropWishlistVisibility(tx Execer) error {
+	return execAll(tx, "drop wishlist visibility column",
+		`ALTER TABLE wishlists DROP COLUMN IF EXISTS visibility`,
+	)
+}
+
+// AddWishlistShareToken adds the share link token column to wishlists
+func AddWishlistShareToken(tx Execer) error {
+	return execAll(tx, "add wishlist share token column",
+		`ALTER TABLE wishlists ADD COLUMN IF NOT EXISTS share_token VARCHAR(64) UNIQUE`,
+	)
+}
+
+// DropWishlistShareToken removes the share link token column from wishlists
+func DropWishlistShareToken(tx Execer) error {
+	return execAll(tx, "drop wishlist share token column",
+		`ALTER TABLE wishlists DROP COLUMN IF EXISTS share_token`,
+	)
+}
+
+// AddWishItemImage adds the image storage key column to wish_items
+func AddWishItemImage(tx Execer) error {
+	return execAll(tx, "add wish item image column",
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS image_key VARCHAR(255) DEFAULT '' NOT NULL`,
+	)
+}
+
+// DropWishItemImage removes the image storage key column from wish_items
+func DropWishItemImage(tx Execer) error {
+	return execAll(tx, "drop wish item image column",
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS image_key`,
+	)
+}
+
+// CreateReactionsTable creates the reactions table. Reactions point at wishlists or wish items
+// through target_type and target_id, so their rows are removed by the application when the
+// target is deleted rather than by a foreign key.
+func CreateReactionsTable(tx Execer) error {
+	return execAll(tx, "create reactions table",
+		`CREATE TABLE IF NOT EXISTS reactions (
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			target_type VARCHAR(16) NOT NULL CHECK (target_type IN ('wishlist', 'item')),
+			target_id INTEGER NOT NULL,
+			kind VARCHAR(16) NOT NULL CHECK (kind IN ('like', 'heart', 'want')),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, target_type, target_id, kind)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_reactions_target ON reactions(target_type, target_id)`,
+	)
+}
+
+// DropReactionsTable drops the reactions table
+func DropReactionsTable(tx Execer) error {
+	return execAll(tx, "drop reactions table", `DROP TABLE IF EXISTS reactions`)
+}
+
+// CreateWishlistSubscriptionsTable creates the wishlist_subscriptions table of users watching
+// wishlists of someone else
+func CreateWishlistSubscriptionsTable(tx Execer) error {
+	return execAll(tx, "create wishlist subscriptions table",
+		`CREATE TABLE IF NOT EXISTS wishlist_subscriptions (
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			wishlist_id INTEGER NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, wishlist_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_wishlist_subscriptions_wishlist_id ON wishlist_subscriptions(wishlist_id)`,
+	)
+}
+
+// DropWishlistSubscriptionsTable drops the wishlist_subscriptions table
+func DropWishlistSubscriptionsTable(tx Execer) error {
+	return execAll(tx, "drop wishlist subscriptions table", `DROP TABLE IF EXISTS wishlist_subscriptions`)
+}
+
+// AddWishItemPriceIndex indexes wish items by price within a wishlist for the price filters
+func AddWishItemPriceIndex(tx Execer) error {
+	return execAll(tx, "add wish item price index",
+		`CREATE INDEX IF NOT EXISTS idx_wish_items_wishlist_price ON wish_items(wishlist_id, price)`,
+	)
+}
+
+// DropWishItemPriceIndex removes the wish item price index
+func DropWishItemPriceIndex(tx Execer) error {
+	return execAll(tx, "drop wish item price index", `DROP INDEX IF EXISTS idx_wish_items_wishlist_price`)
+}
+
+// AddWishlistPublicID adds the public identifier exposed instead of the wishlist ID
+func AddWishlistPublicID(tx Execer) error {
+	return addPublicID(tx, "wishlists")
+}
+
+// BackfillWishlistPublicID gives existing wishlists a public identifier
+func BackfillWishlistPublicID(db Execer) error {
+	return backfillPublicID(db, "wishlists")
+}
+
+// DropWishlistPublicID removes the public identifier from wishlists
+func DropWishlistPublicID(tx Execer) error {
+	return dropPublicID(tx, "wishlists")
+}
+
+// AddTranslations adds the language of wishlists and the JSONB translations of wishlist titles
+// and wish item titles and descriptions
+func AddTranslations(tx Execer) error {
+	return execAll(tx, "add translation columns",
+		`ALTER TABLE wishlists ADD COLUMN IF NOT EXISTS language VARCHAR(35) DEFAULT '' NOT NULL`,
+		`ALTER TABLE wishlists ADD COLUMN IF NOT EXISTS translations JSONB DEFAULT '{}' NOT NULL`,
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS translations JSONB DEFAULT '{}' NOT NULL`,
+	)
+}
+
+// DropTranslations removes the language and translations of wishlists and wish items
+func DropTranslations(tx Execer) error {
+	return execAll(tx, "drop translation columns",
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS translations`,
+		`ALTER TABLE wishlists DROP COLUMN IF EXISTS translations`,
+		`ALTER TABLE wishlists DROP COLUMN IF EXISTS language`,
+	)
+}
+
+// CreatePriceHistoryTable creates the price_history table of prices read from wish item pages,
+// and adds the price under which an item's drop is announced and when its price was last read
+func CreatePriceHistoryTable(tx Execer) error {
+	return execAll(tx, "create price history table",
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS price_alert_below NUMERIC(12, 2) CHECK (price_alert_below > 0)`,
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS price_checked_at TIMESTAMP WITH TIME ZONE`,
+		`CREATE INDEX IF NOT EXISTS idx_wish_items_price_checked_at ON wish_items(price_checked_at NULLS FIRST) WHERE url <> ''`,
+		`CREATE TABLE IF NOT EXISTS price_history (
+			id SERIAL PRIMARY KEY,
+			item_id INTEGER NOT NULL REFERENCES wish_items(id) ON DELETE CASCADE,
+			price NUMERIC(12, 2) NOT NULL CHECK (price >= 0),
+			currency VARCHAR(3) NOT NULL,
+			checked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_price_history_item_checked_at ON price_history(item_id, checked_at)`,
+	)
+}
+
+// DropPriceHistoryTable drops the price_history table and the price tracking columns of wish items
+func DropPriceHistoryTable(tx Execer) error {
+	return execAll(tx, "drop price history table",
+		`DROP TABLE IF EXISTS price_history`,
+		`DROP INDEX IF EXISTS idx_wish_items_price_checked_at`,
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS price_checked_at`,
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS price_alert_below`,
+	)
+}
+
+// AddWishItemImageAlt adds the alternative text of wish item images, empty for images uploaded
+// before it was required
+func AddWishItemImageAlt(tx Execer) error {
+	return execAll(tx, "add wish item image alt column",
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS image_alt VARCHAR(250) DEFAULT '' NOT NULL`,
+	)
+}
+
+// DropWishItemImageAlt removes the alternative text of wish item images
+func DropWishItemImageAlt(tx Execer) error {
+	return execAll(tx, "drop wish item image alt column",
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS image_alt`,
+	)
+}
+
+// ConvertPricesToMinor adds the prices of wish items, their price alerts and the price history
+// as whole numbers of the minor unit of their currency, e.g. cents, next to the decimal columns.
+// Old application versions keep reading and writing the decimals, so both are written for a
+// release and a later migration drops the decimals. Until then triggers recompute the minor units
+// whenever a writer leaves them out of step with the decimals, as older versions do.
+func ConvertPricesToMinor(tx Execer) error {
+	scale := currency.SQLScale("NEW.currency")
+	return execAll(tx, "add prices in minor units",
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS price_minor BIGINT`,
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS price_alert_below_minor BIGINT`,
+		`ALTER TABLE price_history ADD COLUMN IF NOT EXISTS price_minor BIGINT`,
+		`CREATE INDEX IF NOT EXISTS idx_wish_items_wishlist_price_minor ON wish_items(wishlist_id, price_minor)`,
+		`CREATE OR REPLACE FUNCTION sync_wish_item_minor_prices() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.price IS DISTINCT FROM ROUND(NEW.price_minor::numeric / `+scale+`, 2) THEN
+				NEW.price_minor := ROUND(NEW.price * `+scale+`);
+			END IF;
+			IF NEW.price_alert_below IS DISTINCT FROM ROUND(NEW.price_alert_below_minor::numeric / `+scale+`, 2) THEN
+				NEW.price_alert_below_minor := CASE WHEN NEW.price_alert_below IS NOT NULL
+					THEN GREATEST(ROUND(NEW.price_alert_below * `+scale+`), 1) END;
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS trg_wish_items_minor_prices ON wish_items`,
+		`CREATE TRIGGER trg_wish_items_minor_prices BEFORE INSERT OR UPDATE ON wish_items
+			FOR EACH ROW EXECUTE FUNCTION sync_wish_item_minor_prices()`,
+		`CREATE OR REPLACE FUNCTION sync_price_history_minor_price() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.price IS DISTINCT FROM ROUND(NEW.price_minor::numeric / `+scale+`, 2) THEN
+				NEW.price_minor := ROUND(NEW.price * `+scale+`);
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS trg_price_history_minor_price ON price_history`,
+		`CREATE TRIGGER trg_price_history_minor_price BEFORE INSERT OR UPDATE ON price_history
+			FOR EACH ROW EXECUTE FUNCTION sync_price_history_minor_price()`,
+	)
+}
+
+// BackfillPricesToMinor converts the prices written before the minor unit columns existed
+func BackfillPricesToMinor(db Execer) error {
+	scale := currency.SQLScale("currency")
+	if err := backfillInBatches(db, "backfill wish item prices in minor units",
+		`UPDATE wish_items SET price_minor = ROUND(price * `+scale+`),
+			price_alert_below_minor = CASE WHEN price_alert_below IS NOT NULL
+				THEN GREATEST(ROUND(price_alert_below * `+scale+`), 1) END
+		WHERE id IN (SELECT id FROM wish_items WHERE price_minor IS NULL LIMIT $1)`,
+		1000,
+	); err != nil {
+		return err
+	}
+
+	return backfillInBatches(db, "backfill price history in minor units",
+		`UPDATE price_history SET price_minor = ROUND(price * `+scale+`)
+		WHERE id IN (SELECT id FROM price_history WHERE price_minor IS NULL LIMIT $1)`,
+		1000,
+	)
+}
+
+// RevertPricesToMinor removes the prices in minor units, leaving the decimal ones
+func RevertPricesToMinor(tx Execer) error {
+	return execAll(tx, "drop prices in minor units",
+		`DROP TRIGGER IF EXISTS trg_price_history_minor_price ON price_history`,
+		`DROP FUNCTION IF EXISTS sync_price_history_minor_price()`,
+		`DROP TRIGGER IF EXISTS trg_wish_items_minor_prices ON wish_items`,
+		`DROP FUNCTION IF EXISTS sync_wish_item_minor_prices()`,
+		`DROP INDEX IF EXISTS idx_wish_items_wishlist_price_minor`,
+		`ALTER TABLE price_history DROP COLUMN IF EXISTS price_minor`,
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS price_alert_below_minor`,
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS price_minor`,
+	)
+}
+
+// CreateContributionsTable creates the contributions table, the amounts givers pledge towards
+// the price of an item they buy together
+func CreateContributionsTable(tx Execer) error {
+	return execAll(tx, "create contributions table",
+		`CREATE TABLE IF NOT EXISTS contributions (
+			id SERIAL PRIMARY KEY,
+			item_id INTEGER NOT NULL REFERENCES wish_items(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			amount_minor BIGINT NOT NULL CHECK (amount_minor > 0),
+			currency VARCHAR(3) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (item_id, user_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_contributions_user_id ON contributions(user_id)`,
+	)
+}
+
+// DropContributionsTable drops the contributions table
+func DropContributionsTable(tx Execer) error {
+	return execAll(tx, "drop contributions table", `DROP TABLE IF EXISTS contributions`)
+}
+
+// CreateWishlistMembersTable creates the wishlist_members table, the users sharing the ownership
+// of a wishlist with its creator. Invitations are members not accepted yet.
+func CreateWishlistMembersTable(tx Execer) error {
+	return execAll(tx, "create wishlist members table",
+		`CREATE TABLE IF NOT EXISTS wishlist_members (
+			wishlist_id INTEGER NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'editor')),
+			invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+			invited_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			accepted_at TIMESTAMP WITH TIME ZONE,
+			PRIMARY KEY (wishlist_id, user_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_wishlist_members_user_id ON wishlist_members(user_id)`,
+	)
+}
+
+// DropWishlistMembersTable drops the wishlist_members table
+func DropWishlistMembersTable(tx Execer) error {
+	return execAll(tx, "drop wishlist members table", `DROP TABLE IF EXISTS wishlist_members`)
+}
+
+// AddWishlistEvents adds the event, shipping address and archival date of wishlists, and the
+// quantity of wish items already received; existing wishlists are for no event
+func AddWishlistEvents(tx Execer) error {
+	return execAll(tx, "add wishlist events",
+		`ALTER TABLE wishlists ADD COLUMN IF NOT EXISTS event_type VARCHAR(20) DEFAULT '' NOT NULL
+			CHECK (event_type IN ('', 'birthday', 'wedding', 'baby_registry', 'holiday'))`,
+		`ALTER TABLE wishlists ADD COLUMN IF NOT EXISTS event_date DATE`,
+		`ALTER TABLE wishlists ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE`,
+		`ALTER TABLE wishlists ADD COLUMN IF NOT EXISTS shipping_address JSONB`,
+		`CREATE INDEX IF NOT EXISTS idx_wishlists_event_date ON wishlists(event_date) WHERE archived_at IS NULL`,
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS received_quantity INTEGER DEFAULT 0 NOT NULL
+			CHECK (received_quantity >= 0)`,
+	)
+}
+
+// DropWishlistEvents removes the event, shipping address and archival date of wishlists, and the
+// received quantity of wish items
+func DropWishlistEvents(tx Execer) error {
+	return execAll(tx, "drop wishlist events",
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS received_quantity`,
+		`DROP INDEX IF EXISTS idx_wishlists_event_date`,
+		`ALTER TABLE wishlists DROP COLUMN IF EXISTS shipping_address`,
+		`ALTER TABLE wishlists DROP COLUMN IF EXISTS archived_at`,
+		`ALTER TABLE wishlists DROP COLUMN IF EXISTS event_date`,
+		`ALTER TABLE wishlists DROP COLUMN IF EXISTS event_type`,
+	)
+}
+
+// CreateGiftHistory adds the purchase date of reservations and creates the gift_history table,
+// recording the gifts users gave and received. Titles and prices are copied from the item, so the
+// history outlives it.
+func CreateGiftHistory(tx Execer) error {
+	return execAll(tx, "create gift history",
+		`ALTER TABLE reservations ADD COLUMN IF NOT EXISTS purchased_at TIMESTAMP WITH TIME ZONE`,
+		`CREATE TABLE IF NOT EXISTS gift_history (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			direction VARCHAR(10) NOT NULL CHECK (direction IN ('given', 'received')),
+			recipient_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+			item_id INTEGER REFERENCES wish_items(id) ON DELETE SET NULL,
+			title VARCHAR(200) NOT NULL,
+			quantity INTEGER NOT NULL CHECK (quantity >= 1),
+			price_minor BIGINT DEFAULT 0 NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_gift_history_user_id ON gift_history(user_id, occurred_at)`,
+	)
+}
+
+// DropGiftHistory drops the gift_history table and the purchase date of reservations
+func DropGiftHistory(tx Execer) error {
+	return execAll(tx, "drop gift history",
+		`DROP TABLE IF EXISTS gift_history`,
+		`ALTER TABLE reservations DROP COLUMN IF EXISTS purchased_at`,
+	)
+}
+
+// AddWishItemTags adds the category of wish items and creates the tags table, shared by every
+// user, and the wish_item_tags join table
+func AddWishItemTags(tx Execer) error {
+	return execAll(tx, "add wish item tags",
+		`ALTER TABLE wish_items ADD COLUMN IF NOT EXISTS category VARCHAR(20) DEFAULT '' NOT NULL
+			CHECK (category IN ('', 'books', 'clothing', 'electronics', 'home', 'kitchen', 'garden', 'toys',
+				'games', 'sports', 'outdoors', 'beauty', 'jewelry', 'music', 'art', 'food', 'baby', 'pets',
+				'travel', 'experiences', 'other'))`,
+		`CREATE TABLE IF NOT EXISTS tags (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(30) UNIQUE NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS wish_item_tags (
+			item_id INTEGER NOT NULL REFERENCES wish_items(id) ON DELETE CASCADE,
+			tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			PRIMARY KEY (item_id, tag_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_wish_item_tags_tag_id ON wish_item_tags(tag_id)`,
+	)
+}
+
+// DropWishItemTags drops the tag tables and the category of wish items
+func DropWishItemTags(tx Execer) error {
+	return execAll(tx, "drop wish item tags",
+		`DROP TABLE IF EXISTS wish_item_tags`,
+		`DROP TABLE IF EXISTS tags`,
+		`ALTER TABLE wish_items DROP COLUMN IF EXISTS category`,
+	)
+}
+
+// CreateWishlistImports creates the wishlist_imports table queuing the imports of items into a
+// wishlist, with the uploaded CSV or the Amazon URL to read and the errors of rejected rows
+func CreateWishlistImports(tx Execer) error {
+	return execAll(tx, "create wishlist imports",
+		`CREATE TABLE IF NOT EXISTS wishlist_imports (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			wishlist_id INTEGER NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+			source VARCHAR(10) NOT NULL CHECK (source IN ('csv', 'amazon')),
+			source_url TEXT DEFAULT '' NOT NULL,
+			payload TEXT DEFAULT '' NOT NULL,
+			status VARCHAR(10) DEFAULT 'pending' NOT NULL CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+			total_rows INTEGER DEFAULT 0 NOT NULL,
+			processed_rows INTEGER DEFAULT 0 NOT NULL,
+			imported_rows INTEGER DEFAULT 0 NOT NULL,
+			row_errors JSONB DEFAULT '[]' NOT NULL,
+			error TEXT DEFAULT '' NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			started_at TIMESTAMP WITH TIME ZONE,
+			finished_at TIMESTAMP WITH TIME ZONE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_wishlist_imports_user_id ON wishlist_imports(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_wishlist_imports_queue ON wishlist_imports(created_at)
+			WHERE status IN ('pending', 'running')`,
+	)
+}
+
+// DropWishlistImports drops the wishlist_imports table
+func DropWishlistImports(tx Execer) error {
+	return execAll(tx, "drop wishlist imports",
+		`DROP TABLE IF EXISTS wishlist_imports`,
+	)
+}
+
+// AddWishlistImportOneActive allows one pending or running import per user. Imports queued
+// together before it are failed, all but the first of each user.
+func AddWishlistImportOneActive(tx Execer) error {
+	return execAll(tx, "add wishlist import one active index",
+		`UPDATE wishlist_imports SET status = 'failed', error = 'Another import was started first',
+			finished_at = CURRENT_TIMESTAMP
+		WHERE status IN ('pending', 'running') AND id > (
+			SELECT MIN(first.id) FROM wishlist_imports first
+			WHERE first.user_id = wishlist_imports.user_id AND first.status IN ('pending', 'running')
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlist_imports_one_active ON wishlist_imports(user_id)
+			WHERE status IN ('pending', 'running')`,
+	)
+}
+
+// DropWishlistImportOneActive allows several active imports per user again
+func DropWishlistImportOneActive(tx Execer) error {
+	return execAll(tx, "drop wishlist import one active index",
+		`DROP INDEX IF EXISTS idx_wishlist_imports_one_active`,
+	)
+}
@@ -0,0 +1,81 @@
+// Package demo holds the sandbox of demo deployments: the dataset seeded on every reset and the
+// middleware confining writes to the sandbox accounts.
+package demo
+
+import "github.com/alex-1900/wishlist/src/model"
+
+// Dataset returns the sandbox seeded on every reset. Its accounts follow each other, so friends
+// wishlists and reservations can be tried from any of them.
+func Dataset() *model.DemoDataset {
+	return &model.DemoDataset{
+		Users: []model.DemoUser{
+			{
+				Username: "demo_alice",
+				Email:    "alice@demo.example.com",
+				Wishlists: []model.DemoWishlist{
+					{
+						Title:      "Birthday",
+						Visibility: model.VisibilityFriends,
+						Items: []model.DemoItem{
+							{Title: "Noise-cancelling headphones", Description: "Over-ear, in black", PriceMinor: 24900, Currency: "EUR", Priority: 5, Quantity: 1, ReservedBy: []string{"demo_bob"}},
+							{Title: "Pour-over coffee set", PriceMinor: 4500, Currency: "EUR", Priority: 3, Quantity: 1},
+							{Title: "Hiking socks", Description: "Size 38-40", PriceMinor: 1800, Currency: "EUR", Priority: 2, Quantity: 3, ReservedBy: []string{"demo_carol"}},
+						},
+					},
+					{
+						Title:      "Books to read",
+						Visibility: model.VisibilityPublic,
+						Items: []model.DemoItem{
+							{Title: "The Left Hand of Darkness", PriceMinor: 1299, Currency: "EUR", Priority: 4, Quantity: 1},
+							{Title: "A Pattern Language", PriceMinor: 5500, Currency: "EUR", Priority: 3, Quantity: 1},
+						},
+					},
+				},
+			},
+			{
+				Username: "demo_bob",
+				Email:    "bob@demo.example.com",
+				Wishlists: []model.DemoWishlist{
+					{
+						Title:      "New flat",
+						Visibility: model.VisibilityFriends,
+						Items: []model.DemoItem{
+							{Title: "Cast iron skillet", PriceMinor: 3900, Currency: "USD", Priority: 4, Quantity: 1, ReservedBy: []string{"demo_alice"}},
+							{Title: "Houseplant", Description: "Anything hard to kill", PriceMinor: 2500, Currency: "USD", Priority: 2, Quantity: 2},
+							{Title: "Stand mixer", PriceMinor: 44999, Currency: "USD", Priority: 5, Quantity: 1},
+						},
+					},
+				},
+			},
+			{
+				Username: "demo_carol",
+				Email:    "carol@demo.example.com",
+				Wishlists: []model.DemoWishlist{
+					{
+						Title:      "Holidays",
+						Visibility: model.VisibilityPrivate,
+						Items: []model.DemoItem{
+							{Title: "Board game night", Description: "Something for four players", PriceMinor: 3500, Currency: "GBP", Priority: 3, Quantity: 1},
+							{Title: "Wool scarf", PriceMinor: 6000, Currency: "GBP", Priority: 4, Quantity: 1},
+						},
+					},
+				},
+			},
+		},
+		Follows: [][2]string{
+			{"demo_alice", "demo_bob"}, {"demo_bob", "demo_alice"},
+			{"demo_alice", "demo_carol"}, {"demo_carol", "demo_alice"},
+			{"demo_bob", "demo_carol"}, {"demo_carol", "demo_bob"},
+		},
+	}
+}
+
+// Accounts returns the credentials of the sandbox accounts, published to visitors
+func Accounts(password string) []model.DemoAccount {
+	users := Dataset().Users
+	accounts := make([]model.DemoAccount, 0, len(users))
+	for _, user := range users {
+		accounts = append(accounts, model.DemoAccount{Username: user.Username, Email: user.Email, Password: password})
+	}
+	return accounts
+}
@@ -0,0 +1,76 @@
+package demo
+
+import (
+	"net/http"
+
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/gin-gonic/gin"
+)
+
+// openRoutes may be called by anyone: signing in and out changes no sandbox data
+var openRoutes = map[string]bool{
+	"/user-login":         true,
+	"/refresh-auth-token": true,
+	"/oauth/token":        true,
+	"/user-logout":        true,
+}
+
+// externalSignInRoutes are refused whatever the method, as they create accounts outside the sandbox
+var externalSignInRoutes = map[string]bool{
+	"/oauth/:provider/start":    true,
+	"/oauth/:provider/callback": true,
+	"/oauth/:provider/id-token": true,
+}
+
+// closedRoutes only read for sandbox accounts too: uploads would outlive the reset, and profile
+// changes would lock other visitors out of the shared accounts
+var closedRoutes = map[string]bool{
+	"/user-avatar":                       true,
+	"/wishlists/:id/items/:itemID/image": true,
+	"/update-user-profile":               true,
+	"/user-profile":                      true,
+}
+
+// Guard confines the writes of a demo deployment to the sandbox: reads stay open, and any other
+// request must come from a sandbox account. It must be installed on the engine before the routes.
+func Guard(jwtManager *auth.JWTManager, users model.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if externalSignInRoutes[route] || (closedRoutes[route] && !safeMethod(c.Request.Method)) {
+			c.Error(apperror.Forbidden("This action is disabled in the demo").WithCode(apperror.CodeDemoReadOnly))
+			c.Abort()
+			return
+		}
+
+		if safeMethod(c.Request.Method) || openRoutes[route] {
+			c.Next()
+			return
+		}
+
+		if c.GetHeader("Authorization") == "" {
+			c.Error(apperror.Forbidden("Sign in with a demo account to make changes").WithCode(apperror.CodeDemoReadOnly))
+			c.Abort()
+			return
+		}
+		if !auth.Authenticate(c, jwtManager) {
+			return
+		}
+
+		userID, _ := auth.GetUserID(c)
+		user, err := users.GetByID(userID)
+		if err != nil || !user.Sandbox {
+			c.Error(apperror.Forbidden("Only demo accounts can make changes").WithCode(apperror.CodeDemoReadOnly))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// safeMethod reports whether requests with method only read
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,182 @@
+// Package imaging generates the downscaled variants of uploaded images.
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/storage"
+)
+
+// MaxPixels bounds the width times height of the images Resize decodes. A decoded image takes
+// four bytes per pixel, and a small, highly compressed upload can declare huge dimensions.
+const MaxPixels = 40_000_000
+
+// maxConcurrent is the number of images resized at the same time
+const maxConcurrent = 2
+
+var (
+	// ErrTooManyPixels is returned for images with more than MaxPixels pixels
+	ErrTooManyPixels = errors.New("image has too many pixels")
+	// ErrInvalidImage is returned for images whose header cannot be read
+	ErrInvalidImage = errors.New("image cannot be read")
+)
+
+// Processor writes the size variants of uploaded images in the background
+type Processor struct {
+	storage storage.Storage
+	wg      sync.WaitGroup
+	sem     chan struct{}
+}
+
+// NewProcessor creates a new Processor storing variants next to their originals
+func NewProcessor(store storage.Storage) *Processor {
+	return &Processor{storage: store, sem: make(chan struct{}, maxConcurrent)}
+}
+
+// Process generates and stores every size variant of an image asynchronously, resizing at most
+// maxConcurrent images at a time. Until it finishes, variant URLs of the image may not resolve yet.
+func (p *Processor) Process(key string, img *storage.Image) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		p.sem <- struct{}{}
+		defer func() { <-p.sem }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		for _, size := range storage.ImageSizes {
+			data, err := Resize(img.Data, img.ContentType, size.MaxSide)
+			if err != nil {
+				log.Printf("Failed to resize %s to %s: %v", key, size.Name, err)
+				return
+			}
+			if err := p.storage.Put(ctx, storage.VariantKey(key, size.Name), data, img.ContentType); err != nil {
+				log.Printf("Failed to store %s variant of %s: %v", size.Name, key, err)
+				return
+			}
+		}
+	}()
+}
+
+// Remove deletes an image and its size variants
+func (p *Processor) Remove(ctx context.Context, key string) error {
+	for _, size := range storage.ImageSizes {
+		if err := p.storage.Delete(ctx, storage.VariantKey(key, size.Name)); err != nil {
+			return err
+		}
+	}
+	return p.storage.Delete(ctx, key)
+}
+
+// Wait blocks until every pending variant has been written
+func (p *Processor) Wait() {
+	p.wg.Wait()
+}
+
+// Check reads the dimensions of a JPEG or PNG image from its header, without decoding it, and
+// returns ErrTooManyPixels when it is too large to resize or ErrInvalidImage when the header
+// cannot be read. Other formats are never decoded and always pass.
+func Check(data []byte, contentType string) error {
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return ErrTooManyPixels
+	}
+	return nil
+}
+
+// Resize scales a JPEG or PNG image down so its longest side is at most maxSide pixels,
+// keeping its format. Smaller images, and formats the standard library cannot encode
+// (GIF animations, WebP), are returned unchanged. Images rejected by Check are not decoded.
+func Resize(data []byte, contentType string, maxSide int) ([]byte, error) {
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return data, nil
+	}
+	if err := Check(data, contentType); err != nil {
+		return nil, err
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return data, nil
+	}
+	if width >= height {
+		width, height = maxSide, max(1, height*maxSide/width)
+	} else {
+		width, height = max(1, width*maxSide/height), maxSide
+	}
+
+	dst := downscale(src, width, height)
+
+	var buf bytes.Buffer
+	if contentType == "image/png" {
+		err = png.Encode(&buf, dst)
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// downscale shrinks src to width x height by averaging the source pixels covered by each
+// destination pixel, which avoids the aliasing of nearest-neighbour sampling
+func downscale(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, max((y+1)*srcH/height, y*srcH/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, max((x+1)*srcW/width, x*srcW/width+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(rgba.Pix[offset])
+					g += uint64(rgba.Pix[offset+1])
+					b += uint64(rgba.Pix[offset+2])
+					a += uint64(rgba.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
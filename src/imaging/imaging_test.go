@@ -0,0 +1,171 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// encodeImage returns a width x height image filled with one colour, encoded as contentType
+func encodeImage(t *testing.T, contentType string, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = 200, 100, 50, 255
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// pngHeader returns the signature and header chunk of a PNG declaring the given dimensions,
+// without any pixel data
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	ihdr[12], ihdr[13] = 8, 6 // 8-bit RGBA
+
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&buf, binary.BigEndian, uint32(len(ihdr)-4))
+	buf.Write(ihdr)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(ihdr))
+	return buf.Bytes()
+}
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		maxSide     int
+		wantWidth   int
+		wantHeight  int
+		unchanged   bool
+		wantErr     error
+	}{
+		{
+			name:        "wide PNG is scaled to the max side",
+			data:        encodeImage(t, "image/png", 200, 100),
+			contentType: "image/png",
+			maxSide:     40,
+			wantWidth:   40,
+			wantHeight:  20,
+		},
+		{
+			name:        "tall JPEG is scaled to the max side",
+			data:        encodeImage(t, "image/jpeg", 90, 300),
+			contentType: "image/jpeg",
+			maxSide:     160,
+			wantWidth:   48,
+			wantHeight:  160,
+		},
+		{
+			name:        "image within the max side is returned unchanged",
+			data:        encodeImage(t, "image/png", 30, 20),
+			contentType: "image/png",
+			maxSide:     160,
+			unchanged:   true,
+		},
+		{
+			name:        "formats that cannot be encoded are returned unchanged",
+			data:        []byte("GIF89a not decoded"),
+			contentType: "image/gif",
+			maxSide:     160,
+			unchanged:   true,
+		},
+		{
+			name:        "image with too many pixels is not decoded",
+			data:        pngHeader(10000, 5000),
+			contentType: "image/png",
+			maxSide:     160,
+			wantErr:     ErrTooManyPixels,
+		},
+		{
+			name:        "unreadable header",
+			data:        []byte("\x89PNG\r\n\x1a\ntruncated"),
+			contentType: "image/png",
+			maxSide:     160,
+			wantErr:     ErrInvalidImage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resize(tt.data, tt.contentType, tt.maxSide)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resize() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resize() error = %v", err)
+			}
+
+			if tt.unchanged {
+				if !bytes.Equal(got, tt.data) {
+					t.Fatal("Resize() changed an image it should have returned as is")
+				}
+				return
+			}
+
+			img, format, err := image.Decode(bytes.NewReader(got))
+			if err != nil {
+				t.Fatalf("failed to decode resized image: %v", err)
+			}
+			if want := tt.contentType[len("image/"):]; format != want {
+				t.Errorf("format = %s, want %s", format, want)
+			}
+			if bounds := img.Bounds(); bounds.Dx() != tt.wantWidth || bounds.Dy() != tt.wantHeight {
+				t.Errorf("size = %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), tt.wantWidth, tt.wantHeight)
+			}
+			if tt.contentType == "image/png" {
+				if c := color.RGBAModel.Convert(img.At(0, 0)).(color.RGBA); c != (color.RGBA{200, 100, 50, 255}) {
+					t.Errorf("pixel = %v, want the source colour", c)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		wantErr     error
+	}{
+		{"within the pixel limit", pngHeader(8000, 5000), "image/png", nil},
+		{"over the pixel limit", pngHeader(8000, 5001), "image/png", ErrTooManyPixels},
+		{"one long row over the pixel limit", pngHeader(MaxPixels+1, 1), "image/png", ErrTooManyPixels},
+		{"undecodable JPEG", []byte("\xff\xd8\xff garbage"), "image/jpeg", ErrInvalidImage},
+		{"WebP is not decoded", []byte("RIFF....WEBP"), "image/webp", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Check(tt.data, tt.contentType); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
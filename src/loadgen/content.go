@@ -0,0 +1,87 @@
+package loadgen
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// Words the generated titles are made of. Search is tested against real words, so a prefix
+// query matches a realistic share of the rows.
+var (
+	occasions   = []string{"Birthday", "Christmas", "Wedding", "Housewarming", "Graduation", "Anniversary", "Baby shower", "Holiday"}
+	adjectives  = []string{"Wireless", "Vintage", "Handmade", "Ceramic", "Leather", "Wooden", "Organic", "Compact", "Waterproof", "Cozy", "Classic", "Portable"}
+	products    = []string{"headphones", "teapot", "backpack", "notebook", "lamp", "blanket", "camera", "board game", "novel", "sneakers", "scarf", "speaker", "plant pot", "watch", "puzzle", "chef knife"}
+	descriptors = []string{"", "In any color.", "Size M if possible.", "The one from the shop downtown.", "Second hand is fine.", "Would love the newest model."}
+)
+
+// currencies weights the currencies of generated prices like a mostly American and European
+// user base
+var currencies = []struct {
+	code   string
+	weight int
+}{{"USD", 50}, {"EUR", 30}, {"GBP", 12}, {"JPY", 5}, {"CAD", 3}}
+
+// wishlistTitle returns a title like "Birthday 2025"
+func (g *generator) wishlistTitle() string {
+	occasion := occasions[g.rand.IntN(len(occasions))]
+	if g.rand.IntN(2) == 0 {
+		return occasion + " wishes"
+	}
+	return fmt.Sprintf("%s %d", occasion, g.start.Year()-g.rand.IntN(3))
+}
+
+// visibility returns a visibility, mostly public as on a social site
+func (g *generator) visibility() model.WishlistVisibility {
+	switch n := g.rand.IntN(100); {
+	case n < 60:
+		return model.VisibilityPublic
+	case n < 85:
+		return model.VisibilityFriends
+	default:
+		return model.VisibilityPrivate
+	}
+}
+
+// item returns an item of wishlist at position. A fifth of the items have no price; prices
+// follow a log-normal distribution around 30 in major units.
+func (g *generator) item(wishlist *model.Wishlist, position int) *model.WishItem {
+	item := &model.WishItem{
+		WishlistID:  wishlist.ID,
+		Title:       adjectives[g.rand.IntN(len(adjectives))] + " " + products[g.rand.IntN(len(products))],
+		Description: descriptors[g.rand.IntN(len(descriptors))],
+		Currency:    g.currency(),
+		Priority:    1 + g.rand.IntN(model.WishItemMaxPriority),
+		Quantity:    1,
+		Position:    position,
+		Category:    model.WishItemCategories[g.rand.IntN(len(model.WishItemCategories))],
+		CreatedAt:   wishlist.CreatedAt,
+		UpdatedAt:   wishlist.CreatedAt,
+	}
+	if g.rand.IntN(10) == 0 {
+		item.Quantity = 2 + g.rand.IntN(4)
+	}
+	if g.rand.IntN(5) > 0 {
+		price := math.Exp(math.Log(30) + g.rand.NormFloat64())
+		item.PriceMinor = currency.ToMinor(math.Round(price*100)/100, item.Currency)
+	}
+	return item
+}
+
+// currency draws a currency code by weight
+func (g *generator) currency() string {
+	total := 0
+	for _, c := range currencies {
+		total += c.weight
+	}
+	n := g.rand.IntN(total)
+	for _, c := range currencies {
+		if n < c.weight {
+			return c.code
+		}
+		n -= c.weight
+	}
+	return currencies[0].code
+}
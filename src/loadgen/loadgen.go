@@ -0,0 +1,279 @@
+// Package loadgen generates large datasets for capacity testing pagination, feeds and search:
+// users with skewed numbers of wishlists and items, and a follower graph where a few popular
+// accounts have most of the followers. Rows are written in batches straight to the database.
+package loadgen
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// EmailDomain is the domain of generated accounts, which tells them apart from real ones. It is
+// reserved, so no mail can be delivered to it.
+const EmailDomain = "loadgen.invalid"
+
+// Options sizes a generated dataset. Counts per user and per wishlist are averages; actual
+// counts follow an exponential distribution, so most lists are short and a few are very long.
+type Options struct {
+	Users            int
+	WishlistsPerUser int
+	ItemsPerWishlist int
+	FollowsPerUser   int
+	BatchSize        int   // rows per insert statement
+	Seed             int64 // the same seed generates the same dataset
+}
+
+// DefaultOptions returns the options of a 100k user dataset with about two million items
+func DefaultOptions() Options {
+	return Options{
+		Users:            100000,
+		WishlistsPerUser: 2,
+		ItemsPerWishlist: 10,
+		FollowsPerUser:   20,
+		BatchSize:        1000,
+		Seed:             1,
+	}
+}
+
+// Validate checks the options describe a dataset that can be generated
+func (o Options) Validate() error {
+	if o.Users < 2 {
+		return errors.New("users must be at least 2")
+	}
+	if o.WishlistsPerUser < 0 || o.ItemsPerWishlist < 0 || o.FollowsPerUser < 0 {
+		return errors.New("wishlists, items and follows must not be negative")
+	}
+	// Wish items have the most columns, 11, and Postgres takes at most 65535 parameters
+	if o.BatchSize < 1 || o.BatchSize > 5000 {
+		return errors.New("batch size must be between 1 and 5000")
+	}
+	return nil
+}
+
+// Summary counts the rows a run inserted
+type Summary struct {
+	Users     int
+	Wishlists int
+	Items     int
+	Follows   int
+	Duration  time.Duration
+}
+
+// String formats the summary for the command line
+func (s *Summary) String() string {
+	return fmt.Sprintf("%d users, %d wishlists, %d items and %d follows in %s",
+		s.Users, s.Wishlists, s.Items, s.Follows, s.Duration.Round(time.Second))
+}
+
+// Per-list caps keep the exponential tail from producing absurd lists
+const (
+	maxWishlistsPerUser = 50
+	maxItemsPerWishlist = 1000
+	maxFollowsPerUser   = 5000
+)
+
+// generator holds the state of a run
+type generator struct {
+	repo         model.LoadGenRepository
+	opts         Options
+	rand         *rand.Rand
+	passwordHash string
+	start        time.Time
+	summary      *Summary
+}
+
+// Run generates a dataset with opts into repo. Every account gets passwordHash, so testers can
+// sign in as any of them. Usernames carry the seed, so datasets of different seeds can coexist;
+// delete a dataset with model.LoadGenRepository.DeleteUsers(EmailDomain) before generating
+// the same seed again.
+func Run(repo model.LoadGenRepository, opts Options, passwordHash string) (*Summary, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		repo:         repo,
+		opts:         opts,
+		rand:         rand.New(rand.NewPCG(uint64(opts.Seed), uint64(opts.Seed))),
+		passwordHash: passwordHash,
+		start:        time.Now().UTC(),
+		summary:      &Summary{},
+	}
+
+	userIDs := make([]int, 0, opts.Users)
+	for offset := 0; offset < opts.Users; offset += opts.BatchSize {
+		users, err := g.users(offset, min(opts.BatchSize, opts.Users-offset))
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			userIDs = append(userIDs, user.ID)
+		}
+		if err := g.wishlists(users); err != nil {
+			return nil, err
+		}
+		log.Printf("Generated %d/%d users", len(userIDs), opts.Users)
+	}
+
+	if err := g.follows(userIDs); err != nil {
+		return nil, err
+	}
+
+	g.summary.Duration = time.Since(g.start)
+	return g.summary, nil
+}
+
+// users inserts count users, numbered from offset, who signed up over the last two years
+func (g *generator) users(offset, count int) ([]*model.User, error) {
+	genders := []model.Gender{model.GenderUnknown, model.GenderFemale, model.GenderMale}
+
+	users := make([]*model.User, count)
+	for i := range users {
+		username := fmt.Sprintf("load%d_%d", g.opts.Seed, offset+i)
+		users[i] = &model.User{
+			Username:     username,
+			Email:        username + "@" + EmailDomain,
+			Gender:       genders[g.rand.IntN(len(genders))],
+			PasswordHash: g.passwordHash,
+			CreatedAt:    g.past(2 * 365 * 24 * time.Hour),
+		}
+	}
+
+	if err := g.repo.InsertUsers(users); err != nil {
+		return nil, fmt.Errorf("failed to insert users: %w", err)
+	}
+	g.summary.Users += count
+	return users, nil
+}
+
+// wishlists inserts the wishlists of users and their items
+func (g *generator) wishlists(users []*model.User) error {
+	var wishlists []*model.Wishlist
+	for _, user := range users {
+		for range g.skewed(g.opts.WishlistsPerUser, maxWishlistsPerUser) {
+			createdAt := user.CreatedAt.Add(time.Duration(g.rand.Int64N(int64(g.start.Sub(user.CreatedAt)) + 1)))
+			wishlists = append(wishlists, &model.Wishlist{
+				UserID:     user.ID,
+				Title:      g.wishlistTitle(),
+				Visibility: g.visibility(),
+				CreatedAt:  createdAt,
+				UpdatedAt:  createdAt,
+			})
+		}
+	}
+
+	for _, batch := range chunks(wishlists, g.opts.BatchSize) {
+		if err := g.repo.InsertWishlists(batch); err != nil {
+			return fmt.Errorf("failed to insert wishlists: %w", err)
+		}
+		g.summary.Wishlists += len(batch)
+
+		if err := g.items(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// items inserts the items of wishlists
+func (g *generator) items(wishlists []*model.Wishlist) error {
+	var items []*model.WishItem
+	flush := func() error {
+		if len(items) == 0 {
+			return nil
+		}
+		if err := g.repo.InsertWishItems(items); err != nil {
+			return fmt.Errorf("failed to insert wish items: %w", err)
+		}
+		g.summary.Items += len(items)
+		items = items[:0]
+		return nil
+	}
+
+	for _, wishlist := range wishlists {
+		for position := range g.skewed(g.opts.ItemsPerWishlist, maxItemsPerWishlist) {
+			items = append(items, g.item(wishlist, position))
+			if len(items) == g.opts.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return flush()
+}
+
+// follows inserts the follower graph. Followees are drawn from a Zipf distribution, so the first
+// users generated are the popular accounts followed by many.
+func (g *generator) follows(userIDs []int) error {
+	zipf := rand.NewZipf(g.rand, 1.1, 1, uint64(len(userIDs)-1))
+
+	var follows [][2]int
+	for _, followerID := range userIDs {
+		followed := make(map[int]bool)
+		for range g.skewed(g.opts.FollowsPerUser, min(maxFollowsPerUser, len(userIDs)-1)) {
+			followeeID := userIDs[zipf.Uint64()]
+			if followeeID == followerID || followed[followeeID] {
+				continue
+			}
+			followed[followeeID] = true
+			follows = append(follows, [2]int{followerID, followeeID})
+		}
+
+		if len(follows) >= g.opts.BatchSize {
+			if err := g.insertFollows(follows); err != nil {
+				return err
+			}
+			follows = follows[:0]
+		}
+	}
+	if err := g.insertFollows(follows); err != nil {
+		return err
+	}
+	log.Printf("Generated %d follows", g.summary.Follows)
+	return nil
+}
+
+// insertFollows inserts a batch of follows
+func (g *generator) insertFollows(follows [][2]int) error {
+	if len(follows) == 0 {
+		return nil
+	}
+	if err := g.repo.InsertFollows(follows); err != nil {
+		return fmt.Errorf("failed to insert follows: %w", err)
+	}
+	g.summary.Follows += len(follows)
+	return nil
+}
+
+// skewed draws a count averaging mean from an exponential distribution, capped at limit
+func (g *generator) skewed(mean, limit int) int {
+	if mean == 0 {
+		return 0
+	}
+	return min(int(math.Round(g.rand.ExpFloat64()*float64(mean))), limit)
+}
+
+// past returns a time within the given duration before the start of the run
+func (g *generator) past(within time.Duration) time.Time {
+	return g.start.Add(-time.Duration(g.rand.Int64N(int64(within))))
+}
+
+// chunks splits s into slices of at most size elements
+func chunks[T any](s []T, size int) [][]T {
+	var result [][]T
+	for len(s) > size {
+		result = append(result, s[:size])
+		s = s[size:]
+	}
+	if len(s) > 0 {
+		result = append(result, s)
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+// Package mailer delivers email through pluggable providers.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Message is a single outgoing email
+type Message struct {
+	To       []string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Mailer sends email messages
+type Mailer interface {
+	// Send delivers a fully composed message
+	Send(ctx context.Context, msg Message) error
+	// SendTemplate renders a registered template with data and delivers it
+	SendTemplate(ctx context.Context, to []string, templateName string, data interface{}) error
+}
+
+// Validate checks that a message can be delivered
+func (m Message) Validate() error {
+	if len(m.To) == 0 {
+		return errors.New("message has no recipients")
+	}
+	if strings.TrimSpace(m.Subject) == "" {
+		return errors.New("message has no subject")
+	}
+	if m.TextBody == "" && m.HTMLBody == "" {
+		return errors.New("message has no body")
+	}
+	return nil
+}
+
+// sendTemplate renders a template and delivers it with the given send function.
+// Every Mailer implementation uses it to provide SendTemplate.
+func sendTemplate(ctx context.Context, send func(context.Context, Message) error, to []string, templateName string, data interface{}) error {
+	msg, err := Render(templateName, data)
+	if err != nil {
+		return err
+	}
+	msg.To = to
+	return send(ctx, msg)
+}
+
+// LogMailer writes messages to the application log instead of delivering them.
+// It is the default for local development.
+type LogMailer struct{}
+
+// NewLogMailer creates a new LogMailer
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs the message
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+	log.Printf("Mail to %s: %s\n%s", strings.Join(msg.To, ", "), msg.Subject, msg.TextBody)
+	return nil
+}
+
+// SendTemplate renders and logs a templated message
+func (m *LogMailer) SendTemplate(ctx context.Context, to []string, templateName string, data interface{}) error {
+	return sendTemplate(ctx, m.Send, to, templateName, data)
+}
@@ -0,0 +1,101 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// sendGridEndpoint is the SendGrid v3 mail send API
+const sendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGridMailer delivers messages through the SendGrid HTTP API
+type SendGridMailer struct {
+	apiKey string
+	from   string
+	client *http.Client
+}
+
+// NewSendGridMailer creates a new SendGridMailer
+func NewSendGridMailer(apiKey, from string) *SendGridMailer {
+	return &SendGridMailer{
+		apiKey: apiKey,
+		from:   from,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridPayload struct {
+	Personalizations []struct {
+		To []sendGridAddress `json:"to"`
+	} `json:"personalizations"`
+	From    sendGridAddress   `json:"from"`
+	Subject string            `json:"subject"`
+	Content []sendGridContent `json:"content"`
+}
+
+// Send delivers the message through SendGrid
+func (m *SendGridMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	payload := sendGridPayload{
+		From:    sendGridAddress{Email: m.from},
+		Subject: msg.Subject,
+	}
+	payload.Personalizations = make([]struct {
+		To []sendGridAddress `json:"to"`
+	}, 1)
+	for _, to := range msg.To {
+		payload.Personalizations[0].To = append(payload.Personalizations[0].To, sendGridAddress{Email: to})
+	}
+	if msg.TextBody != "" {
+		payload.Content = append(payload.Content, sendGridContent{Type: "text/plain", Value: msg.TextBody})
+	}
+	if msg.HTMLBody != "" {
+		payload.Content = append(payload.Content, sendGridContent{Type: "text/html", Value: msg.HTMLBody})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode SendGrid payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sendGridEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build SendGrid request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send mail via SendGrid: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("SendGrid returned status %d: %s", resp.StatusCode, detail)
+	}
+	return nil
+}
+
+// SendTemplate renders and delivers a templated message
+func (m *SendGridMailer) SendTemplate(ctx context.Context, to []string, templateName string, data interface{}) error {
+	return sendTemplate(ctx, m.Send, to, templateName, data)
+}
@@ -0,0 +1,109 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/awssig"
+)
+
+// SESConfig holds the settings of the Amazon SES v2 API
+type SESConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// SESMailer delivers messages through the Amazon SES v2 HTTP API
+type SESMailer struct {
+	config SESConfig
+	from   string
+	client *http.Client
+}
+
+// NewSESMailer creates a new SESMailer
+func NewSESMailer(config SESConfig, from string) *SESMailer {
+	return &SESMailer{
+		config: config,
+		from:   from,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesPayload struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				Text *sesContent `json:"Text,omitempty"`
+				HTML *sesContent `json:"Html,omitempty"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+// Send delivers the message through SES
+func (m *SESMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	var payload sesPayload
+	payload.FromEmailAddress = m.from
+	payload.Destination.ToAddresses = msg.To
+	payload.Content.Simple.Subject = sesContent{Data: msg.Subject, Charset: "UTF-8"}
+	if msg.TextBody != "" {
+		payload.Content.Simple.Body.Text = &sesContent{Data: msg.TextBody, Charset: "UTF-8"}
+	}
+	if msg.HTMLBody != "" {
+		payload.Content.Simple.Body.HTML = &sesContent{Data: msg.HTMLBody, Charset: "UTF-8"}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode SES payload: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("https://email.%s.amazonaws.com/v2/email/outbound-emails", m.config.Region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build SES request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	awssig.SignRequest(req, body, awssig.Credentials{
+		AccessKeyID:     m.config.AccessKeyID,
+		SecretAccessKey: m.config.SecretAccessKey,
+	}, m.config.Region, "ses", time.Now())
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send mail via SES: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("SES returned status %d: %s", resp.StatusCode, detail)
+	}
+	return nil
+}
+
+// SendTemplate renders and delivers a templated message
+func (m *SESMailer) SendTemplate(ctx context.Context, to []string, templateName string, data interface{}) error {
+	return sendTemplate(ctx, m.Send, to, templateName, data)
+}
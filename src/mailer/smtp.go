@@ -0,0 +1,104 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig holds the settings of an SMTP relay
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+}
+
+// SMTPMailer delivers messages through an SMTP relay.
+// STARTTLS is used automatically when the server supports it.
+type SMTPMailer struct {
+	config SMTPConfig
+	from   string
+}
+
+// NewSMTPMailer creates a new SMTPMailer
+func NewSMTPMailer(config SMTPConfig, from string) *SMTPMailer {
+	return &SMTPMailer{
+		config: config,
+		from:   from,
+	}
+}
+
+// Send delivers the message through the SMTP relay
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	body, err := buildMIMEMessage(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+	if err := smtp.SendMail(addr, auth, m.from, msg.To, body); err != nil {
+		return fmt.Errorf("failed to send mail via SMTP: %w", err)
+	}
+	return nil
+}
+
+// SendTemplate renders and delivers a templated message
+func (m *SMTPMailer) SendTemplate(ctx context.Context, to []string, templateName string, data interface{}) error {
+	return sendTemplate(ctx, m.Send, to, templateName, data)
+}
+
+// buildMIMEMessage composes an RFC 5322 message with text and optional HTML alternatives
+func buildMIMEMessage(from string, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writeHeader := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	writeHeader("From", from)
+	writeHeader("To", strings.Join(msg.To, ", "))
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("MIME-Version", "1.0")
+
+	if msg.HTMLBody == "" {
+		writeHeader("Content-Type", `text/plain; charset="utf-8"`)
+		buf.WriteString("\r\n")
+		buf.WriteString(msg.TextBody)
+		return buf.Bytes(), nil
+	}
+
+	boundaryBytes := make([]byte, 12)
+	if _, err := rand.Read(boundaryBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate MIME boundary: %w", err)
+	}
+	boundary := hex.EncodeToString(boundaryBytes)
+
+	writeHeader("Content-Type", fmt.Sprintf(`multipart/alternative; boundary="%s"`, boundary))
+	buf.WriteString("\r\n")
+
+	fmt.Fprintf(&buf, "--%s\r\nContent-Type: text/plain; charset=\"utf-8\"\r\n\r\n%s\r\n", boundary, msg.TextBody)
+	fmt.Fprintf(&buf, "--%s\r\nContent-Type: text/html; charset=\"utf-8\"\r\n\r\n%s\r\n", boundary, msg.HTMLBody)
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
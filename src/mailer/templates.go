@@ -0,0 +1,91 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"sync"
+	texttemplate "text/template"
+)
+
+// Template names
+const (
+	TemplateVerificationCode = "verification_code"
+)
+
+// Template is a named email template with text and optional HTML bodies
+type Template struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type compiledTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+var (
+	templates   = map[string]*compiledTemplate{}
+	templatesMu sync.RWMutex
+)
+
+func init() {
+	RegisterTemplate(TemplateVerificationCode, Template{
+		Subject: "Your verification code",
+		Text: "Hello {{.Username}},\n\n" +
+			"Your verification code is {{.Code}}.\n" +
+			"It expires in {{.ExpiresInMinutes}} minutes.\n\n" +
+			"If you did not request this code you can ignore this email.\n",
+		HTML: "<p>Hello {{.Username}},</p>" +
+			"<p>Your verification code is <strong>{{.Code}}</strong>.</p>" +
+			"<p>It expires in {{.ExpiresInMinutes}} minutes.</p>" +
+			"<p>If you did not request this code you can ignore this email.</p>",
+	})
+}
+
+// RegisterTemplate compiles and registers a template under the given name.
+// It panics if the template does not parse, so registration errors surface at startup.
+func RegisterTemplate(name string, tmpl Template) {
+	compiled := &compiledTemplate{
+		subject: texttemplate.Must(texttemplate.New(name + ".subject").Parse(tmpl.Subject)),
+		text:    texttemplate.Must(texttemplate.New(name + ".text").Parse(tmpl.Text)),
+	}
+	if tmpl.HTML != "" {
+		compiled.html = htmltemplate.Must(htmltemplate.New(name + ".html").Parse(tmpl.HTML))
+	}
+
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	templates[name] = compiled
+}
+
+// Render renders a registered template into a message without recipients
+func Render(name string, data interface{}) (Message, error) {
+	templatesMu.RLock()
+	compiled, ok := templates[name]
+	templatesMu.RUnlock()
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := compiled.subject.Execute(&subject, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render subject of %s: %w", name, err)
+	}
+	if err := compiled.text.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render text body of %s: %w", name, err)
+	}
+	if compiled.html != nil {
+		if err := compiled.html.Execute(&html, data); err != nil {
+			return Message{}, fmt.Errorf("failed to render HTML body of %s: %w", name, err)
+		}
+	}
+
+	return Message{
+		Subject:  subject.String(),
+		TextBody: text.String(),
+		HTMLBody: html.String(),
+	}, nil
+}
@@ -1,21 +1,53 @@
 package main
 
 import (
-	"fmt"
+	"log"
+	"os"
 
 	"github.com/alex-1900/wishlist/src/app"
-	"github.com/alex-1900/wishlist/src/module"
+	_ "github.com/alex-1900/wishlist/src/module"
+	"github.com/alex-1900/wishlist/src/pii"
+	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Mask personal data in everything the application and gin log
+	log.SetOutput(pii.NewWriter(os.Stderr))
+	gin.DefaultWriter = pii.NewWriter(os.Stdout)
+	gin.DefaultErrorWriter = pii.NewWriter(os.Stderr)
+
+	// Database migration CLI: `wishlist migrate up|down|status [--dry-run] [--env NAME]`
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.RunMigrationCommand(os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// Capacity test dataset CLI: `wishlist loadgen [--users N] ... [--clean] [--env NAME]`
+	if len(os.Args) > 1 && os.Args[1] == "loadgen" {
+		if err := app.RunLoadGenCommand(os.Args[2:]); err != nil {
+			log.Fatalf("Load generation failed: %v", err)
+		}
+		return
+	}
+
 	// Get app instance from dependency manager
 	app := app.GetInstance()
 
-	// Register routes
-	module.RouteDefinition(app.GinEngine)
+	// Register routes of all registered modules
+	app.RegisterRoutes()
+
+	// Run module start hooks
+	if err := app.Start(); err != nil {
+		app.Close()
+		log.Fatalf("Failed to start application: %v", err)
+	}
 
-	// Start server
-	if app.GinEngine.Run() != nil {
-		fmt.Println("gin error.")
+	// Serve until SIGINT/SIGTERM, then stop modules and close the database pool
+	if err := app.Serve(); err != nil {
+		log.Printf("Server error: %v", err)
 	}
+	app.Stop()
+	app.Close()
 }
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// APIKey grants a third-party integration read access to the public API.
+// Only the hash of the key is stored; the raw key is shown once when it is created.
+type APIKey struct {
+	ID         int        `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	CreatedBy  *int       `json:"-" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// APIKeyRepository defines the interface for API key data operations
+type APIKeyRepository interface {
+	Create(key *APIKey) error
+	Authenticate(keyHash string) (int, bool, error)
+	List(opts ListOptions) ([]*APIKey, *PageInfo, error)
+	Revoke(id int) error
+}
+
+// APIKeyCreateRequest represents the request structure for issuing an API key
+type APIKeyCreateRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// APIKeyRevokeRequest represents the request structure for revoking an API key
+type APIKeyRevokeRequest struct {
+	ID int `json:"id" binding:"required"`
+}
+
+// CreatedAPIKey is the response to issuing an API key, the only one carrying the raw key
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// Validate validates the APIKeyCreateRequest fields
+func (r *APIKeyCreateRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("name validation failed: name is required")
+	}
+	return nil
+}
+
+// BeforeCreate sets the CreatedAt field before creating a new API key
+func (k *APIKey) BeforeCreate() {
+	k.CreatedAt = time.Now().UTC()
+}
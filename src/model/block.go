@@ -0,0 +1,49 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// BlockKind is how strongly a user shuts out another user
+type BlockKind string
+
+// BlockKind constants
+const (
+	// BlockKindBlock hides the user's wishlists from the blocked user and stops all interaction
+	BlockKindBlock BlockKind = "block"
+	// BlockKindMute only keeps the muted user out of the muter's notification feed
+	BlockKindMute BlockKind = "mute"
+)
+
+// BlockedUser represents a user in a list of blocked or muted users
+type BlockedUser struct {
+	UserID    int       `json:"-" db:"user_id"`
+	Username  string    `json:"username" db:"username"`
+	Kind      BlockKind `json:"kind" db:"kind"`
+	BlockedAt time.Time `json:"blocked_at" db:"blocked_at"`
+}
+
+// BlockRepository defines the interface for block and mute data operations.
+// A user holds at most one relationship to another user: blocking a muted user
+// turns the mute into a block.
+type BlockRepository interface {
+	Set(blockerID, blockedID int, kind BlockKind) error
+	Remove(blockerID, blockedID int, kind BlockKind) error
+	Get(blockerID, blockedID int) (BlockKind, error)
+	IsBlockedEither(userID, otherID int) (bool, error)
+	List(userID int, opts ListOptions) ([]*BlockedUser, *PageInfo, error)
+}
+
+// BlockRequest represents the request structure for blocking, muting or undoing either
+type BlockRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// Validate checks kind is a known BlockKind
+func (k BlockKind) Validate() error {
+	if k != BlockKindBlock && k != BlockKindMute {
+		return fmt.Errorf("kind validation failed: must be one of %s, %s", BlockKindBlock, BlockKindMute)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/currency"
+)
+
+// Contribution errors
+var (
+	// ErrItemNotPriced is returned when pledging towards an item without a price to reach
+	ErrItemNotPriced = errors.New("item has no price to contribute towards")
+	// ErrPledgeTooLarge is returned when a pledge would take the contributions past the price
+	ErrPledgeTooLarge = errors.New("pledge exceeds the amount still needed")
+)
+
+// Contribution represents an amount a giver pledges towards an item bought together with other
+// givers, in the currency of the item. Contributions are never shown to the owner of the wishlist.
+type Contribution struct {
+	ID          int       `json:"id" db:"id"`
+	ItemID      int       `json:"item_id" db:"item_id"`
+	UserID      int       `json:"-" db:"user_id"`
+	AmountMinor int64     `json:"amount_minor" db:"amount_minor"`
+	Currency    string    `json:"currency" db:"currency"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ContributionRepository defines the interface for contribution data operations
+type ContributionRepository interface {
+	Pledge(contribution *Contribution) (bool, error)
+	Withdraw(itemID, userID int) error
+	ListByWishlist(wishlistID int) ([]*Contribution, error)
+}
+
+// ContributionRequest represents the request structure for pledging towards a wish item, the
+// amount being in major units of the item's currency. Pledging again replaces the amount.
+type ContributionRequest struct {
+	ItemID int     `json:"item_id" binding:"required"`
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// ContributionWithdrawRequest represents the request structure for withdrawing a pledge
+type ContributionWithdrawRequest struct {
+	ItemID int `json:"item_id" binding:"required"`
+}
+
+// Validate validates the ContributionRequest fields
+func (r *ContributionRequest) Validate() error {
+	if r.Amount <= 0 {
+		return errors.New("amount validation failed: amount must be positive")
+	}
+	return nil
+}
+
+// Amount returns the pledged amount
+func (c *Contribution) Amount() currency.Amount {
+	return currency.Amount{Minor: c.AmountMinor, Currency: c.Currency}
+}
+
+// BeforeCreate sets the CreatedAt and UpdatedAt fields before pledging
+func (c *Contribution) BeforeCreate() {
+	now := time.Now().UTC()
+	c.CreatedAt = now
+	c.UpdatedAt = now
+}
+
+// WishItemContributionState summarises the pledges towards a wish item for a giver. Who pledged
+// is not shown, only how many givers did.
+type WishItemContributionState struct {
+	Target       *Money `json:"target"`
+	Pledged      *Money `json:"pledged"`
+	Mine         *Money `json:"mine,omitempty"`
+	Contributors int    `json:"contributors"`
+	Funded       bool   `json:"funded"`
+}
+
+// ContributionTarget returns the amount the contributions to an item must reach, the price of
+// every unit wished for
+func (i *WishItem) ContributionTarget() currency.Amount {
+	return currency.Amount{Minor: i.PriceMinor * int64(i.Quantity), Currency: i.Currency}
+}
+
+// ContributionState sums the contributions to an item for the giver userID. Pledges made before
+// the item changed currency no longer count. Items without a price have no state.
+func (i *WishItem) ContributionState(contributions []*Contribution, userID int) *WishItemContributionState {
+	target := i.ContributionTarget()
+	if target.Minor == 0 {
+		return nil
+	}
+
+	pledged := currency.Amount{Currency: i.Currency}
+	state := &WishItemContributionState{Target: NewMoney(target)}
+	for _, contribution := range contributions {
+		if contribution.ItemID != i.ID || contribution.Currency != i.Currency {
+			continue
+		}
+		pledged.Minor += contribution.AmountMinor
+		state.Contributors++
+		if contribution.UserID == userID {
+			state.Mine = NewMoney(contribution.Amount())
+		}
+	}
+	state.Pledged = NewMoney(pledged)
+	state.Funded = pledged.Minor >= target.Minor
+	return state
+}
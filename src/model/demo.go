@@ -0,0 +1,66 @@
+package model
+
+// DemoDataset is the sandbox seeded on demo deployments. Seeding it always gives the same
+// accounts, wishlists, items, follows and reservations.
+type DemoDataset struct {
+	Users []DemoUser
+	// Follows lists pairs of usernames, the first following the second
+	Follows [][2]string
+}
+
+// DemoUser is a sandbox account; every one of them signs in with the demo password
+type DemoUser struct {
+	Username  string
+	Email     string
+	Wishlists []DemoWishlist
+}
+
+// DemoWishlist is a wishlist of a sandbox account
+type DemoWishlist struct {
+	Title      string
+	Visibility WishlistVisibility
+	Items      []DemoItem
+}
+
+// DemoItem is an item of a sandbox wishlist, reserved by the sandbox accounts in ReservedBy
+type DemoItem struct {
+	Title       string
+	Description string
+	URL         string
+	PriceMinor  int64
+	Currency    string
+	Priority    int
+	Quantity    int
+	ReservedBy  []string
+}
+
+// DemoRepository defines the interface for resetting the demo sandbox
+type DemoRepository interface {
+	// Reset deletes the sandbox accounts with everything they own and seeds the dataset again,
+	// the accounts taking passwordHash, in a single transaction
+	Reset(dataset *DemoDataset, passwordHash string) error
+}
+
+// SiteConfig is the public configuration clients read before signing in
+type SiteConfig struct {
+	RegistrationOpen bool           `json:"registration_open"`
+	Demo             DemoSiteConfig `json:"demo"`
+
+	// Categories is the taxonomy wish items are filed under
+	Categories []WishItemCategory `json:"categories"`
+}
+
+// DemoSiteConfig tells clients whether the deployment is a demo and how to sign in to it
+type DemoSiteConfig struct {
+	Enabled bool `json:"enabled"`
+	// ResetInterval is how often the sandbox is reset, in minutes
+	ResetInterval int           `json:"reset_interval,omitempty"`
+	Accounts      []DemoAccount `json:"accounts,omitempty"`
+}
+
+// DemoAccount holds the credentials of a sandbox account
+type DemoAccount struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// FollowUser represents a user in a followers or following list
+type FollowUser struct {
+	UserID     int       `json:"-" db:"user_id"`
+	Username   string    `json:"username" db:"username"`
+	FollowedAt time.Time `json:"followed_at" db:"followed_at"`
+}
+
+// FollowCounts holds the number of followers and followed users of a user
+type FollowCounts struct {
+	Followers int `json:"followers"`
+	Following int `json:"following"`
+}
+
+// FollowStatus describes the follow relationship between two users
+type FollowStatus struct {
+	Following  bool `json:"following"`
+	FollowedBy bool `json:"followed_by"`
+	Mutual     bool `json:"mutual"`
+}
+
+// FollowRepository defines the interface for follow data operations
+type FollowRepository interface {
+	Follow(followerID, followeeID int) (bool, error)
+	Unfollow(followerID, followeeID int) error
+	IsFollowing(followerID, followeeID int) (bool, error)
+	ListFollowers(userID int, opts ListOptions) ([]*FollowUser, *PageInfo, error)
+	ListFollowing(userID int, opts ListOptions) ([]*FollowUser, *PageInfo, error)
+	Counts(userID int) (*FollowCounts, error)
+}
+
+// FollowRequest represents the request structure for following or unfollowing a user
+type FollowRequest struct {
+	Username string `json:"username" binding:"required"`
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/currency"
+)
+
+// ErrItemFullyReceived is returned when marking more units received than were wished for
+var ErrItemFullyReceived = errors.New("item is already fully received")
+
+// GiftDirection tells whether a user gave or received a gift
+type GiftDirection string
+
+// GiftDirection constants
+const (
+	GiftGiven    GiftDirection = "given"
+	GiftReceived GiftDirection = "received"
+)
+
+// Gift is an entry of a user's gift history: a reservation the user marked purchased, or units
+// of an item of theirs they marked received. The title and price are those of the item at the
+// time, so the entry outlives it. Received gifts do not name their givers, which owners never see.
+type Gift struct {
+	ID          int           `json:"id" db:"id"`
+	UserID      int           `json:"-" db:"user_id"`
+	Direction   GiftDirection `json:"direction" db:"direction"`
+	RecipientID *int          `json:"-" db:"recipient_id"`
+	ItemID      *int          `json:"item_id" db:"item_id"`
+	Title       string        `json:"title" db:"title"`
+	Quantity    int           `json:"quantity" db:"quantity"`
+	PriceMinor  int64         `json:"price_minor" db:"price_minor"`
+	Currency    string        `json:"currency" db:"currency"`
+	OccurredAt  time.Time     `json:"occurred_at" db:"occurred_at"`
+
+	// RecipientUsername is the username of the recipient of a given gift, while they exist
+	RecipientUsername string `json:"-" db:"-"`
+}
+
+// GiftRepository defines the interface for gift history data operations
+type GiftRepository interface {
+	RecordPurchase(itemID, userID int, purchasedAt time.Time) (*Gift, error)
+	RecordReceipt(itemID, quantity int, receivedAt time.Time) (*Gift, error)
+	ListByUser(userID int, opts ListOptions) ([]*Gift, *PageInfo, error)
+	SummarizeByYear(userID int) ([]*GiftYearSummary, error)
+}
+
+// GiftPurchaseRequest represents the request structure for marking a reservation purchased
+type GiftPurchaseRequest struct {
+	ItemID int `json:"item_id" binding:"required"`
+}
+
+// GiftReceiptRequest represents the request structure for marking units of an item received
+type GiftReceiptRequest struct {
+	ItemID   int `json:"item_id" binding:"required"`
+	Quantity int `json:"quantity" binding:"omitempty,min=1"`
+}
+
+// GiftResponse represents the response structure for an entry of the gift history
+type GiftResponse struct {
+	ID        int           `json:"id"`
+	Direction GiftDirection `json:"direction"`
+	ItemID    *int          `json:"item_id"`
+	Title     string        `json:"title"`
+	Quantity  int           `json:"quantity"`
+	Price     *Money        `json:"price,omitempty"`
+	Recipient string        `json:"recipient,omitempty"`
+
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// GiftYearSummary sums up a year of a user's gift history. Units count the gifts times their
+// quantity, and values are per currency, unpriced gifts left out.
+type GiftYearSummary struct {
+	Year          int      `json:"year"`
+	GivenUnits    int      `json:"given_units"`
+	ReceivedUnits int      `json:"received_units"`
+	GivenValue    []*Money `json:"given_value"`
+	ReceivedValue []*Money `json:"received_value"`
+}
+
+// Validate validates the GiftReceiptRequest fields
+func (r *GiftReceiptRequest) Validate() error {
+	if r.Quantity < 0 {
+		return errors.New("quantity validation failed: quantity must be positive")
+	}
+	return nil
+}
+
+// Amount returns the price of one unit of the gift
+func (g *Gift) Amount() currency.Amount {
+	return currency.Amount{Minor: g.PriceMinor, Currency: g.Currency}
+}
+
+// ToResponse converts a Gift to a GiftResponse
+func (g *Gift) ToResponse() *GiftResponse {
+	response := &GiftResponse{
+		ID:         g.ID,
+		Direction:  g.Direction,
+		ItemID:     g.ItemID,
+		Title:      g.Title,
+		Quantity:   g.Quantity,
+		Recipient:  g.RecipientUsername,
+		OccurredAt: g.OccurredAt,
+	}
+	if g.PriceMinor > 0 {
+		response.Price = NewMoney(g.Amount())
+	}
+	return response
+}
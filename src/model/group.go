@@ -0,0 +1,131 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GroupKind represents the kind of a group
+type GroupKind string
+
+// GroupKind constants
+const (
+	GroupKindFamily  GroupKind = "family"
+	GroupKindFriends GroupKind = "friends"
+)
+
+// IsValid checks if the group kind value is valid
+func (k GroupKind) IsValid() bool {
+	return k == GroupKindFamily || k == GroupKindFriends
+}
+
+// GroupRole represents the role of a member within a group
+type GroupRole string
+
+// GroupRole constants
+const (
+	GroupRoleOwner  GroupRole = "owner"
+	GroupRoleMember GroupRole = "member"
+)
+
+// Group represents a household or circle of friends
+type Group struct {
+	ID        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Kind      GroupKind `json:"kind" db:"kind"`
+	OwnerID   int       `json:"-" db:"owner_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// GroupMember represents a user's membership in a group
+type GroupMember struct {
+	GroupID  int       `json:"group_id" db:"group_id"`
+	UserID   int       `json:"-" db:"user_id"`
+	Username string    `json:"username" db:"username"`
+	Role     GroupRole `json:"role" db:"role"`
+	JoinedAt time.Time `json:"joined_at" db:"joined_at"`
+}
+
+// GroupWishlist represents a wishlist shared to a group
+type GroupWishlist struct {
+	Wishlist      *Wishlist `json:"wishlist"`
+	OwnerUsername string    `json:"owner_username"`
+	SharedAt      time.Time `json:"shared_at"`
+}
+
+// GroupRepository defines the interface for group data operations
+type GroupRepository interface {
+	Create(group *Group) error
+	GetByID(id int) (*Group, error)
+	ListByMember(userID int, opts ListOptions) ([]*Group, *PageInfo, error)
+	Delete(id int) error
+	AddMember(groupID, userID int, role GroupRole) error
+	RemoveMember(groupID, userID int) error
+	IsMember(groupID, userID int) (bool, error)
+	ListMembers(groupID int) ([]*GroupMember, error)
+	ShareWishlist(groupID, wishlistID, sharedBy int) error
+	UnshareWishlist(groupID, wishlistID int) error
+	IsWishlistShared(groupID, wishlistID int) (bool, error)
+	ListWishlists(groupID int) ([]*GroupWishlist, error)
+	SharesWishlistWith(wishlistID, userID int) (bool, error)
+}
+
+// GroupCreateRequest represents the request structure for creating a group
+type GroupCreateRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Kind string `json:"kind" binding:"required,oneof=family friends"`
+}
+
+// GroupDeleteRequest represents the request structure for deleting a group
+type GroupDeleteRequest struct {
+	ID int `json:"id" binding:"required"`
+}
+
+// GroupMemberRequest represents the request structure for adding or removing a group member
+type GroupMemberRequest struct {
+	GroupID  int    `json:"group_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+}
+
+// GroupWishlistRequest represents the request structure for sharing a wishlist to a group
+type GroupWishlistRequest struct {
+	GroupID    int    `json:"group_id" binding:"required"`
+	WishlistID string `json:"wishlist_id" binding:"required"`
+}
+
+// Group validation constants
+const (
+	GroupNameMaxLength = 100
+)
+
+// Validate validates the GroupCreateRequest fields
+func (r *GroupCreateRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name validation failed: name is required")
+	}
+
+	if len(r.Name) > GroupNameMaxLength {
+		return errors.New("name validation failed: name is too long")
+	}
+
+	if !GroupKind(r.Kind).IsValid() {
+		return fmt.Errorf("kind validation failed: kind must be one of: %s, %s", GroupKindFamily, GroupKindFriends)
+	}
+
+	return nil
+}
+
+// IsOwnedBy reports whether the group is owned by the given user
+func (g *Group) IsOwnedBy(userID int) bool {
+	return g.OwnerID == userID
+}
+
+// BeforeCreate sets the CreatedAt and UpdatedAt fields before creating a new group
+func (g *Group) BeforeCreate() {
+	now := time.Now().UTC()
+	g.CreatedAt = now
+	g.UpdatedAt = now
+}
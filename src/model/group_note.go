@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// GroupNote is a shared scratchpad for group members planning gifts from a wishlist.
+// It is never shown to the owner of the wishlist.
+type GroupNote struct {
+	GroupID    int       `json:"group_id" db:"group_id"`
+	WishlistID int       `json:"-" db:"wishlist_id"`
+	Content    string    `json:"content" db:"content"`
+	UpdatedBy  *int      `json:"-" db:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// GroupNoteRepository defines the interface for group note data operations
+type GroupNoteRepository interface {
+	Get(groupID, wishlistID int) (*GroupNote, error)
+	Upsert(note *GroupNote) error
+}
+
+// GroupNoteUpdateRequest represents the request structure for updating a group note
+type GroupNoteUpdateRequest struct {
+	GroupID    int    `json:"group_id" binding:"required"`
+	WishlistID string `json:"wishlist_id" binding:"required"`
+	Content    string `json:"content" binding:"max=10000"`
+}
+
+// Group note validation constants
+const (
+	GroupNoteMaxLength = 10000
+)
+
+// Validate validates the GroupNoteUpdateRequest fields
+func (r *GroupNoteUpdateRequest) Validate() error {
+	if len(r.Content) > GroupNoteMaxLength {
+		return errors.New("content validation failed: content is too long")
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// GroupPoll represents a quick poll among the members of a group,
+// optionally about one of the wishlists shared to the group
+type GroupPoll struct {
+	ID         int                `json:"id" db:"id"`
+	GroupID    int                `json:"group_id" db:"group_id"`
+	WishlistID *int               `json:"-" db:"wishlist_id"`
+	CreatedBy  int                `json:"-" db:"created_by"`
+	Question   string             `json:"question" db:"question"`
+	ClosesAt   *time.Time         `json:"closes_at" db:"closes_at"`
+	CreatedAt  time.Time          `json:"created_at" db:"created_at"`
+	Options    []*GroupPollOption `json:"options"`
+
+	// WishlistPublicID names the wishlist of the poll to clients, empty for polls about none
+	WishlistPublicID string `json:"wishlist_id,omitempty" db:"-"`
+}
+
+// GroupPollOption represents one of the choices of a poll along with its vote count
+type GroupPollOption struct {
+	ID       int    `json:"id" db:"id"`
+	PollID   int    `json:"poll_id" db:"poll_id"`
+	Label    string `json:"label" db:"label"`
+	Position int    `json:"position" db:"position"`
+	Votes    int    `json:"votes" db:"votes"`
+}
+
+// GroupPollRepository defines the interface for group poll data operations
+type GroupPollRepository interface {
+	Create(poll *GroupPoll) error
+	GetByID(id int) (*GroupPoll, error)
+	ListByGroup(groupID int) ([]*GroupPoll, error)
+	Delete(id int) error
+	Vote(pollID, optionID, userID int) error
+	GetVote(pollID, userID int) (*int, error)
+}
+
+// GroupPollCreateRequest represents the request structure for creating a group poll.
+// WishlistID is the public ID of a wishlist shared to the group, empty for polls about none.
+type GroupPollCreateRequest struct {
+	GroupID    int        `json:"group_id" binding:"required"`
+	WishlistID string     `json:"wishlist_id"`
+	Question   string     `json:"question" binding:"required,max=200"`
+	Options    []string   `json:"options" binding:"required"`
+	ClosesAt   *time.Time `json:"closes_at"`
+}
+
+// GroupPollVoteRequest represents the request structure for voting in a group poll
+type GroupPollVoteRequest struct {
+	PollID   int `json:"poll_id" binding:"required"`
+	OptionID int `json:"option_id" binding:"required"`
+}
+
+// GroupPollDeleteRequest represents the request structure for deleting a group poll
+type GroupPollDeleteRequest struct {
+	ID int `json:"id" binding:"required"`
+}
+
+// Group poll validation constants
+const (
+	GroupPollQuestionMaxLength = 200
+	GroupPollOptionMaxLength   = 100
+	GroupPollMinOptions        = 2
+	GroupPollMaxOptions        = 10
+)
+
+// Validate validates the GroupPollCreateRequest fields
+func (r *GroupPollCreateRequest) Validate() error {
+	if strings.TrimSpace(r.Question) == "" {
+		return errors.New("question validation failed: question is required")
+	}
+
+	if len(r.Question) > GroupPollQuestionMaxLength {
+		return errors.New("question validation failed: question is too long")
+	}
+
+	if len(r.Options) < GroupPollMinOptions || len(r.Options) > GroupPollMaxOptions {
+		return errors.New("options validation failed: a poll needs between 2 and 10 options")
+	}
+
+	seen := make(map[string]bool, len(r.Options))
+	for _, option := range r.Options {
+		label := strings.TrimSpace(option)
+		if label == "" {
+			return errors.New("options validation failed: options cannot be empty")
+		}
+		if len(label) > GroupPollOptionMaxLength {
+			return errors.New("options validation failed: option is too long")
+		}
+		if seen[strings.ToLower(label)] {
+			return errors.New("options validation failed: options must be unique")
+		}
+		seen[strings.ToLower(label)] = true
+	}
+
+	if r.ClosesAt != nil && !r.ClosesAt.After(time.Now()) {
+		return errors.New("closes_at validation failed: deadline must be in the future")
+	}
+
+	return nil
+}
+
+// IsClosed reports whether the poll deadline has passed
+func (p *GroupPoll) IsClosed() bool {
+	return p.ClosesAt != nil && !time.Now().Before(*p.ClosesAt)
+}
+
+// HasOption reports whether the option belongs to the poll
+func (p *GroupPoll) HasOption(optionID int) bool {
+	for _, option := range p.Options {
+		if option.ID == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+// BeforeCreate sets the CreatedAt field before creating a new poll
+func (p *GroupPoll) BeforeCreate() {
+	p.CreatedAt = time.Now().UTC()
+}
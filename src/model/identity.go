@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Identity links a user to their account with an external identity provider such as Google
+type Identity struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"-" db:"user_id"`
+	Provider  string    `json:"provider" db:"provider"`
+	Subject   string    `json:"subject" db:"subject"`
+	Email     string    `json:"email" db:"email" pii:"true"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// IdentityRepository defines the interface for external identity data operations
+type IdentityRepository interface {
+	Create(identity *Identity) error
+	// Get returns nil without an error when no user is linked to the provider account
+	Get(provider, subject string) (*Identity, error)
+	ListByUser(userID int) ([]*Identity, error)
+}
+
+// BeforeCreate sets the CreatedAt field before linking a new identity
+func (i *Identity) BeforeCreate() {
+	i.CreatedAt = time.Now().UTC()
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ImportantDate is a yearly date a user wants their friends reminded of, such as a name day
+// or an anniversary. Only the month and day are kept, as the date recurs every year.
+type ImportantDate struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"-" db:"user_id"`
+	Label     string    `json:"label" db:"label"`
+	Month     int       `json:"month" db:"month"`
+	Day       int       `json:"day" db:"day"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ImportantDateRepository defines the interface for important date data operations
+type ImportantDateRepository interface {
+	Create(date *ImportantDate) error
+	ListByUser(userID int) ([]*ImportantDate, error)
+	CountByUser(userID int) (int, error)
+	Delete(userID, id int) (bool, error)
+}
+
+// ImportantDateCreateRequest represents the request structure for adding an important date
+type ImportantDateCreateRequest struct {
+	Label string `json:"label" binding:"required"`
+	Month int    `json:"month" binding:"required"`
+	Day   int    `json:"day" binding:"required"`
+}
+
+// ImportantDateDeleteRequest represents the request structure for removing an important date
+type ImportantDateDeleteRequest struct {
+	ID int `json:"id" binding:"required"`
+}
+
+// Important date validation constants
+const (
+	ImportantDateLabelMaxLength = 100
+	MaxImportantDates           = 20 // per user
+)
+
+// Validate validates the ImportantDateCreateRequest fields
+func (r *ImportantDateCreateRequest) Validate() error {
+	r.Label = strings.TrimSpace(r.Label)
+	if r.Label == "" {
+		return errors.New("label validation failed: label cannot be empty")
+	}
+	if len(r.Label) > ImportantDateLabelMaxLength {
+		return fmt.Errorf("label validation failed: label cannot exceed %d characters", ImportantDateLabelMaxLength)
+	}
+
+	// A leap year accepts every month and day that exist, including February 29
+	if r.Month < 1 || r.Month > 12 || r.Day < 1 || r.Day > time.Date(2000, time.Month(r.Month)+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		return errors.New("date validation failed: month and day do not form a valid date")
+	}
+	return nil
+}
+
+// BeforeCreate sets the CreatedAt field before creating a new important date
+func (d *ImportantDate) BeforeCreate() {
+	d.CreatedAt = time.Now().UTC()
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// List option constants
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+	SortAsc          = "asc"
+	SortDesc         = "desc"
+)
+
+// ListOptions describes how a repository list should be paged, sorted and filtered.
+// The zero value returns every row in the repository's default order.
+type ListOptions struct {
+	Page    int
+	Limit   int
+	Cursor  string
+	Sort    string
+	Order   string
+	Filters map[string]string
+}
+
+// Offset returns the number of rows to skip, preferring the cursor over the page number
+func (o ListOptions) Offset() int {
+	if o.Cursor != "" {
+		if offset, err := DecodeCursor(o.Cursor); err == nil {
+			return offset
+		}
+	}
+	if o.Page > 1 && o.Limit > 0 {
+		return (o.Page - 1) * o.Limit
+	}
+	return 0
+}
+
+// PageInfo describes the page returned by a list call
+type PageInfo struct {
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPageInfo builds the page info for a list call that returned count rows
+func NewPageInfo(opts ListOptions, total, count int) *PageInfo {
+	info := &PageInfo{
+		Total:  total,
+		Limit:  opts.Limit,
+		Offset: opts.Offset(),
+	}
+	if next := info.Offset + count; opts.Limit > 0 && next < total {
+		info.NextCursor = EncodeCursor(next)
+	}
+	return info
+}
+
+// EncodeCursor encodes a row offset as an opaque cursor
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+// DecodeCursor decodes a cursor produced by EncodeCursor
+func DecodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), "o:") {
+		return 0, errors.New("invalid cursor")
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), "o:"))
+	if err != nil || offset < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return offset, nil
+}
+
+// ListRequest represents the query parameters accepted by list endpoints
+type ListRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort"`
+	Order  string `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+// Validate validates the ListRequest fields
+func (r *ListRequest) Validate() error {
+	if r.Limit > MaxListLimit {
+		return errors.New("limit validation failed: limit must not exceed 100")
+	}
+	if r.Cursor != "" {
+		if _, err := DecodeCursor(r.Cursor); err != nil {
+			return errors.New("cursor validation failed: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// ToOptions converts the request to list options, copying the allowed filters from the query
+func (r *ListRequest) ToOptions(query url.Values, filters ...string) ListOptions {
+	opts := ListOptions{
+		Page:   r.Page,
+		Limit:  r.Limit,
+		Cursor: r.Cursor,
+		Sort:   r.Sort,
+		Order:  r.Order,
+	}
+	if opts.Limit == 0 {
+		opts.Limit = DefaultListLimit
+	}
+	for _, name := range filters {
+		if value := strings.TrimSpace(query.Get(name)); value != "" {
+			if opts.Filters == nil {
+				opts.Filters = make(map[string]string)
+			}
+			opts.Filters[name] = value
+		}
+	}
+	return opts
+}
@@ -0,0 +1,11 @@
+package model
+
+// LoadGenRepository writes the generated datasets of capacity tests in large batches. Each call
+// runs in a transaction of its own, without the per-query timeout and retries.
+type LoadGenRepository interface {
+	InsertUsers(users []*User) error
+	InsertWishlists(wishlists []*Wishlist) error
+	InsertWishItems(items []*WishItem) error
+	InsertFollows(follows [][2]int) error
+	DeleteUsers(emailDomain string) (int64, error)
+}
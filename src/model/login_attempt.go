@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// LoginAttempt tracks recent failed logins of an account and any temporary lock
+type LoginAttempt struct {
+	UserID       int        `json:"-" db:"user_id"`
+	Failures     int        `json:"failures" db:"failures"`
+	LastFailedAt *time.Time `json:"last_failed_at" db:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until" db:"locked_until"`
+
+	// Username is only filled in when listing locked accounts
+	Username string `json:"username,omitempty" db:"username"`
+}
+
+// LoginAttemptRepository defines the interface for failed login tracking
+type LoginAttemptRepository interface {
+	Get(userID int) (*LoginAttempt, error)
+	RecordFailure(userID, maxFailures int, window, lockDuration time.Duration) (*LoginAttempt, error)
+	Reset(userID int) error
+	ListLocked(now time.Time) ([]*LoginAttempt, error)
+}
+
+// IsLocked reports whether the account is locked at the given time
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// RetryAfter returns how long until the lock expires, rounded up to whole seconds
+func (a *LoginAttempt) RetryAfter(now time.Time) time.Duration {
+	if !a.IsLocked(now) {
+		return 0
+	}
+	return a.LockedUntil.Sub(now).Truncate(time.Second) + time.Second
+}
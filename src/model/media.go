@@ -0,0 +1,62 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alex-1900/wishlist/src/storage"
+)
+
+// MediaAltMaxLength is the maximum length of the alternative text of an image, in characters
+const MediaAltMaxLength = 250
+
+// NormalizeMediaAlt trims the alternative text describing an uploaded image for screen readers
+// and checks it is present and not too long
+func NormalizeMediaAlt(alt string) (string, error) {
+	alt = strings.TrimSpace(alt)
+	if alt == "" {
+		return "", errors.New("alt validation failed: alt text describing the image is required")
+	}
+	if utf8.RuneCountInString(alt) > MediaAltMaxLength {
+		return "", errors.New("alt validation failed: alt text is too long")
+	}
+	return alt, nil
+}
+
+// mediaURL turns a storage key into a public URL. The app installs the resolver of its
+// storage backend at startup; until then keys are returned unchanged.
+var mediaURL = func(key string) string { return key }
+
+// SetMediaURLResolver installs the function that turns storage keys into public URLs
+func SetMediaURLResolver(resolve func(key string) string) {
+	mediaURL = resolve
+}
+
+// MediaURL returns the public URL of a stored file, or "" when there is none
+func MediaURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	return mediaURL(key)
+}
+
+// MediaSizes are the public URLs of the size variants of a stored image. The smaller
+// variants are generated shortly after upload.
+type MediaSizes struct {
+	Thumbnail string `json:"thumbnail"`
+	Medium    string `json:"medium"`
+	Original  string `json:"original"`
+}
+
+// MediaSizeURLs returns the URLs of every size variant of a stored image, or nil when there is none
+func MediaSizeURLs(key string) *MediaSizes {
+	if key == "" {
+		return nil
+	}
+	return &MediaSizes{
+		Thumbnail: mediaURL(storage.VariantKey(key, "thumbnail")),
+		Medium:    mediaURL(storage.VariantKey(key, "medium")),
+		Original:  mediaURL(key),
+	}
+}
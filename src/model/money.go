@@ -0,0 +1,41 @@
+package model
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alex-1900/wishlist/src/currency"
+)
+
+// Money is an amount of money as shown in responses, in both major and minor units
+type Money struct {
+	Amount      float64 `json:"amount"`
+	AmountMinor int64   `json:"amount_minor"`
+	Currency    string  `json:"currency"`
+}
+
+// NewMoney converts an amount to Money
+func NewMoney(amount currency.Amount) *Money {
+	return &Money{
+		Amount:      amount.Major(),
+		AmountMinor: amount.Minor,
+		Currency:    amount.Currency,
+	}
+}
+
+// WithDisplayPrices sets the display price of the priced items, converted to the currency to.
+// Items whose currency has no known rate are left without one.
+func WithDisplayPrices(ctx context.Context, converter *currency.Converter, to string, responses ...*WishItemResponse) {
+	for _, itemResponse := range responses {
+		if itemResponse.PriceMinor == 0 {
+			continue
+		}
+		converted, err := converter.Convert(ctx, itemResponse.Amount(), to)
+		if errors.Is(err, currency.ErrRatesUnavailable) {
+			return
+		}
+		if err == nil {
+			itemResponse.DisplayPrice = NewMoney(converted)
+		}
+	}
+}
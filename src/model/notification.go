@@ -0,0 +1,115 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Notification types
+const (
+	NotificationFollowed     = "followed"
+	NotificationItemAdded    = "item_added"
+	NotificationUpcomingDate = "upcoming_date"
+	NotificationPriceDrop    = "price_drop"
+	NotificationGiftFunded   = "gift_funded"
+	NotificationInvitation   = "wishlist_invitation"
+)
+
+// Notification is an entry in a user's notification feed
+type Notification struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"-" db:"user_id"`
+	Type       string     `json:"type" db:"type"`
+	ActorID    *int       `json:"-" db:"actor_id"`
+	WishlistID *int       `json:"-" db:"wishlist_id"`
+	Message    string     `json:"message" db:"message"`
+	ReadAt     *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+
+	// WishlistPublicID names the wishlist to clients, empty when there is none or it was deleted
+	WishlistPublicID string `json:"wishlist_id,omitempty" db:"-"`
+}
+
+// NotificationRepository defines the interface for notification data operations
+type NotificationRepository interface {
+	Create(notification *Notification) error
+	ListByUser(userID int, opts ListOptions) ([]*Notification, *PageInfo, error)
+	CountUnread(userID int) (int, error)
+	MarkRead(userID int, ids []int) (int64, error)
+	MarkAllRead(userID int) (int64, error)
+	Clear(userID int) (int64, error)
+}
+
+// NotificationReadRequest represents the request structure for marking notifications as read.
+// No IDs marks the whole feed as read.
+type NotificationReadRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// NewFollowedNotification tells a user that someone started following them
+func NewFollowedNotification(userID int, follower *User) *Notification {
+	return &Notification{
+		UserID:    userID,
+		Type:      NotificationFollowed,
+		ActorID:   &follower.ID,
+		Message:   fmt.Sprintf("%s started following you", follower.Username),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// NewItemAddedNotification tells a subscriber of a wishlist that an item was added to it
+func NewItemAddedNotification(userID int, wishlist *Wishlist, item *WishItem) *Notification {
+	return &Notification{
+		UserID:           userID,
+		Type:             NotificationItemAdded,
+		ActorID:          &wishlist.UserID,
+		WishlistID:       &wishlist.ID,
+		WishlistPublicID: wishlist.PublicID,
+		Message:          fmt.Sprintf("%s was added to %s", item.Title, wishlist.Title),
+		CreatedAt:        time.Now().UTC(),
+	}
+}
+
+// NewPriceDropNotification tells the owner of a wishlist, or a giver who reserved the item, that
+// the price of an item fell under its alert. Givers hear it from the owner, so blocks apply.
+func NewPriceDropNotification(userID int, wishlist *Wishlist, item *WishItem, point *PricePoint) *Notification {
+	notification := &Notification{
+		UserID:           userID,
+		Type:             NotificationPriceDrop,
+		WishlistID:       &wishlist.ID,
+		WishlistPublicID: wishlist.PublicID,
+		Message:          fmt.Sprintf("The price of %s on %s dropped to %s", item.Title, wishlist.Title, point.Amount()),
+		CreatedAt:        time.Now().UTC(),
+	}
+	if userID != wishlist.UserID {
+		notification.ActorID = &wishlist.UserID
+	}
+	return notification
+}
+
+// NewGiftFundedNotification tells a giver who pledged towards an item that the contributions
+// reached its price, so the gift can be bought. The owner is never told.
+func NewGiftFundedNotification(userID int, wishlist *Wishlist, item *WishItem) *Notification {
+	return &Notification{
+		UserID:           userID,
+		Type:             NotificationGiftFunded,
+		WishlistID:       &wishlist.ID,
+		WishlistPublicID: wishlist.PublicID,
+		Message:          fmt.Sprintf("The contributions to %s on %s reached %s", item.Title, wishlist.Title, item.ContributionTarget()),
+		CreatedAt:        time.Now().UTC(),
+	}
+}
+
+// NewWishlistInvitationNotification tells a user they were invited to share the ownership of a
+// wishlist
+func NewWishlistInvitationNotification(userID int, wishlist *Wishlist, inviter *User, role WishlistRole) *Notification {
+	return &Notification{
+		UserID:           userID,
+		Type:             NotificationInvitation,
+		ActorID:          &inviter.ID,
+		WishlistID:       &wishlist.ID,
+		WishlistPublicID: wishlist.PublicID,
+		Message:          fmt.Sprintf("%s invited you to %s as %s", inviter.Username, wishlist.Title, role),
+		CreatedAt:        time.Now().UTC(),
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Optional is a member of a JSON Merge Patch document (RFC 7396). It tells a member that
+// was left out of the document, which leaves the field unchanged, apart from one that was
+// explicitly set to null, which clears the field.
+type Optional[T any] struct {
+	Set   bool // the member was present in the document
+	Null  bool // the member was present and null
+	Value T
+}
+
+// UnmarshalJSON records that the member was present and decodes its value
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		var zero T
+		o.Value = zero
+		return nil
+	}
+	o.Null = false
+	return json.Unmarshal(data, &o.Value)
+}
+
+// HasValue reports whether the member was present with a non-null value
+func (o Optional[T]) HasValue() bool {
+	return o.Set && !o.Null
+}
+
+// Apply writes the member into target: its value when set, the zero value when null,
+// and nothing when the member was absent
+func (o Optional[T]) Apply(target *T) {
+	if o.Set {
+		*target = o.Value
+	}
+}
+
+// requireNotNull rejects null for fields that cannot be cleared
+func requireNotNull[T any](field string, o Optional[T]) error {
+	if o.Null {
+		return fmt.Errorf("%s validation failed: %s cannot be null", field, field)
+	}
+	return nil
+}
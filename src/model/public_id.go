@@ -0,0 +1,12 @@
+package model
+
+import "regexp"
+
+// publicIDPattern matches a UUID in its canonical lowercase text form, as Postgres prints it
+var publicIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// IsPublicID reports whether s is a well-formed public ID. Users and wishlists are identified
+// by their public ID in URLs and public responses, so that they cannot be enumerated.
+func IsPublicID(s string) bool {
+	return publicIDPattern.MatchString(s)
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ReactionTarget is the kind of resource a reaction is attached to
+type ReactionTarget string
+
+// ReactionTarget constants
+const (
+	ReactionTargetWishlist ReactionTarget = "wishlist"
+	ReactionTargetItem     ReactionTarget = "item"
+)
+
+// ReactionKind is the kind of reaction a user leaves
+type ReactionKind string
+
+// ReactionKind constants
+const (
+	ReactionLike  ReactionKind = "like"
+	ReactionHeart ReactionKind = "heart"
+	ReactionWant  ReactionKind = "want"
+)
+
+// Reaction represents a user reacting to a wishlist or a wish item. A user leaves at most
+// one reaction of each kind on a target.
+type Reaction struct {
+	UserID     int            `json:"-" db:"user_id"`
+	TargetType ReactionTarget `json:"target_type" db:"target_type"`
+	TargetID   int            `json:"target_id" db:"target_id"`
+	Kind       ReactionKind   `json:"kind" db:"kind"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
+
+// ReactionCounts maps target IDs to the number of reactions of each kind they received
+type ReactionCounts map[int]map[ReactionKind]int
+
+// ReactionRepository defines the interface for reaction data operations
+type ReactionRepository interface {
+	Add(reaction *Reaction) error
+	Remove(userID int, targetType ReactionTarget, targetID int, kind ReactionKind) error
+	Counts(targetType ReactionTarget, targetIDs []int) (ReactionCounts, error)
+	DeleteForWishlist(wishlistID int) error
+	DeleteForItem(itemID int) error
+}
+
+// ReactionRequest represents the request structure for adding or removing a reaction
+type ReactionRequest struct {
+	TargetType ReactionTarget `json:"target_type" binding:"required"`
+	TargetID   TargetID       `json:"target_id" binding:"required"`
+	Kind       ReactionKind   `json:"kind" binding:"required"`
+}
+
+// TargetID identifies the target of a reaction request: the public ID of a wishlist, or the ID
+// of a wish item, which clients may send as a number
+type TargetID string
+
+// UnmarshalJSON accepts a JSON string or number
+func (id *TargetID) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = TargetID(s)
+		return nil
+	}
+
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = TargetID(strconv.Itoa(n))
+	return nil
+}
+
+// Validate validates the ReactionRequest fields
+func (r *ReactionRequest) Validate() error {
+	switch r.TargetType {
+	case ReactionTargetWishlist, ReactionTargetItem:
+	default:
+		return fmt.Errorf("target_type validation failed: must be one of %s, %s", ReactionTargetWishlist, ReactionTargetItem)
+	}
+
+	switch r.Kind {
+	case ReactionLike, ReactionHeart, ReactionWant:
+	default:
+		return fmt.Errorf("kind validation failed: must be one of %s, %s, %s", ReactionLike, ReactionHeart, ReactionWant)
+	}
+	return nil
+}
+
+// BeforeCreate sets the CreatedAt field before creating a new reaction
+func (r *Reaction) BeforeCreate() {
+	r.CreatedAt = time.Now().UTC()
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrRefreshTokenAlreadyUsed is returned when rotating a refresh token that has already been revoked
+var ErrRefreshTokenAlreadyUsed = errors.New("refresh token has already been used")
+
+// RefreshToken represents a long-lived token used to obtain new access tokens.
+// Only the SHA-256 hash of the token is stored.
+type RefreshToken struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"-" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash" pii:"true"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+
+	// SessionStartedAt is when the user logged in; rotated tokens keep the original time
+	SessionStartedAt time.Time `json:"session_started_at" db:"session_started_at"`
+}
+
+// RefreshTokenRepository defines the interface for refresh token data operations
+type RefreshTokenRepository interface {
+	Create(token *RefreshToken) error
+	GetByHash(tokenHash string) (*RefreshToken, error)
+	Rotate(oldTokenID int, newToken *RefreshToken) error
+	Revoke(id int) error
+	RevokeAllForUser(userID int) error
+}
+
+// RefreshTokenRequest represents the request structure for refreshing an access token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" pii:"true"`
+}
+
+// LogoutRequest represents the request structure for logging out
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" pii:"true"`
+}
+
+// IsRevoked reports whether the refresh token has been revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsExpired reports whether the refresh token has expired
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().UTC().After(t.ExpiresAt)
+}
+
+// BeforeCreate sets the CreatedAt field before creating a new refresh token, starting a new
+// session unless the token continues one
+func (t *RefreshToken) BeforeCreate() {
+	t.CreatedAt = time.Now().UTC()
+	if t.SessionStartedAt.IsZero() {
+		t.SessionStartedAt = t.CreatedAt
+	}
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// OccasionKind tells a birthday apart from an important date
+type OccasionKind string
+
+// OccasionKind constants
+const (
+	OccasionBirthday      OccasionKind = "birthday"
+	OccasionImportantDate OccasionKind = "important_date"
+)
+
+// Occasion is the birthday or an important date of a user falling on a given day
+type Occasion struct {
+	UserID   int
+	Username string
+	Kind     OccasionKind
+	DateID   int    // ID of the important date, 0 for birthdays
+	Label    string // label of the important date, empty for birthdays
+	On       time.Time
+}
+
+// Key identifies the occasion among the user's occasions on the same day
+func (o *Occasion) Key() string {
+	if o.Kind == OccasionBirthday {
+		return string(OccasionBirthday)
+	}
+	return fmt.Sprintf("%s:%d", o.Kind, o.DateID)
+}
+
+// ReminderRecipient is a friend to remind of an occasion, with the most recently updated
+// wishlist of the celebrated user they may view, if any
+type ReminderRecipient struct {
+	UserID     int
+	WishlistID *int
+}
+
+// ReminderRepository defines the interface for the data behind birthday and important date reminders
+type ReminderRepository interface {
+	ListOccasions(on time.Time) ([]*Occasion, error)
+	ListRecipients(userID int) ([]*ReminderRecipient, error)
+	Claim(occasion *Occasion) (bool, error)
+	Prune(before time.Time) (int64, error)
+}
+
+// NewUpcomingDateNotification tells a friend that a user's birthday or important date is coming up,
+// linking the wishlist of theirs the friend may view
+func NewUpcomingDateNotification(userID int, occasion *Occasion, wishlistID *int) *Notification {
+	what := occasion.Username + "'s birthday"
+	if occasion.Kind == OccasionImportantDate {
+		what = fmt.Sprintf("%s of %s", occasion.Label, occasion.Username)
+	}
+
+	return &Notification{
+		UserID:     userID,
+		Type:       NotificationUpcomingDate,
+		ActorID:    &occasion.UserID,
+		WishlistID: wishlistID,
+		Message:    fmt.Sprintf("%s is on %s", what, occasion.On.Format("January 2")),
+		CreatedAt:  time.Now().UTC(),
+	}
+}
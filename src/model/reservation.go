@@ -0,0 +1,125 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/currency"
+)
+
+// Reservation errors
+var (
+	// ErrItemFullyReserved is returned when a reservation would exceed the quantity wished for
+	ErrItemFullyReserved = errors.New("item is already fully reserved")
+	// ErrNoUnclaimedItem is returned when a wishlist has no unclaimed item within the budget
+	ErrNoUnclaimedItem = errors.New("no unclaimed item within budget")
+	// ErrReservationPurchased is returned when changing a reservation the giver marked purchased
+	ErrReservationPurchased = errors.New("reservation is already purchased")
+	// ErrNoReservation is returned when the giver has no reservation of the item
+	ErrNoReservation = errors.New("no reservation of the item")
+)
+
+// Reservation represents a giver claiming one or more units of a wish item, until they mark it
+// purchased. Reservations are never shown to the owner of the wishlist.
+type Reservation struct {
+	ID          int        `json:"id" db:"id"`
+	ItemID      int        `json:"item_id" db:"item_id"`
+	UserID      int        `json:"-" db:"user_id"`
+	Quantity    int        `json:"quantity" db:"quantity"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	PurchasedAt *time.Time `json:"purchased_at" db:"purchased_at"`
+}
+
+// ReservationRepository defines the interface for reservation data operations
+type ReservationRepository interface {
+	Reserve(reservation *Reservation) error
+	Release(itemID, userID int) error
+	ListByWishlist(wishlistID int) ([]*Reservation, error)
+	PickUnclaimed(wishlistID int, budget *currency.Amount) (int, error)
+	ClaimRandom(wishlistID, userID int, budget *currency.Amount) (*Reservation, error)
+}
+
+// ReservationRequest represents the request structure for reserving a wish item
+type ReservationRequest struct {
+	ItemID   int `json:"item_id" binding:"required"`
+	Quantity int `json:"quantity" binding:"omitempty,min=1"`
+}
+
+// ReservationReleaseRequest represents the request structure for releasing a reservation
+type ReservationReleaseRequest struct {
+	ItemID int `json:"item_id" binding:"required"`
+}
+
+// SurpriseRequest represents the query parameters of the random gift picker. Without a budget
+// every unclaimed item may be picked; a budget is in major units of its currency, and only items
+// priced in that currency are compared with it. With claim the picked item is reserved for the giver.
+type SurpriseRequest struct {
+	Budget   *float64 `form:"budget" binding:"omitempty,min=0"`
+	Currency string   `form:"currency"`
+	Claim    bool     `form:"claim"`
+}
+
+// Validate validates the SurpriseRequest fields and normalizes the currency
+func (r *SurpriseRequest) Validate() error {
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	if r.Budget != nil && r.Currency == "" {
+		return errors.New("currency validation failed: a budget needs its currency")
+	}
+	if r.Currency != "" && !currency.IsCode(r.Currency) {
+		return errors.New("currency validation failed: currency must be a 3-letter ISO code")
+	}
+	return nil
+}
+
+// BudgetAmount returns the budget in minor units of its currency, or nil when there is none
+func (r *SurpriseRequest) BudgetAmount() *currency.Amount {
+	if r.Budget == nil {
+		return nil
+	}
+	return &currency.Amount{Minor: currency.ToMinor(*r.Budget, r.Currency), Currency: r.Currency}
+}
+
+// Validate validates the ReservationRequest fields
+func (r *ReservationRequest) Validate() error {
+	if r.Quantity < 0 {
+		return errors.New("quantity validation failed: quantity must be positive")
+	}
+	return nil
+}
+
+// BeforeCreate sets the CreatedAt field before creating a new reservation
+func (r *Reservation) BeforeCreate() {
+	r.CreatedAt = time.Now().UTC()
+}
+
+// WishItemReservationState summarises the reservations of a wish item for a giver. Purchased
+// counts the reserved units givers already bought.
+type WishItemReservationState struct {
+	Reserved      int  `json:"reserved"`
+	Mine          int  `json:"mine"`
+	Purchased     int  `json:"purchased"`
+	MinePurchased bool `json:"mine_purchased"`
+	FullyReserved bool `json:"fully_reserved"`
+}
+
+// ReservationStates sums up the reservations of items for a giver, by item ID
+func ReservationStates(reservations []*Reservation, userID int) map[int]*WishItemReservationState {
+	states := make(map[int]*WishItemReservationState)
+	for _, reservation := range reservations {
+		state, ok := states[reservation.ItemID]
+		if !ok {
+			state = &WishItemReservationState{}
+			states[reservation.ItemID] = state
+		}
+		state.Reserved += reservation.Quantity
+		if reservation.PurchasedAt != nil {
+			state.Purchased += reservation.Quantity
+		}
+		if reservation.UserID == userID {
+			state.Mine = reservation.Quantity
+			state.MinePurchased = reservation.PurchasedAt != nil
+		}
+	}
+	return states
+}
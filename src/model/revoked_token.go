@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// RevokedToken represents an access token that has been revoked before its expiry.
+// Tokens are keyed by their JTI claim and can be purged once they would have expired anyway.
+type RevokedToken struct {
+	JTI       string    `json:"jti" db:"jti"`
+	UserID    int       `json:"-" db:"user_id"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// RevokedTokenRepository defines the interface for the access token denylist
+type RevokedTokenRepository interface {
+	Revoke(token *RevokedToken) error
+	IsRevoked(jti string, userID int, issuedAt time.Time) (bool, error)
+	PurgeExpired() (int64, error)
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SearchType is the kind of resource a search result points at
+type SearchType string
+
+// SearchType constants
+const (
+	SearchTypeUser     SearchType = "user"
+	SearchTypeWishlist SearchType = "wishlist"
+	SearchTypeItem     SearchType = "item"
+)
+
+// SearchTypes lists every searchable type
+var SearchTypes = []SearchType{SearchTypeUser, SearchTypeWishlist, SearchTypeItem}
+
+// Search validation constants
+const (
+	SearchQueryMaxLength = 100
+	SearchQueryMaxTerms  = 8
+)
+
+// SearchResult represents one ranked hit of a search. Users and wishlists are named by their
+// PublicID, items by their ID. Username is the user found or the owner of the wishlist or item;
+// WishlistID, the public ID of the wishlist, is set for wishlists and items.
+type SearchResult struct {
+	Type       SearchType `json:"type"`
+	ID         int        `json:"id,omitempty"`
+	PublicID   string     `json:"public_id,omitempty"`
+	Title      string     `json:"title"`
+	Username   string     `json:"username"`
+	WishlistID string     `json:"wishlist_id,omitempty"`
+	Rank       float64    `json:"rank"`
+}
+
+// SearchRepository defines the interface for full-text search. Terms are matched as prefixes
+// and every term must match. viewerID is 0 for anonymous visitors; wishlists and items are only
+// returned when the viewer may see them.
+type SearchRepository interface {
+	Search(terms []string, types []SearchType, viewerID int, opts ListOptions) ([]*SearchResult, *PageInfo, error)
+	BrowseTag(tag string, category WishItemCategory, viewerID int, opts ListOptions) ([]*TaggedWishlist, *PageInfo, error)
+}
+
+// SearchRequest represents the query parameters of a search
+type SearchRequest struct {
+	Query string `form:"q" binding:"required"`
+	Type  string `form:"type"`
+}
+
+// Validate validates the SearchRequest fields
+func (r *SearchRequest) Validate() error {
+	if len(r.Query) > SearchQueryMaxLength {
+		return fmt.Errorf("q validation failed: must not exceed %d characters", SearchQueryMaxLength)
+	}
+
+	terms := r.Terms()
+	if len(terms) == 0 {
+		return errors.New("q validation failed: must contain a letter or digit")
+	}
+	if len(terms) > SearchQueryMaxTerms {
+		return fmt.Errorf("q validation failed: must not contain more than %d words", SearchQueryMaxTerms)
+	}
+
+	_, err := r.Types()
+	return err
+}
+
+// Terms splits the query into lowercase words, dropping punctuation
+func (r *SearchRequest) Terms() []string {
+	return strings.FieldsFunc(strings.ToLower(r.Query), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+}
+
+// Types returns the requested result types from the comma-separated type parameter,
+// every type when it is empty
+func (r *SearchRequest) Types() ([]SearchType, error) {
+	if strings.TrimSpace(r.Type) == "" {
+		return SearchTypes, nil
+	}
+
+	var types []SearchType
+	for _, name := range strings.Split(r.Type, ",") {
+		t := SearchType(strings.TrimSpace(name))
+		switch t {
+		case SearchTypeUser, SearchTypeWishlist, SearchTypeItem:
+			types = append(types, t)
+		default:
+			return nil, fmt.Errorf("type validation failed: must be a comma-separated list of %s, %s, %s", SearchTypeUser, SearchTypeWishlist, SearchTypeItem)
+		}
+	}
+	return types, nil
+}
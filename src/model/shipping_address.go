@@ -0,0 +1,78 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ShippingAddress is where givers send the gifts of a wishlist whose items ship to the owner.
+// It is stored as a JSONB object.
+type ShippingAddress struct {
+	Name       string `json:"name" pii:"true"`
+	Line1      string `json:"line1" pii:"true"`
+	Line2      string `json:"line2,omitempty" pii:"true"`
+	City       string `json:"city" pii:"true"`
+	Region     string `json:"region,omitempty" pii:"true"`
+	PostalCode string `json:"postal_code,omitempty" pii:"true"`
+	// Country is an ISO 3166-1 alpha-2 code
+	Country string `json:"country"`
+}
+
+// Shipping address validation constants
+const (
+	ShippingAddressFieldMaxLength = 200
+)
+
+var countryCodeRegex = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Value implements driver.Valuer for the JSONB column
+func (a ShippingAddress) Value() (driver.Value, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner for the JSONB column
+func (a *ShippingAddress) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ShippingAddress", value)
+	}
+	return json.Unmarshal(data, a)
+}
+
+// Normalize trims the fields of the address, upper-cases the country and checks the required
+// fields are set
+func (a *ShippingAddress) Normalize() error {
+	fields := map[string]*string{
+		"name": &a.Name, "line1": &a.Line1, "line2": &a.Line2, "city": &a.City,
+		"region": &a.Region, "postal_code": &a.PostalCode,
+	}
+	for name, value := range fields {
+		*value = strings.TrimSpace(*value)
+		if len(*value) > ShippingAddressFieldMaxLength {
+			return fmt.Errorf("shipping_address validation failed: %s is too long", name)
+		}
+	}
+
+	if a.Name == "" || a.Line1 == "" || a.City == "" {
+		return errors.New("shipping_address validation failed: name, line1 and city are required")
+	}
+
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	if !countryCodeRegex.MatchString(a.Country) {
+		return errors.New("shipping_address validation failed: country must be a 2-letter ISO code")
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SiteSettings holds the deployment-wide settings administrators change at runtime
+type SiteSettings struct {
+	// RegistrationOpen lets anyone sign up; when false no new accounts can be created
+	RegistrationOpen bool `json:"registration_open" db:"registration_open"`
+	// AllowedEmailDomains limits sign-ups to these domains, e.g. for company-only deployments.
+	// Empty allows every domain.
+	AllowedEmailDomains []string `json:"allowed_email_domains" db:"allowed_email_domains"`
+	// DeniedEmailDomains are refused even when the allowlist is empty, e.g. disposable mail providers
+	DeniedEmailDomains []string `json:"denied_email_domains" db:"denied_email_domains"`
+
+	UpdatedBy *int       `json:"-" db:"updated_by"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// DefaultSiteSettings returns the settings of a deployment no administrator has changed
+func DefaultSiteSettings() *SiteSettings {
+	return &SiteSettings{
+		RegistrationOpen:    true,
+		AllowedEmailDomains: []string{},
+		DeniedEmailDomains:  []string{},
+	}
+}
+
+// Email domain policy errors
+var (
+	ErrEmailDomainNotAllowed = errors.New("email domain is not on the allowlist")
+	ErrEmailDomainDenied     = errors.New("email domain is on the denylist")
+)
+
+// CheckEmailDomain reports whether an address may be used by an account, at sign-up or when
+// the address changes. The denylist wins over the allowlist.
+func (s *SiteSettings) CheckEmailDomain(email string) error {
+	domain := EmailDomain(email)
+	if containsDomain(s.DeniedEmailDomains, domain) {
+		return ErrEmailDomainDenied
+	}
+	if len(s.AllowedEmailDomains) > 0 && !containsDomain(s.AllowedEmailDomains, domain) {
+		return ErrEmailDomainNotAllowed
+	}
+	return nil
+}
+
+// containsDomain reports whether domain is in the list
+func containsDomain(domains []string, domain string) bool {
+	for _, d := range domains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailDomain returns the lowercase domain of an email address
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
+
+// SiteSettingsRepository defines the interface for site settings data operations
+type SiteSettingsRepository interface {
+	Get() (*SiteSettings, error)
+	Update(settings *SiteSettings) error
+}
+
+// SiteSettingsPatch is a JSON Merge Patch (RFC 7396) of the site settings
+type SiteSettingsPatch struct {
+	RegistrationOpen    Optional[bool]     `json:"registration_open"`
+	AllowedEmailDomains Optional[[]string] `json:"allowed_email_domains"`
+	DeniedEmailDomains  Optional[[]string] `json:"denied_email_domains"`
+}
+
+// Site settings validation constants
+const (
+	EmailDomainsMax = 100
+)
+
+var emailDomainRegex = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)*\.[a-z]{2,}$`)
+
+// Validate validates the SiteSettingsPatch fields
+func (p *SiteSettingsPatch) Validate() error {
+	if err := requireNotNull("registration_open", p.RegistrationOpen); err != nil {
+		return err
+	}
+
+	if err := validateEmailDomains("allowed_email_domains", p.AllowedEmailDomains.Value); err != nil {
+		return err
+	}
+	return validateEmailDomains("denied_email_domains", p.DeniedEmailDomains.Value)
+}
+
+// Apply writes the patch into the settings; a null domain list clears it.
+// Domains are stored lowercase.
+func (p *SiteSettingsPatch) Apply(settings *SiteSettings) {
+	p.RegistrationOpen.Apply(&settings.RegistrationOpen)
+	if p.AllowedEmailDomains.Set {
+		settings.AllowedEmailDomains = normalizeEmailDomains(p.AllowedEmailDomains.Value)
+	}
+	if p.DeniedEmailDomains.Set {
+		settings.DeniedEmailDomains = normalizeEmailDomains(p.DeniedEmailDomains.Value)
+	}
+}
+
+// validateEmailDomains checks a list of bare email domains such as "example.com"
+func validateEmailDomains(field string, domains []string) error {
+	if len(domains) > EmailDomainsMax {
+		return fmt.Errorf("%s validation failed: must not contain more than %d domains", field, EmailDomainsMax)
+	}
+	for _, domain := range domains {
+		if !emailDomainRegex.MatchString(strings.ToLower(strings.TrimSpace(domain))) {
+			return fmt.Errorf("%s validation failed: %q is not a domain", field, domain)
+		}
+	}
+	return nil
+}
+
+// normalizeEmailDomains lowercases the domains and drops duplicates
+func normalizeEmailDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	seen := make(map[string]bool)
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if !seen[domain] {
+			seen[domain] = true
+			normalized = append(normalized, domain)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// SitemapProfile is a user profile listed in the sitemap
+type SitemapProfile struct {
+	Username  string    `json:"username" db:"username"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SitemapWishlist is a wishlist listed in the sitemap
+type SitemapWishlist struct {
+	PublicID  string    `json:"public_id" db:"public_id"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SitemapRepository defines the interface for reading the public content listed in the sitemap.
+// Both methods call fn as rows are read and stop at the first error it returns.
+type SitemapRepository interface {
+	StreamProfiles(fn func(*SitemapProfile) error) error
+	StreamWishlists(fn func(*SitemapWishlist) error) error
+}
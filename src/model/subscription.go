@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// Subscription represents a user watching a wishlist of someone else without following them
+type Subscription struct {
+	UserID     int       `json:"-" db:"user_id"`
+	WishlistID int       `json:"-" db:"wishlist_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// SubscribedWishlist represents a wishlist, by its public ID, in a user's subscriptions list
+type SubscribedWishlist struct {
+	WishlistID    string    `json:"wishlist_id" db:"public_id"`
+	Title         string    `json:"title" db:"title"`
+	OwnerID       int       `json:"-" db:"owner_id"`
+	OwnerUsername string    `json:"owner_username" db:"owner_username"`
+	SubscribedAt  time.Time `json:"subscribed_at" db:"subscribed_at"`
+}
+
+// SubscriptionRepository defines the interface for wishlist subscription data operations
+type SubscriptionRepository interface {
+	Subscribe(userID, wishlistID int) (bool, error)
+	Unsubscribe(userID, wishlistID int) error
+	ListByUser(userID int, opts ListOptions) ([]*SubscribedWishlist, *PageInfo, error)
+	ListSubscribers(wishlistID int) ([]int, error)
+}
+
+// SubscriptionRequest represents the request structure for subscribing to or unsubscribing from a wishlist
+type SubscriptionRequest struct {
+	WishlistID string `json:"wishlist_id" binding:"required"`
+}
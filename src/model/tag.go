@@ -0,0 +1,111 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WishItemCategory is one of the fixed categories a wish item may be filed under
+type WishItemCategory string
+
+// WishItemCategories lists every category, in display order. The empty category files nothing.
+var WishItemCategories = []WishItemCategory{
+	"books", "clothing", "electronics", "home", "kitchen", "garden", "toys", "games", "sports",
+	"outdoors", "beauty", "jewelry", "music", "art", "food", "baby", "pets", "travel",
+	"experiences", "other",
+}
+
+// IsValid reports whether the category is known, or empty
+func (c WishItemCategory) IsValid() bool {
+	if c == "" {
+		return true
+	}
+	for _, category := range WishItemCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Tag validation constants
+const (
+	TagMaxLength       = 30
+	WishItemMaxTags    = 10
+	TagBrowseMaxLength = 100
+)
+
+// NormalizeTag lowercases a tag and collapses its spaces. Tags hold letters, digits, spaces and
+// hyphens, so "Board  Games" and "board games" are the same tag.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+	if tag == "" {
+		return "", errors.New("tag cannot be empty")
+	}
+	if utf8.RuneCountInString(tag) > TagMaxLength {
+		return "", fmt.Errorf("tag %q must not exceed %d characters", tag, TagMaxLength)
+	}
+	for _, c := range tag {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != ' ' && c != '-' {
+			return "", fmt.Errorf("tag %q may only contain letters, digits, spaces and hyphens", tag)
+		}
+	}
+	return tag, nil
+}
+
+// NormalizeTags normalizes the tags of a wish item, dropping duplicates, and sorts them
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > WishItemMaxTags {
+		return nil, fmt.Errorf("an item may have at most %d tags", WishItemMaxTags)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// TagBrowseRequest represents the query parameters of browsing wishlists by tag
+type TagBrowseRequest struct {
+	Tag      string `form:"tag" binding:"required"`
+	Category string `form:"category"`
+}
+
+// Validate validates and normalizes the TagBrowseRequest fields
+func (r *TagBrowseRequest) Validate() error {
+	if len(r.Tag) > TagBrowseMaxLength {
+		return fmt.Errorf("tag validation failed: must not exceed %d characters", TagBrowseMaxLength)
+	}
+	tag, err := NormalizeTag(r.Tag)
+	if err != nil {
+		return fmt.Errorf("tag validation failed: %w", err)
+	}
+	r.Tag = tag
+
+	if !WishItemCategory(r.Category).IsValid() {
+		return errors.New("category validation failed: unknown category")
+	}
+	return nil
+}
+
+// TaggedWishlist represents a wishlist, by its public ID, with items carrying a browsed tag, and
+// how many do
+type TaggedWishlist struct {
+	WishlistID  string `json:"wishlist_id"`
+	Title       string `json:"title"`
+	Username    string `json:"username"`
+	TaggedItems int    `json:"tagged_items"`
+}
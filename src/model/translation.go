@@ -0,0 +1,150 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Translation is the content of a wishlist or wish item in one language
+type Translation struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// Translations maps BCP 47 language tags, such as "de" or "pt-BR", to translated content.
+// It is stored as a JSONB object.
+type Translations map[string]Translation
+
+// MaxTranslations is the number of languages a wishlist or wish item may be translated to
+const MaxTranslations = 20
+
+// Value implements driver.Valuer, storing no translations as an empty object
+func (t Translations) Value() (driver.Value, error) {
+	if t == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner for the JSONB column
+func (t *Translations) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Translations", value)
+	}
+
+	var translations Translations
+	if err := json.Unmarshal(data, &translations); err != nil {
+		return err
+	}
+	if len(translations) == 0 {
+		translations = nil
+	}
+	*t = translations
+	return nil
+}
+
+// Normalize checks the translations and rewrites their language tags in canonical form.
+// Descriptions are rejected when descriptionMax is 0, as wishlists have none.
+func (t Translations) Normalize(titleMax, descriptionMax int) (Translations, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	if len(t) > MaxTranslations {
+		return nil, fmt.Errorf("at most %d translations are allowed", MaxTranslations)
+	}
+
+	normalized := make(Translations, len(t))
+	for name, translation := range t {
+		tag, err := ParseLanguage(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := normalized[tag]; exists {
+			return nil, fmt.Errorf("language %q is given twice", tag)
+		}
+
+		translation.Title = strings.TrimSpace(translation.Title)
+		translation.Description = strings.TrimSpace(translation.Description)
+		if translation.Title == "" {
+			return nil, fmt.Errorf("title of the %s translation is required", tag)
+		}
+		if len(translation.Title) > titleMax {
+			return nil, fmt.Errorf("title of the %s translation is too long", tag)
+		}
+		if descriptionMax == 0 && translation.Description != "" {
+			return nil, errors.New("translations of a wishlist have no description")
+		}
+		if len(translation.Description) > descriptionMax {
+			return nil, fmt.Errorf("description of the %s translation is too long", tag)
+		}
+		normalized[tag] = translation
+	}
+	return normalized, nil
+}
+
+// Pick returns the translation best matching the viewer's preferred languages, best first.
+// original is the language of the untranslated content, empty when unknown; a viewer who
+// prefers it gets the untranslated content, which is also the fallback when no translation
+// matches. ok is false when the untranslated content should be shown.
+func (t Translations) Pick(preferences []language.Tag, original string) (string, Translation, bool) {
+	if len(t) == 0 || len(preferences) == 0 {
+		return "", Translation{}, false
+	}
+
+	// The first supported tag stands for the untranslated content and is the matcher's default
+	supported := []language.Tag{language.Und}
+	if tag, err := language.Parse(original); err == nil {
+		supported[0] = tag
+	}
+
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tagged := make([]string, 0, len(names))
+	for _, name := range names {
+		tag, err := language.Parse(name)
+		if err != nil {
+			continue
+		}
+		supported = append(supported, tag)
+		tagged = append(tagged, name)
+	}
+
+	_, index, confidence := language.NewMatcher(supported).Match(preferences...)
+	if index == 0 || confidence == language.No {
+		return "", Translation{}, false
+	}
+	name := tagged[index-1]
+	return name, t[name], true
+}
+
+// ParseLanguage checks a BCP 47 language tag and returns it in canonical form
+func ParseLanguage(value string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(value))
+	if err != nil || tag == language.Und {
+		return "", fmt.Errorf("%q is not a valid language tag", value)
+	}
+	return tag.String(), nil
+}
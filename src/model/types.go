@@ -5,14 +5,19 @@ package model
 
 // Domain Models
 // User - defined in user.go
+// Wishlist - defined in wishlist.go
+// WishItem - defined in wish_item.go
 
 // Request/Response Types
 // UserCreateRequest - defined in user.go
-// UserUpdateRequest - defined in user.go
+// UserPatch - defined in user.go
 // UserResponse - defined in user.go
+// WishlistCreateRequest, WishlistRenameRequest, WishlistPatch, WishlistResponse - defined in wishlist.go
 
 // Repository Interfaces
 // UserRepository - defined in user.go
+// WishlistRepository - defined in wishlist.go
+// WishItemRepository - defined in wish_item.go
 
 // Validation Constants and Functions
 // All validation logic is defined in user.go
@@ -21,7 +26,15 @@ package model
 type (
 	UserModel         = User
 	UserCreateReq     = UserCreateRequest
-	UserUpdateReq     = UserUpdateRequest
+	UserPatchReq      = UserPatch
 	UserResp          = UserResponse
 	UserRepoInterface = UserRepository
+
+	WishlistModel         = Wishlist
+	WishlistResp          = WishlistResponse
+	WishlistRepoInterface = WishlistRepository
+
+	WishItemModel         = WishItem
+	WishItemResp          = WishItemResponse
+	WishItemRepoInterface = WishItemRepository
 )
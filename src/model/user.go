@@ -10,6 +10,9 @@ import (
 	"time"
 )
 
+// ErrUserNotFound is returned when the user does not exist or was deleted
+var ErrUserNotFound = errors.New("user not found")
+
 // Gender represents the gender type for users
 type Gender string
 
@@ -44,54 +47,131 @@ func (g Gender) String() string {
 
 // User represents the user domain model
 type User struct {
-	ID           int       `json:"id" db:"id"`
+	ID           int       `json:"-" db:"id"`
+	PublicID     string    `json:"public_id" db:"public_id"`
 	Username     string    `json:"username" db:"username"`
-	Email        string    `json:"email" db:"email"`
-	Gender       Gender    `json:"gender" db:"gender"`
-	PasswordHash string    `json:"-" db:"password_hash"` // Hidden from JSON output
+	Email        string    `json:"email" db:"email" pii:"true"`
+	Gender       Gender    `json:"gender" db:"gender" pii:"true"`
+	PasswordHash string    `json:"-" db:"password_hash" pii:"true"` // Hidden from JSON output
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+
+	// AvatarKey is the storage key of the uploaded avatar, empty when there is none
+	AvatarKey string `json:"-" db:"avatar_key"`
+	// AvatarAlt describes the avatar for screen readers
+	AvatarAlt string `json:"-" db:"avatar_alt"`
+
+	// DeletedAt is set once the user is soft-deleted; the row is kept until it is purged
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// NoIndex opts the user's profile and wishlists out of search engine indexing
+	NoIndex bool `json:"noindex" db:"noindex"`
+
+	// Birthday is shown to the user only; friends are reminded of it ahead of time
+	Birthday *time.Time `json:"birthday,omitempty" db:"birthday" pii:"true"`
+
+	// Sandbox marks the accounts of the demo sandbox, the only ones allowed to write in demo mode
+	Sandbox bool `json:"-" db:"sandbox"`
 }
 
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
 	Create(user *User) error
 	GetByID(id int) (*User, error)
+	GetByPublicID(publicID string) (*User, error)
 	GetByUsername(username string) (*User, error)
 	GetByEmail(email string) (*User, error)
 	Update(user *User) error
 	Delete(id int) error
-	List() ([]*User, error)
+	Restore(publicID string) error
+	Purge(deletedBefore time.Time) (int64, error)
+	List(opts ListOptions) ([]*User, *PageInfo, error)
+	Stream(opts ListOptions, fn func(*User) error) error
 	ExistsByUsername(username string) (bool, error)
 	ExistsByEmail(email string) (bool, error)
 	GetTotalCount() (int, error)
 	UpdatePassword(userID int, passwordHash string) error
+	ResetCredentials(userID int, passwordHash string) error
+	MarkEmailVerified(userID int) error
+	SetAvatar(userID int, key, alt string) error
 }
 
 // UserCreateRequest represents the request structure for creating a user
 type UserCreateRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
-	Email    string `json:"email" binding:"required,email"`
-	Gender   string `json:"gender" binding:"omitempty,oneof=male female unknown"`
-	Password string `json:"password" binding:"required,min=8"`
+	Email    string `json:"email" binding:"required,email" pii:"true"`
+	Gender   string `json:"gender" binding:"omitempty,oneof=male female unknown" pii:"true"`
+	Password string `json:"password" binding:"required,min=8" pii:"true"`
+}
+
+// UserPatch represents a JSON Merge Patch of a user's profile; a null gender resets it to unknown
+type UserPatch struct {
+	Username Optional[string] `json:"username"`
+	Email    Optional[string] `json:"email" pii:"true"`
+	Gender   Optional[string] `json:"gender" pii:"true"`
+	Password Optional[string] `json:"password" pii:"true"`
+	NoIndex  Optional[bool]   `json:"noindex"`
+	Birthday Optional[string] `json:"birthday" pii:"true"`
 }
 
-// UserUpdateRequest represents the request structure for updating a user
-type UserUpdateRequest struct {
-	Username *string `json:"username,omitempty" binding:"omitempty,min=3,max=50"`
-	Email    *string `json:"email,omitempty" binding:"omitempty,email"`
-	Gender   *string `json:"gender,omitempty" binding:"omitempty,oneof=male female unknown"`
-	Password *string `json:"password,omitempty" binding:"omitempty,min=8"`
+// UserIDRequest represents the request structure for admin actions on a single user, named by
+// their public ID
+type UserIDRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// UserPurgeRequest represents the request structure for purging soft-deleted users. Users deleted
+// less than OlderThanDays days ago are kept; without it DefaultPurgeAfterDays applies.
+type UserPurgeRequest struct {
+	OlderThanDays *int `json:"older_than_days" binding:"omitempty,min=0"`
+}
+
+// DefaultPurgeAfterDays is how long soft-deleted users can be restored before a purge removes them
+const DefaultPurgeAfterDays = 30
+
+// Cutoff returns the time before which soft-deleted users are purged
+func (r *UserPurgeRequest) Cutoff(now time.Time) time.Time {
+	days := DefaultPurgeAfterDays
+	if r.OlderThanDays != nil {
+		days = *r.OlderThanDays
+	}
+	return now.AddDate(0, 0, -days)
 }
 
 // UserResponse represents the safe response structure for user data
 type UserResponse struct {
-	ID        int       `json:"id"`
+	ID        int       `json:"-"`
+	PublicID  string    `json:"public_id"`
 	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Gender    Gender    `json:"gender"`
+	Email     string    `json:"email" pii:"true"`
+	Gender    Gender    `json:"gender" pii:"true"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	EmailVerified bool        `json:"email_verified"`
+	AvatarURL     string      `json:"avatar_url,omitempty"`
+	AvatarSizes   *MediaSizes `json:"avatar_sizes,omitempty"`
+	AvatarAlt     string      `json:"avatar_alt,omitempty"`
+	NoIndex       bool        `json:"noindex"`
+	Birthday      string      `json:"birthday,omitempty" pii:"true"`
+
+	FollowersCount int `json:"followers_count"`
+	FollowingCount int `json:"following_count"`
+}
+
+// PublicProfile is the part of a user's profile shown through the public API
+type PublicProfile struct {
+	PublicID    string      `json:"public_id"`
+	Username    string      `json:"username"`
+	AvatarURL   string      `json:"avatar_url,omitempty"`
+	AvatarSizes *MediaSizes `json:"avatar_sizes,omitempty"`
+	AvatarAlt   string      `json:"avatar_alt,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+
+	FollowersCount int `json:"followers_count"`
+	FollowingCount int `json:"following_count"`
 }
 
 // Validation constants
@@ -129,35 +209,74 @@ func (ucr *UserCreateRequest) Validate() error {
 	return nil
 }
 
-// Validate validates the UserUpdateRequest fields
-func (uur *UserUpdateRequest) Validate() error {
-	if uur.Username != nil {
-		if err := validateUsername(*uur.Username); err != nil {
+// Validate validates the UserPatch fields
+func (up *UserPatch) Validate() error {
+	if err := requireNotNull("username", up.Username); err != nil {
+		return err
+	}
+	if err := requireNotNull("email", up.Email); err != nil {
+		return err
+	}
+	if err := requireNotNull("password", up.Password); err != nil {
+		return err
+	}
+
+	if up.Username.HasValue() {
+		if err := validateUsername(up.Username.Value); err != nil {
 			return fmt.Errorf("username validation failed: %w", err)
 		}
 	}
 
-	if uur.Email != nil {
-		if err := validateEmail(*uur.Email); err != nil {
+	if up.Email.HasValue() {
+		if err := validateEmail(up.Email.Value); err != nil {
 			return fmt.Errorf("email validation failed: %w", err)
 		}
 	}
 
-	if uur.Gender != nil {
-		if err := validateGender(*uur.Gender); err != nil {
+	if up.Gender.HasValue() {
+		if err := validateGender(up.Gender.Value); err != nil {
 			return fmt.Errorf("gender validation failed: %w", err)
 		}
 	}
 
-	if uur.Password != nil {
-		if err := validatePassword(*uur.Password); err != nil {
+	if up.Password.HasValue() {
+		if err := validatePassword(up.Password.Value); err != nil {
 			return fmt.Errorf("password validation failed: %w", err)
 		}
 	}
 
+	if up.Birthday.HasValue() {
+		if _, err := ParseBirthday(up.Birthday.Value); err != nil {
+			return fmt.Errorf("birthday validation failed: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// BirthdayLayout is the format of birthdays in requests and responses
+const BirthdayLayout = "2006-01-02"
+
+// ParseBirthday parses a birthday in BirthdayLayout, rejecting dates in the future or before 1900
+func ParseBirthday(value string) (time.Time, error) {
+	birthday, err := time.Parse(BirthdayLayout, value)
+	if err != nil {
+		return time.Time{}, errors.New("birthday must be a date in YYYY-MM-DD format")
+	}
+	if birthday.Year() < 1900 || birthday.After(time.Now().UTC()) {
+		return time.Time{}, errors.New("birthday must be between 1900 and today")
+	}
+	return birthday, nil
+}
+
+// formatBirthday formats a birthday in BirthdayLayout, empty when there is none
+func formatBirthday(birthday *time.Time) string {
+	if birthday == nil {
+		return ""
+	}
+	return birthday.Format(BirthdayLayout)
+}
+
 // validateUsername validates the username field
 func validateUsername(username string) error {
 	if len(username) < UsernameMinLength {
@@ -202,20 +321,38 @@ func validateGender(gender string) error {
 	return nil
 }
 
-// validatePassword validates the password field
+// ErrPasswordTooWeak is returned when a password does not follow the password policy
+var ErrPasswordTooWeak = errors.New("password is too weak")
+
+// PasswordPolicy describes the passwords users may choose, published by GET /password-policy
+type PasswordPolicy struct {
+	MinLength        int  `json:"min_length"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireNumber    bool `json:"require_number"`
+}
+
+// CurrentPasswordPolicy is the policy validatePassword enforces
+var CurrentPasswordPolicy = PasswordPolicy{
+	MinLength:        PasswordMinLength,
+	RequireUppercase: true,
+	RequireLowercase: true,
+	RequireNumber:    true,
+}
+
+// validatePassword validates the password field against CurrentPasswordPolicy
 func validatePassword(password string) error {
-	if len(password) < PasswordMinLength {
-		return errors.New("password is too short")
+	policy := CurrentPasswordPolicy
+	if len(password) < policy.MinLength {
+		return fmt.Errorf("%w: it must be at least %d characters long", ErrPasswordTooWeak, policy.MinLength)
 	}
 
-	// You can add more password complexity requirements here
-	// For example: check for uppercase, lowercase, numbers, special characters
 	hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(password)
 	hasLower := regexp.MustCompile(`[a-z]`).MatchString(password)
 	hasNumber := regexp.MustCompile(`[0-9]`).MatchString(password)
 
-	if !hasUpper || !hasLower || !hasNumber {
-		return errors.New("password must contain at least one uppercase letter, one lowercase letter, and one number")
+	if (policy.RequireUppercase && !hasUpper) || (policy.RequireLowercase && !hasLower) || (policy.RequireNumber && !hasNumber) {
+		return fmt.Errorf("%w: it must contain at least one uppercase letter, one lowercase letter, and one number", ErrPasswordTooWeak)
 	}
 
 	return nil
@@ -225,14 +362,39 @@ func validatePassword(password string) error {
 func (u *User) ToResponse() *UserResponse {
 	return &UserResponse{
 		ID:        u.ID,
+		PublicID:  u.PublicID,
 		Username:  u.Username,
 		Email:     u.Email,
 		Gender:    u.Gender,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
+
+		EmailVerified: u.IsEmailVerified(),
+		AvatarURL:     MediaURL(u.AvatarKey),
+		AvatarSizes:   MediaSizeURLs(u.AvatarKey),
+		AvatarAlt:     u.AvatarAlt,
+		NoIndex:       u.NoIndex,
+		Birthday:      formatBirthday(u.Birthday),
 	}
 }
 
+// ToPublicProfile converts a User to a PublicProfile, leaving out the email, gender and ID
+func (u *User) ToPublicProfile() *PublicProfile {
+	return &PublicProfile{
+		PublicID:    u.PublicID,
+		Username:    u.Username,
+		AvatarURL:   MediaURL(u.AvatarKey),
+		AvatarSizes: MediaSizeURLs(u.AvatarKey),
+		AvatarAlt:   u.AvatarAlt,
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+// IsEmailVerified reports whether the user's current email address has been verified
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 // BeforeCreate sets the CreatedAt and UpdatedAt fields before creating a new user
 func (u *User) BeforeCreate() {
 	now := time.Now().UTC()
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// VerificationPurpose identifies what a verification code is used for
+type VerificationPurpose string
+
+// VerificationPurpose constants
+const (
+	VerificationPurposeEmail VerificationPurpose = "email"
+)
+
+// VerificationCode represents a one-time code sent to a user.
+// Only the SHA-256 hash of the code is stored.
+type VerificationCode struct {
+	ID         int                 `json:"id" db:"id"`
+	UserID     int                 `json:"-" db:"user_id"`
+	Email      string              `json:"email" db:"email" pii:"true"`
+	Purpose    VerificationPurpose `json:"purpose" db:"purpose"`
+	CodeHash   string              `json:"-" db:"code_hash" pii:"true"`
+	Attempts   int                 `json:"attempts" db:"attempts"`
+	ExpiresAt  time.Time           `json:"expires_at" db:"expires_at"`
+	ConsumedAt *time.Time          `json:"consumed_at,omitempty" db:"consumed_at"`
+	CreatedAt  time.Time           `json:"created_at" db:"created_at"`
+}
+
+// VerificationCodeRepository defines the interface for verification code data operations
+type VerificationCodeRepository interface {
+	Create(code *VerificationCode) error
+	GetLatestActive(userID int, purpose VerificationPurpose) (*VerificationCode, error)
+	IncrementAttempts(id int) error
+	Consume(id int) error
+	InvalidateAll(userID int, purpose VerificationPurpose) error
+}
+
+// IsExpired reports whether the verification code has expired
+func (v *VerificationCode) IsExpired() bool {
+	return time.Now().UTC().After(v.ExpiresAt)
+}
+
+// BeforeCreate sets the CreatedAt field before creating a new verification code
+func (v *VerificationCode) BeforeCreate() {
+	v.CreatedAt = time.Now().UTC()
+}
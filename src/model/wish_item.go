@@ -0,0 +1,794 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alex-1900/wishlist/src/currency"
+	"golang.org/x/text/language"
+)
+
+// WishItem represents an item on a wishlist
+type WishItem struct {
+	ID          int    `json:"id" db:"id"`
+	WishlistID  int    `json:"-" db:"wishlist_id"`
+	Title       string `json:"title" db:"title"`
+	Description string `json:"description" db:"description"`
+	URL         string `json:"url" db:"url"`
+	Priority    int    `json:"priority" db:"priority"`
+	Quantity    int    `json:"quantity" db:"quantity"`
+	Position    int    `json:"position" db:"position"`
+
+	// Category is one of WishItemCategories, or empty; Tags are the owner's own, normalized
+	Category WishItemCategory `json:"category" db:"category"`
+	Tags     []string         `json:"tags" db:"-"`
+
+	// ReceivedQuantity counts the units the owner already received, which givers no longer
+	// reserve; Quantity is the desired quantity
+	ReceivedQuantity int `json:"received_quantity" db:"received_quantity"`
+
+	// PriceMinor is the price in the minor unit of Currency, e.g. cents for USD and yen for JPY
+	PriceMinor int64  `json:"price_minor" db:"price_minor"`
+	Currency   string `json:"currency" db:"currency"`
+
+	// Giver-facing preferences, only revealed to givers once they reserve the item
+	PreferredVariant string `json:"preferred_variant" db:"preferred_variant"`
+	ShipTo           ShipTo `json:"ship_to" db:"ship_to"`
+	GiftWrap         bool   `json:"gift_wrap" db:"gift_wrap"`
+
+	// ImageKey is the storage key of the uploaded image, empty when there is none
+	ImageKey string `json:"-" db:"image_key"`
+	// ImageAlt describes the image for screen readers
+	ImageAlt string `json:"image_alt" db:"image_alt"`
+
+	// Translations are in the languages other than the wishlist's one
+	Translations Translations `json:"translations,omitempty" db:"translations"`
+	// Translation is the language the item was localized to by Localize, empty for the original
+	Translation string `json:"translation,omitempty" db:"-"`
+
+	// PriceAlertBelowMinor is the price, in minor units, under which a drop is announced, nil
+	// when no alert is set
+	PriceAlertBelowMinor *int64 `json:"price_alert_below_minor" db:"price_alert_below_minor"`
+	// PriceCheckedAt is when the price was last read from the URL, nil until it is tracked
+	PriceCheckedAt *time.Time `json:"price_checked_at" db:"price_checked_at"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ShipTo represents where the owner would like a gift delivered
+type ShipTo string
+
+// ShipTo constants
+const (
+	ShipToUnspecified ShipTo = ""
+	ShipToOwner       ShipTo = "owner"
+	ShipToGiver       ShipTo = "giver"
+)
+
+// IsValid checks if the ship-to value is valid
+func (s ShipTo) IsValid() bool {
+	return s == ShipToUnspecified || s == ShipToOwner || s == ShipToGiver
+}
+
+// WishItemRepository defines the interface for wish item data operations
+type WishItemRepository interface {
+	Create(item *WishItem) error
+	GetByID(id int) (*WishItem, error)
+	ListByWishlist(wishlistID int, opts ListOptions) ([]*WishItem, *PageInfo, error)
+	Update(item *WishItem) error
+	Delete(id int) error
+	Reorder(wishlistID int, itemIDs []int) error
+	SetImage(itemID int, key, alt string) error
+	Stats(wishlistID int) (*WishlistStats, error)
+	SetTags(itemID int, tags []string) error
+}
+
+// PricePoint is a price read from the page of a wish item by the price tracking job
+type PricePoint struct {
+	ID         int       `json:"id" db:"id"`
+	ItemID     int       `json:"item_id" db:"item_id"`
+	PriceMinor int64     `json:"price_minor" db:"price_minor"`
+	Currency   string    `json:"currency" db:"currency"`
+	CheckedAt  time.Time `json:"checked_at" db:"checked_at"`
+}
+
+// PricePointResponse represents the response structure for a recorded price
+type PricePointResponse struct {
+	ID         int       `json:"id"`
+	ItemID     int       `json:"item_id"`
+	Price      float64   `json:"price"`
+	PriceMinor int64     `json:"price_minor"`
+	Currency   string    `json:"currency"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// Amount returns the recorded price
+func (p *PricePoint) Amount() currency.Amount {
+	return currency.Amount{Minor: p.PriceMinor, Currency: p.Currency}
+}
+
+// ToResponse converts a PricePoint to a PricePointResponse
+func (p *PricePoint) ToResponse() *PricePointResponse {
+	return &PricePointResponse{
+		ID:         p.ID,
+		ItemID:     p.ItemID,
+		Price:      p.Amount().Major(),
+		PriceMinor: p.PriceMinor,
+		Currency:   p.Currency,
+		CheckedAt:  p.CheckedAt,
+	}
+}
+
+// PriceHistoryRepository defines the interface for tracking the prices of wish items with a URL
+type PriceHistoryRepository interface {
+	ListDue(checkedBefore time.Time, limit int) ([]*WishItem, error)
+	Record(point *PricePoint) error
+	MarkChecked(itemID int, checkedAt time.Time) error
+	ListByItem(itemID int, limit int) ([]*PricePoint, error)
+	Prune(before time.Time) (int64, error)
+}
+
+// PriceHistoryRequest represents the request structure for listing the price history of a wish item
+type PriceHistoryRequest struct {
+	ItemID int `form:"item_id" binding:"required"`
+}
+
+// PriceHistoryLimit is the number of most recent prices listed in a price history
+const PriceHistoryLimit = 100
+
+// NewPricePoint creates the history entry of a price read from the item's page, in major units.
+// A page without a valid currency is taken to use the item's one.
+func (i *WishItem) NewPricePoint(price float64, code string, checkedAt time.Time) *PricePoint {
+	if !currency.IsCode(code) {
+		code = i.Currency
+	}
+	return &PricePoint{
+		ItemID:     i.ID,
+		PriceMinor: currency.ToMinor(price, code),
+		Currency:   code,
+		CheckedAt:  checkedAt,
+	}
+}
+
+// PriceDropped reports whether a price read from the item's page crosses below the item's alert.
+// A price of zero is unknown rather than free, so any price under the alert then counts as a drop.
+func (i *WishItem) PriceDropped(point *PricePoint) bool {
+	if i.PriceAlertBelowMinor == nil || point.Currency != i.Currency || point.PriceMinor <= 0 {
+		return false
+	}
+	below := *i.PriceAlertBelowMinor
+	return point.PriceMinor < below && (i.PriceMinor == 0 || i.PriceMinor >= below)
+}
+
+// WishlistStats summarises the items of a wishlist for its owner. Reservations are left out,
+// since owners never see them.
+type WishlistStats struct {
+	ItemCount     int             `json:"item_count"`
+	TotalQuantity int             `json:"total_quantity"`
+	ByPriority    map[int]int     `json:"by_priority"`
+	Currencies    []CurrencyStats `json:"currencies"`
+
+	// ByCategory counts the items of every category; items filed under none are Uncategorized
+	ByCategory    map[WishItemCategory]int `json:"by_category"`
+	Uncategorized int                      `json:"uncategorized"`
+
+	// DisplayTotal is the total value of every currency converted to the viewer's display
+	// currency, when one was asked for and every rate is known
+	DisplayTotal *Money `json:"display_total,omitempty"`
+}
+
+// CurrencyStats summarises the priced items of a wishlist in one currency, in major units.
+// Items without a price are not counted.
+type CurrencyStats struct {
+	Currency     string  `json:"currency"`
+	ItemCount    int     `json:"item_count"`
+	TotalValue   float64 `json:"total_value"`
+	AveragePrice float64 `json:"average_price"`
+
+	// TotalValueMinor is the exact total value, in minor units
+	TotalValueMinor int64 `json:"total_value_minor"`
+}
+
+// WishItemCreateRequest represents the request structure for adding an item to a wishlist.
+// The title may be left out when a URL is given; it is then read from the linked page.
+type WishItemCreateRequest struct {
+	WishlistID  string  `json:"wishlist_id" binding:"required"`
+	Title       string  `json:"title" binding:"omitempty,max=200"`
+	Description string  `json:"description" binding:"omitempty,max=2000"`
+	URL         string  `json:"url" binding:"omitempty,max=2048"`
+	Price       float64 `json:"price" binding:"omitempty,min=0"`
+	Currency    string  `json:"currency" binding:"omitempty,len=3"`
+	Priority    int     `json:"priority" binding:"omitempty,min=1,max=5"`
+	Quantity    int     `json:"quantity" binding:"omitempty,min=1"`
+
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+
+	PreferredVariant string `json:"preferred_variant" binding:"omitempty,max=200"`
+	ShipTo           string `json:"ship_to" binding:"omitempty,oneof=owner giver"`
+	GiftWrap         bool   `json:"gift_wrap"`
+
+	Translations Translations `json:"translations"`
+
+	PriceAlertBelow *float64 `json:"price_alert_below"`
+}
+
+// WishItemPatch represents a JSON Merge Patch of a wish item; null clears a field back to its default
+type WishItemPatch struct {
+	Title       Optional[string]  `json:"title"`
+	Description Optional[string]  `json:"description"`
+	URL         Optional[string]  `json:"url"`
+	Price       Optional[float64] `json:"price"`
+	Currency    Optional[string]  `json:"currency"`
+	Priority    Optional[int]     `json:"priority"`
+	Quantity    Optional[int]     `json:"quantity"`
+
+	// ReceivedQuantity null resets it to 0
+	ReceivedQuantity Optional[int] `json:"received_quantity"`
+
+	// Category null files the item under no category; Tags are replaced as a whole, null
+	// removes them
+	Category Optional[WishItemCategory] `json:"category"`
+	Tags     Optional[[]string]         `json:"tags"`
+
+	PreferredVariant Optional[string] `json:"preferred_variant"`
+	ShipTo           Optional[ShipTo] `json:"ship_to"`
+	GiftWrap         Optional[bool]   `json:"gift_wrap"`
+
+	// Translations are replaced as a whole, null removes them
+	Translations Optional[Translations] `json:"translations"`
+
+	// PriceAlertBelow null turns the price drop alert off
+	PriceAlertBelow Optional[*float64] `json:"price_alert_below"`
+
+	// ImageAlt replaces the alternative text of the uploaded image, it cannot be cleared
+	ImageAlt Optional[string] `json:"image_alt"`
+}
+
+// WishItemEditRequest represents the request structure for editing a wish item by ID in the body
+type WishItemEditRequest struct {
+	ID int `json:"id" binding:"required"`
+	WishItemPatch
+}
+
+// WishItemReorderRequest represents the request structure for reordering the items of a wishlist
+type WishItemReorderRequest struct {
+	WishlistID string `json:"wishlist_id" binding:"required"`
+	ItemIDs    []int  `json:"item_ids" binding:"required,min=1"`
+}
+
+// WishItemDeleteRequest represents the request structure for removing a wish item
+type WishItemDeleteRequest struct {
+	ID int `json:"id" binding:"required"`
+}
+
+// WishItemFilterRequest represents the price and availability filters accepted when listing
+// someone else's wish items. Availability filters need a signed-in giver, since anonymous
+// visitors never see reservations.
+type WishItemFilterRequest struct {
+	MinPrice  *float64 `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice  *float64 `form:"max_price" binding:"omitempty,min=0"`
+	Available *bool    `form:"available"`
+	Unclaimed *bool    `form:"unclaimed"`
+}
+
+// Validate validates the WishItemFilterRequest fields
+func (r *WishItemFilterRequest) Validate() error {
+	if r.MinPrice != nil && r.MaxPrice != nil && *r.MinPrice > *r.MaxPrice {
+		return errors.New("max_price validation failed: max_price cannot be lower than min_price")
+	}
+	return nil
+}
+
+// FiltersAvailability reports whether the request filters on reservations
+func (r *WishItemFilterRequest) FiltersAvailability() bool {
+	return r.Available != nil || r.Unclaimed != nil
+}
+
+// Apply adds the requested filters to list options
+func (r *WishItemFilterRequest) Apply(opts *ListOptions) {
+	filters := make(map[string]string)
+	if r.MinPrice != nil {
+		filters["min_price"] = strconv.FormatFloat(*r.MinPrice, 'f', -1, 64)
+	}
+	if r.MaxPrice != nil {
+		filters["max_price"] = strconv.FormatFloat(*r.MaxPrice, 'f', -1, 64)
+	}
+	if r.Available != nil {
+		filters["available"] = strconv.FormatBool(*r.Available)
+	}
+	if r.Unclaimed != nil {
+		filters["unclaimed"] = strconv.FormatBool(*r.Unclaimed)
+	}
+	if len(filters) == 0 {
+		return
+	}
+
+	if opts.Filters == nil {
+		opts.Filters = make(map[string]string)
+	}
+	for name, value := range filters {
+		opts.Filters[name] = value
+	}
+}
+
+// WishItemResponse represents the response structure for wish item data
+type WishItemResponse struct {
+	ID          int     `json:"id"`
+	WishlistID  int     `json:"-"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	URL         string  `json:"url"`
+	Price       float64 `json:"price"`
+	PriceMinor  int64   `json:"price_minor"`
+	Currency    string  `json:"currency"`
+	Priority    int     `json:"priority"`
+	Quantity    int     `json:"quantity"`
+	Position    int     `json:"position"`
+	ImageURL    string  `json:"image_url,omitempty"`
+	ImageAlt    string  `json:"image_alt,omitempty"`
+
+	ReceivedQuantity int `json:"received_quantity"`
+
+	Category WishItemCategory `json:"category,omitempty"`
+	Tags     []string         `json:"tags"`
+
+	// DisplayPrice is the price converted to the viewer's display currency, when one was asked for
+	DisplayPrice *Money `json:"display_price,omitempty"`
+
+	ImageSizes *MediaSizes          `json:"image_sizes,omitempty"`
+	Reactions  map[ReactionKind]int `json:"reactions,omitempty"`
+
+	Translation  string       `json:"translation,omitempty"`
+	Translations Translations `json:"translations,omitempty"`
+
+	// Price tracking is shown to the owner and to givers who reserved the item
+	PriceAlertBelow *float64   `json:"price_alert_below,omitempty"`
+	PriceCheckedAt  *time.Time `json:"price_checked_at,omitempty"`
+
+	// Gift preferences are omitted for viewers who may not see them
+	GiftPreferences *WishItemGiftPreferences `json:"gift_preferences,omitempty"`
+
+	// Reservation and contribution states are only shown to givers, never to the owner
+	Reservation   *WishItemReservationState  `json:"reservation,omitempty"`
+	Contributions *WishItemContributionState `json:"contributions,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WishItemGiftPreferences groups the giver-facing preferences of a wish item
+type WishItemGiftPreferences struct {
+	PreferredVariant string `json:"preferred_variant"`
+	ShipTo           ShipTo `json:"ship_to"`
+	GiftWrap         bool   `json:"gift_wrap"`
+
+	// ShippingAddress is the wishlist's address, shown to givers of items shipping to the owner
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty" pii:"true"`
+}
+
+// Wish item validation constants and defaults
+const (
+	WishItemTitleMaxLength       = 200
+	WishItemDescriptionMaxLength = 2000
+	WishItemURLMaxLength         = 2048
+	WishItemMinPriority          = 1
+	WishItemMaxPriority          = 5
+	WishItemDefaultPriority      = 3
+	WishItemDefaultQuantity      = 1
+	WishItemDefaultCurrency      = "USD"
+	WishItemVariantMaxLength     = 200
+)
+
+// Validate validates the WishItemCreateRequest fields
+func (r *WishItemCreateRequest) Validate() error {
+	if r.Title != "" || r.URL == "" {
+		if err := validateWishItemTitle(r.Title); err != nil {
+			return fmt.Errorf("title validation failed: %w", err)
+		}
+	}
+
+	if err := validateWishItemFields(r.Description, r.URL, r.Price, r.Currency); err != nil {
+		return err
+	}
+
+	if r.Priority != 0 {
+		if err := validateWishItemPriority(r.Priority); err != nil {
+			return fmt.Errorf("priority validation failed: %w", err)
+		}
+	}
+
+	if r.Quantity < 0 {
+		return errors.New("quantity validation failed: quantity must be at least 1")
+	}
+
+	if err := validateGiftPreferences(r.PreferredVariant, r.ShipTo); err != nil {
+		return err
+	}
+
+	if !WishItemCategory(r.Category).IsValid() {
+		return errors.New("category validation failed: unknown category")
+	}
+
+	tags, err := NormalizeTags(r.Tags)
+	if err != nil {
+		return fmt.Errorf("tags validation failed: %w", err)
+	}
+	r.Tags = tags
+
+	translations, err := r.Translations.Normalize(WishItemTitleMaxLength, WishItemDescriptionMaxLength)
+	if err != nil {
+		return fmt.Errorf("translations validation failed: %w", err)
+	}
+	r.Translations = translations
+
+	if err := validatePriceAlert(r.PriceAlertBelow); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates the WishItemPatch fields
+func (r *WishItemPatch) Validate() error {
+	if err := requireNotNull("title", r.Title); err != nil {
+		return err
+	}
+	if r.Title.HasValue() {
+		if err := validateWishItemTitle(r.Title.Value); err != nil {
+			return fmt.Errorf("title validation failed: %w", err)
+		}
+	}
+
+	if err := validateWishItemFields(r.Description.Value, r.URL.Value, r.Price.Value, r.Currency.Value); err != nil {
+		return err
+	}
+
+	if r.Priority.HasValue() {
+		if err := validateWishItemPriority(r.Priority.Value); err != nil {
+			return fmt.Errorf("priority validation failed: %w", err)
+		}
+	}
+
+	if r.Quantity.HasValue() && r.Quantity.Value < 1 {
+		return errors.New("quantity validation failed: quantity must be at least 1")
+	}
+
+	if r.ReceivedQuantity.Value < 0 {
+		return errors.New("received_quantity validation failed: received_quantity cannot be negative")
+	}
+
+	if err := validateGiftPreferences(r.PreferredVariant.Value, string(r.ShipTo.Value)); err != nil {
+		return err
+	}
+
+	if !r.Category.Value.IsValid() {
+		return errors.New("category validation failed: unknown category")
+	}
+
+	if r.Tags.HasValue() {
+		tags, err := NormalizeTags(r.Tags.Value)
+		if err != nil {
+			return fmt.Errorf("tags validation failed: %w", err)
+		}
+		r.Tags.Value = tags
+	}
+
+	if r.Translations.HasValue() {
+		translations, err := r.Translations.Value.Normalize(WishItemTitleMaxLength, WishItemDescriptionMaxLength)
+		if err != nil {
+			return fmt.Errorf("translations validation failed: %w", err)
+		}
+		r.Translations.Value = translations
+	}
+
+	if err := validatePriceAlert(r.PriceAlertBelow.Value); err != nil {
+		return err
+	}
+
+	if err := requireNotNull("image_alt", r.ImageAlt); err != nil {
+		return err
+	}
+	if r.ImageAlt.HasValue() {
+		alt, err := NormalizeMediaAlt(r.ImageAlt.Value)
+		if err != nil {
+			return err
+		}
+		r.ImageAlt.Value = alt
+	}
+
+	return nil
+}
+
+// ApplyTo merges the patch into a wish item
+func (r *WishItemPatch) ApplyTo(item *WishItem) {
+	r.Title.Apply(&item.Title)
+	r.Description.Apply(&item.Description)
+	r.URL.Apply(&item.URL)
+	// Prices are patched in major units, so they keep their amount when the currency changes
+	price := item.Amount().Major()
+	alert := item.PriceAlertBelow()
+	r.Price.Apply(&price)
+	r.Currency.Apply(&item.Currency)
+	r.PriceAlertBelow.Apply(&alert)
+	r.Priority.Apply(&item.Priority)
+	r.Quantity.Apply(&item.Quantity)
+	r.ReceivedQuantity.Apply(&item.ReceivedQuantity)
+	r.Category.Apply(&item.Category)
+	r.Tags.Apply(&item.Tags)
+	r.PreferredVariant.Apply(&item.PreferredVariant)
+	r.ShipTo.Apply(&item.ShipTo)
+	r.GiftWrap.Apply(&item.GiftWrap)
+	r.Translations.Apply(&item.Translations)
+	r.ImageAlt.Apply(&item.ImageAlt)
+
+	// Cleared fields fall back to their defaults
+	item.ApplyDefaults()
+	item.SetPrices(price, alert)
+}
+
+// Validate validates the WishItemReorderRequest fields
+func (r *WishItemReorderRequest) Validate() error {
+	seen := make(map[int]bool, len(r.ItemIDs))
+	for _, id := range r.ItemIDs {
+		if seen[id] {
+			return fmt.Errorf("item_ids validation failed: duplicate item ID %d", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// validateWishItemTitle validates the wish item title field
+func validateWishItemTitle(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return errors.New("title is required")
+	}
+
+	if len(title) > WishItemTitleMaxLength {
+		return errors.New("title is too long")
+	}
+
+	return nil
+}
+
+// validateWishItemFields validates the optional descriptive fields of a wish item
+func validateWishItemFields(description, rawURL string, price float64, code string) error {
+	if len(description) > WishItemDescriptionMaxLength {
+		return errors.New("description validation failed: description is too long")
+	}
+
+	if rawURL != "" {
+		if err := validateWishItemURL(rawURL); err != nil {
+			return fmt.Errorf("url validation failed: %w", err)
+		}
+	}
+
+	if price < 0 {
+		return errors.New("price validation failed: price cannot be negative")
+	}
+
+	if code != "" && !currency.IsCode(code) {
+		return errors.New("currency validation failed: currency must be a 3-letter ISO code")
+	}
+
+	return nil
+}
+
+// validatePriceAlert validates the price under which a drop is announced, nil turning the alert off
+func validatePriceAlert(below *float64) error {
+	if below != nil && *below <= 0 {
+		return errors.New("price_alert_below validation failed: price_alert_below must be positive")
+	}
+	return nil
+}
+
+// validateWishItemURL validates the wish item URL field
+func validateWishItemURL(rawURL string) error {
+	if len(rawURL) > WishItemURLMaxLength {
+		return errors.New("url is too long")
+	}
+
+	parsed, err := url.ParseRequestURI(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+
+	return nil
+}
+
+// validateGiftPreferences validates the giver-facing preference fields
+func validateGiftPreferences(variant, shipTo string) error {
+	if len(variant) > WishItemVariantMaxLength {
+		return errors.New("preferred_variant validation failed: preferred variant is too long")
+	}
+
+	if !ShipTo(shipTo).IsValid() {
+		return errors.New("ship_to validation failed: ship_to must be one of: owner, giver")
+	}
+
+	return nil
+}
+
+// validateWishItemPriority validates the wish item priority field
+func validateWishItemPriority(priority int) error {
+	if priority < WishItemMinPriority || priority > WishItemMaxPriority {
+		return fmt.Errorf("priority must be between %d and %d", WishItemMinPriority, WishItemMaxPriority)
+	}
+	return nil
+}
+
+// Prefill fills the empty title, description, price and currency of a new item from
+// metadata read from its URL, truncating text to the field limits. The price is in major units.
+func (i *WishItem) Prefill(title, description string, price float64, code string) {
+	if i.Title == "" {
+		i.Title = truncate(strings.TrimSpace(title), WishItemTitleMaxLength)
+	}
+	if i.Description == "" {
+		i.Description = truncate(strings.TrimSpace(description), WishItemDescriptionMaxLength)
+	}
+	if i.PriceMinor == 0 && price > 0 {
+		if i.Currency == "" && currency.IsCode(code) {
+			i.Currency = code
+		}
+		i.ApplyDefaults()
+		i.PriceMinor = currency.ToMinor(price, i.Currency)
+	}
+}
+
+// Amount returns the price of the item
+func (i *WishItem) Amount() currency.Amount {
+	return currency.Amount{Minor: i.PriceMinor, Currency: i.Currency}
+}
+
+// PriceAlertBelow returns the price drop alert in major units, nil when no alert is set
+func (i *WishItem) PriceAlertBelow() *float64 {
+	if i.PriceAlertBelowMinor == nil {
+		return nil
+	}
+	below := currency.FromMinor(*i.PriceAlertBelowMinor, i.Currency)
+	return &below
+}
+
+// SetPrices sets the price and the price drop alert, given in major units of the item's
+// currency, which must be set first. A nil alert turns it off.
+func (i *WishItem) SetPrices(price float64, alertBelow *float64) {
+	i.PriceMinor = currency.ToMinor(price, i.Currency)
+	i.PriceAlertBelowMinor = nil
+	if alertBelow != nil {
+		below := max(currency.ToMinor(*alertBelow, i.Currency), 1)
+		i.PriceAlertBelowMinor = &below
+	}
+}
+
+// truncate shortens s to at most max bytes without splitting a UTF-8 sequence
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+// ApplyDefaults fills unset optional fields with their default values
+func (i *WishItem) ApplyDefaults() {
+	if i.Priority == 0 {
+		i.Priority = WishItemDefaultPriority
+	}
+	if i.Quantity == 0 {
+		i.Quantity = WishItemDefaultQuantity
+	}
+	if i.Currency == "" {
+		i.Currency = WishItemDefaultCurrency
+	}
+}
+
+// ToResponse converts a WishItem to a WishItemResponse including gift preferences,
+// for the owner and for givers who have reserved the item
+func (i *WishItem) ToResponse() *WishItemResponse {
+	response := i.ToPublicResponse()
+	response.GiftPreferences = &WishItemGiftPreferences{
+		PreferredVariant: i.PreferredVariant,
+		ShipTo:           i.ShipTo,
+		GiftWrap:         i.GiftWrap,
+	}
+	response.PriceAlertBelow = i.PriceAlertBelow()
+	response.PriceCheckedAt = i.PriceCheckedAt
+	return response
+}
+
+// ToPublicResponse converts a WishItem to a WishItemResponse without gift preferences
+func (i *WishItem) ToPublicResponse() *WishItemResponse {
+	tags := i.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return &WishItemResponse{
+		ID:          i.ID,
+		WishlistID:  i.WishlistID,
+		Title:       i.Title,
+		Description: i.Description,
+		URL:         i.URL,
+		Price:       i.Amount().Major(),
+		PriceMinor:  i.PriceMinor,
+		Currency:    i.Currency,
+		Priority:    i.Priority,
+		Quantity:    i.Quantity,
+		Position:    i.Position,
+		ImageURL:    MediaURL(i.ImageKey),
+		ImageAlt:    i.ImageAlt,
+		ImageSizes:  MediaSizeURLs(i.ImageKey),
+		CreatedAt:   i.CreatedAt,
+		UpdatedAt:   i.UpdatedAt,
+
+		ReceivedQuantity: i.ReceivedQuantity,
+
+		Category: i.Category,
+		Tags:     tags,
+
+		Translation:  i.Translation,
+		Translations: i.Translations,
+	}
+}
+
+// Localize shows the title and description in the viewer's preferred language when the item is
+// translated to it; original is the language of the wishlist. A translation without a description
+// keeps the original one. Localized items are for responses only and must not be saved.
+func (i *WishItem) Localize(preferences []language.Tag, original string) {
+	if name, translation, ok := i.Translations.Pick(preferences, original); ok {
+		i.Title = translation.Title
+		if translation.Description != "" {
+			i.Description = translation.Description
+		}
+		i.Translation = name
+	}
+}
+
+// Amount returns the price of the item shown in the response
+func (r *WishItemResponse) Amount() currency.Amount {
+	return currency.Amount{Minor: r.PriceMinor, Currency: r.Currency}
+}
+
+// ToGiverResponse converts a WishItem to a WishItemResponse for a giver, given the state of its
+// reservations, nil when there are none, and the contributions to the item, if priced.
+// Gift preferences are only included once the giver has reserved or pledged towards the item.
+func (i *WishItem) ToGiverResponse(reservation *WishItemReservationState, contributions *WishItemContributionState) *WishItemResponse {
+	if reservation == nil {
+		reservation = &WishItemReservationState{}
+	}
+	reservation.FullyReserved = reservation.Reserved >= i.StillWished()
+
+	response := i.ToPublicResponse()
+	if reservation.Mine > 0 || (contributions != nil && contributions.Mine != nil) {
+		response = i.ToResponse()
+	}
+	response.Reservation = reservation
+	response.Contributions = contributions
+	return response
+}
+
+// StillWished returns the units the owner has not received yet
+func (i *WishItem) StillWished() int {
+	return max(i.Quantity-i.ReceivedQuantity, 0)
+}
+
+// BeforeCreate sets the CreatedAt and UpdatedAt fields before creating a new wish item
+func (i *WishItem) BeforeCreate() {
+	now := time.Now().UTC()
+	i.CreatedAt = now
+	i.UpdatedAt = now
+}
+
+// BeforeUpdate updates the UpdatedAt field before updating an existing wish item
+func (i *WishItem) BeforeUpdate() {
+	i.UpdatedAt = time.Now().UTC()
+}
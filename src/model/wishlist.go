@@ -0,0 +1,442 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Wishlist represents a wishlist owned by a user
+type Wishlist struct {
+	ID         int                `json:"-" db:"id"`
+	PublicID   string             `json:"public_id" db:"public_id"`
+	UserID     int                `json:"-" db:"user_id"`
+	Title      string             `json:"title" db:"title"`
+	Visibility WishlistVisibility `json:"visibility" db:"visibility"`
+	CreatedAt  time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" db:"updated_at"`
+
+	// Language is the language of the title and items, empty when not given
+	Language     string       `json:"language" db:"language"`
+	Translations Translations `json:"translations,omitempty" db:"translations"`
+	// Translation is the language the title was localized to by Localize, empty for the original
+	Translation string `json:"translation,omitempty" db:"-"`
+
+	// ShareToken is only handed to the owner through the share link endpoints
+	ShareToken *string `json:"-" db:"share_token"`
+
+	// EventType and EventDate tell what the wishlist is for; a wishlist whose event is over is
+	// archived by the archival job
+	EventType WishlistEventType `json:"event_type" db:"event_type"`
+	EventDate *time.Time        `json:"event_date,omitempty" db:"event_date"`
+	// ArchivedAt is set once the event is over; archived wishlists take no more reservations
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+
+	// ShippingAddress is only revealed to givers who reserved an item shipping to the owner
+	ShippingAddress *ShippingAddress `json:"-" db:"shipping_address" pii:"true"`
+}
+
+// WishlistEventType represents the occasion a wishlist is for
+type WishlistEventType string
+
+// WishlistEventType constants
+const (
+	EventNone         WishlistEventType = ""
+	EventBirthday     WishlistEventType = "birthday"
+	EventWedding      WishlistEventType = "wedding"
+	EventBabyRegistry WishlistEventType = "baby_registry"
+	EventHoliday      WishlistEventType = "holiday"
+)
+
+// IsValid checks if the event type value is valid
+func (e WishlistEventType) IsValid() bool {
+	switch e {
+	case EventNone, EventBirthday, EventWedding, EventBabyRegistry, EventHoliday:
+		return true
+	}
+	return false
+}
+
+// EventDateLayout is the format of event dates in requests and responses
+const EventDateLayout = "2006-01-02"
+
+// ErrWishlistArchived is returned when giving towards a wishlist whose event is over
+var ErrWishlistArchived = errors.New("wishlist is archived")
+
+// WishlistVisibility represents who may view a wishlist
+type WishlistVisibility string
+
+// WishlistVisibility constants
+const (
+	// VisibilityPrivate limits the wishlist to its owner and the groups it is shared to
+	VisibilityPrivate WishlistVisibility = "private"
+	// VisibilityFriends opens the wishlist to users the owner mutually follows
+	VisibilityFriends WishlistVisibility = "friends"
+	// VisibilityLinkOnly opens the wishlist to anyone holding its share link
+	VisibilityLinkOnly WishlistVisibility = "link-only"
+	// VisibilityPublic opens the wishlist to everyone, including anonymous visitors
+	VisibilityPublic WishlistVisibility = "public"
+)
+
+// IsValid checks if the visibility value is valid
+func (v WishlistVisibility) IsValid() bool {
+	switch v {
+	case VisibilityPrivate, VisibilityFriends, VisibilityLinkOnly, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// WishlistRepository defines the interface for wishlist data operations
+type WishlistRepository interface {
+	Create(wishlist *Wishlist) error
+	GetByID(id int) (*Wishlist, error)
+	GetByPublicID(publicID string) (*Wishlist, error)
+	ListByUser(userID int, opts ListOptions) ([]*Wishlist, *PageInfo, error)
+	ListVisibleByUser(ownerID, viewerID int, visibilities []WishlistVisibility, opts ListOptions) ([]*Wishlist, *PageInfo, error)
+	GetByShareToken(token string) (*Wishlist, error)
+	Update(wishlist *Wishlist) error
+	SetShareToken(id int, token *string) error
+	Delete(id int) error
+	ArchiveEnded(before time.Time, archivedAt time.Time) (int64, error)
+}
+
+// WishlistCreateRequest represents the request structure for creating a wishlist
+type WishlistCreateRequest struct {
+	Title        string       `json:"title" binding:"required,max=100"`
+	Visibility   string       `json:"visibility"`
+	Language     string       `json:"language"`
+	Translations Translations `json:"translations"`
+
+	EventType       string           `json:"event_type"`
+	EventDate       string           `json:"event_date"`
+	ShippingAddress *ShippingAddress `json:"shipping_address" pii:"true"`
+}
+
+// WishlistRenameRequest represents the request structure for renaming a wishlist
+type WishlistRenameRequest struct {
+	ID    string `json:"id" binding:"required"`
+	Title string `json:"title" binding:"required,max=100"`
+}
+
+// WishlistVisibilityRequest represents the request structure for changing a wishlist's visibility
+type WishlistVisibilityRequest struct {
+	ID         string `json:"id" binding:"required"`
+	Visibility string `json:"visibility" binding:"required"`
+}
+
+// WishlistPatch represents a JSON Merge Patch of a wishlist; a null visibility resets it to private.
+// Translations are replaced as a whole, null removes them.
+type WishlistPatch struct {
+	Title        Optional[string]             `json:"title"`
+	Visibility   Optional[WishlistVisibility] `json:"visibility"`
+	Language     Optional[string]             `json:"language"`
+	Translations Optional[Translations]       `json:"translations"`
+
+	// Null clears the event type, the event date and the shipping address
+	EventType       Optional[WishlistEventType] `json:"event_type"`
+	EventDate       Optional[string]            `json:"event_date"`
+	ShippingAddress Optional[*ShippingAddress]  `json:"shipping_address" pii:"true"`
+}
+
+// WishlistShareLinkRequest represents the request structure for regenerating or revoking a share link
+type WishlistShareLinkRequest struct {
+	ID string `json:"id" binding:"required"`
+}
+
+// WishlistShareLinkResponse represents the share link of a wishlist
+type WishlistShareLinkResponse struct {
+	WishlistID string `json:"wishlist_id"`
+	Token      string `json:"token"`
+	Path       string `json:"path"`
+}
+
+// WishlistDeleteRequest represents the request structure for deleting a wishlist
+type WishlistDeleteRequest struct {
+	ID string `json:"id" binding:"required"`
+}
+
+// WishlistResponse represents the response structure for wishlist data
+type WishlistResponse struct {
+	ID         int                `json:"-"`
+	PublicID   string             `json:"public_id"`
+	UserID     int                `json:"-"`
+	Title      string             `json:"title"`
+	Visibility WishlistVisibility `json:"visibility"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+
+	Language     string       `json:"language,omitempty"`
+	Translation  string       `json:"translation,omitempty"`
+	Translations Translations `json:"translations,omitempty"`
+
+	EventType  WishlistEventType `json:"event_type,omitempty"`
+	EventDate  string            `json:"event_date,omitempty"`
+	ArchivedAt *time.Time        `json:"archived_at,omitempty"`
+
+	// ShippingAddress is only shown to the owner and editors
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty" pii:"true"`
+
+	Reactions map[ReactionKind]int `json:"reactions,omitempty"`
+}
+
+// PublicWishlist is the part of a public wishlist shown through the public API, identified by
+// its public ID only
+type PublicWishlist struct {
+	PublicID     string       `json:"public_id"`
+	Title        string       `json:"title"`
+	Language     string       `json:"language,omitempty"`
+	Translation  string       `json:"translation,omitempty"`
+	Translations Translations `json:"translations,omitempty"`
+	EventType    string       `json:"event_type,omitempty"`
+	EventDate    string       `json:"event_date,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// Wishlist validation constants
+const (
+	WishlistTitleMaxLength = 100
+)
+
+// Validate validates the WishlistCreateRequest fields
+func (wcr *WishlistCreateRequest) Validate() error {
+	if err := validateWishlistTitle(wcr.Title); err != nil {
+		return fmt.Errorf("title validation failed: %w", err)
+	}
+	if wcr.Visibility != "" {
+		if err := validateWishlistVisibility(wcr.Visibility); err != nil {
+			return fmt.Errorf("visibility validation failed: %w", err)
+		}
+	}
+	if wcr.Language != "" {
+		tag, err := ParseLanguage(wcr.Language)
+		if err != nil {
+			return fmt.Errorf("language validation failed: %w", err)
+		}
+		wcr.Language = tag
+	}
+	translations, err := wcr.Translations.Normalize(WishlistTitleMaxLength, 0)
+	if err != nil {
+		return fmt.Errorf("translations validation failed: %w", err)
+	}
+	wcr.Translations = translations
+	return validateWishlistEvent(WishlistEventType(wcr.EventType), wcr.EventDate, wcr.ShippingAddress)
+}
+
+// Validate validates the WishlistRenameRequest fields
+func (wrr *WishlistRenameRequest) Validate() error {
+	if err := validateWishlistTitle(wrr.Title); err != nil {
+		return fmt.Errorf("title validation failed: %w", err)
+	}
+	return nil
+}
+
+// Validate validates the WishlistVisibilityRequest fields
+func (wvr *WishlistVisibilityRequest) Validate() error {
+	if err := validateWishlistVisibility(wvr.Visibility); err != nil {
+		return fmt.Errorf("visibility validation failed: %w", err)
+	}
+	return nil
+}
+
+// Validate validates the WishlistPatch fields
+func (wp *WishlistPatch) Validate() error {
+	if err := requireNotNull("title", wp.Title); err != nil {
+		return err
+	}
+	if wp.Title.HasValue() {
+		if err := validateWishlistTitle(wp.Title.Value); err != nil {
+			return fmt.Errorf("title validation failed: %w", err)
+		}
+	}
+	if wp.Visibility.HasValue() {
+		if err := validateWishlistVisibility(string(wp.Visibility.Value)); err != nil {
+			return fmt.Errorf("visibility validation failed: %w", err)
+		}
+	}
+	if wp.Language.HasValue() && wp.Language.Value != "" {
+		tag, err := ParseLanguage(wp.Language.Value)
+		if err != nil {
+			return fmt.Errorf("language validation failed: %w", err)
+		}
+		wp.Language.Value = tag
+	}
+	if wp.Translations.HasValue() {
+		translations, err := wp.Translations.Value.Normalize(WishlistTitleMaxLength, 0)
+		if err != nil {
+			return fmt.Errorf("translations validation failed: %w", err)
+		}
+		wp.Translations.Value = translations
+	}
+	return validateWishlistEvent(wp.EventType.Value, wp.EventDate.Value, wp.ShippingAddress.Value)
+}
+
+// ApplyTo merges the patch into a wishlist
+func (wp *WishlistPatch) ApplyTo(wishlist *Wishlist) {
+	wp.Title.Apply(&wishlist.Title)
+	wp.Visibility.Apply(&wishlist.Visibility)
+	wp.Language.Apply(&wishlist.Language)
+	wp.Translations.Apply(&wishlist.Translations)
+	wp.EventType.Apply(&wishlist.EventType)
+	wp.ShippingAddress.Apply(&wishlist.ShippingAddress)
+	if wishlist.Visibility == "" {
+		wishlist.Visibility = VisibilityPrivate
+	}
+
+	// A wishlist moved to another date is no longer over; the archival job archives it again
+	// if the new date is past too
+	if wp.EventDate.Set {
+		wishlist.EventDate = parseEventDate(wp.EventDate.Value)
+		wishlist.ArchivedAt = nil
+	}
+}
+
+// SetEvent sets the event of a new wishlist from validated request fields
+func (w *Wishlist) SetEvent(eventType, eventDate string, address *ShippingAddress) {
+	w.EventType = WishlistEventType(eventType)
+	w.EventDate = parseEventDate(eventDate)
+	w.ShippingAddress = address
+}
+
+// validateWishlistEvent validates the event type, the event date, in EventDateLayout, and the
+// shipping address of a wishlist, normalizing the address
+func validateWishlistEvent(eventType WishlistEventType, eventDate string, address *ShippingAddress) error {
+	if !eventType.IsValid() {
+		return fmt.Errorf("event_type validation failed: event_type must be one of: %s, %s, %s, %s",
+			EventBirthday, EventWedding, EventBabyRegistry, EventHoliday)
+	}
+	if eventDate != "" {
+		if _, err := time.Parse(EventDateLayout, eventDate); err != nil {
+			return errors.New("event_date validation failed: event_date must be a date in YYYY-MM-DD format")
+		}
+	}
+	if address != nil {
+		return address.Normalize()
+	}
+	return nil
+}
+
+// parseEventDate parses a validated event date, nil when empty
+func parseEventDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := time.Parse(EventDateLayout, value)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+// formatEventDate formats an event date in EventDateLayout, empty when there is none
+func formatEventDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(EventDateLayout)
+}
+
+// IsArchived reports whether the wishlist was archived after its event
+func (w *Wishlist) IsArchived() bool {
+	return w.ArchivedAt != nil
+}
+
+// validateWishlistVisibility validates the wishlist visibility field
+func validateWishlistVisibility(visibility string) error {
+	if !WishlistVisibility(visibility).IsValid() {
+		return fmt.Errorf("visibility must be one of: %s, %s, %s, %s",
+			VisibilityPrivate, VisibilityFriends, VisibilityLinkOnly, VisibilityPublic)
+	}
+	return nil
+}
+
+// validateWishlistTitle validates the wishlist title field
+func validateWishlistTitle(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return errors.New("title is required")
+	}
+
+	if len(title) > WishlistTitleMaxLength {
+		return errors.New("title is too long")
+	}
+
+	return nil
+}
+
+// IsOwnedBy reports whether the wishlist belongs to the given user
+func (w *Wishlist) IsOwnedBy(userID int) bool {
+	return w.UserID == userID
+}
+
+// ToResponse converts a Wishlist to a WishlistResponse
+func (w *Wishlist) ToResponse() *WishlistResponse {
+	return &WishlistResponse{
+		ID:         w.ID,
+		PublicID:   w.PublicID,
+		UserID:     w.UserID,
+		Title:      w.Title,
+		Visibility: w.Visibility,
+		CreatedAt:  w.CreatedAt,
+		UpdatedAt:  w.UpdatedAt,
+
+		Language:     w.Language,
+		Translation:  w.Translation,
+		Translations: w.Translations,
+
+		EventType:  w.EventType,
+		EventDate:  formatEventDate(w.EventDate),
+		ArchivedAt: w.ArchivedAt,
+	}
+}
+
+// ToOwnerResponse converts a Wishlist to a WishlistResponse including the shipping address, for
+// the owner and editors
+func (w *Wishlist) ToOwnerResponse() *WishlistResponse {
+	response := w.ToResponse()
+	response.ShippingAddress = w.ShippingAddress
+	return response
+}
+
+// ToPublicWishlist converts a Wishlist to a PublicWishlist, leaving out the IDs and visibility
+func (w *Wishlist) ToPublicWishlist() *PublicWishlist {
+	return &PublicWishlist{
+		PublicID:     w.PublicID,
+		Title:        w.Title,
+		Language:     w.Language,
+		Translation:  w.Translation,
+		Translations: w.Translations,
+		EventType:    string(w.EventType),
+		EventDate:    formatEventDate(w.EventDate),
+		CreatedAt:    w.CreatedAt,
+		UpdatedAt:    w.UpdatedAt,
+	}
+}
+
+// Localize shows the title in the viewer's preferred language when the wishlist is translated to it.
+// Localized wishlists are for responses only and must not be saved.
+func (w *Wishlist) Localize(preferences []language.Tag) {
+	if name, translation, ok := w.Translations.Pick(preferences, w.Language); ok {
+		w.Title = translation.Title
+		w.Translation = name
+	}
+}
+
+// BeforeCreate sets the CreatedAt and UpdatedAt fields before creating a new wishlist,
+// defaulting new wishlists to private
+func (w *Wishlist) BeforeCreate() {
+	if w.Visibility == "" {
+		w.Visibility = VisibilityPrivate
+	}
+	now := time.Now().UTC()
+	w.CreatedAt = now
+	w.UpdatedAt = now
+}
+
+// BeforeUpdate updates the UpdatedAt field before updating an existing wishlist
+func (w *Wishlist) BeforeUpdate() {
+	w.UpdatedAt = time.Now().UTC()
+}
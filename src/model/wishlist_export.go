@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/alex-1900/wishlist/src/currency"
+)
+
+// WishlistExportFormat is the file format of a wishlist export
+type WishlistExportFormat string
+
+// WishlistExportFormat constants
+const (
+	ExportCSV WishlistExportFormat = "csv"
+	ExportPDF WishlistExportFormat = "pdf"
+)
+
+// WishlistExportRequest represents the query of a wishlist export, CSV by default
+type WishlistExportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv pdf"`
+}
+
+// csvEscapedStart lists the first characters of the values CSVRecord prefixes with an apostrophe:
+// those spreadsheets would run as formulas, and the apostrophe itself, which spreadsheets hide
+const csvEscapedStart = "=+-@\t\r'"
+
+// CSVRecord returns the values of the item in the order of WishItemCSVColumns. The price is in
+// major units, empty when the item has none, and tags are separated by semicolons. Values that
+// spreadsheets would run as formulas, or that start with an apostrophe, are prefixed with an
+// apostrophe, which ParseImportCSV removes.
+func (i *WishItem) CSVRecord() []string {
+	price := ""
+	if i.PriceMinor > 0 {
+		price = strconv.FormatFloat(currency.FromMinor(i.PriceMinor, i.Currency), 'f', currency.Exponent(i.Currency), 64)
+	}
+
+	record := []string{
+		i.Title,
+		i.URL,
+		i.Description,
+		price,
+		i.Currency,
+		strconv.Itoa(i.Quantity),
+		strconv.Itoa(i.Priority),
+		string(i.Category),
+		strings.Join(i.Tags, ";"),
+	}
+	for n, value := range record {
+		if value != "" && strings.ContainsRune(csvEscapedStart, rune(value[0])) {
+			record[n] = "'" + value
+		}
+	}
+	return record
+}
+
+// unescapeCSVValue removes the apostrophe CSVRecord prefixes to a value
+func unescapeCSVValue(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvEscapedStart, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
@@ -0,0 +1,298 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WishlistImportSource is where the items of an import come from
+type WishlistImportSource string
+
+// WishlistImportSource constants
+const (
+	ImportSourceCSV    WishlistImportSource = "csv"
+	ImportSourceAmazon WishlistImportSource = "amazon"
+)
+
+// WishlistImportStatus is the progress of an import
+type WishlistImportStatus string
+
+// WishlistImportStatus constants
+const (
+	ImportPending   WishlistImportStatus = "pending"
+	ImportRunning   WishlistImportStatus = "running"
+	ImportCompleted WishlistImportStatus = "completed"
+	ImportFailed    WishlistImportStatus = "failed"
+)
+
+// ErrImportInProgress is returned when starting an import while another one of the user is
+// pending or running
+var ErrImportInProgress = errors.New("another import is still in progress")
+
+// ImportStaleAfter is how long an import may run before it is taken for abandoned by a stopped
+// instance and resumed by another one, from its last processed row
+const ImportStaleAfter = time.Hour
+
+// WishlistImport is the import of items into a wishlist, run in the background. Payload holds
+// the uploaded CSV until the import finishes; Amazon imports read SourceURL instead.
+type WishlistImport struct {
+	ID            int                  `json:"id" db:"id"`
+	UserID        int                  `json:"-" db:"user_id"`
+	WishlistID    int                  `json:"-" db:"wishlist_id"`
+	Source        WishlistImportSource `json:"source" db:"source"`
+	SourceURL     string               `json:"source_url" db:"source_url"`
+	Payload       string               `json:"-" db:"payload"`
+	Status        WishlistImportStatus `json:"status" db:"status"`
+	TotalRows     int                  `json:"total_rows" db:"total_rows"`
+	ProcessedRows int                  `json:"processed_rows" db:"processed_rows"`
+	ImportedRows  int                  `json:"imported_rows" db:"imported_rows"`
+	RowErrors     ImportRowErrors      `json:"row_errors" db:"row_errors"`
+	Error         string               `json:"error" db:"error"`
+	CreatedAt     time.Time            `json:"created_at" db:"created_at"`
+	StartedAt     *time.Time           `json:"started_at" db:"started_at"`
+	FinishedAt    *time.Time           `json:"finished_at" db:"finished_at"`
+}
+
+// ImportRowError is the reason a row of an import was not imported. Row is the line of the CSV
+// file, or the position of the item on the Amazon wish list.
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ImportRowErrors are the errors of the rows of an import, stored as a JSONB array
+type ImportRowErrors []ImportRowError
+
+// Value implements driver.Valuer for the JSONB column
+func (e ImportRowErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner for the JSONB column
+func (e *ImportRowErrors) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*e = ImportRowErrors{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ImportRowErrors", value)
+	}
+
+	errs := ImportRowErrors{}
+	if err := json.Unmarshal(data, &errs); err != nil {
+		return err
+	}
+	*e = errs
+	return nil
+}
+
+// WishlistImportRepository defines the interface for wishlist import data operations
+type WishlistImportRepository interface {
+	Create(imp *WishlistImport, wishlist *Wishlist) error
+	GetByID(id int) (*WishlistImport, error)
+	ClaimNext(now, staleBefore time.Time) (*WishlistImport, error)
+	UpdateProgress(imp *WishlistImport) error
+	Finish(imp *WishlistImport) error
+}
+
+// WishlistImportRequest represents the form of an import: the CSV file is uploaded in the "file"
+// field, or url names a public Amazon wish list. Items go to the wishlist wishlist_id, or to a
+// new private wishlist named title.
+type WishlistImportRequest struct {
+	WishlistID string `form:"wishlist_id" json:"wishlist_id"`
+	Title      string `form:"title" json:"title"`
+	URL        string `form:"url" json:"url" binding:"omitempty,max=2048"`
+}
+
+// Validate validates the WishlistImportRequest fields
+func (r *WishlistImportRequest) Validate() error {
+	if r.WishlistID == "" {
+		if err := validateWishlistTitle(r.Title); err != nil {
+			return fmt.Errorf("title validation failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// WishlistImportStatusRequest represents the route parameter of the status of an import
+type WishlistImportStatusRequest struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+// WishlistImportResponse represents the response structure for the progress of an import.
+// WishlistID is the public ID of the wishlist.
+type WishlistImportResponse struct {
+	ID            int                  `json:"id"`
+	WishlistID    string               `json:"wishlist_id"`
+	Source        WishlistImportSource `json:"source"`
+	SourceURL     string               `json:"source_url,omitempty"`
+	Status        WishlistImportStatus `json:"status"`
+	TotalRows     int                  `json:"total_rows"`
+	ProcessedRows int                  `json:"processed_rows"`
+	ImportedRows  int                  `json:"imported_rows"`
+	RowErrors     ImportRowErrors      `json:"row_errors"`
+	Error         string               `json:"error,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	StartedAt     *time.Time           `json:"started_at"`
+	FinishedAt    *time.Time           `json:"finished_at"`
+}
+
+// ToResponse converts a WishlistImport into its wishlist, named by its public ID, to a
+// WishlistImportResponse
+func (i *WishlistImport) ToResponse(wishlistPublicID string) *WishlistImportResponse {
+	rowErrors := i.RowErrors
+	if rowErrors == nil {
+		rowErrors = ImportRowErrors{}
+	}
+	return &WishlistImportResponse{
+		ID:            i.ID,
+		WishlistID:    wishlistPublicID,
+		Source:        i.Source,
+		SourceURL:     i.SourceURL,
+		Status:        i.Status,
+		TotalRows:     i.TotalRows,
+		ProcessedRows: i.ProcessedRows,
+		ImportedRows:  i.ImportedRows,
+		RowErrors:     rowErrors,
+		Error:         i.Error,
+		CreatedAt:     i.CreatedAt,
+		StartedAt:     i.StartedAt,
+		FinishedAt:    i.FinishedAt,
+	}
+}
+
+// BeforeCreate queues the import
+func (i *WishlistImport) BeforeCreate() {
+	i.Status = ImportPending
+	i.RowErrors = ImportRowErrors{}
+	i.CreatedAt = time.Now().UTC()
+}
+
+// ImportRow is a row of an import: the item it describes, or why it could not be read
+type ImportRow struct {
+	Row  int
+	Item *WishItemCreateRequest
+	Err  error
+}
+
+// WishItemCSVColumns are the columns of wish items in CSV files, read by imports and written by
+// exports, so an exported file can be imported again
+var WishItemCSVColumns = []string{"title", "url", "description", "price", "currency", "quantity", "priority", "category", "tags"}
+
+// ParseImportCSV reads the rows of an import CSV. The first line names the columns, in any order
+// and case: title, url, description, price, currency, quantity, priority, category and tags,
+// separated by semicolons; other columns are ignored and a title or url column is required.
+// Blank lines are skipped, and the apostrophe exports put before values spreadsheets would run as
+// formulas is removed. A row whose values cannot be read gets an error instead of an item; only a
+// file that is not CSV fails as a whole.
+func ParseImportCSV(data string) ([]*ImportRow, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff")))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("the file is empty")
+	} else if err != nil {
+		return nil, fmt.Errorf("the file is not valid CSV: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for _, known := range WishItemCSVColumns {
+			if name == known {
+				if _, seen := columns[name]; !seen {
+					columns[name] = i
+				}
+			}
+		}
+	}
+	_, hasTitle := columns["title"]
+	_, hasURL := columns["url"]
+	if !hasTitle && !hasURL {
+		return nil, errors.New("the first line must name a title or url column")
+	}
+
+	var rows []*ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("the file is not valid CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return unescapeCSVValue(strings.TrimSpace(record[i]))
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		item, err := parseImportRecord(value)
+		rows = append(rows, &ImportRow{Row: line, Item: item, Err: err})
+	}
+}
+
+// parseImportRecord builds the item of a CSV row from the values of its columns
+func parseImportRecord(value func(column string) string) (*WishItemCreateRequest, error) {
+	item := &WishItemCreateRequest{
+		Title:       value("title"),
+		URL:         value("url"),
+		Description: value("description"),
+		Currency:    strings.ToUpper(value("currency")),
+		Category:    strings.ToLower(value("category")),
+	}
+
+	if price := value("price"); price != "" {
+		parsed, err := strconv.ParseFloat(price, 64)
+		if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+			return nil, errors.New("price is not a number")
+		}
+		item.Price = parsed
+	}
+	if quantity := value("quantity"); quantity != "" {
+		parsed, err := strconv.Atoi(quantity)
+		if err != nil || parsed < 1 {
+			return nil, errors.New("quantity must be a whole number of at least 1")
+		}
+		item.Quantity = parsed
+	}
+	if priority := value("priority"); priority != "" {
+		parsed, err := strconv.Atoi(priority)
+		if err != nil {
+			return nil, errors.New("priority is not a whole number")
+		}
+		item.Priority = parsed
+	}
+	if tags := value("tags"); tags != "" {
+		item.Tags = strings.Split(tags, ";")
+	}
+
+	return item, nil
+}
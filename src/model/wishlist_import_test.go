@@ -0,0 +1,176 @@
+package model_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		want     []*model.WishItemCreateRequest
+		wantRows []int
+		wantErrs []bool
+		wantErr  bool
+	}{
+		{
+			name: "columns in any order and case",
+			data: "\ufeffPrice,Title,Tags,Quantity\n12.50,Book,a;b,2\n",
+			want: []*model.WishItemCreateRequest{
+				{Title: "Book", Price: 12.5, Quantity: 2, Tags: []string{"a", "b"}},
+			},
+			wantRows: []int{2},
+			wantErrs: []bool{false},
+		},
+		{
+			name: "blank lines are skipped and unknown columns ignored",
+			data: "title,notes\nLamp,x\n\n,\nMug,y\n",
+			want: []*model.WishItemCreateRequest{
+				{Title: "Lamp"},
+				{Title: "Mug"},
+			},
+			wantRows: []int{2, 5},
+			wantErrs: []bool{false, false},
+		},
+		{
+			name: "the apostrophe before a formula is removed once",
+			data: "title,description,url,tags\n'=SUM(A1),'+1,'@home,'-x\n''=x,'plain,'\tx,'\n",
+			want: []*model.WishItemCreateRequest{
+				{Title: "=SUM(A1)", Description: "+1", URL: "@home", Tags: []string{"-x"}},
+				{Title: "'=x", Description: "'plain", URL: "\tx", Tags: []string{"'"}},
+			},
+			wantRows: []int{2, 3},
+			wantErrs: []bool{false, false},
+		},
+		{
+			name:     "rows with unreadable values get an error",
+			data:     "title,price,quantity\nBad price,abc,1\nBad quantity,1,0\nFine,,\n",
+			want:     []*model.WishItemCreateRequest{nil, nil, {Title: "Fine"}},
+			wantRows: []int{2, 3, 4},
+			wantErrs: []bool{true, true, false},
+		},
+		{
+			name:    "empty file",
+			data:    "",
+			wantErr: true,
+		},
+		{
+			name:    "no title or url column",
+			data:    "price,currency\n1,EUR\n",
+			wantErr: true,
+		},
+		{
+			name:    "not CSV",
+			data:    "title\n\"unterminated\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := model.ParseImportCSV(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseImportCSV() = %d rows, want an error", len(rows))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseImportCSV() error = %v", err)
+			}
+
+			if len(rows) != len(tt.want) {
+				t.Fatalf("ParseImportCSV() = %d rows, want %d", len(rows), len(tt.want))
+			}
+			for i, row := range rows {
+				if row.Row != tt.wantRows[i] {
+					t.Errorf("row %d: line = %d, want %d", i, row.Row, tt.wantRows[i])
+				}
+				if (row.Err != nil) != tt.wantErrs[i] {
+					t.Errorf("row %d: error = %v, want error %t", i, row.Err, tt.wantErrs[i])
+				}
+				if !reflect.DeepEqual(row.Item, tt.want[i]) {
+					t.Errorf("row %d: item = %+v, want %+v", i, row.Item, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCSVRecordRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		item *model.WishItem
+	}{
+		{
+			name: "plain values",
+			item: &model.WishItem{
+				Title: "Headphones", URL: "https://example.org/headphones", Description: "Over-ear",
+				PriceMinor: 12999, Currency: "EUR", Quantity: 1, Priority: 3,
+				Category: "electronics", Tags: []string{"music", "travel"},
+			},
+		},
+		{
+			name: "values spreadsheets would run as formulas",
+			item: &model.WishItem{
+				Title: "=HYPERLINK(\"http://evil\")", URL: "@example", Description: "+1 for blue",
+				Currency: "USD", Quantity: 2, Priority: 1, Tags: []string{"-sale", "=x"},
+			},
+		},
+		{
+			name: "values starting with an apostrophe",
+			item: &model.WishItem{
+				Title: "'=kept", Description: "'quoted'", Currency: "JPY", PriceMinor: 1500, Quantity: 1, Priority: 5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer := csv.NewWriter(&buf)
+			writer.Write(model.WishItemCSVColumns)
+			writer.Write(tt.item.CSVRecord())
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				t.Fatalf("failed to write CSV: %v", err)
+			}
+
+			rows, err := model.ParseImportCSV(buf.String())
+			if err != nil {
+				t.Fatalf("ParseImportCSV() error = %v", err)
+			}
+			if len(rows) != 1 || rows[0].Err != nil {
+				t.Fatalf("ParseImportCSV() = %+v, want one item", rows)
+			}
+
+			got := rows[0].Item
+			want := tt.item
+			if got.Title != want.Title || got.URL != want.URL || got.Description != want.Description {
+				t.Errorf("text = %q, %q, %q, want %q, %q, %q", got.Title, got.URL, got.Description, want.Title, want.URL, want.Description)
+			}
+			if got.Currency != want.Currency || got.Quantity != want.Quantity || got.Priority != want.Priority {
+				t.Errorf("currency, quantity, priority = %s, %d, %d, want %s, %d, %d",
+					got.Currency, got.Quantity, got.Priority, want.Currency, want.Quantity, want.Priority)
+			}
+			if got.Category != string(want.Category) || !reflect.DeepEqual(got.Tags, want.Tags) {
+				t.Errorf("category, tags = %q, %q, want %q, %q", got.Category, got.Tags, want.Category, want.Tags)
+			}
+			if minor := wishItemPriceMinor(got); minor != want.PriceMinor {
+				t.Errorf("price = %d minor units, want %d", minor, want.PriceMinor)
+			}
+		})
+	}
+}
+
+// wishItemPriceMinor returns the price of an imported item in minor units, as it would be saved
+func wishItemPriceMinor(req *model.WishItemCreateRequest) int64 {
+	item := &model.WishItem{Currency: req.Currency}
+	item.SetPrices(req.Price, nil)
+	return item.PriceMinor
+}
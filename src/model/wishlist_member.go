@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// WishlistRole represents the role of a user sharing the ownership of a wishlist
+type WishlistRole string
+
+// WishlistRole constants
+const (
+	// WishlistRoleOwner manages the wishlist, its items and its members like its creator, who
+	// alone may delete it
+	WishlistRoleOwner WishlistRole = "owner"
+	// WishlistRoleEditor adds, edits and removes items
+	WishlistRoleEditor WishlistRole = "editor"
+)
+
+// IsValid checks if the role value is valid
+func (r WishlistRole) IsValid() bool {
+	return r == WishlistRoleOwner || r == WishlistRoleEditor
+}
+
+// CanManage reports whether the role may change the wishlist itself and its members
+func (r WishlistRole) CanManage() bool {
+	return r == WishlistRoleOwner
+}
+
+// CanEditItems reports whether the role may add, edit and remove items
+func (r WishlistRole) CanEditItems() bool {
+	return r == WishlistRoleOwner || r == WishlistRoleEditor
+}
+
+// WishlistMember represents a user sharing the ownership of a wishlist, or invited to.
+// Members see the wishlist like its creator, so reservations and contributions stay hidden.
+type WishlistMember struct {
+	WishlistID int          `json:"-" db:"wishlist_id"`
+	UserID     int          `json:"-" db:"user_id"`
+	Username   string       `json:"username" db:"username"`
+	Role       WishlistRole `json:"role" db:"role"`
+	InvitedBy  *int         `json:"-" db:"invited_by"`
+	InvitedAt  time.Time    `json:"invited_at" db:"invited_at"`
+	AcceptedAt *time.Time   `json:"accepted_at" db:"accepted_at"`
+
+	// Creator marks the user who created the wishlist, listed first with the owner role
+	Creator bool `json:"creator,omitempty" db:"-"`
+}
+
+// WishlistInvitation is a pending invitation to share the ownership of a wishlist, named by its
+// public ID
+type WishlistInvitation struct {
+	WishlistID        string       `json:"wishlist_id"`
+	WishlistTitle     string       `json:"wishlist_title"`
+	Role              WishlistRole `json:"role"`
+	InvitedByUsername string       `json:"invited_by_username,omitempty"`
+	InvitedAt         time.Time    `json:"invited_at"`
+}
+
+// WishlistMemberRepository defines the interface for wishlist member data operations
+type WishlistMemberRepository interface {
+	Invite(member *WishlistMember) error
+	Accept(wishlistID, userID int) error
+	Remove(wishlistID, userID int) error
+	GetRole(wishlistID, userID int) (WishlistRole, error)
+	ListByWishlist(wishlistID int) ([]*WishlistMember, error)
+	ListInvitations(userID int) ([]*WishlistInvitation, error)
+}
+
+// WishlistMemberInviteRequest represents the request structure for inviting a user to share the
+// ownership of a wishlist. Inviting a member again changes their role.
+type WishlistMemberInviteRequest struct {
+	WishlistID string `json:"wishlist_id" binding:"required"`
+	Username   string `json:"username" binding:"required"`
+	Role       string `json:"role" binding:"required"`
+}
+
+// WishlistMemberRequest represents the request structure for removing a member of a wishlist
+type WishlistMemberRequest struct {
+	WishlistID string `json:"wishlist_id" binding:"required"`
+	Username   string `json:"username" binding:"required"`
+}
+
+// WishlistInvitationRequest represents the request structure for accepting an invitation or
+// leaving a wishlist
+type WishlistInvitationRequest struct {
+	WishlistID string `json:"wishlist_id" binding:"required"`
+}
+
+// Validate validates the WishlistMemberInviteRequest fields
+func (r *WishlistMemberInviteRequest) Validate() error {
+	if !WishlistRole(r.Role).IsValid() {
+		return fmt.Errorf("role validation failed: role must be one of: %s, %s", WishlistRoleOwner, WishlistRoleEditor)
+	}
+	return nil
+}
+
+// BeforeCreate sets the InvitedAt field before inviting a member
+func (m *WishlistMember) BeforeCreate() {
+	m.InvitedAt = time.Now().UTC()
+}
@@ -0,0 +1,186 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/bruteforce"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// LockoutsResponse lists the accounts and addresses currently refused for failed logins
+type LockoutsResponse struct {
+	LockedAccounts []*model.LoginAttempt    `json:"locked_accounts"`
+	ThrottledIPs   []bruteforce.ThrottledIP `json:"throttled_ips"`
+}
+
+// ActionListLockouts returns the locked accounts and the IPs throttled by this instance
+func ActionListLockouts() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		locked, err := app.GetRepository().LoginAttempt().ListLocked(time.Now().UTC())
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve locked accounts", err))
+			return
+		}
+
+		throttled := app.GetBruteForceMonitor().Throttled()
+
+		response.OK(ctx, LockoutsResponse{
+			LockedAccounts: locked,
+			ThrottledIPs:   throttled,
+		}, response.Message(fmt.Sprintf("%d locked accounts, %d throttled IPs", len(locked), len(throttled))))
+	}
+}
+
+// ActionGetSiteSettings returns the site settings
+func ActionGetSiteSettings() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		settings, err := app.GetRepository().SiteSettings().Get()
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve site settings", err))
+			return
+		}
+
+		response.OK(ctx, settings, response.Message("Site settings retrieved successfully"))
+	}
+}
+
+// ActionUpdateSiteSettings applies a merge patch to the site settings
+func ActionUpdateSiteSettings() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.SiteSettingsPatch
+
+		// Bind JSON merge patch to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		settingsRepo := app.GetRepository().SiteSettings()
+
+		settings, err := settingsRepo.Get()
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve site settings", err))
+			return
+		}
+
+		req.Apply(settings)
+		now := time.Now().UTC()
+		settings.UpdatedBy = &userID
+		settings.UpdatedAt = &now
+
+		if err := settingsRepo.Update(settings); err != nil {
+			ctx.Error(apperror.Internal("Failed to update site settings", err))
+			return
+		}
+
+		response.OK(ctx, settings, response.Message("Site settings updated successfully"))
+	}
+}
+
+// ActionDeleteUser soft-deletes a user and signs them out everywhere. The user can be restored
+// until the next purge.
+func ActionDeleteUser() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req model.UserIDRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		user, err := app.GetRepository().User().GetByPublicID(req.UserID)
+		if errors.Is(err, model.ErrUserNotFound) {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve user", err))
+			return
+		}
+
+		// Deleting also ends their sessions: access tokens already issued are refused from now on
+		err = app.GetRepository().User().Delete(user.ID)
+		if errors.Is(err, model.ErrUserNotFound) {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to delete user", err))
+			return
+		}
+
+		// Their profile and wishlists may be cached by the CDN, all tagged with the user's key
+		app.GetCDN().Purge(cdn.UserKey(user.PublicID))
+
+		app.GetRealtimeHub().DisconnectUser(user.ID)
+
+		if err := app.GetRepository().RefreshToken().RevokeAllForUser(user.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to revoke sessions", err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("User deleted successfully"))
+	}
+}
+
+// ActionRestoreUser brings back a soft-deleted user that was not purged yet
+func ActionRestoreUser() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req model.UserIDRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		if err := app.GetRepository().User().Restore(req.UserID); err != nil {
+			ctx.Error(apperror.NotFound("Deleted user not found"))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("User restored successfully"))
+	}
+}
+
+// ActionPurgeDeletedUsers permanently removes the users soft-deleted before the cutoff
+func ActionPurgeDeletedUsers() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req model.UserPurgeRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		purged, err := app.GetRepository().User().Purge(req.Cutoff(time.Now().UTC()))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to purge deleted users", err))
+			return
+		}
+
+		response.OK(ctx, gin.H{"purged": purged}, response.Message(fmt.Sprintf("Purged %d deleted users", purged)))
+	}
+}
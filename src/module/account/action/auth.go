@@ -1,12 +1,16 @@
 package action
 
 import (
-	"net/http"
+	"errors"
+	"log"
+	"strconv"
 	"time"
 
 	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
 	"github.com/alex-1900/wishlist/src/auth"
 	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,10 +22,11 @@ type UserLoginRequest struct {
 
 // UserLoginResponse represents the response structure for user login
 type UserLoginResponse struct {
-	Token     string              `json:"token"`
-	User      *model.UserResponse `json:"user"`
-	ExpiresIn int64               `json:"expires_in"`
-	TokenType string              `json:"token_type"`
+	Token        string              `json:"token"`
+	RefreshToken string              `json:"refresh_token"`
+	User         *model.UserResponse `json:"user"`
+	ExpiresIn    int64               `json:"expires_in"`
+	TokenType    string              `json:"token_type"`
 }
 
 // ActionLogin handles user authentication
@@ -31,123 +36,316 @@ func ActionLogin() gin.HandlerFunc {
 
 		// Bind JSON request to struct
 		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request format",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.InvalidRequest(err))
 			return
 		}
 
-		// Get repository
-		userRepo := app.GetRepository().User()
-
-		// Find user by email
-		user, err := userRepo.GetByEmail(req.Email)
-		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid email or password",
-			})
+		user, ok := authenticateCredentials(ctx, req.Email, req.Password)
+		if !ok {
 			return
 		}
 
-		// Check password
-		if err := auth.CheckPassword(req.Password, user.PasswordHash); err != nil {
-			ctx.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid email or password",
-			})
-			return
+		respondWithSession(ctx, user)
+	}
+}
+
+// respondWithSession starts a session for a user who signed in, writing the login response
+// with a new access token and refresh token
+func respondWithSession(ctx *gin.Context, user *model.User) {
+	// Generate JWT token
+	token, err := app.GetJWTManager().GenerateToken(user.ID, user.Username, user.Email)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to generate authentication token", err))
+		return
+	}
+
+	// Issue a refresh token for obtaining new access tokens later
+	refreshToken, err := issueRefreshToken(user.ID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to generate refresh token", err))
+		return
+	}
+
+	// Return login response
+	response.OK(ctx, UserLoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         user.ToResponse(),
+		ExpiresIn:    int64(app.GetJWTManager().Duration().Seconds()),
+		TokenType:    "Bearer",
+	}, response.Message("Login successful"))
+}
+
+// authenticateCredentials checks an email and password, applying IP throttling, account
+// lockout and the email verification requirement. It writes the error response and returns
+// false when the login is refused.
+func authenticateCredentials(ctx *gin.Context, email, password string) (*model.User, bool) {
+	// Refuse IPs throttled for too many failed logins, whichever accounts they tried
+	monitor := app.GetBruteForceMonitor()
+	if retryAfter := monitor.RetryAfter(ctx.ClientIP()); retryAfter > 0 {
+		seconds := int(retryAfter.Seconds())
+		ctx.Header("Retry-After", strconv.Itoa(seconds))
+		ctx.Error(apperror.TooManyRequests("Too many failed logins from this address").
+			WithCode(apperror.CodeLoginThrottled).
+			With("retry_after", seconds))
+		return nil, false
+	}
+
+	// Get repository
+	userRepo := app.GetRepository().User()
+
+	// Find user by email
+	user, err := userRepo.GetByEmail(email)
+	if err != nil {
+		monitor.RecordFailure(ctx.ClientIP())
+		ctx.Error(apperror.Unauthorized("Invalid email or password").WithCode(apperror.CodeInvalidCredentials))
+		return nil, false
+	}
+
+	config := app.GetConfig()
+	attemptRepo := app.GetRepository().LoginAttempt()
+
+	// Refuse temporarily locked accounts before looking at the password
+	attempt, err := attemptRepo.Get(user.ID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to check login attempts", err))
+		return nil, false
+	}
+	if attempt.IsLocked(time.Now()) {
+		respondAccountLocked(ctx, attempt)
+		return nil, false
+	}
+
+	// Check password
+	if err := auth.CheckPassword(password, user.PasswordHash); err != nil {
+		monitor.RecordFailure(ctx.ClientIP())
+
+		lockout := config.LoginLockout
+		attempt, recordErr := attemptRepo.RecordFailure(
+			user.ID,
+			lockout.MaxFailures,
+			time.Duration(lockout.Window)*time.Minute,
+			time.Duration(lockout.Duration)*time.Minute,
+		)
+		if recordErr == nil && attempt.IsLocked(time.Now()) {
+			monitor.AccountLocked(user.ID, ctx.ClientIP(), *attempt.LockedUntil)
+			respondAccountLocked(ctx, attempt)
+			return nil, false
 		}
 
-		// Get JWT manager from app config
-		config := app.GetConfig()
-		jwtManager := auth.NewJWTManager(config.JWTSecret, time.Duration(config.JWTExpiration)*time.Hour)
+		ctx.Error(apperror.Unauthorized("Invalid email or password").WithCode(apperror.CodeInvalidCredentials))
+		return nil, false
+	}
 
-		// Generate JWT token
-		token, err := jwtManager.GenerateToken(user.ID, user.Username, user.Email)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to generate authentication token",
-				"details": err.Error(),
-			})
-			return
+	if attempt.Failures > 0 || attempt.LockedUntil != nil {
+		if err := attemptRepo.Reset(user.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to reset login attempts", err))
+			return nil, false
 		}
+	}
 
-		// Return login response
-		ctx.JSON(http.StatusOK, gin.H{
-			"message": "Login successful",
-			"data": UserLoginResponse{
-				Token:     token,
-				User:      user.ToResponse(),
-				ExpiresIn: int64(config.JWTExpiration * 3600), // Convert hours to seconds
-				TokenType: "Bearer",
-			},
-		})
+	// Optionally require a verified email address
+	if config.EmailVerification.RequireOnLogin && !user.IsEmailVerified() {
+		ctx.Error(apperror.Forbidden("Email address has not been verified").WithCode(apperror.CodeEmailNotVerified))
+		return nil, false
 	}
+
+	return user, true
 }
 
-// ActionLogout handles user logout (placeholder for token blacklisting)
+// respondAccountLocked rejects a login to a temporarily locked account with retry information
+func respondAccountLocked(ctx *gin.Context, attempt *model.LoginAttempt) {
+	retryAfter := int(attempt.RetryAfter(time.Now()).Seconds())
+	ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+	ctx.Error(apperror.Locked("Account temporarily locked after too many failed logins").
+		WithCode(apperror.CodeAccountLocked).
+		With("retry_after", retryAfter))
+}
+
+// ActionLogout revokes the current access token and the given refresh token,
+// or every refresh token of the user when none is given
 func ActionLogout() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		// In a real implementation, you would:
-		// 1. Add the token to a blacklist/revocation list
-		// 2. Or use a refresh token pattern
-		// 3. Or implement token rotation
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.LogoutRequest
+
+		// The body is optional; an empty body logs out every session
+		if ctx.Request.ContentLength > 0 {
+			if err := ctx.ShouldBindJSON(&req); err != nil {
+				ctx.Error(apperror.InvalidRequest(err))
+				return
+			}
+		}
+
+		// Deny the presented access token for the rest of its lifetime
+		if claims, ok := auth.GetClaims(ctx); ok && claims.ID != "" && claims.ExpiresAt != nil {
+			revoked := &model.RevokedToken{
+				JTI:       claims.ID,
+				UserID:    userID,
+				ExpiresAt: claims.ExpiresAt.Time,
+				RevokedAt: time.Now().UTC(),
+			}
+			if err := app.GetRepository().RevokedToken().Revoke(revoked); err != nil {
+				ctx.Error(apperror.Internal("Failed to revoke access token", err))
+				return
+			}
+			app.GetRealtimeHub().DisconnectToken(userID, claims.ID)
+		}
+
+		tokenRepo := app.GetRepository().RefreshToken()
+
+		if req.RefreshToken == "" {
+			if err := tokenRepo.RevokeAllForUser(userID); err != nil {
+				ctx.Error(apperror.Internal("Failed to revoke refresh tokens", err))
+				return
+			}
+			app.GetRealtimeHub().DisconnectUser(userID)
+		} else {
+			token, err := tokenRepo.GetByHash(auth.HashRefreshToken(req.RefreshToken))
+			if err == nil && token.UserID == userID {
+				if err := tokenRepo.Revoke(token.ID); err != nil {
+					ctx.Error(apperror.Internal("Failed to revoke refresh token", err))
+					return
+				}
+			}
+		}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"message": "Logout successful",
-		})
+		response.OK(ctx, nil, response.Message("Logout successful"))
 	}
 }
 
-// ActionRefreshToken handles token refresh
+// ActionRefreshToken exchanges a valid refresh token for a new access token.
+// The refresh token is rotated: the presented token is revoked and a new one is returned.
 func ActionRefreshToken() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		// Get user from context (should be authenticated)
-		userID, exists := auth.GetUserID(ctx)
-		if !exists {
-			ctx.JSON(http.StatusUnauthorized, gin.H{
-				"error": "User not authenticated",
-			})
+		var req model.RefreshTokenRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
 			return
 		}
 
-		username, exists := auth.GetUsername(ctx)
-		if !exists {
-			ctx.JSON(http.StatusUnauthorized, gin.H{
-				"error": "User not authenticated",
-			})
+		user, rawToken, ok := rotateRefreshToken(ctx, req.RefreshToken)
+		if !ok {
 			return
 		}
 
-		email, exists := auth.GetEmail(ctx)
-		if !exists {
-			ctx.JSON(http.StatusUnauthorized, gin.H{
-				"error": "User not authenticated",
-			})
+		// Generate new access token
+		token, err := app.GetJWTManager().GenerateToken(user.ID, user.Username, user.Email)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to refresh authentication token", err))
 			return
 		}
 
-		// Get JWT manager
-		config := app.GetConfig()
-		jwtManager := auth.NewJWTManager(config.JWTSecret, time.Duration(config.JWTExpiration)*time.Hour)
+		response.OK(ctx, gin.H{
+			"token":         token,
+			"refresh_token": rawToken,
+			"expires_in":    int64(app.GetJWTManager().Duration().Seconds()),
+			"token_type":    "Bearer",
+		}, response.Message("Token refreshed successfully"))
+	}
+}
+
+// rotateRefreshToken exchanges a refresh token for a new one, returning its user and the new
+// raw token. Presenting an already revoked token revokes every refresh token of the user.
+// It writes the error response and returns false when the token is refused.
+func rotateRefreshToken(ctx *gin.Context, raw string) (*model.User, string, bool) {
+	repo := app.GetRepository()
+	tokenRepo := repo.RefreshToken()
 
-		// Generate new token
-		token, err := jwtManager.GenerateToken(userID, username, email)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to refresh authentication token",
-				"details": err.Error(),
-			})
-			return
+	stored, err := tokenRepo.GetByHash(auth.HashRefreshToken(raw))
+	if err != nil {
+		ctx.Error(apperror.Unauthorized("Invalid refresh token"))
+		return nil, "", false
+	}
+
+	// A revoked token being replayed means it may have leaked: end every session
+	if stored.IsRevoked() {
+		if err := tokenRepo.RevokeAllForUser(stored.UserID); err != nil {
+			log.Printf("Error revoking sessions after refresh token reuse: %v", err)
+		}
+		ctx.Error(apperror.Unauthorized("Invalid refresh token"))
+		return nil, "", false
+	}
+
+	if stored.IsExpired() {
+		ctx.Error(apperror.Unauthorized("Session has expired, please log in again").WithCode(apperror.CodeSessionExpired))
+		return nil, "", false
+	}
+
+	user, err := repo.User().GetByID(stored.UserID)
+	if err != nil {
+		ctx.Error(apperror.Unauthorized("Invalid refresh token"))
+		return nil, "", false
+	}
+
+	// Rotate the refresh token
+	rawToken, tokenHash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to generate refresh token", err))
+		return nil, "", false
+	}
+
+	replacement := &model.RefreshToken{
+		UserID:           user.ID,
+		TokenHash:        tokenHash,
+		ExpiresAt:        refreshTokenExpiry(stored.SessionStartedAt),
+		SessionStartedAt: stored.SessionStartedAt,
+	}
+	replacement.BeforeCreate()
+
+	if err := tokenRepo.Rotate(stored.ID, replacement); err != nil {
+		if errors.Is(err, model.ErrRefreshTokenAlreadyUsed) {
+			ctx.Error(apperror.Unauthorized("Invalid refresh token").WithCode(apperror.CodeRefreshTokenReused))
+			return nil, "", false
 		}
+		ctx.Error(apperror.Internal("Failed to rotate refresh token", err))
+		return nil, "", false
+	}
+
+	return user, rawToken, true
+}
+
+// issueRefreshToken creates and stores a new refresh token for the user, returning the raw token
+func issueRefreshToken(userID int) (string, error) {
+	rawToken, tokenHash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	refreshToken := &model.RefreshToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: refreshTokenExpiry(time.Now().UTC()),
+	}
+	refreshToken.BeforeCreate()
+
+	if err := app.GetRepository().RefreshToken().Create(refreshToken); err != nil {
+		return "", err
+	}
+	return rawToken, nil
+}
+
+// refreshTokenExpiry returns when a refresh token issued now for a session started at
+// sessionStart expires. Sliding sessions extend by the idle timeout on every refresh but
+// never beyond their maximum lifetime.
+func refreshTokenExpiry(sessionStart time.Time) time.Time {
+	config := app.GetConfig()
+	now := time.Now().UTC()
+
+	if !config.Session.Sliding {
+		return now.Add(time.Duration(config.RefreshTokenExpiration) * time.Hour)
+	}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"message": "Token refreshed successfully",
-			"data": gin.H{
-				"token":      token,
-				"expires_in": int64(config.JWTExpiration * 3600), // Convert hours to seconds
-				"token_type": "Bearer",
-			},
-		})
+	expiry := now.Add(time.Duration(config.Session.IdleTimeout) * time.Hour)
+	if limit := sessionStart.Add(time.Duration(config.Session.MaxLifetime) * time.Hour); expiry.After(limit) {
+		return limit
 	}
+	return expiry
 }
@@ -0,0 +1,66 @@
+package action
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/alex-1900/wishlist/src/storage"
+	"github.com/alex-1900/wishlist/src/upload"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionUploadAvatar replaces the authenticated user's avatar with the image in the "image" form
+// field, described by the "alt" field
+func ActionUploadAvatar() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		image, alt, ok := upload.ReadImage(ctx, app.GetConfig().Storage.MaxUploadKB)
+		if !ok {
+			return
+		}
+
+		userRepo := app.GetRepository().User()
+		user, err := userRepo.GetByID(userID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		key, err := storage.NewKey(fmt.Sprintf("avatars/%d", userID), image.Extension)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to store avatar", err))
+			return
+		}
+		if err := app.GetStorage().Put(ctx.Request.Context(), key, image.Data, image.ContentType); err != nil {
+			ctx.Error(apperror.Internal("Failed to store avatar", err))
+			return
+		}
+		if err := userRepo.SetAvatar(userID, key, alt); err != nil {
+			ctx.Error(apperror.Internal("Failed to save avatar", err))
+			return
+		}
+		app.GetImages().Process(key, image)
+		app.GetCDN().Purge(cdn.UserKey(user.PublicID))
+
+		// The previous avatar is no longer referenced
+		if user.AvatarKey != "" {
+			if err := app.GetImages().Remove(ctx.Request.Context(), user.AvatarKey); err != nil {
+				log.Printf("Failed to delete previous avatar %s: %v", user.AvatarKey, err)
+			}
+		}
+
+		user.AvatarKey = key
+		user.AvatarAlt = alt
+		response.OK(ctx, user.ToResponse(), response.Message("Avatar updated successfully"))
+	}
+}
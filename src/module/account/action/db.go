@@ -1,12 +1,43 @@
 package action
 
 import (
-	"net/http"
+	"database/sql"
 
 	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/response"
 	"github.com/gin-gonic/gin"
 )
 
+// PoolStats describes the state of the database connection pool
+type PoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMS     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+// newPoolStats converts database/sql pool statistics to their response form
+func newPoolStats(stats sql.DBStats) PoolStats {
+	return PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMS:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+// ActionDBTest checks the database answers a query and reports the connection pool statistics
 func ActionDBTest() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		db := app.GetDB()
@@ -14,16 +45,10 @@ func ActionDBTest() gin.HandlerFunc {
 		// Test database connection with a simple query
 		var result int
 		if err := db.QueryRow("SELECT 1").Scan(&result); err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"message": "Database connection failed",
-				"error":   err.Error(),
-			})
+			ctx.Error(apperror.Internal("Database connection failed", err))
 			return
 		}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"message": "Database connection successful",
-			"result":  result,
-		})
+		response.OK(ctx, gin.H{"result": result, "pool": newPoolStats(db.Stats())}, response.Message("Database connection successful"))
 	}
 }
@@ -0,0 +1,115 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionCreateImportantDate adds a yearly date the authenticated user's friends are reminded of
+func ActionCreateImportantDate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.ImportantDateCreateRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		dateRepo := app.GetRepository().ImportantDate()
+
+		count, err := dateRepo.CountByUser(userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to count important dates", err))
+			return
+		}
+		if count >= model.MaxImportantDates {
+			message := fmt.Sprintf("You cannot have more than %d important dates", model.MaxImportantDates)
+			ctx.Error(apperror.Conflict(message).WithCode(apperror.CodeImportantDateLimit))
+			return
+		}
+
+		date := &model.ImportantDate{
+			UserID: userID,
+			Label:  req.Label,
+			Month:  req.Month,
+			Day:    req.Day,
+		}
+		date.BeforeCreate()
+
+		if err := dateRepo.Create(date); err != nil {
+			ctx.Error(apperror.Internal("Failed to create important date", err))
+			return
+		}
+
+		response.Created(ctx, date, response.Message("Important date added successfully"))
+	}
+}
+
+// ActionListImportantDates returns the authenticated user's important dates in calendar order
+func ActionListImportantDates() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		dates, err := app.GetRepository().ImportantDate().ListByUser(userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve important dates", err))
+			return
+		}
+
+		response.OK(ctx, dates, response.Message("Important dates retrieved successfully"))
+	}
+}
+
+// ActionDeleteImportantDate removes one of the authenticated user's important dates
+func ActionDeleteImportantDate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.ImportantDateDeleteRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		deleted, err := app.GetRepository().ImportantDate().Delete(userID, req.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to delete important date", err))
+			return
+		}
+		if !deleted {
+			ctx.Error(apperror.NotFound("Important date not found"))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Important date deleted successfully"))
+	}
+}
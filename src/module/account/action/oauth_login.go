@@ -0,0 +1,373 @@
+package action
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/auth/oauth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/gin-gonic/gin"
+)
+
+// oauthStateCookie carries the state and PKCE verifier from the start of a sign-in to its callback
+const oauthStateCookie = "oauth_state"
+
+// oauthStateMaxAge is how long a user has to complete a sign-in with a provider, in seconds
+const oauthStateMaxAge = 600
+
+// ActionOAuthStart redirects the browser to the provider's sign-in page
+func ActionOAuthStart() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		provider, ok := findOAuthProvider(ctx)
+		if !ok {
+			return
+		}
+
+		state, err := oauth.NewState()
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to start sign-in", err))
+			return
+		}
+		verifier, err := oauth.NewState()
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to start sign-in", err))
+			return
+		}
+
+		// Lax cookies survive the provider redirecting back, but Apple posts the callback
+		// cross-site, which only SameSite=None cookies survive; those require HTTPS
+		secure := strings.HasPrefix(app.GetConfig().OAuth.RedirectBaseURL, "https://")
+		if secure {
+			ctx.SetSameSite(http.SameSiteNoneMode)
+		} else {
+			ctx.SetSameSite(http.SameSiteLaxMode)
+		}
+		ctx.SetCookie(oauthStateCookie, state+"."+verifier, oauthStateMaxAge, oauthCallbackPath(provider), "", secure, true)
+
+		ctx.Redirect(http.StatusFound, provider.AuthCodeURL(state, verifier))
+	}
+}
+
+// ActionOAuthCallback completes a sign-in with a provider. The provider account signs in the
+// user it is linked to; otherwise it is linked to the user with the same verified email
+// address, or a new user is registered for it. Parameters arrive in the query, or in a form
+// for providers that post the callback.
+func ActionOAuthCallback() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		provider, ok := findOAuthProvider(ctx)
+		if !ok {
+			return
+		}
+
+		if reason := ctx.Request.FormValue("error"); reason != "" {
+			ctx.Error(apperror.Unauthorized(fmt.Sprintf("Sign-in with %s was not completed: %s", provider.Name(), reason)))
+			return
+		}
+
+		cookie, err := ctx.Cookie(oauthStateCookie)
+		ctx.SetCookie(oauthStateCookie, "", -1, oauthCallbackPath(provider), "", false, true)
+		state, verifier, found := strings.Cut(cookie, ".")
+		if err != nil || !found || subtle.ConstantTimeCompare([]byte(state), []byte(ctx.Request.FormValue("state"))) != 1 {
+			ctx.Error(apperror.BadRequest("Sign-in expired or was started in another browser, please try again"))
+			return
+		}
+
+		code := ctx.Request.FormValue("code")
+		if code == "" {
+			ctx.Error(apperror.BadRequest("Missing authorization code"))
+			return
+		}
+
+		token, err := provider.Exchange(ctx.Request.Context(), code, verifier)
+		if err != nil {
+			ctx.Error(apperror.Upstream(fmt.Sprintf("Failed to complete sign-in with %s", provider.Name()), err))
+			return
+		}
+
+		identity, err := provider.Identity(ctx.Request.Context(), token)
+		if err != nil {
+			ctx.Error(apperror.Upstream(fmt.Sprintf("Failed to read your %s account", provider.Name()), err))
+			return
+		}
+		if identity.Login == "" {
+			identity.Login = appleUserName(ctx.Request.FormValue("user"))
+		}
+
+		user, ok := findOrCreateOAuthUser(ctx, identity)
+		if !ok {
+			return
+		}
+
+		respondWithSession(ctx, user)
+	}
+}
+
+// OAuthIDTokenRequest represents the request structure for signing in with an ID token
+// obtained by a native app
+type OAuthIDTokenRequest struct {
+	IDToken string `json:"id_token" binding:"required" pii:"true"`
+	// Nonce is the raw nonce whose SHA-256 digest the app put in the provider request
+	Nonce string `json:"nonce" binding:"required"`
+	// Name is the user's name, which Apple only hands to the app on the first sign-in
+	Name string `json:"name" pii:"true"`
+}
+
+// ActionOAuthIDToken signs in with an ID token a native app obtained from the provider,
+// such as Sign in with Apple on iOS, with the same linking rules as the callback
+func ActionOAuthIDToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		provider, ok := findOAuthProvider(ctx)
+		if !ok {
+			return
+		}
+		if !provider.SupportsIDTokens() {
+			ctx.Error(apperror.BadRequest(fmt.Sprintf("%s does not support ID token sign-in", provider.Name())))
+			return
+		}
+
+		var req OAuthIDTokenRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		identity, err := provider.VerifyIDToken(ctx.Request.Context(), req.IDToken, req.Nonce)
+		if err != nil {
+			ctx.Error(apperror.Unauthorized("Invalid ID token").WithCode(apperror.CodeTokenInvalid))
+			return
+		}
+		identity.Login = req.Name
+
+		user, ok := findOrCreateOAuthUser(ctx, identity)
+		if !ok {
+			return
+		}
+
+		respondWithSession(ctx, user)
+	}
+}
+
+// appleUserName reads the name from the user form field Apple posts on the first sign-in
+func appleUserName(raw string) string {
+	var user struct {
+		Name struct {
+			FirstName string `json:"firstName"`
+			LastName  string `json:"lastName"`
+		} `json:"name"`
+	}
+	if raw == "" || json.Unmarshal([]byte(raw), &user) != nil {
+		return ""
+	}
+	return user.Name.FirstName + user.Name.LastName
+}
+
+// findOAuthProvider returns the provider named in the URL, writing a 404 response and
+// returning false when it is not configured
+func findOAuthProvider(ctx *gin.Context) (*oauth.Provider, bool) {
+	provider, err := app.GetOAuthProviders().Get(ctx.Param("provider"))
+	if err != nil {
+		ctx.Error(apperror.NotFound("Sign-in provider not found"))
+		return nil, false
+	}
+	return provider, true
+}
+
+// oauthCallbackPath scopes the state cookie to the provider's callback
+func oauthCallbackPath(provider *oauth.Provider) string {
+	return "/oauth/" + provider.Name() + "/callback"
+}
+
+// findOrCreateOAuthUser returns the user a provider account signs in, linking or registering
+// one on first use. It writes the error response and returns false when that is not possible.
+func findOrCreateOAuthUser(ctx *gin.Context, identity *oauth.Identity) (*model.User, bool) {
+	repo := app.GetRepository()
+
+	linked, err := repo.Identity().Get(identity.Provider, identity.Subject)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to look up linked account", err))
+		return nil, false
+	}
+	if linked != nil {
+		user, err := repo.User().GetByID(linked.UserID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to load linked account", err))
+			return nil, false
+		}
+		return user, true
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		ctx.Error(apperror.Forbidden(fmt.Sprintf("Your %s account has no verified email address", identity.Provider)))
+		return nil, false
+	}
+
+	exists, err := repo.User().ExistsByEmail(identity.Email)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to check email availability", err))
+		return nil, false
+	}
+
+	var user *model.User
+	if exists {
+		if user, err = repo.User().GetByEmail(identity.Email); err != nil {
+			ctx.Error(apperror.Internal("Failed to load account", err))
+			return nil, false
+		}
+		if !user.IsEmailVerified() && !claimUnverifiedUser(ctx, user) {
+			return nil, false
+		}
+	} else {
+		if !checkRegistrationAllowed(ctx, identity.Email) {
+			return nil, false
+		}
+		var created bool
+		if user, created = createOAuthUser(ctx, identity); !created {
+			return nil, false
+		}
+	}
+
+	return user, linkIdentity(ctx, user, identity)
+}
+
+// createOAuthUser registers a user for a provider account. The user has no usable password
+// and signs in through the provider.
+func createOAuthUser(ctx *gin.Context, identity *oauth.Identity) (*model.User, bool) {
+	userRepo := app.GetRepository().User()
+
+	username, err := availableUsername(identity)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to choose a username", err))
+		return nil, false
+	}
+
+	passwordHash, err := unusablePasswordHash()
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to create user", err))
+		return nil, false
+	}
+
+	user := &model.User{
+		Username:     username,
+		Email:        identity.Email,
+		Gender:       model.GenderUnknown,
+		PasswordHash: passwordHash,
+	}
+	user.BeforeCreate()
+
+	if err := userRepo.Create(user); err != nil {
+		ctx.Error(apperror.Internal("Failed to create user", err))
+		return nil, false
+	}
+
+	// The provider verified the address
+	if err := userRepo.MarkEmailVerified(user.ID); err != nil {
+		ctx.Error(apperror.Internal("Failed to create user", err))
+		return nil, false
+	}
+	verifiedAt := time.Now().UTC()
+	user.EmailVerifiedAt = &verifiedAt
+
+	return user, true
+}
+
+// claimUnverifiedUser hands an account whose email was never verified to the provider account
+// that proved it owns the address. Whoever registered it may not be the owner, so its password
+// is replaced and every session it has is ended before the provider account is linked.
+func claimUnverifiedUser(ctx *gin.Context, user *model.User) bool {
+	repo := app.GetRepository()
+
+	passwordHash, err := unusablePasswordHash()
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to link account", err))
+		return false
+	}
+	if err := repo.User().ResetCredentials(user.ID, passwordHash); err != nil {
+		ctx.Error(apperror.Internal("Failed to link account", err))
+		return false
+	}
+	if err := repo.RefreshToken().RevokeAllForUser(user.ID); err != nil {
+		ctx.Error(apperror.Internal("Failed to link account", err))
+		return false
+	}
+	app.GetRealtimeHub().DisconnectUser(user.ID)
+
+	// The provider verified the address
+	if err := repo.User().MarkEmailVerified(user.ID); err != nil {
+		ctx.Error(apperror.Internal("Failed to link account", err))
+		return false
+	}
+	verifiedAt := time.Now().UTC()
+	user.EmailVerifiedAt = &verifiedAt
+
+	return true
+}
+
+// unusablePasswordHash returns the hash of a random password nobody knows, for users who sign
+// in through a provider
+func unusablePasswordHash() (string, error) {
+	password, err := oauth.NewState()
+	if err != nil {
+		return "", err
+	}
+	return auth.HashPassword(password)
+}
+
+// linkIdentity records that the provider account signs in the user
+func linkIdentity(ctx *gin.Context, user *model.User, identity *oauth.Identity) bool {
+	link := &model.Identity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+	link.BeforeCreate()
+
+	if err := app.GetRepository().Identity().Create(link); err != nil {
+		ctx.Error(apperror.Internal("Failed to link account", err))
+		return false
+	}
+	return true
+}
+
+// usernameInvalidChars matches the characters usernames may not contain
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// availableUsername derives a free username from the provider login or the email address,
+// adding a number when it is taken
+func availableUsername(identity *oauth.Identity) (string, error) {
+	base := usernameInvalidChars.ReplaceAllString(identity.Login, "")
+	if len(base) < model.UsernameMinLength {
+		local, _, _ := strings.Cut(identity.Email, "@")
+		base = usernameInvalidChars.ReplaceAllString(local, "")
+	}
+	if len(base) < model.UsernameMinLength {
+		base = "user"
+	}
+	if len(base) > model.UsernameMaxLength-6 {
+		base = base[:model.UsernameMaxLength-6]
+	}
+
+	userRepo := app.GetRepository().User()
+	candidate := base
+	for i := 2; i < 100; i++ {
+		exists, err := userRepo.ExistsByUsername(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+	return "", fmt.Errorf("no free username found for %q", base)
+}
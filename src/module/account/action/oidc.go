@@ -0,0 +1,155 @@
+package action
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/gin-gonic/gin"
+)
+
+// The OpenID Connect endpoints answer in the shapes fixed by the OpenID and OAuth 2.0
+// specifications rather than the response envelope, so standard client libraries can read them.
+
+// OAuthTokenRequest represents the form posted to the OAuth 2.0 token endpoint
+type OAuthTokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	ClientID     string `form:"client_id" binding:"required"`
+	Scope        string `form:"scope"`
+	Username     string `form:"username" pii:"true"`
+	Password     string `form:"password" pii:"true"`
+	RefreshToken string `form:"refresh_token" pii:"true"`
+}
+
+// OAuthTokenResponse represents a successful token endpoint response (RFC 6749 section 5.1)
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// ActionOpenIDConfiguration publishes the OpenID Connect discovery document
+func ActionOpenIDConfiguration() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		issuer := strings.TrimSuffix(app.GetIDTokenSigner().Issuer(), "/")
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"issuer":                                issuer,
+			"token_endpoint":                        issuer + "/oauth/token",
+			"userinfo_endpoint":                     issuer + "/oauth/userinfo",
+			"jwks_uri":                              issuer + "/.well-known/jwks.json",
+			"grant_types_supported":                 []string{"password", "refresh_token"},
+			"response_types_supported":              []string{"token"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+			"scopes_supported":                      []string{"openid", "profile", "email"},
+			"token_endpoint_auth_methods_supported": []string{"none"},
+			"claims_supported":                      []string{"sub", "iss", "aud", "exp", "iat", "preferred_username", "email", "email_verified"},
+		})
+	}
+}
+
+// ActionJWKS publishes the public keys that verify ID tokens
+func ActionJWKS() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Cache-Control", "public, max-age=3600")
+		ctx.JSON(http.StatusOK, app.GetIDTokenSigner().JWKS())
+	}
+}
+
+// ActionOAuthToken is the OAuth 2.0 token endpoint for first-party public clients. It accepts
+// the password and refresh_token grants and adds an ID token when the openid scope is requested.
+func ActionOAuthToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req OAuthTokenRequest
+
+		// Bind form request to struct
+		if err := ctx.ShouldBind(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		if !slices.Contains(app.GetConfig().OIDC.ClientIDs, req.ClientID) {
+			ctx.Error(apperror.Unauthorized("Unknown client").WithCode(apperror.CodeInvalidClient))
+			return
+		}
+
+		var user *model.User
+		var refreshToken string
+		var ok bool
+		switch req.GrantType {
+		case "password":
+			if user, ok = authenticateCredentials(ctx, req.Username, req.Password); !ok {
+				return
+			}
+			var err error
+			if refreshToken, err = issueRefreshToken(user.ID); err != nil {
+				ctx.Error(apperror.Internal("Failed to generate refresh token", err))
+				return
+			}
+		case "refresh_token":
+			if user, refreshToken, ok = rotateRefreshToken(ctx, req.RefreshToken); !ok {
+				return
+			}
+		default:
+			ctx.Error(apperror.BadRequest("Unsupported grant type").WithCode(apperror.CodeUnsupportedGrantType))
+			return
+		}
+
+		accessToken, err := app.GetJWTManager().GenerateToken(user.ID, user.Username, user.Email)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to generate authentication token", err))
+			return
+		}
+
+		resp := OAuthTokenResponse{
+			AccessToken:  accessToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    int64(app.GetJWTManager().Duration().Seconds()),
+			RefreshToken: refreshToken,
+			Scope:        req.Scope,
+		}
+		if slices.Contains(strings.Fields(req.Scope), "openid") {
+			resp.IDToken, err = app.GetIDTokenSigner().Issue(user.ID, user.Username, user.Email, user.IsEmailVerified(), req.ClientID)
+			if err != nil {
+				ctx.Error(apperror.Internal("Failed to generate ID token", err))
+				return
+			}
+		}
+
+		ctx.Header("Cache-Control", "no-store")
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+// ActionUserInfo returns the OpenID Connect claims of the authenticated user
+func ActionUserInfo() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		user, err := app.GetRepository().User().GetByID(userID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"sub":                strconv.Itoa(user.ID),
+			"preferred_username": user.Username,
+			"email":              user.Email,
+			"email_verified":     user.IsEmailVerified(),
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package action
+
+import (
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionGetPasswordPolicy returns the rules passwords must follow, so that clients can check
+// them before submitting a form
+func ActionGetPasswordPolicy() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		response.OK(ctx, model.CurrentPasswordPolicy, response.Message("Password policy retrieved successfully"))
+	}
+}
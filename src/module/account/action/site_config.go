@@ -0,0 +1,36 @@
+package action
+
+import (
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/demo"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionGetSiteConfig returns the public configuration clients need before signing in: whether
+// sign-ups are open, and on demo deployments the credentials of the sandbox accounts
+func ActionGetSiteConfig() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		settings, err := app.GetRepository().SiteSettings().Get()
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve site settings", err))
+			return
+		}
+
+		demoConfig := app.GetConfig().Demo
+		config := &model.SiteConfig{
+			// Demo deployments refuse sign-ups, visitors use the sandbox accounts
+			RegistrationOpen: settings.RegistrationOpen && !demoConfig.Enabled,
+			Demo:             model.DemoSiteConfig{Enabled: demoConfig.Enabled},
+			Categories:       model.WishItemCategories,
+		}
+		if demoConfig.Enabled {
+			config.Demo.ResetInterval = demoConfig.ResetInterval
+			config.Demo.Accounts = demo.Accounts(demoConfig.Password)
+		}
+
+		response.OK(ctx, config, response.Message("Site configuration retrieved successfully"))
+	}
+}
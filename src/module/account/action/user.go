@@ -3,12 +3,16 @@ package action
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
-	"net/http"
+	"log"
 
 	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
 	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/cdn"
 	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -16,9 +20,7 @@ import (
 // ActionPing returns a simple health check response
 func ActionPing() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		ctx.JSON(200, gin.H{
-			"message": "pong",
-		})
+		response.OK(ctx, nil, response.Message("pong"))
 	}
 }
 
@@ -29,19 +31,17 @@ func ActionCreateUser() gin.HandlerFunc {
 
 		// Bind JSON request to struct
 		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request format",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.InvalidRequest(err))
 			return
 		}
 
 		// Validate the request
 		if err := req.Validate(); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Validation failed",
-				"details": err.Error(),
-			})
+			ctx.Error(userValidationError(err))
+			return
+		}
+
+		if !checkRegistrationAllowed(ctx, req.Email) {
 			return
 		}
 
@@ -50,39 +50,26 @@ func ActionCreateUser() gin.HandlerFunc {
 
 		// Check if username already exists
 		if exists, err := userRepo.ExistsByUsername(req.Username); err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to check username availability",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.Internal("Failed to check username availability", err))
 			return
 		} else if exists {
-			ctx.JSON(http.StatusConflict, gin.H{
-				"error": "Username already exists",
-			})
+			ctx.Error(apperror.Conflict("Username already exists").WithCode(apperror.CodeUsernameTaken))
 			return
 		}
 
 		// Check if email already exists
 		if exists, err := userRepo.ExistsByEmail(req.Email); err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to check email availability",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.Internal("Failed to check email availability", err))
 			return
 		} else if exists {
-			ctx.JSON(http.StatusConflict, gin.H{
-				"error": "Email already exists",
-			})
+			ctx.Error(apperror.Conflict("Email already exists").WithCode(apperror.CodeEmailTaken))
 			return
 		}
 
 		// Hash the password using auth package
 		passwordHash, err := auth.HashPassword(req.Password)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to hash password",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.Internal("Failed to hash password", err))
 			return
 		}
 
@@ -97,18 +84,12 @@ func ActionCreateUser() gin.HandlerFunc {
 
 		// Save user to database
 		if err := userRepo.Create(user); err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to create user",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.Internal("Failed to create user", err))
 			return
 		}
 
 		// Return user response (without password hash)
-		ctx.JSON(http.StatusCreated, gin.H{
-			"message": "User created successfully",
-			"user":    user.ToResponse(),
-		})
+		response.Created(ctx, user.ToResponse(), response.Message("User created successfully"))
 	}
 }
 
@@ -133,10 +114,7 @@ func ActionCreateTestUser() gin.HandlerFunc {
 		// Hash the password
 		passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to hash password",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.Internal("Failed to hash password", err))
 			return
 		}
 
@@ -150,36 +128,61 @@ func ActionCreateTestUser() gin.HandlerFunc {
 
 		// Save user to database
 		if err := userRepo.Create(user); err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to create test user",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.Internal("Failed to create test user", err))
 			return
 		}
 
 		// Return success response with created user info (excluding password)
-		ctx.JSON(http.StatusCreated, gin.H{
-			"message": "Test user created successfully",
-			"user":    user.ToResponse(),
-			"test_credentials": gin.H{
+		response.Created(ctx, user.ToResponse(),
+			response.Message("Test user created successfully"),
+			response.Meta("test_credentials", gin.H{
 				"username": username,
 				"password": password,
-			},
-		})
+			}),
+		)
 	}
 }
 
-// ActionListUsers returns a list of all users (for testing)
+// ActionListUsers returns a page of users (for testing)
 func ActionListUsers() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
 		userRepo := app.GetRepository().User()
+		opts := listReq.ToOptions(ctx.Request.URL.Query(), "username", "email")
 
-		users, err := userRepo.List()
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to retrieve users",
-				"details": err.Error(),
+		// Stream rows straight to the client when asked for NDJSON
+		if response.WantsNDJSON(ctx) {
+			stream := response.NDJSON(ctx)
+			err := userRepo.Stream(opts, func(user *model.User) error {
+				return stream.Write(user.ToResponse())
 			})
+			if err != nil {
+				if !stream.Started() {
+					ctx.Error(apperror.Internal("Failed to retrieve users", err))
+					return
+				}
+				log.Printf("User stream aborted: %v", err)
+			}
+			stream.Finish()
+			return
+		}
+
+		users, page, err := userRepo.List(opts)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve users", err))
 			return
 		}
 
@@ -189,10 +192,7 @@ func ActionListUsers() gin.HandlerFunc {
 			userResponses[i] = user.ToResponse()
 		}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"message": fmt.Sprintf("Retrieved %d users", len(users)),
-			"users":   userResponses,
-		})
+		response.OK(ctx, userResponses, response.Message(fmt.Sprintf("Retrieved %d users", len(users))), response.Page(page))
 	}
 }
 
@@ -226,9 +226,7 @@ func ActionGetProfile() gin.HandlerFunc {
 		// Get user ID from context (set by auth middleware)
 		userID, exists := auth.GetUserID(ctx)
 		if !exists {
-			ctx.JSON(http.StatusUnauthorized, gin.H{
-				"error": "User not authenticated",
-			})
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
 			return
 		}
 
@@ -238,50 +236,47 @@ func ActionGetProfile() gin.HandlerFunc {
 		// Find user by ID
 		user, err := userRepo.GetByID(userID)
 		if err != nil {
-			ctx.JSON(http.StatusNotFound, gin.H{
-				"error":   "User not found",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.NotFound("User not found"))
 			return
 		}
 
+		// Attach follow counts
+		counts, err := app.GetRepository().Follow().Counts(userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve follow counts", err))
+			return
+		}
+
+		profile := user.ToResponse()
+		profile.FollowersCount = counts.Followers
+		profile.FollowingCount = counts.Following
+
 		// Return user profile
-		ctx.JSON(http.StatusOK, gin.H{
-			"message": "Profile retrieved successfully",
-			"data":    user.ToResponse(),
-		})
+		response.OK(ctx, profile, response.Message("Profile retrieved successfully"))
 	}
 }
 
-// ActionUpdateProfile updates the authenticated user's profile
+// ActionUpdateProfile applies a JSON Merge Patch to the authenticated user's profile
 func ActionUpdateProfile() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Get user ID from context (set by auth middleware)
 		userID, exists := auth.GetUserID(ctx)
 		if !exists {
-			ctx.JSON(http.StatusUnauthorized, gin.H{
-				"error": "User not authenticated",
-			})
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
 			return
 		}
 
-		var req model.UserUpdateRequest
+		var req model.UserPatch
 
-		// Bind JSON request to struct
+		// Bind JSON merge patch to struct
 		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request format",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.InvalidRequest(err))
 			return
 		}
 
 		// Validate the request
 		if err := req.Validate(); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Validation failed",
-				"details": err.Error(),
-			})
+			ctx.Error(userValidationError(err))
 			return
 		}
 
@@ -291,185 +286,127 @@ func ActionUpdateProfile() gin.HandlerFunc {
 		// Get existing user
 		user, err := userRepo.GetByID(userID)
 		if err != nil {
-			ctx.JSON(http.StatusNotFound, gin.H{
-				"error":   "User not found",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.NotFound("User not found"))
 			return
 		}
 
 		// Check if new username already exists (if being updated)
-		if req.Username != nil && *req.Username != user.Username {
-			if exists, err := userRepo.ExistsByUsername(*req.Username); err != nil {
-				ctx.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to check username availability",
-					"details": err.Error(),
-				})
+		if req.Username.HasValue() && req.Username.Value != user.Username {
+			if exists, err := userRepo.ExistsByUsername(req.Username.Value); err != nil {
+				ctx.Error(apperror.Internal("Failed to check username availability", err))
 				return
 			} else if exists {
-				ctx.JSON(http.StatusConflict, gin.H{
-					"error": "Username already exists",
-				})
+				ctx.Error(apperror.Conflict("Username already exists").WithCode(apperror.CodeUsernameTaken))
 				return
 			}
-			user.Username = *req.Username
+			user.Username = req.Username.Value
 		}
 
 		// Check if new email already exists (if being updated)
-		if req.Email != nil && *req.Email != user.Email {
-			if exists, err := userRepo.ExistsByEmail(*req.Email); err != nil {
-				ctx.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to check email availability",
-					"details": err.Error(),
-				})
+		if req.Email.HasValue() && req.Email.Value != user.Email {
+			settings, err := app.GetRepository().SiteSettings().Get()
+			if err != nil {
+				ctx.Error(apperror.Internal("Failed to retrieve site settings", err))
+				return
+			}
+			if !checkEmailDomain(ctx, settings, req.Email.Value) {
+				return
+			}
+
+			if exists, err := userRepo.ExistsByEmail(req.Email.Value); err != nil {
+				ctx.Error(apperror.Internal("Failed to check email availability", err))
 				return
 			} else if exists {
-				ctx.JSON(http.StatusConflict, gin.H{
-					"error": "Email already exists",
-				})
+				ctx.Error(apperror.Conflict("Email already exists").WithCode(apperror.CodeEmailTaken))
 				return
 			}
-			user.Email = *req.Email
+			user.Email = req.Email.Value
+
+			// A new email address has to be verified again
+			user.EmailVerifiedAt = nil
 		}
 
-		// Update gender (if provided)
-		if req.Gender != nil {
-			user.Gender = model.ParseGender(*req.Gender)
+		// Update gender (if provided, null resets it to unknown)
+		if req.Gender.Set {
+			user.Gender = model.ParseGender(req.Gender.Value)
 		}
 
 		// Update password (if provided)
-		if req.Password != nil {
-			passwordHash, err := auth.HashPassword(*req.Password)
+		if req.Password.HasValue() {
+			passwordHash, err := auth.HashPassword(req.Password.Value)
 			if err != nil {
-				ctx.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to hash password",
-					"details": err.Error(),
-				})
+				ctx.Error(apperror.Internal("Failed to hash password", err))
 				return
 			}
 			user.PasswordHash = passwordHash
 		}
 
+		// Update the indexing opt-out (if provided, null lets search engines index again)
+		if req.NoIndex.Set {
+			user.NoIndex = req.NoIndex.Value
+		}
+
+		// Update the birthday (if provided, null removes it)
+		if req.Birthday.Set {
+			user.Birthday = nil
+			if req.Birthday.HasValue() {
+				birthday, _ := model.ParseBirthday(req.Birthday.Value) // checked by Validate
+				user.Birthday = &birthday
+			}
+		}
+
 		// Update timestamp
 		user.BeforeUpdate()
 
 		// Save user to database
 		if err := userRepo.Update(user); err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to update user",
-				"details": err.Error(),
-			})
+			ctx.Error(apperror.Internal("Failed to update user", err))
 			return
 		}
+		app.GetCDN().Purge(cdn.UserKey(user.PublicID))
 
 		// Return updated user profile
-		ctx.JSON(http.StatusOK, gin.H{
-			"message": "Profile updated successfully",
-			"data":    user.ToResponse(),
-		})
+		response.OK(ctx, user.ToResponse(), response.Message("Profile updated successfully"))
 	}
 }
 
-// EmailVerificationRequest represents the request structure for email verification
-type EmailVerificationRequest struct {
-	Email string `json:"email" binding:"required,email"`
-}
-
-// ActionSendVerificationCode sends a verification code to the user's email (placeholder)
-func ActionSendVerificationCode() gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		var req EmailVerificationRequest
-
-		// Bind JSON request to struct
-		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request format",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Get repository
-		userRepo := app.GetRepository().User()
-
-		// Check if user exists with this email
-		_, err := userRepo.GetByEmail(req.Email)
-		if err != nil {
-			ctx.JSON(http.StatusNotFound, gin.H{
-				"error": "User with this email not found",
-			})
-			return
-		}
+// checkRegistrationAllowed enforces the site settings on a new account's email address.
+// It writes a 403 response and returns false when registration is closed or the domain is not allowed.
+func checkRegistrationAllowed(ctx *gin.Context, email string) bool {
+	settings, err := app.GetRepository().SiteSettings().Get()
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to retrieve site settings", err))
+		return false
+	}
 
-		// In a real implementation, you would:
-		// 1. Generate a verification code
-		// 2. Store it with expiration
-		// 3. Send it via email service
-		// 4. Add rate limiting
-
-		// For now, return a success response with placeholder data
-		ctx.JSON(http.StatusOK, gin.H{
-			"message": "Verification code sent successfully",
-			"data": gin.H{
-				"email":              req.Email,
-				"code":               "123456", // Placeholder code for testing
-				"expires_in_minutes": 10,
-			},
-		})
+	if !settings.RegistrationOpen {
+		ctx.Error(apperror.Forbidden("Registration is closed").WithCode(apperror.CodeRegistrationClosed))
+		return false
 	}
-}
 
-// EmailVerificationConfirmRequest represents the request structure for confirming email verification
-type EmailVerificationConfirmRequest struct {
-	Email string `json:"email" binding:"required,email"`
-	Code  string `json:"code" binding:"required,len=6"`
+	return checkEmailDomain(ctx, settings, email)
 }
 
-// ActionConfirmVerificationCode verifies the email verification code (placeholder)
-func ActionConfirmVerificationCode() gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		var req EmailVerificationConfirmRequest
-
-		// Bind JSON request to struct
-		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request format",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Get repository
-		userRepo := app.GetRepository().User()
-
-		// Check if user exists with this email
-		_, err := userRepo.GetByEmail(req.Email)
-		if err != nil {
-			ctx.JSON(http.StatusNotFound, gin.H{
-				"error": "User with this email not found",
-			})
-			return
-		}
-
-		// In a real implementation, you would:
-		// 1. Verify the code against stored value
-		// 2. Check if code has expired
-		// 3. Mark email as verified in database
+// checkEmailDomain enforces the email domain allowlist and denylist of the site settings.
+// It writes a 403 response and returns false when the address may not be used.
+func checkEmailDomain(ctx *gin.Context, settings *model.SiteSettings, email string) bool {
+	switch err := settings.CheckEmailDomain(email); {
+	case errors.Is(err, model.ErrEmailDomainDenied):
+		ctx.Error(apperror.Forbidden("Email addresses of this domain cannot be used").WithCode(apperror.CodeEmailDomainDenied))
+		return false
+	case errors.Is(err, model.ErrEmailDomainNotAllowed):
+		ctx.Error(apperror.Forbidden("Only email addresses of allowed domains can be used").WithCode(apperror.CodeEmailDomainNotAllowed))
+		return false
+	}
 
-		// For now, accept the placeholder code "123456"
-		if req.Code != "123456" {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid verification code",
-			})
-			return
-		}
+	return true
+}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"message": "Email verified successfully",
-			"data": gin.H{
-				"email":    req.Email,
-				"verified": true,
-			},
-		})
+// userValidationError reports a user request that failed validation, with a specific code when
+// the password does not follow the password policy
+func userValidationError(err error) *apperror.Error {
+	if errors.Is(err, model.ErrPasswordTooWeak) {
+		return apperror.Validation(err).WithCode(apperror.CodePasswordTooWeak)
 	}
+	return apperror.Validation(err)
 }
@@ -0,0 +1,171 @@
+package action
+
+import (
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/mailer"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailVerificationRequest represents the request structure for email verification
+type EmailVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// EmailVerificationConfirmRequest represents the request structure for confirming email verification
+type EmailVerificationConfirmRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Code  string `json:"code" binding:"required,len=6"`
+}
+
+// ActionSendVerificationCode generates a new verification code for the user's email.
+// Previous codes are invalidated and requests are throttled by a resend cooldown.
+func ActionSendVerificationCode() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req EmailVerificationRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		repo := app.GetRepository()
+		verificationConfig := app.GetConfig().EmailVerification
+
+		// Check if user exists with this email
+		user, err := repo.User().GetByEmail(req.Email)
+		if err != nil {
+			ctx.Error(apperror.NotFound("User with this email not found"))
+			return
+		}
+
+		if user.IsEmailVerified() {
+			ctx.Error(apperror.Conflict("Email address is already verified"))
+			return
+		}
+
+		codeRepo := repo.VerificationCode()
+
+		// Throttle resends
+		if latest, err := codeRepo.GetLatestActive(user.ID, model.VerificationPurposeEmail); err == nil {
+			cooldown := time.Duration(verificationConfig.ResendCooldown) * time.Second
+			if wait := time.Until(latest.CreatedAt.Add(cooldown)); wait > 0 {
+				ctx.Error(apperror.TooManyRequests("Verification code was sent recently, please wait before requesting another").
+					With("retry_after_seconds", int(wait.Seconds())+1))
+				return
+			}
+		}
+
+		if err := codeRepo.InvalidateAll(user.ID, model.VerificationPurposeEmail); err != nil {
+			ctx.Error(apperror.Internal("Failed to invalidate previous verification codes", err))
+			return
+		}
+
+		code, codeHash, err := auth.GenerateVerificationCode()
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to generate verification code", err))
+			return
+		}
+
+		verificationCode := &model.VerificationCode{
+			UserID:    user.ID,
+			Email:     user.Email,
+			Purpose:   model.VerificationPurposeEmail,
+			CodeHash:  codeHash,
+			ExpiresAt: time.Now().UTC().Add(time.Duration(verificationConfig.CodeExpiration) * time.Minute),
+		}
+		verificationCode.BeforeCreate()
+
+		if err := codeRepo.Create(verificationCode); err != nil {
+			ctx.Error(apperror.Internal("Failed to store verification code", err))
+			return
+		}
+
+		err = app.GetMailer().SendTemplate(ctx.Request.Context(), []string{user.Email}, mailer.TemplateVerificationCode, gin.H{
+			"Username":         user.Username,
+			"Code":             code,
+			"ExpiresInMinutes": verificationConfig.CodeExpiration,
+		})
+		if err != nil {
+			ctx.Error(apperror.Upstream("Failed to send verification email", err))
+			return
+		}
+
+		response.OK(ctx, gin.H{
+			"email":              user.Email,
+			"expires_in_minutes": verificationConfig.CodeExpiration,
+		}, response.Message("Verification code sent successfully"))
+	}
+}
+
+// ActionConfirmVerificationCode verifies the email verification code and marks the email as verified
+func ActionConfirmVerificationCode() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req EmailVerificationConfirmRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		repo := app.GetRepository()
+		verificationConfig := app.GetConfig().EmailVerification
+
+		// Check if user exists with this email
+		user, err := repo.User().GetByEmail(req.Email)
+		if err != nil {
+			ctx.Error(apperror.NotFound("User with this email not found"))
+			return
+		}
+
+		codeRepo := repo.VerificationCode()
+
+		verificationCode, err := codeRepo.GetLatestActive(user.ID, model.VerificationPurposeEmail)
+		if err != nil || verificationCode.IsExpired() || verificationCode.Email != user.Email {
+			ctx.Error(apperror.BadRequest("Verification code has expired or was not requested").WithCode(apperror.CodeVerificationCodeExpired))
+			return
+		}
+
+		if verificationCode.Attempts >= verificationConfig.MaxAttempts {
+			if err := codeRepo.Consume(verificationCode.ID); err != nil {
+				ctx.Error(apperror.Internal("Failed to invalidate verification code", err))
+				return
+			}
+			ctx.Error(apperror.TooManyRequests("Too many failed attempts, please request a new code"))
+			return
+		}
+
+		if !auth.CheckVerificationCode(req.Code, verificationCode.CodeHash) {
+			if err := codeRepo.IncrementAttempts(verificationCode.ID); err != nil {
+				ctx.Error(apperror.Internal("Failed to record verification attempt", err))
+				return
+			}
+			ctx.Error(apperror.BadRequest("Invalid verification code").
+				WithCode(apperror.CodeVerificationCodeInvalid).
+				With("attempts_remaining", verificationConfig.MaxAttempts-verificationCode.Attempts-1))
+			return
+		}
+
+		if err := codeRepo.Consume(verificationCode.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to consume verification code", err))
+			return
+		}
+
+		if err := repo.User().MarkEmailVerified(user.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to mark email as verified", err))
+			return
+		}
+
+		response.OK(ctx, gin.H{
+			"email":    user.Email,
+			"verified": true,
+		}, response.Message("Email verified successfully"))
+	}
+}
@@ -1,34 +1,196 @@
 package account
 
 import (
-	"time"
-
 	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
 	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/database"
 	"github.com/alex-1900/wishlist/src/module/account/action"
 	"github.com/gin-gonic/gin"
 )
 
+// Module is the account module handling registration, authentication and profiles
+type Module struct{}
+
+func init() {
+	app.RegisterModule(&Module{})
+
+	apperror.Register(apperror.CodePasswordTooWeak, apperror.Remediation{
+		Hint: "Choose a password following the password policy.",
+		Docs: "/password-policy",
+	})
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return "account"
+}
+
+// Migrations returns the account schema migrations
+func (m *Module) Migrations() []database.Migration {
+	return []database.Migration{
+		{
+			Version: database.VersionCreateUsers,
+			Name:    "create_users_table",
+			Up:      database.CreateUsersTable,
+			Down:    database.DropUsersTable,
+		},
+		{
+			Version: database.VersionCreateRefreshTokens,
+			Name:    "create_refresh_tokens_table",
+			Up:      database.CreateRefreshTokensTable,
+			Down:    database.DropRefreshTokensTable,
+		},
+		{
+			Version: database.VersionCreateRevokedTokens,
+			Name:    "create_revoked_tokens_table",
+			Up:      database.CreateRevokedTokensTable,
+			Down:    database.DropRevokedTokensTable,
+		},
+		{
+			Version: database.VersionCreateVerificationCodes,
+			Name:    "create_verification_codes_table",
+			Up:      database.CreateVerificationCodesTable,
+			Down:    database.DropVerificationCodesTable,
+		},
+		{
+			Version: database.VersionCreateLoginAttempts,
+			Name:    "create_login_attempts_table",
+			Up:      database.CreateLoginAttemptsTable,
+			Down:    database.DropLoginAttemptsTable,
+		},
+		{
+			Version: database.VersionAddUserAvatar,
+			Name:    "add_user_avatar",
+			Up:      database.AddUserAvatar,
+			Down:    database.DropUserAvatar,
+		},
+		{
+			Version:  database.VersionAddRefreshTokenSessionStart,
+			Name:     "add_refresh_token_session_start",
+			Up:       database.AddRefreshTokenSessionStart,
+			Backfill: database.BackfillRefreshTokenSessionStart,
+			Down:     database.DropRefreshTokenSessionStart,
+		},
+		{
+			Version: database.VersionCreateSiteSettings,
+			Name:    "create_site_settings_table",
+			Up:      database.CreateSiteSettingsTable,
+			Down:    database.DropSiteSettingsTable,
+		},
+		{
+			Version: database.VersionAddDeniedEmailDomains,
+			Name:    "add_denied_email_domains",
+			Up:      database.AddDeniedEmailDomains,
+			Down:    database.DropDeniedEmailDomains,
+		},
+		{
+			Version: database.VersionCreateIdentities,
+			Name:    "create_identities_table",
+			Up:      database.CreateIdentitiesTable,
+			Down:    database.DropIdentitiesTable,
+		},
+		{
+			Version: database.VersionAddUserDeletedAt,
+			Name:    "add_user_deleted_at",
+			Up:      database.AddUserDeletedAt,
+			Down:    database.DropUserDeletedAt,
+		},
+		{
+			Version:  database.VersionAddUserPublicID,
+			Name:     "add_user_public_id",
+			Up:       database.AddUserPublicID,
+			Backfill: database.BackfillUserPublicID,
+			Down:     database.DropUserPublicID,
+		},
+		{
+			Version: database.VersionAddUserNoIndex,
+			Name:    "add_user_noindex",
+			Up:      database.AddUserNoIndex,
+			Down:    database.DropUserNoIndex,
+		},
+		{
+			Version: database.VersionAddUserBirthday,
+			Name:    "add_user_birthday",
+			Up:      database.AddUserBirthday,
+			Down:    database.DropUserBirthday,
+		},
+		{
+			Version: database.VersionCreateImportantDates,
+			Name:    "create_important_dates_table",
+			Up:      database.CreateImportantDatesTable,
+			Down:    database.DropImportantDatesTable,
+		},
+		{
+			Version: database.VersionAddUserAvatarAlt,
+			Name:    "add_user_avatar_alt",
+			Up:      database.AddUserAvatarAlt,
+			Down:    database.DropUserAvatarAlt,
+		},
+		{
+			Version: database.VersionAddUserSandbox,
+			Name:    "add_user_sandbox",
+			Up:      database.AddUserSandbox,
+			Down:    database.DropUserSandbox,
+		},
+		{
+			Version: database.VersionAddUserSessionsEnd,
+			Name:    "add_user_sessions_revoked_at",
+			Up:      database.AddUserSessionsEnd,
+			Down:    database.DropUserSessionsEnd,
+		},
+	}
+}
+
+// Start is a no-op for the account module
+func (m *Module) Start(a *app.App) error {
+	return nil
+}
+
+// Stop is a no-op for the account module
+func (m *Module) Stop(a *app.App) error {
+	return nil
+}
+
 // RegisterRoutes registers all account-related routes following the new routing principles
-func RegisterRoutes(router *gin.Engine) {
+func (m *Module) RegisterRoutes(router *gin.Engine) {
 	// Health check endpoints (keep them for now)
 	router.GET("/ping", action.ActionPing())
 	router.GET("/db-test", action.ActionDBTest())
 
+	// Public configuration read by clients before signing in, with the demo accounts
+	router.GET("/site-config", action.ActionGetSiteConfig())
+
+	// Rules passwords must follow, linked from password_too_weak errors
+	router.GET("/password-policy", action.ActionGetPasswordPolicy())
+
 	// User registration endpoint
 	router.POST("/user-register", action.ActionCreateUser())
 
-	// Email verification endpoints (placeholder implementation)
+	// Email verification endpoints
 	router.POST("/send-verification-code", action.ActionSendVerificationCode())
 	router.POST("/confirm-verification-code", action.ActionConfirmVerificationCode())
 
 	// Authentication endpoint - email and password login
 	router.POST("/user-login", action.ActionLogin())
 
+	// Exchange a refresh token for a new access token (rotates the refresh token)
+	router.POST("/refresh-auth-token", action.ActionRefreshToken())
+
 	// Create auth middleware for protected routes
-	config := app.GetConfig()
-	jwtManager := auth.NewJWTManager(config.JWTSecret, time.Duration(config.JWTExpiration)*time.Hour)
-	authMiddleware := auth.AuthMiddleware(jwtManager)
+	authMiddleware := auth.AuthMiddleware(app.GetJWTManager())
+
+	// OpenID Connect discovery and token endpoints for first-party apps
+	router.GET("/.well-known/openid-configuration", action.ActionOpenIDConfiguration())
+	router.GET("/.well-known/jwks.json", action.ActionJWKS())
+	router.POST("/oauth/token", action.ActionOAuthToken())
+	router.GET("/oauth/userinfo", authMiddleware, action.ActionUserInfo())
+
+	// Sign-in with external identity providers (authorization code flow)
+	router.GET("/oauth/:provider/start", action.ActionOAuthStart())
+	router.GET("/oauth/:provider/callback", action.ActionOAuthCallback())
+	router.POST("/oauth/:provider/callback", action.ActionOAuthCallback())
+	router.POST("/oauth/:provider/id-token", action.ActionOAuthIDToken())
 
 	// Protected routes (require authentication)
 	protected := router.Group("/")
@@ -37,15 +199,36 @@ func RegisterRoutes(router *gin.Engine) {
 		// Profile management - get user profile
 		protected.GET("/user-profile", action.ActionGetProfile())
 
-		// Profile management - update user profile (username, email, gender, password)
+		// Profile management - update user profile (username, email, gender, password) as a merge patch
 		protected.POST("/update-user-profile", action.ActionUpdateProfile())
+		protected.PATCH("/user-profile", action.ActionUpdateProfile())
+
+		// Profile management - upload avatar image (multipart "image" field)
+		protected.POST("/user-avatar", action.ActionUploadAvatar())
+
+		// Important dates friends are reminded of, next to the birthday set on the profile
+		protected.POST("/create-important-date", action.ActionCreateImportantDate())
+		protected.GET("/list-important-dates", action.ActionListImportantDates())
+		protected.POST("/delete-important-date", action.ActionDeleteImportantDate())
 
 		// Authentication management
 		protected.POST("/user-logout", action.ActionLogout())
-		protected.POST("/refresh-auth-token", action.ActionRefreshToken())
+	}
+
+	// Administration endpoints, limited to Config.AdminUserIDs
+	admin := router.Group("/admin")
+	admin.Use(authMiddleware, auth.AdminMiddleware(app.GetConfig().AdminUserIDs))
+	{
+		admin.GET("/lockouts", action.ActionListLockouts())
+		admin.GET("/site-settings", action.ActionGetSiteSettings())
+		admin.POST("/update-site-settings", action.ActionUpdateSiteSettings())
+		admin.PATCH("/site-settings", action.ActionUpdateSiteSettings())
+		admin.POST("/delete-user", action.ActionDeleteUser())
+		admin.POST("/restore-user", action.ActionRestoreUser())
+		admin.POST("/purge-deleted-users", action.ActionPurgeDeletedUsers())
 	}
 
 	// Testing endpoints (keep for development)
 	router.POST("/create-test-user", action.ActionCreateTestUser())
 	router.GET("/list-users", action.ActionListUsers())
-}
\ No newline at end of file
+}
@@ -0,0 +1,105 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionCreateAPIKey issues an API key for an integration. The raw key is only part of this response.
+func ActionCreateAPIKey() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.APIKeyCreateRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		raw, prefix, hash, err := auth.GenerateAPIKey()
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to generate API key", err))
+			return
+		}
+
+		key := &model.APIKey{
+			Name:      req.Name,
+			Prefix:    prefix,
+			KeyHash:   hash,
+			CreatedBy: &userID,
+		}
+		key.BeforeCreate()
+
+		if err := app.GetRepository().APIKey().Create(key); err != nil {
+			ctx.Error(apperror.Internal("Failed to create API key", err))
+			return
+		}
+
+		response.Created(ctx, model.CreatedAPIKey{APIKey: key, Key: raw}, response.Message("API key created; store it now, it is not shown again"))
+	}
+}
+
+// ActionListAPIKeys retrieves a page of the API keys, without their raw values
+func ActionListAPIKeys() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		keys, page, err := app.GetRepository().APIKey().List(listReq.ToOptions(ctx.Request.URL.Query(), "name", "status"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve API keys", err))
+			return
+		}
+
+		response.OK(ctx, keys, response.Message(fmt.Sprintf("Retrieved %d API keys", len(keys))), response.Page(page))
+	}
+}
+
+// ActionRevokeAPIKey revokes an API key; requests using it are refused from then on
+func ActionRevokeAPIKey() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req model.APIKeyRevokeRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		if err := app.GetRepository().APIKey().Revoke(req.ID); err != nil {
+			ctx.Error(apperror.NotFound("API key not found"))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("API key revoked successfully"))
+	}
+}
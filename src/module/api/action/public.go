@@ -0,0 +1,144 @@
+package action
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// PublicWishlistResponse is a public wishlist with its owner and items
+type PublicWishlistResponse struct {
+	Wishlist      *model.PublicWishlist     `json:"wishlist"`
+	OwnerUsername string                    `json:"owner_username"`
+	Items         []*model.WishItemResponse `json:"items"`
+}
+
+// ActionGetPublicProfile returns the public profile of a user
+func ActionGetPublicProfile() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := app.GetRepository().User().GetByUsername(ctx.Param("username"))
+		if err != nil {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		counts, err := app.GetRepository().Follow().Counts(user.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to count follows", err))
+			return
+		}
+
+		profile := user.ToPublicProfile()
+		profile.FollowersCount = counts.Followers
+		profile.FollowingCount = counts.Following
+
+		cdn.Tag(ctx, cdn.UserKey(user.PublicID))
+		response.Cached(ctx, cacheMaxAge(), profile, response.Message("Profile retrieved successfully"), response.Robots(access.CanIndexProfile(user)))
+	}
+}
+
+// ActionListPublicWishlists retrieves a page of a user's public wishlists
+func ActionListPublicWishlists() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		owner, err := app.GetRepository().User().GetByUsername(ctx.Param("username"))
+		if err != nil {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		// Integrations see what an anonymous visitor sees
+		wishlists, page, err := app.GetRepository().Wishlist().ListVisibleByUser(owner.ID, 0, []model.WishlistVisibility{model.VisibilityPublic}, listReq.ToOptions(ctx.Request.URL.Query(), "title"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wishlists", err))
+			return
+		}
+
+		languages := response.Languages(ctx)
+		responses := make([]*model.PublicWishlist, len(wishlists))
+		for i, wishlist := range wishlists {
+			wishlist.Localize(languages)
+			responses[i] = wishlist.ToPublicWishlist()
+		}
+
+		cdn.Tag(ctx, cdn.UserKey(owner.PublicID))
+		response.Cached(ctx, cacheMaxAge(), responses, response.Message(fmt.Sprintf("Retrieved %d wishlists", len(wishlists))), response.Page(page), response.Robots(access.CanIndexProfile(owner)))
+	}
+}
+
+// ActionGetPublicWishlist returns a public wishlist, identified by its public ID, with its items.
+// Reservations and gift preferences are left out, as for anonymous visitors.
+func ActionGetPublicWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		wishlist, err := app.GetRepository().Wishlist().GetByPublicID(ctx.Param("id"))
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+
+		visible, err := access.CanViewWishlist(app.GetRepository(), wishlist, 0)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+			return
+		}
+		if !visible {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+
+		owner, err := app.GetRepository().User().GetByID(wishlist.UserID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+
+		items, _, err := app.GetRepository().WishItem().ListByWishlist(wishlist.ID, model.ListOptions{})
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wish items", err))
+			return
+		}
+
+		languages := response.Languages(ctx)
+		responses := make([]*model.WishItemResponse, len(items))
+		for i, item := range items {
+			item.Localize(languages, wishlist.Language)
+			responses[i] = item.ToPublicResponse()
+		}
+		if to := response.DisplayCurrency(ctx); to != "" {
+			model.WithDisplayPrices(ctx.Request.Context(), app.GetCurrency(), to, responses...)
+		}
+		wishlist.Localize(languages)
+
+		cdn.Tag(ctx, cdn.WishlistKey(wishlist.PublicID), cdn.UserKey(owner.PublicID))
+		response.Cached(ctx, cacheMaxAge(), PublicWishlistResponse{
+			Wishlist:      wishlist.ToPublicWishlist(),
+			OwnerUsername: owner.Username,
+			Items:         responses,
+		}, response.Message("Wishlist retrieved successfully"), response.Robots(access.CanIndexWishlist(wishlist, owner)))
+	}
+}
+
+// cacheMaxAge returns how long public API responses may be cached
+func cacheMaxAge() time.Duration {
+	return time.Duration(app.GetConfig().PublicAPI.CacheMaxAge) * time.Second
+}
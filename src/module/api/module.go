@@ -0,0 +1,70 @@
+package api
+
+import (
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/database"
+	"github.com/alex-1900/wishlist/src/module/api/action"
+	"github.com/gin-gonic/gin"
+)
+
+// Module is the public API module serving read-only public data to integrations holding an API key
+type Module struct{}
+
+func init() {
+	app.RegisterModule(&Module{})
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return "api"
+}
+
+// Migrations returns the public API schema migrations
+func (m *Module) Migrations() []database.Migration {
+	return []database.Migration{
+		{
+			Version: database.VersionCreateAPIKeys,
+			Name:    "create_api_keys_table",
+			Up:      database.CreateAPIKeysTable,
+			Down:    database.DropAPIKeysTable,
+		},
+	}
+}
+
+// Start is a no-op for the public API module
+func (m *Module) Start(a *app.App) error {
+	return nil
+}
+
+// Stop is a no-op for the public API module
+func (m *Module) Stop(a *app.App) error {
+	return nil
+}
+
+// RegisterRoutes registers the versioned public API and the admin routes managing its keys
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	// Version 1 of the public API; breaking changes go to a new version. Responses vary by API key,
+	// so a CDN never serves them to requests without a valid one.
+	v1 := router.Group("/api/v1")
+	v1.Use(
+		app.GetCDN().Middleware(cdn.GroupPublicAPI, "X-API-Key"),
+		auth.APIKeyMiddleware(app.GetRepository().APIKey()),
+		app.GetRateLimiter().Middleware(),
+	)
+	{
+		v1.GET("/users/:username", action.ActionGetPublicProfile())
+		v1.GET("/users/:username/wishlists", action.ActionListPublicWishlists())
+		v1.GET("/wishlists/:id", action.ActionGetPublicWishlist())
+	}
+
+	// API keys are issued to integrations by administrators
+	admin := router.Group("/admin")
+	admin.Use(auth.AuthMiddleware(app.GetJWTManager()), auth.AdminMiddleware(app.GetConfig().AdminUserIDs))
+	{
+		admin.POST("/create-api-key", action.ActionCreateAPIKey())
+		admin.GET("/list-api-keys", action.ActionListAPIKeys())
+		admin.POST("/revoke-api-key", action.ActionRevokeAPIKey())
+	}
+}
@@ -0,0 +1,98 @@
+package demo
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/database"
+	"github.com/alex-1900/wishlist/src/demo"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Module is the demo module seeding the sandbox of demo deployments and resetting it on
+// schedule. It does nothing unless Config.Demo.Enabled is set.
+type Module struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+func init() {
+	app.RegisterModule(&Module{})
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return "demo"
+}
+
+// Migrations returns nil, the sandbox flag of users belongs to the account module
+func (m *Module) Migrations() []database.Migration {
+	return nil
+}
+
+// Start launches the reset job, which seeds the sandbox right away
+func (m *Module) Start(a *app.App) error {
+	config := a.Config.Demo
+	if !config.Enabled {
+		return nil
+	}
+	if config.Password == "" {
+		return errors.New("demo Password must be set")
+	}
+	if config.ResetInterval <= 0 {
+		return errors.New("demo ResetInterval must be positive")
+	}
+
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.run(a, time.Duration(config.ResetInterval)*time.Minute)
+	return nil
+}
+
+// Stop stops the reset job, waiting for a reset in progress to finish
+func (m *Module) Stop(a *app.App) error {
+	if m.stop == nil {
+		return nil
+	}
+	close(m.stop)
+	<-m.done
+	return nil
+}
+
+// RegisterRoutes registers nothing, GET /site-config of the account module publishes the demo
+// accounts
+func (m *Module) RegisterRoutes(router *gin.Engine) {}
+
+// run resets the sandbox now and then every interval until the module is stopped
+func (m *Module) run(a *app.App, interval time.Duration) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.reset(a); err != nil {
+			// The sandbox is left as it was until the next reset
+			log.Printf("Failed to reset demo sandbox: %v", err)
+		}
+
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reset seeds the sandbox again, discarding what visitors changed
+func (m *Module) reset(a *app.App) error {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(a.Config.Demo.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash demo password: %w", err)
+	}
+	return a.Repository.Demo().Reset(demo.Dataset(), string(passwordHash))
+}
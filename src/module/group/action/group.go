@@ -0,0 +1,264 @@
+package action
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionCreateGroup creates a new group owned by the authenticated user
+func ActionCreateGroup() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupCreateRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		group := &model.Group{
+			Name:    req.Name,
+			Kind:    model.GroupKind(req.Kind),
+			OwnerID: userID,
+		}
+		group.BeforeCreate()
+
+		if err := app.GetRepository().Group().Create(group); err != nil {
+			ctx.Error(apperror.Internal("Failed to create group", err))
+			return
+		}
+
+		response.Created(ctx, group, response.Message("Group created successfully"), response.GroupLinks(group.ID))
+	}
+}
+
+// ActionListGroups returns the groups the authenticated user belongs to
+func ActionListGroups() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		groups, page, err := app.GetRepository().Group().ListByMember(userID, listReq.ToOptions(ctx.Request.URL.Query(), "kind", "name"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve groups", err))
+			return
+		}
+
+		response.OK(ctx, groups, response.Message(fmt.Sprintf("Retrieved %d groups", len(groups))), response.Page(page))
+	}
+}
+
+// ActionDeleteGroup deletes a group owned by the authenticated user
+func ActionDeleteGroup() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupDeleteRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		group, ok := findMemberGroup(ctx, req.ID, userID)
+		if !ok {
+			return
+		}
+
+		if !group.IsOwnedBy(userID) {
+			ctx.Error(apperror.Forbidden("Only the group owner can delete the group"))
+			return
+		}
+
+		if err := app.GetRepository().Group().Delete(group.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to delete group", err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Group deleted successfully"))
+	}
+}
+
+// ActionListGroupMembers returns the members of a group the authenticated user belongs to
+func ActionListGroupMembers() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		groupID, err := strconv.Atoi(ctx.Query("group_id"))
+		if err != nil {
+			ctx.Error(apperror.BadRequest("Invalid group_id"))
+			return
+		}
+
+		if _, ok := findMemberGroup(ctx, groupID, userID); !ok {
+			return
+		}
+
+		members, err := app.GetRepository().Group().ListMembers(groupID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve group members", err))
+			return
+		}
+
+		response.OK(ctx, members, response.Message(fmt.Sprintf("Retrieved %d group members", len(members))))
+	}
+}
+
+// ActionAddGroupMember adds a user to a group owned by the authenticated user
+func ActionAddGroupMember() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupMemberRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		group, ok := findMemberGroup(ctx, req.GroupID, userID)
+		if !ok {
+			return
+		}
+
+		if !group.IsOwnedBy(userID) {
+			ctx.Error(apperror.Forbidden("Only the group owner can add members"))
+			return
+		}
+
+		member, err := app.GetRepository().User().GetByUsername(req.Username)
+		if err != nil {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		if err := app.GetRepository().Group().AddMember(group.ID, member.ID, model.GroupRoleMember); err != nil {
+			ctx.Error(apperror.Internal("Failed to add group member", err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Group member added successfully"))
+	}
+}
+
+// ActionRemoveGroupMember removes a user from a group.
+// The owner can remove anyone but themselves; members can remove themselves to leave.
+func ActionRemoveGroupMember() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupMemberRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		group, ok := findMemberGroup(ctx, req.GroupID, userID)
+		if !ok {
+			return
+		}
+
+		member, err := app.GetRepository().User().GetByUsername(req.Username)
+		if err != nil {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		if member.ID != userID && !group.IsOwnedBy(userID) {
+			ctx.Error(apperror.Forbidden("Only the group owner can remove other members"))
+			return
+		}
+
+		if group.IsOwnedBy(member.ID) {
+			ctx.Error(apperror.BadRequest("The group owner cannot leave the group, delete it instead"))
+			return
+		}
+
+		if err := app.GetRepository().Group().RemoveMember(group.ID, member.ID); err != nil {
+			ctx.Error(apperror.NotFound("Failed to remove group member"))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Group member removed successfully"))
+	}
+}
+
+// findMemberGroup loads a group and checks the user belongs to it.
+// It writes a 404 response and returns false when the group is missing or the user is not a member.
+func findMemberGroup(ctx *gin.Context, groupID, userID int) (*model.Group, bool) {
+	groupRepo := app.GetRepository().Group()
+
+	group, err := groupRepo.GetByID(groupID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Group not found"))
+		return nil, false
+	}
+
+	isMember, err := groupRepo.IsMember(groupID, userID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to check group membership", err))
+		return nil, false
+	}
+	if !isMember {
+		ctx.Error(apperror.NotFound("Group not found"))
+		return nil, false
+	}
+
+	return group, true
+}
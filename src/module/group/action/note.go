@@ -0,0 +1,123 @@
+package action
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionGetGroupNotes returns the shared shopping notes of a group for one of its wishlists
+func ActionGetGroupNotes() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		groupID, err := strconv.Atoi(ctx.Query("group_id"))
+		if err != nil {
+			ctx.Error(apperror.BadRequest("Invalid group_id"))
+			return
+		}
+
+		wishlist, ok := checkNoteAccess(ctx, groupID, ctx.Query("wishlist_id"), userID)
+		if !ok {
+			return
+		}
+
+		note, err := app.GetRepository().GroupNote().Get(groupID, wishlist.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve group notes", err))
+			return
+		}
+
+		response.OK(ctx, note, response.Message("Group notes retrieved successfully"))
+	}
+}
+
+// ActionUpdateGroupNotes replaces the shared shopping notes of a group for one of its wishlists
+func ActionUpdateGroupNotes() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupNoteUpdateRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlist, ok := checkNoteAccess(ctx, req.GroupID, req.WishlistID, userID)
+		if !ok {
+			return
+		}
+
+		note := &model.GroupNote{
+			GroupID:    req.GroupID,
+			WishlistID: wishlist.ID,
+			Content:    req.Content,
+			UpdatedBy:  &userID,
+			UpdatedAt:  time.Now().UTC(),
+		}
+
+		if err := app.GetRepository().GroupNote().Upsert(note); err != nil {
+			ctx.Error(apperror.Internal("Failed to update group notes", err))
+			return
+		}
+
+		response.OK(ctx, note, response.Message("Group notes updated successfully"))
+	}
+}
+
+// checkNoteAccess loads a wishlist by its public ID and verifies the user is a group member, the
+// wishlist is shared to the group, and the user does not own the wishlist. It writes a 404
+// response and returns false otherwise, so the owner cannot even learn that notes exist.
+func checkNoteAccess(ctx *gin.Context, groupID int, publicID string, userID int) (*model.Wishlist, bool) {
+	if _, ok := findMemberGroup(ctx, groupID, userID); !ok {
+		return nil, false
+	}
+
+	wishlist, err := app.GetRepository().Wishlist().GetByPublicID(publicID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Group notes not found"))
+		return nil, false
+	}
+
+	shared, err := app.GetRepository().Group().IsWishlistShared(groupID, wishlist.ID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to check wishlist share", err))
+		return nil, false
+	}
+	if !shared || ownsWishlist(wishlist, userID) {
+		ctx.Error(apperror.NotFound("Group notes not found"))
+		return nil, false
+	}
+
+	return wishlist, true
+}
+
+// ownsWishlist reports whether the user created the wishlist or is one of its members, from whom
+// notes and polls about it are kept. Failing to tell counts as owning it, so nothing leaks.
+func ownsWishlist(wishlist *model.Wishlist, userID int) bool {
+	role, err := access.WishlistRole(app.GetRepository(), wishlist, userID)
+	return err != nil || role != ""
+}
@@ -0,0 +1,290 @@
+package action
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GroupPollResponse represents a poll as seen by a group member
+type GroupPollResponse struct {
+	*model.GroupPoll
+	Closed bool `json:"closed"`
+	MyVote *int `json:"my_vote"`
+}
+
+// ActionCreateGroupPoll creates a poll in a group the authenticated user belongs to
+func ActionCreateGroupPoll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupPollCreateRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		if _, ok := findMemberGroup(ctx, req.GroupID, userID); !ok {
+			return
+		}
+
+		poll := &model.GroupPoll{
+			GroupID:   req.GroupID,
+			CreatedBy: userID,
+			Question:  strings.TrimSpace(req.Question),
+			ClosesAt:  req.ClosesAt,
+		}
+
+		if req.WishlistID != "" {
+			wishlist, ok := findPollWishlist(ctx, req.GroupID, req.WishlistID, userID)
+			if !ok {
+				return
+			}
+			poll.WishlistID = &wishlist.ID
+			poll.WishlistPublicID = wishlist.PublicID
+		}
+		for _, label := range req.Options {
+			poll.Options = append(poll.Options, &model.GroupPollOption{Label: strings.TrimSpace(label)})
+		}
+		poll.BeforeCreate()
+
+		if err := app.GetRepository().GroupPoll().Create(poll); err != nil {
+			ctx.Error(apperror.Internal("Failed to create poll", err))
+			return
+		}
+
+		response.Created(ctx, &GroupPollResponse{GroupPoll: poll}, response.Message("Poll created successfully"))
+	}
+}
+
+// ActionListGroupPolls returns the polls of a group, leaving out those about the user's own wishlists
+func ActionListGroupPolls() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		groupID, err := strconv.Atoi(ctx.Query("group_id"))
+		if err != nil {
+			ctx.Error(apperror.BadRequest("Invalid group_id"))
+			return
+		}
+
+		if _, ok := findMemberGroup(ctx, groupID, userID); !ok {
+			return
+		}
+
+		polls, err := app.GetRepository().GroupPoll().ListByGroup(groupID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve polls", err))
+			return
+		}
+
+		responses := make([]*GroupPollResponse, 0, len(polls))
+		for _, poll := range polls {
+			if isPollHiddenFrom(poll, userID) {
+				continue
+			}
+
+			myVote, err := app.GetRepository().GroupPoll().GetVote(poll.ID, userID)
+			if err != nil {
+				ctx.Error(apperror.Internal("Failed to retrieve votes", err))
+				return
+			}
+
+			responses = append(responses, &GroupPollResponse{GroupPoll: poll, Closed: poll.IsClosed(), MyVote: myVote})
+		}
+
+		response.OK(ctx, responses, response.Message(fmt.Sprintf("Retrieved %d polls", len(responses))))
+	}
+}
+
+// ActionGetGroupPoll returns a poll with its current results
+func ActionGetGroupPoll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		pollID, err := strconv.Atoi(ctx.Query("id"))
+		if err != nil {
+			ctx.Error(apperror.BadRequest("Invalid id"))
+			return
+		}
+
+		poll, _, ok := findVisiblePoll(ctx, pollID, userID)
+		if !ok {
+			return
+		}
+
+		myVote, err := app.GetRepository().GroupPoll().GetVote(poll.ID, userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve votes", err))
+			return
+		}
+
+		response.OK(ctx, &GroupPollResponse{GroupPoll: poll, Closed: poll.IsClosed(), MyVote: myVote}, response.Message("Poll retrieved successfully"))
+	}
+}
+
+// ActionVoteGroupPoll records or changes the authenticated user's vote in an open poll
+func ActionVoteGroupPoll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupPollVoteRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		poll, _, ok := findVisiblePoll(ctx, req.PollID, userID)
+		if !ok {
+			return
+		}
+
+		if poll.IsClosed() {
+			ctx.Error(apperror.Conflict("Poll is closed").WithCode(apperror.CodePollClosed))
+			return
+		}
+
+		if !poll.HasOption(req.OptionID) {
+			ctx.Error(apperror.BadRequest("Option does not belong to this poll"))
+			return
+		}
+
+		pollRepo := app.GetRepository().GroupPoll()
+		if err := pollRepo.Vote(poll.ID, req.OptionID, userID); err != nil {
+			ctx.Error(apperror.Internal("Failed to record vote", err))
+			return
+		}
+
+		poll, err := pollRepo.GetByID(poll.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve poll", err))
+			return
+		}
+
+		response.OK(ctx, &GroupPollResponse{GroupPoll: poll, Closed: poll.IsClosed(), MyVote: &req.OptionID}, response.Message("Vote recorded successfully"))
+	}
+}
+
+// ActionDeleteGroupPoll deletes a poll; only its creator or the group owner may do so
+func ActionDeleteGroupPoll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupPollDeleteRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		poll, group, ok := findVisiblePoll(ctx, req.ID, userID)
+		if !ok {
+			return
+		}
+
+		if poll.CreatedBy != userID && !group.IsOwnedBy(userID) {
+			ctx.Error(apperror.Forbidden("Only the poll creator or the group owner can delete the poll"))
+			return
+		}
+
+		if err := app.GetRepository().GroupPoll().Delete(poll.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to delete poll", err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Poll deleted successfully"))
+	}
+}
+
+// findVisiblePoll loads a poll the user may see: they must belong to its group and
+// must not own the wishlist it is about. It writes a 404 response and returns false otherwise.
+func findVisiblePoll(ctx *gin.Context, pollID, userID int) (*model.GroupPoll, *model.Group, bool) {
+	poll, err := app.GetRepository().GroupPoll().GetByID(pollID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Poll not found"))
+		return nil, nil, false
+	}
+
+	group, ok := findMemberGroup(ctx, poll.GroupID, userID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if isPollHiddenFrom(poll, userID) {
+		ctx.Error(apperror.NotFound("Poll not found"))
+		return nil, nil, false
+	}
+
+	return poll, group, true
+}
+
+// isPollHiddenFrom reports whether the poll is about a wishlist the user owns or is a member of
+func isPollHiddenFrom(poll *model.GroupPoll, userID int) bool {
+	if poll.WishlistID == nil {
+		return false
+	}
+
+	wishlist, err := app.GetRepository().Wishlist().GetByID(*poll.WishlistID)
+	return err != nil || ownsWishlist(wishlist, userID)
+}
+
+// findPollWishlist loads the wishlist, by its public ID, a poll is about. A poll about a wishlist
+// needs the wishlist shared to the group, and is kept from its owner. It writes a 404 response
+// and returns false otherwise.
+func findPollWishlist(ctx *gin.Context, groupID int, publicID string, userID int) (*model.Wishlist, bool) {
+	wishlist, err := app.GetRepository().Wishlist().GetByPublicID(publicID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, false
+	}
+
+	shared, err := app.GetRepository().Group().IsWishlistShared(groupID, wishlist.ID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to check wishlist share", err))
+		return nil, false
+	}
+	if !shared || ownsWishlist(wishlist, userID) {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, false
+	}
+
+	return wishlist, true
+}
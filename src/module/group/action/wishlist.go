@@ -0,0 +1,115 @@
+package action
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionShareWishlistToGroup shares one of the user's wishlists with every member of a group
+func ActionShareWishlistToGroup() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupWishlistRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		if _, ok := findMemberGroup(ctx, req.GroupID, userID); !ok {
+			return
+		}
+
+		wishlist, err := app.GetRepository().Wishlist().GetByPublicID(req.WishlistID)
+		if err != nil || !wishlist.IsOwnedBy(userID) {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+
+		if err := app.GetRepository().Group().ShareWishlist(req.GroupID, wishlist.ID, userID); err != nil {
+			ctx.Error(apperror.Internal("Failed to share wishlist", err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Wishlist shared with group successfully"))
+	}
+}
+
+// ActionUnshareWishlistFromGroup stops sharing one of the user's wishlists with a group
+func ActionUnshareWishlistFromGroup() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GroupWishlistRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, err := app.GetRepository().Wishlist().GetByPublicID(req.WishlistID)
+		if err != nil || !wishlist.IsOwnedBy(userID) {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+
+		if err := app.GetRepository().Group().UnshareWishlist(req.GroupID, wishlist.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to unshare wishlist", err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Wishlist unshared from group successfully"))
+	}
+}
+
+// ActionListGroupWishlists returns the combined view of wishlists shared to a group by its members
+func ActionListGroupWishlists() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		groupID, err := strconv.Atoi(ctx.Query("group_id"))
+		if err != nil {
+			ctx.Error(apperror.BadRequest("Invalid group_id"))
+			return
+		}
+
+		if _, ok := findMemberGroup(ctx, groupID, userID); !ok {
+			return
+		}
+
+		shared, err := app.GetRepository().Group().ListWishlists(groupID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve group wishlists", err))
+			return
+		}
+
+		languages := response.Languages(ctx)
+		for _, entry := range shared {
+			entry.Wishlist.Localize(languages)
+		}
+
+		response.OK(ctx, shared, response.Message(fmt.Sprintf("Retrieved %d group wishlists", len(shared))))
+	}
+}
@@ -0,0 +1,88 @@
+package group
+
+import (
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/database"
+	"github.com/alex-1900/wishlist/src/module/group/action"
+	"github.com/gin-gonic/gin"
+)
+
+// Module is the group module handling households and circles of friends
+type Module struct{}
+
+func init() {
+	app.RegisterModule(&Module{})
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return "group"
+}
+
+// Migrations returns the group schema migrations
+func (m *Module) Migrations() []database.Migration {
+	return []database.Migration{
+		{
+			Version: database.VersionCreateGroups,
+			Name:    "create_group_tables",
+			Up:      database.CreateGroupTables,
+			Down:    database.DropGroupTables,
+		},
+		{
+			Version: database.VersionCreateGroupNotes,
+			Name:    "create_group_notes_table",
+			Up:      database.CreateGroupNotesTable,
+			Down:    database.DropGroupNotesTable,
+		},
+		{
+			Version: database.VersionCreateGroupPolls,
+			Name:    "create_group_poll_tables",
+			Up:      database.CreateGroupPollTables,
+			Down:    database.DropGroupPollTables,
+		},
+	}
+}
+
+// Start is a no-op for the group module
+func (m *Module) Start(a *app.App) error {
+	return nil
+}
+
+// Stop is a no-op for the group module
+func (m *Module) Stop(a *app.App) error {
+	return nil
+}
+
+// RegisterRoutes registers all group-related routes
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	// All group routes require authentication
+	protected := router.Group("/")
+	protected.Use(auth.AuthMiddleware(app.GetJWTManager()))
+	{
+		protected.POST("/create-group", action.ActionCreateGroup())
+		protected.GET("/list-groups", action.ActionListGroups())
+		protected.POST("/delete-group", action.ActionDeleteGroup())
+
+		// Membership management
+		protected.GET("/list-group-members", action.ActionListGroupMembers())
+		protected.POST("/add-group-member", action.ActionAddGroupMember())
+		protected.POST("/remove-group-member", action.ActionRemoveGroupMember())
+
+		// Wishlists shared to the group
+		protected.POST("/share-wishlist-to-group", action.ActionShareWishlistToGroup())
+		protected.POST("/unshare-wishlist-from-group", action.ActionUnshareWishlistFromGroup())
+		protected.GET("/list-group-wishlists", action.ActionListGroupWishlists())
+
+		// Shared shopping notes, hidden from the wishlist owner
+		protected.GET("/group-wishlist-notes", action.ActionGetGroupNotes())
+		protected.POST("/update-group-wishlist-notes", action.ActionUpdateGroupNotes())
+
+		// Group polls
+		protected.POST("/create-group-poll", action.ActionCreateGroupPoll())
+		protected.GET("/list-group-polls", action.ActionListGroupPolls())
+		protected.GET("/group-poll", action.ActionGetGroupPoll())
+		protected.POST("/vote-group-poll", action.ActionVoteGroupPoll())
+		protected.POST("/delete-group-poll", action.ActionDeleteGroupPoll())
+	}
+}
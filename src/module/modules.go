@@ -0,0 +1,22 @@
+// Package module bundles every business module of the application.
+// Importing it registers each module with the App through their init functions.
+package module
+
+import (
+	// Account module: registration, authentication and profiles
+	_ "github.com/alex-1900/wishlist/src/module/account"
+	// API module: read-only public API for integrations holding an API key
+	_ "github.com/alex-1900/wishlist/src/module/api"
+	// Demo module: resettable sandbox of demo deployments
+	_ "github.com/alex-1900/wishlist/src/module/demo"
+	// Group module: households and circles of friends
+	_ "github.com/alex-1900/wishlist/src/module/group"
+	// Search module: full-text search over users, wishlists and items
+	_ "github.com/alex-1900/wishlist/src/module/search"
+	// SEO module: robots.txt and the sitemap of public profiles and wishlists
+	_ "github.com/alex-1900/wishlist/src/module/seo"
+	// Social module: follows between users
+	_ "github.com/alex-1900/wishlist/src/module/social"
+	// Wishlist module: wishlists owned by users
+	_ "github.com/alex-1900/wishlist/src/module/wishlist"
+)
@@ -0,0 +1,54 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionSearch searches usernames, wishlist titles and item titles, best matches first.
+// Narrow the result types with ?type=user,wishlist,item.
+func ActionSearch() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Anonymous visitors have no user ID
+		userID, _ := auth.GetUserID(ctx)
+
+		var req model.SearchRequest
+		var listReq model.ListRequest
+
+		// Bind query parameters to structs
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the requests
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		types, _ := req.Types()
+
+		results, page, err := app.GetRepository().Search().Search(req.Terms(), types, userID, listReq.ToOptions(ctx.Request.URL.Query()))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to search", err))
+			return
+		}
+
+		response.OK(ctx, results, response.Message(fmt.Sprintf("Found %d results", len(results))), response.Page(page))
+	}
+}
@@ -0,0 +1,52 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionBrowseTag lists the wishlists with items carrying a tag, those with the most tagged
+// items first. Narrow the items to a category with ?category=.
+func ActionBrowseTag() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Anonymous visitors have no user ID
+		userID, _ := auth.GetUserID(ctx)
+
+		var req model.TagBrowseRequest
+		var listReq model.ListRequest
+
+		// Bind query parameters to structs
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the requests
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlists, page, err := app.GetRepository().Search().BrowseTag(req.Tag, model.WishItemCategory(req.Category), userID, listReq.ToOptions(ctx.Request.URL.Query()))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to browse tag", err))
+			return
+		}
+
+		response.OK(ctx, wishlists, response.Message(fmt.Sprintf("Found %d wishlists tagged %q", len(wishlists), req.Tag)), response.Page(page))
+	}
+}
@@ -0,0 +1,51 @@
+package search
+
+import (
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/database"
+	"github.com/alex-1900/wishlist/src/module/search/action"
+	"github.com/gin-gonic/gin"
+)
+
+// Module is the search module handling full-text search over users, wishlists and items
+type Module struct{}
+
+func init() {
+	app.RegisterModule(&Module{})
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return "search"
+}
+
+// Migrations returns the search schema migrations
+func (m *Module) Migrations() []database.Migration {
+	return []database.Migration{
+		{
+			Version:  database.VersionAddSearchVectors,
+			Name:     "add_search_vectors",
+			Up:       database.AddSearchVectors,
+			Backfill: database.BackfillSearchVectors,
+			Down:     database.DropSearchVectors,
+		},
+	}
+}
+
+// Start is a no-op for the search module
+func (m *Module) Start(a *app.App) error {
+	return nil
+}
+
+// Stop is a no-op for the search module
+func (m *Module) Stop(a *app.App) error {
+	return nil
+}
+
+// RegisterRoutes registers all search routes
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	// Anonymous visitors may search and browse tags; they only find public wishlists and items
+	router.GET("/search", auth.OptionalAuthMiddleware(app.GetJWTManager()), action.ActionSearch())
+	router.GET("/browse-tag", auth.OptionalAuthMiddleware(app.GetJWTManager()), action.ActionBrowseTag())
+}
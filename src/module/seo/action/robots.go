@@ -0,0 +1,38 @@
+package action
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionRobotsTxt serves the crawl policy of Config.Robots, pointing crawlers to the sitemap
+// when it is enabled
+func ActionRobotsTxt() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		config := app.GetConfig()
+
+		var body strings.Builder
+		body.WriteString("User-agent: *\n")
+		switch {
+		case config.Robots.DisallowAll:
+			body.WriteString("Disallow: /\n")
+		case len(config.Robots.Disallow) == 0:
+			// An empty rule allows everything
+			body.WriteString("Disallow:\n")
+		default:
+			for _, path := range config.Robots.Disallow {
+				body.WriteString("Disallow: " + path + "\n")
+			}
+		}
+
+		if config.Sitemap.Enabled && !config.Robots.DisallowAll {
+			body.WriteString("\nSitemap: " + strings.TrimRight(config.Sitemap.BaseURL, "/") + "/sitemap.xml\n")
+		}
+
+		ctx.Header("Cache-Control", "public, max-age=3600")
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(body.String()))
+	}
+}
@@ -0,0 +1,82 @@
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/sitemap"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionSitemapIndex serves the sitemap index listing every page of the latest sitemap
+func ActionSitemapIndex(store *sitemap.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		snapshot, ok := loadSnapshot(ctx, store)
+		if !ok {
+			return
+		}
+
+		base := strings.TrimRight(app.GetConfig().Sitemap.BaseURL, "/")
+		pages := make([]sitemap.URL, len(snapshot.Pages))
+		for i := range snapshot.Pages {
+			pages[i] = sitemap.URL{Loc: fmt.Sprintf("%s/sitemaps/%d.xml", base, i+1), LastMod: snapshot.GeneratedAt}
+		}
+
+		var body bytes.Buffer
+		if err := sitemap.WriteIndex(&body, pages); err != nil {
+			ctx.Error(apperror.Internal("Failed to render sitemap index", err))
+			return
+		}
+		writeXML(ctx, body.Bytes())
+	}
+}
+
+// ActionSitemapPage serves one page of the latest sitemap, numbered from 1 as in /sitemaps/1.xml
+func ActionSitemapPage(store *sitemap.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		number, err := strconv.Atoi(strings.TrimSuffix(ctx.Param("page"), ".xml"))
+		if err != nil {
+			ctx.Error(apperror.NotFound("Sitemap not found"))
+			return
+		}
+
+		snapshot, ok := loadSnapshot(ctx, store)
+		if !ok {
+			return
+		}
+		if number < 1 || number > len(snapshot.Pages) {
+			ctx.Error(apperror.NotFound("Sitemap not found"))
+			return
+		}
+
+		var body bytes.Buffer
+		if err := sitemap.WritePage(&body, snapshot.Pages[number-1]); err != nil {
+			ctx.Error(apperror.Internal("Failed to render sitemap", err))
+			return
+		}
+		writeXML(ctx, body.Bytes())
+	}
+}
+
+// loadSnapshot returns the latest sitemap, reporting it as unavailable until the first one is generated
+func loadSnapshot(ctx *gin.Context, store *sitemap.Store) (*sitemap.Snapshot, bool) {
+	snapshot := store.Load()
+	if snapshot == nil {
+		ctx.Header("Retry-After", "60")
+		ctx.Error(apperror.Unavailable("The sitemap is being generated, please try again later"))
+		return nil, false
+	}
+	return snapshot, true
+}
+
+// writeXML writes an XML document that caches may keep until the next refresh
+func writeXML(ctx *gin.Context, body []byte) {
+	maxAge := app.GetConfig().Sitemap.RefreshInterval * 60
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	ctx.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
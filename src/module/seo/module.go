@@ -0,0 +1,137 @@
+package seo
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/database"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/module/seo/action"
+	"github.com/alex-1900/wishlist/src/sitemap"
+	"github.com/gin-gonic/gin"
+)
+
+// Module is the SEO module serving the crawl policy and, for SEO-enabled deployments, a sitemap
+// of public content. The sitemap is off unless Config.Sitemap.Enabled is set.
+type Module struct {
+	store *sitemap.Store
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func init() {
+	app.RegisterModule(&Module{store: sitemap.NewStore()})
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return "seo"
+}
+
+// Migrations returns nil, the sitemap is built from the tables of other modules
+func (m *Module) Migrations() []database.Migration {
+	return nil
+}
+
+// Start launches the job refreshing the sitemap, which generates the first one right away
+func (m *Module) Start(a *app.App) error {
+	config := a.Config.Sitemap
+	if !config.Enabled {
+		return nil
+	}
+	if config.BaseURL == "" || config.ProfileURL == "" || config.WishlistURL == "" {
+		return errors.New("sitemap BaseURL, ProfileURL and WishlistURL must be set")
+	}
+	if config.RefreshInterval <= 0 {
+		return errors.New("sitemap RefreshInterval must be positive")
+	}
+
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.run(a, time.Duration(config.RefreshInterval)*time.Minute)
+	return nil
+}
+
+// Stop stops the refresh job, waiting for a refresh in progress to finish
+func (m *Module) Stop(a *app.App) error {
+	if m.stop == nil {
+		return nil
+	}
+	close(m.stop)
+	<-m.done
+	return nil
+}
+
+// RegisterRoutes registers robots.txt, and the public sitemap routes when the sitemap is enabled
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	seo := router.Group("/")
+	seo.Use(app.GetCDN().Middleware(cdn.GroupSEO))
+
+	seo.GET("/robots.txt", action.ActionRobotsTxt())
+
+	if !app.GetConfig().Sitemap.Enabled {
+		return
+	}
+
+	seo.GET("/sitemap.xml", action.ActionSitemapIndex(m.store))
+	seo.GET("/sitemaps/:page", action.ActionSitemapPage(m.store))
+}
+
+// run refreshes the sitemap now and then every interval until the module is stopped
+func (m *Module) run(a *app.App, interval time.Duration) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.refresh(a); err != nil {
+			// The previous sitemap keeps being served
+			log.Printf("Failed to refresh sitemap: %v", err)
+		}
+
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh generates the sitemap from the public profiles and wishlists and replaces the served one
+func (m *Module) refresh(a *app.App) error {
+	config := a.Config.Sitemap
+	var urls []sitemap.URL
+
+	err := a.Repository.Sitemap().StreamProfiles(func(profile *model.SitemapProfile) error {
+		urls = append(urls, sitemap.URL{
+			Loc:     strings.ReplaceAll(config.ProfileURL, "{username}", url.PathEscape(profile.Username)),
+			LastMod: profile.UpdatedAt,
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list public profiles: %w", err)
+	}
+
+	err = a.Repository.Sitemap().StreamWishlists(func(wishlist *model.SitemapWishlist) error {
+		urls = append(urls, sitemap.URL{
+			Loc:     strings.ReplaceAll(config.WishlistURL, "{id}", wishlist.PublicID),
+			LastMod: wishlist.UpdatedAt,
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list public wishlists: %w", err)
+	}
+
+	m.store.Replace(urls, config.URLsPerPage, time.Now().UTC())
+	log.Printf("Sitemap refreshed with %d URLs", len(urls))
+	return nil
+}
@@ -0,0 +1,138 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionBlockUser blocks another user: neither can follow the other any more, existing follows
+// are removed and the blocked user can no longer view the authenticated user's wishlists
+func ActionBlockUser() gin.HandlerFunc {
+	return setBlock(model.BlockKindBlock, "You blocked %s")
+}
+
+// ActionUnblockUser removes the authenticated user's block of another user
+func ActionUnblockUser() gin.HandlerFunc {
+	return removeBlock(model.BlockKindBlock, "You unblocked %s")
+}
+
+// ActionMuteUser mutes another user, keeping them out of the authenticated user's notification feed
+func ActionMuteUser() gin.HandlerFunc {
+	return setBlock(model.BlockKindMute, "You muted %s")
+}
+
+// ActionUnmuteUser removes the authenticated user's mute of another user
+func ActionUnmuteUser() gin.HandlerFunc {
+	return removeBlock(model.BlockKindMute, "You unmuted %s")
+}
+
+// ActionListBlockedUsers returns the users the authenticated user blocked or muted.
+// Filter with ?kind=block|mute.
+func ActionListBlockedUsers() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		if kind := ctx.Query("kind"); kind != "" {
+			if err := model.BlockKind(kind).Validate(); err != nil {
+				ctx.Error(apperror.Validation(err))
+				return
+			}
+		}
+
+		users, page, err := app.GetRepository().Block().List(userID, listReq.ToOptions(ctx.Request.URL.Query(), "kind", "username"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve blocked users", err))
+			return
+		}
+
+		response.OK(ctx, users, response.Message(fmt.Sprintf("Retrieved %d blocked users", len(users))), response.Page(page))
+	}
+}
+
+// setBlock builds a handler blocking or muting the user named in the request
+func setBlock(kind model.BlockKind, message string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, target, ok := bindBlockRequest(ctx)
+		if !ok {
+			return
+		}
+
+		if target.ID == userID {
+			ctx.Error(apperror.BadRequest(fmt.Sprintf("You cannot %s yourself", kind)))
+			return
+		}
+
+		if err := app.GetRepository().Block().Set(userID, target.ID, kind); err != nil {
+			ctx.Error(apperror.Internal(fmt.Sprintf("Failed to %s user", kind), err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message(fmt.Sprintf(message, target.Username)))
+	}
+}
+
+// removeBlock builds a handler undoing a block or a mute of the user named in the request
+func removeBlock(kind model.BlockKind, message string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, target, ok := bindBlockRequest(ctx)
+		if !ok {
+			return
+		}
+
+		if err := app.GetRepository().Block().Remove(userID, target.ID, kind); err != nil {
+			ctx.Error(apperror.Internal(fmt.Sprintf("Failed to un%s user", kind), err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message(fmt.Sprintf(message, target.Username)))
+	}
+}
+
+// bindBlockRequest binds a block request and loads the user it names.
+// It writes an error response and returns false otherwise.
+func bindBlockRequest(ctx *gin.Context) (int, *model.User, bool) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.Error(apperror.Unauthorized("User not authenticated"))
+		return 0, nil, false
+	}
+
+	var req model.BlockRequest
+
+	// Bind JSON request to struct
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidRequest(err))
+		return 0, nil, false
+	}
+
+	target, ok := findUserByUsername(ctx, req.Username)
+	if !ok {
+		return 0, nil, false
+	}
+
+	return userID, target, true
+}
@@ -0,0 +1,198 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionFollowUser makes the authenticated user follow another user
+func ActionFollowUser() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.FollowRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		target, ok := findUserByUsername(ctx, req.Username)
+		if !ok {
+			return
+		}
+
+		if target.ID == userID {
+			ctx.Error(apperror.BadRequest("You cannot follow yourself"))
+			return
+		}
+
+		blocked, err := app.GetRepository().Block().IsBlockedEither(userID, target.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to check block", err))
+			return
+		}
+		if blocked {
+			ctx.Error(apperror.Forbidden("You cannot follow this user").WithCode(apperror.CodeUserBlocked))
+			return
+		}
+
+		created, err := app.GetRepository().Follow().Follow(userID, target.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to follow user", err))
+			return
+		}
+
+		if created {
+			if follower, err := app.GetRepository().User().GetByID(userID); err == nil {
+				notify(model.NewFollowedNotification(target.ID, follower))
+			}
+		}
+
+		response.OK(ctx, nil, response.Message(fmt.Sprintf("You are now following %s", target.Username)))
+	}
+}
+
+// ActionUnfollowUser makes the authenticated user stop following another user
+func ActionUnfollowUser() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.FollowRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		target, ok := findUserByUsername(ctx, req.Username)
+		if !ok {
+			return
+		}
+
+		if err := app.GetRepository().Follow().Unfollow(userID, target.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to unfollow user", err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message(fmt.Sprintf("You are no longer following %s", target.Username)))
+	}
+}
+
+// ActionListFollowers returns the followers of a user, the authenticated user by default
+func ActionListFollowers() gin.HandlerFunc {
+	return listFollows("followers", func(userID int, opts model.ListOptions) ([]*model.FollowUser, *model.PageInfo, error) {
+		return app.GetRepository().Follow().ListFollowers(userID, opts)
+	})
+}
+
+// ActionListFollowing returns the users a user follows, the authenticated user by default
+func ActionListFollowing() gin.HandlerFunc {
+	return listFollows("followed users", func(userID int, opts model.ListOptions) ([]*model.FollowUser, *model.PageInfo, error) {
+		return app.GetRepository().Follow().ListFollowing(userID, opts)
+	})
+}
+
+// ActionCheckMutualFollow reports the follow relationship between the authenticated user and another user
+func ActionCheckMutualFollow() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		target, ok := findUserByUsername(ctx, ctx.Query("username"))
+		if !ok {
+			return
+		}
+
+		followRepo := app.GetRepository().Follow()
+
+		following, err := followRepo.IsFollowing(userID, target.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to check follow", err))
+			return
+		}
+
+		followedBy, err := followRepo.IsFollowing(target.ID, userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to check follow", err))
+			return
+		}
+
+		response.OK(ctx, &model.FollowStatus{
+			Following:  following,
+			FollowedBy: followedBy,
+			Mutual:     following && followedBy,
+		}, response.Message("Follow status retrieved successfully"))
+	}
+}
+
+// listFollows builds a handler listing one side of a user's follow relationships
+func listFollows(what string, list func(userID int, opts model.ListOptions) ([]*model.FollowUser, *model.PageInfo, error)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		if username := ctx.Query("of"); username != "" {
+			target, ok := findUserByUsername(ctx, username)
+			if !ok {
+				return
+			}
+			userID = target.ID
+		}
+
+		users, page, err := list(userID, listReq.ToOptions(ctx.Request.URL.Query(), "username"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve "+what, err))
+			return
+		}
+
+		response.OK(ctx, users, response.Message(fmt.Sprintf("Retrieved %d %s", len(users), what)), response.Page(page))
+	}
+}
+
+// findUserByUsername loads a user by username, writing a 404 response and returning false if missing
+func findUserByUsername(ctx *gin.Context, username string) (*model.User, bool) {
+	user, err := app.GetRepository().User().GetByUsername(username)
+	if err != nil || username == "" {
+		ctx.Error(apperror.NotFound("User not found"))
+		return nil, false
+	}
+
+	return user, true
+}
@@ -0,0 +1,161 @@
+package action
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/realtime"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionListNotifications returns the authenticated user's notification feed, newest first.
+// Filter with ?status=unread|read and ?type=.
+func ActionListNotifications() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		notificationRepo := app.GetRepository().Notification()
+
+		notifications, page, err := notificationRepo.ListByUser(userID, listReq.ToOptions(ctx.Request.URL.Query(), "status", "type"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve notifications", err))
+			return
+		}
+
+		unread, err := notificationRepo.CountUnread(userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to count unread notifications", err))
+			return
+		}
+
+		response.OK(ctx, notifications,
+			response.Message(fmt.Sprintf("Retrieved %d notifications", len(notifications))),
+			response.Meta("unread_count", unread),
+			response.Page(page),
+		)
+	}
+}
+
+// ActionMarkNotificationsRead marks notifications of the authenticated user as read, all of them when no IDs are given
+func ActionMarkNotificationsRead() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.NotificationReadRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		notificationRepo := app.GetRepository().Notification()
+
+		var marked int64
+		var err error
+		if len(req.IDs) == 0 {
+			marked, err = notificationRepo.MarkAllRead(userID)
+		} else {
+			marked, err = notificationRepo.MarkRead(userID, req.IDs)
+		}
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to mark notifications as read", err))
+			return
+		}
+
+		response.OK(ctx, gin.H{"marked": marked}, response.Message(fmt.Sprintf("Marked %d notifications as read", marked)))
+	}
+}
+
+// ActionClearNotifications deletes the authenticated user's notification feed
+func ActionClearNotifications() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		cleared, err := app.GetRepository().Notification().Clear(userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to clear notifications", err))
+			return
+		}
+
+		response.OK(ctx, gin.H{"cleared": cleared}, response.Message(fmt.Sprintf("Cleared %d notifications", cleared)))
+	}
+}
+
+// notify adds a notification to a feed and pushes it to the user's open connections.
+// Notifications are a side effect of the request, so failures are logged rather than returned.
+func notify(notification *model.Notification) {
+	if notification.ActorID != nil {
+		kind, err := app.GetRepository().Block().Get(notification.UserID, *notification.ActorID)
+		if err != nil {
+			log.Printf("Failed to check block before notifying user %d: %v", notification.UserID, err)
+			return
+		}
+		// Blocked and muted users stay out of the feed
+		if kind != "" {
+			return
+		}
+	}
+
+	if err := app.GetRepository().Notification().Create(notification); err != nil {
+		log.Printf("Failed to send %s notification to user %d: %v", notification.Type, notification.UserID, err)
+		return
+	}
+	app.GetRealtimeHub().Publish(notification.UserID, realtime.Event{Type: realtime.EventNotification, Data: notification})
+}
+
+// ActionRealtime upgrades the connection to a WebSocket that receives the authenticated
+// user's new notifications and wishlist changes as JSON events
+func ActionRealtime() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		// The connection lasts as long as the access token it was opened with
+		var tokenID string
+		var expiresAt time.Time
+		if claims, ok := auth.GetClaims(ctx); ok {
+			tokenID = claims.ID
+			if claims.ExpiresAt != nil {
+				expiresAt = claims.ExpiresAt.Time
+			}
+		}
+
+		app.GetRealtimeHub().Handler(userID, tokenID, expiresAt).ServeHTTP(ctx.Writer, ctx.Request)
+	}
+}
@@ -0,0 +1,50 @@
+package action
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// SendDateReminders notifies the friends of users whose birthday or important date falls in
+// daysBefore days, linking a wishlist of the user they may view. Each occasion is announced once,
+// so the job can run often and on several instances.
+func SendDateReminders(daysBefore int) error {
+	reminderRepo := app.GetRepository().Reminder()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	on := today.AddDate(0, 0, daysBefore)
+
+	occasions, err := reminderRepo.ListOccasions(on)
+	if err != nil {
+		return fmt.Errorf("failed to list upcoming occasions: %w", err)
+	}
+
+	for _, occasion := range occasions {
+		claimed, err := reminderRepo.Claim(occasion)
+		if err != nil {
+			return fmt.Errorf("failed to claim reminders of user %d: %w", occasion.UserID, err)
+		}
+		if !claimed {
+			continue
+		}
+
+		recipients, err := reminderRepo.ListRecipients(occasion.UserID)
+		if err != nil {
+			// The occasion is claimed, so its reminders are skipped rather than sent twice
+			log.Printf("Failed to list friends to remind of %s of user %d: %v", occasion.Key(), occasion.UserID, err)
+			continue
+		}
+		for _, recipient := range recipients {
+			notify(model.NewUpcomingDateNotification(recipient.UserID, occasion, recipient.WishlistID))
+		}
+	}
+
+	// Occasions before today can no longer be claimed again
+	if _, err := reminderRepo.Prune(today); err != nil {
+		return fmt.Errorf("failed to prune sent reminders: %w", err)
+	}
+	return nil
+}
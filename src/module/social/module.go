@@ -0,0 +1,136 @@
+package social
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/database"
+	"github.com/alex-1900/wishlist/src/module/social/action"
+	"github.com/gin-gonic/gin"
+)
+
+// Module is the social module handling the follow graph between users, blocks and mutes, and
+// notifications, including the reminders of friends' birthdays and important dates
+type Module struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+func init() {
+	app.RegisterModule(&Module{})
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return "social"
+}
+
+// Migrations returns the social schema migrations
+func (m *Module) Migrations() []database.Migration {
+	return []database.Migration{
+		{
+			Version: database.VersionCreateFollows,
+			Name:    "create_follows_table",
+			Up:      database.CreateFollowsTable,
+			Down:    database.DropFollowsTable,
+		},
+		{
+			Version: database.VersionCreateNotifications,
+			Name:    "create_notifications_table",
+			Up:      database.CreateNotificationsTable,
+			Down:    database.DropNotificationsTable,
+		},
+		{
+			Version: database.VersionCreateBlocks,
+			Name:    "create_blocks_table",
+			Up:      database.CreateBlocksTable,
+			Down:    database.DropBlocksTable,
+		},
+		{
+			Version: database.VersionCreateDateReminders,
+			Name:    "create_date_reminders_table",
+			Up:      database.CreateDateRemindersTable,
+			Down:    database.DropDateRemindersTable,
+		},
+	}
+}
+
+// Start launches the job reminding friends of upcoming birthdays and important dates
+func (m *Module) Start(a *app.App) error {
+	config := a.Config.Reminders
+	if !config.Enabled {
+		return nil
+	}
+	if config.DaysBefore < 0 {
+		return errors.New("reminders DaysBefore must not be negative")
+	}
+	if config.CheckInterval <= 0 {
+		return errors.New("reminders CheckInterval must be positive")
+	}
+
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.run(config.DaysBefore, time.Duration(config.CheckInterval)*time.Minute)
+	return nil
+}
+
+// Stop stops the reminder job, waiting for a run in progress to finish
+func (m *Module) Stop(a *app.App) error {
+	if m.stop == nil {
+		return nil
+	}
+	close(m.stop)
+	<-m.done
+	return nil
+}
+
+// RegisterRoutes registers all social routes
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	// All social routes require authentication
+	protected := router.Group("/")
+	protected.Use(auth.AuthMiddleware(app.GetJWTManager()))
+	{
+		protected.POST("/follow-user", action.ActionFollowUser())
+		protected.POST("/unfollow-user", action.ActionUnfollowUser())
+		protected.GET("/list-followers", action.ActionListFollowers())
+		protected.GET("/list-following", action.ActionListFollowing())
+		protected.GET("/check-mutual-follow", action.ActionCheckMutualFollow())
+
+		protected.POST("/block-user", action.ActionBlockUser())
+		protected.POST("/unblock-user", action.ActionUnblockUser())
+		protected.POST("/mute-user", action.ActionMuteUser())
+		protected.POST("/unmute-user", action.ActionUnmuteUser())
+		protected.GET("/list-blocked-users", action.ActionListBlockedUsers())
+
+		protected.GET("/list-notifications", action.ActionListNotifications())
+		protected.POST("/mark-notifications-read", action.ActionMarkNotificationsRead())
+		protected.POST("/clear-notifications", action.ActionClearNotifications())
+	}
+
+	// Browsers cannot set headers on a WebSocket handshake, so the token may come from the query
+	router.GET("/ws", auth.WebSocketAuthMiddleware(app.GetJWTManager()), action.ActionRealtime())
+}
+
+// run sends the date reminders now and then every interval until the module is stopped
+func (m *Module) run(daysBefore int, interval time.Duration) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := action.SendDateReminders(daysBefore); err != nil {
+			// Occasions not claimed yet are picked up by the next run
+			log.Printf("Failed to send date reminders: %v", err)
+		}
+
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package action
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+)
+
+// ArchiveEndedWishlists archives the wishlists whose event date is more than archiveAfterDays
+// days past. Event dates are days, so a wishlist is only archived once its whole day is over.
+func ArchiveEndedWishlists(archiveAfterDays int) error {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	archived, err := app.GetRepository().Wishlist().ArchiveEnded(today.AddDate(0, 0, -archiveAfterDays), now)
+	if err != nil {
+		return fmt.Errorf("failed to archive ended wishlists: %w", err)
+	}
+	if archived > 0 {
+		log.Printf("Archived %d wishlists whose event is over", archived)
+	}
+	return nil
+}
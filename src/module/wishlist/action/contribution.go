@@ -0,0 +1,140 @@
+package action
+
+import (
+	"errors"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionPledgeContribution pledges an amount towards an item on a wishlist shared with the
+// authenticated user, to buy it together with other givers. The contributors are notified once
+// the pledges reach the price.
+func ActionPledgeContribution() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.ContributionRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		item, wishlist, ok := findViewableWishItem(ctx, req.ItemID, userID)
+		if !ok || !checkNotArchived(ctx, wishlist) {
+			return
+		}
+
+		contribution := &model.Contribution{
+			ItemID:      item.ID,
+			UserID:      userID,
+			AmountMinor: currency.ToMinor(req.Amount, item.Currency),
+			Currency:    item.Currency,
+		}
+		if contribution.AmountMinor <= 0 {
+			ctx.Error(apperror.Validation(errors.New("amount validation failed: amount is below the smallest unit of the currency")))
+			return
+		}
+		contribution.BeforeCreate()
+
+		funded, err := app.GetRepository().Contribution().Pledge(contribution)
+		if err != nil {
+			if errors.Is(err, model.ErrItemNotPriced) {
+				ctx.Error(apperror.Conflict("Item has no price to contribute towards").WithCode(apperror.CodeItemNotPriced))
+				return
+			}
+			if errors.Is(err, model.ErrPledgeTooLarge) {
+				ctx.Error(apperror.Conflict("Pledge exceeds the amount still needed").WithCode(apperror.CodePledgeTooLarge))
+				return
+			}
+			ctx.Error(apperror.Internal("Failed to pledge towards wish item", err))
+			return
+		}
+
+		if funded {
+			notifyGiftFunded(item)
+		}
+
+		respondWithGiverItem(ctx, wishlist, item, userID, "Contribution pledged successfully")
+	}
+}
+
+// ActionWithdrawContribution withdraws the authenticated user's pledge towards an item
+func ActionWithdrawContribution() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.ContributionWithdrawRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		item, wishlist, ok := findViewableWishItem(ctx, req.ItemID, userID)
+		if !ok {
+			return
+		}
+
+		if err := app.GetRepository().Contribution().Withdraw(item.ID, userID); err != nil {
+			ctx.Error(apperror.NotFound("Contribution not found"))
+			return
+		}
+
+		respondWithGiverItem(ctx, wishlist, item, userID, "Contribution withdrawn successfully")
+	}
+}
+
+// notifyGiftFunded tells every giver who pledged towards an item that the contributions reached
+// its price. The owner is left out, so the surprise is kept, and givers who may no longer view
+// the wishlist are skipped.
+func notifyGiftFunded(item *model.WishItem) {
+	wishlist, err := app.GetRepository().Wishlist().GetByID(item.WishlistID)
+	if err != nil {
+		log.Printf("Failed to notify contributors of wish item %d: %v", item.ID, err)
+		return
+	}
+
+	contributions, err := app.GetRepository().Contribution().ListByWishlist(wishlist.ID)
+	if err != nil {
+		log.Printf("Failed to notify contributors of wish item %d: %v", item.ID, err)
+		return
+	}
+
+	for _, contribution := range contributions {
+		if contribution.ItemID != item.ID || contribution.Currency != item.Currency {
+			continue
+		}
+		visible, err := access.CanViewWishlist(app.GetRepository(), wishlist, contribution.UserID)
+		if err != nil {
+			log.Printf("Failed to check wishlist access before notifying user %d: %v", contribution.UserID, err)
+			continue
+		}
+		if visible {
+			notify(model.NewGiftFundedNotification(contribution.UserID, wishlist, item))
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package action
+
+import (
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// withDisplayPrices adds the prices converted to the display currency the viewer asked for.
+// Items without a price, or whose currency has no known rate, are left as they are.
+func withDisplayPrices(ctx *gin.Context, responses ...*model.WishItemResponse) {
+	if to := response.DisplayCurrency(ctx); to != "" {
+		model.WithDisplayPrices(ctx.Request.Context(), app.GetCurrency(), to, responses...)
+	}
+}
+
+// displayTotal converts the total value of every currency of a wishlist to the display currency
+// the viewer asked for, nil when none was asked for or a rate is missing
+func displayTotal(ctx *gin.Context, stats *model.WishlistStats) *model.Money {
+	to := response.DisplayCurrency(ctx)
+	if to == "" {
+		return nil
+	}
+
+	total := currency.Amount{Currency: to}
+	for _, byCurrency := range stats.Currencies {
+		converted, err := app.GetCurrency().Convert(ctx.Request.Context(), currency.Amount{Minor: byCurrency.TotalValueMinor, Currency: byCurrency.Currency}, to)
+		if err != nil {
+			return nil
+		}
+		total.Minor += converted.Minor
+	}
+	return model.NewMoney(total)
+}
@@ -0,0 +1,167 @@
+package action
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/pdf"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionExportWishlist returns a wishlist the authenticated user is a member of or may view as
+// a file to download, with its items in their order: CSV with the columns imports read, or PDF
+// for printing. Exports never show reservations or contributions; viewers get the titles in
+// their language like /view-wishlist.
+func ActionExportWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistExportRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, err := app.GetRepository().Wishlist().GetByPublicID(ctx.Param("id"))
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+		role, err := access.WishlistRole(app.GetRepository(), wishlist, userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+			return
+		}
+		if role == "" {
+			if _, ok := checkViewableWishlist(ctx, wishlist, userID); !ok {
+				return
+			}
+		}
+
+		items, _, err := app.GetRepository().WishItem().ListByWishlist(wishlist.ID, model.ListOptions{})
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wish items", err))
+			return
+		}
+
+		if role == "" {
+			languages := response.Languages(ctx)
+			localizeItems(items, languages, wishlist.Language)
+			wishlist.Localize(languages)
+		}
+
+		var body bytes.Buffer
+		contentType := "text/csv; charset=utf-8"
+		format := model.WishlistExportFormat(req.Format)
+		switch format {
+		case model.ExportPDF:
+			contentType = "application/pdf"
+			if _, err := exportPDF(wishlist, items).WriteTo(&body); err != nil {
+				ctx.Error(apperror.Internal("Failed to export wishlist", err))
+				return
+			}
+		default:
+			format = model.ExportCSV
+			if err := exportCSV(&body, items); err != nil {
+				ctx.Error(apperror.Internal("Failed to export wishlist", err))
+				return
+			}
+		}
+
+		ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": exportFilename(wishlist.Title) + "." + string(format),
+		}))
+		ctx.Data(http.StatusOK, contentType, body.Bytes())
+	}
+}
+
+// exportCSV writes the items as CSV, a header line naming model.WishItemCSVColumns first
+func exportCSV(body *bytes.Buffer, items []*model.WishItem) error {
+	writer := csv.NewWriter(body)
+	if err := writer.Write(model.WishItemCSVColumns); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := writer.Write(item.CSVRecord()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportPDF lays the wishlist out for printing: its title and event, then each item with its
+// price, quantity, priority, category, tags, description and link
+func exportPDF(wishlist *model.Wishlist, items []*model.WishItem) *pdf.Document {
+	doc := pdf.New(wishlist.Title)
+	doc.Write(pdf.Heading, wishlist.Title)
+
+	var about []string
+	if wishlist.EventType != model.EventNone {
+		event := strings.ReplaceAll(string(wishlist.EventType), "_", " ")
+		if wishlist.EventDate != nil {
+			event += " on " + wishlist.EventDate.Format("2006-01-02")
+		}
+		about = append(about, event)
+	}
+	about = append(about, fmt.Sprintf("%d items", len(items)), "exported "+time.Now().UTC().Format("2006-01-02"))
+	doc.Write(pdf.Note, strings.Join(about, " · "))
+
+	for n, item := range items {
+		doc.Space(12)
+		doc.Write(pdf.Strong, fmt.Sprintf("%d. %s", n+1, item.Title))
+
+		details := []string{fmt.Sprintf("Priority %d of 5", item.Priority)}
+		if item.PriceMinor > 0 {
+			details = append([]string{item.Amount().String()}, details...)
+		}
+		if item.Quantity > 1 {
+			details = append(details, fmt.Sprintf("Quantity %d", item.Quantity))
+		}
+		if item.Category != "" {
+			details = append(details, string(item.Category))
+		}
+		if len(item.Tags) > 0 {
+			details = append(details, "#"+strings.Join(item.Tags, " #"))
+		}
+		doc.Write(pdf.Body, strings.Join(details, " · "))
+
+		if item.Description != "" {
+			doc.Write(pdf.Body, item.Description)
+		}
+		if item.URL != "" {
+			doc.Write(pdf.Note, item.URL)
+		}
+	}
+	return doc
+}
+
+// exportFilename turns a wishlist title into a file name, keeping letters and digits and
+// joining words with hyphens
+func exportFilename(title string) string {
+	name := strings.Join(strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "-")
+	if name == "" {
+		return "wishlist"
+	}
+	return name
+}
@@ -0,0 +1,162 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionMarkWishItemPurchased marks the authenticated user's reservation of an item purchased,
+// adding it to their gift history. A purchased reservation can no longer be changed or released.
+func ActionMarkWishItemPurchased() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GiftPurchaseRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		item, wishlist, ok := findViewableWishItem(ctx, req.ItemID, userID)
+		if !ok {
+			return
+		}
+
+		if _, err := app.GetRepository().Gift().RecordPurchase(item.ID, userID, time.Now().UTC()); err != nil {
+			switch {
+			case errors.Is(err, model.ErrNoReservation):
+				ctx.Error(apperror.NotFound("Reservation not found"))
+			case errors.Is(err, model.ErrReservationPurchased):
+				ctx.Error(apperror.Conflict("Reservation is already purchased").WithCode(apperror.CodeReservationPurchased))
+			default:
+				ctx.Error(apperror.Internal("Failed to mark wish item purchased", err))
+			}
+			return
+		}
+
+		respondWithGiverItem(ctx, wishlist, item, userID, "Wish item marked purchased successfully")
+	}
+}
+
+// ActionMarkWishItemReceived adds units to the received quantity of an item of a wishlist the
+// authenticated user owns or edits, adding them to the gift history of the wishlist's owner
+func ActionMarkWishItemReceived() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.GiftReceiptRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+		if req.Quantity == 0 {
+			req.Quantity = 1
+		}
+
+		item, wishlist, ok := findEditableWishItem(ctx, req.ItemID, userID)
+		if !ok {
+			return
+		}
+
+		if _, err := app.GetRepository().Gift().RecordReceipt(item.ID, req.Quantity, time.Now().UTC()); err != nil {
+			if errors.Is(err, model.ErrItemFullyReceived) {
+				ctx.Error(apperror.Conflict("Item is already fully received").WithCode(apperror.CodeItemFullyReceived))
+				return
+			}
+			ctx.Error(apperror.Internal("Failed to mark wish item received", err))
+			return
+		}
+
+		item, err := app.GetRepository().WishItem().GetByID(item.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wish item", err))
+			return
+		}
+
+		publishWishlistChange(wishlist, item.ID, "item_updated")
+		response.OK(ctx, item.ToResponse(), response.Message("Wish item marked received successfully"))
+	}
+}
+
+// ActionListGiftHistory retrieves a page of the gifts the authenticated user gave and received
+func ActionListGiftHistory() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		gifts, page, err := app.GetRepository().Gift().ListByUser(userID, listReq.ToOptions(ctx.Request.URL.Query(), "direction", "year"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve gift history", err))
+			return
+		}
+
+		responses := make([]*model.GiftResponse, len(gifts))
+		for i, gift := range gifts {
+			responses[i] = gift.ToResponse()
+		}
+
+		response.OK(ctx, responses, response.Message(fmt.Sprintf("Retrieved %d gifts", len(gifts))), response.Page(page))
+	}
+}
+
+// ActionGiftHistorySummary returns the yearly summaries of the authenticated user's gift history
+func ActionGiftHistorySummary() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		summaries, err := app.GetRepository().Gift().SummarizeByYear(userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to summarize gift history", err))
+			return
+		}
+
+		response.OK(ctx, summaries, response.Message("Gift history summary retrieved successfully"))
+	}
+}
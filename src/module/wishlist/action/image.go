@@ -0,0 +1,75 @@
+package action
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/alex-1900/wishlist/src/storage"
+	"github.com/alex-1900/wishlist/src/upload"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionUploadWishItemImage replaces the image of an item on a wishlist the authenticated user
+// owns or edits with the image in the "image" form field, described by the "alt" field
+func ActionUploadWishItemImage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		itemID, err := strconv.Atoi(ctx.Param("itemID"))
+		if err != nil {
+			ctx.Error(apperror.BadRequest("Invalid item id"))
+			return
+		}
+
+		wishlist, ok := findEditableWishlist(ctx, ctx.Param("id"), userID)
+		if !ok {
+			return
+		}
+		item, err := app.GetRepository().WishItem().GetByID(itemID)
+		if err != nil || item.WishlistID != wishlist.ID {
+			ctx.Error(apperror.NotFound("Wish item not found"))
+			return
+		}
+
+		image, alt, ok := upload.ReadImage(ctx, app.GetConfig().Storage.MaxUploadKB)
+		if !ok {
+			return
+		}
+
+		key, err := storage.NewKey(fmt.Sprintf("items/%d", item.ID), image.Extension)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to store image", err))
+			return
+		}
+		if err := app.GetStorage().Put(ctx.Request.Context(), key, image.Data, image.ContentType); err != nil {
+			ctx.Error(apperror.Internal("Failed to store image", err))
+			return
+		}
+		if err := app.GetRepository().WishItem().SetImage(item.ID, key, alt); err != nil {
+			ctx.Error(apperror.Internal("Failed to save image", err))
+			return
+		}
+		app.GetImages().Process(key, image)
+
+		// The previous image is no longer referenced
+		if item.ImageKey != "" {
+			if err := app.GetImages().Remove(ctx.Request.Context(), item.ImageKey); err != nil {
+				log.Printf("Failed to delete previous image %s: %v", item.ImageKey, err)
+			}
+		}
+
+		item.ImageKey = key
+		item.ImageAlt = alt
+		publishWishlistChange(wishlist, item.ID, "item_updated")
+		response.OK(ctx, item.ToResponse(), response.Message("Wish item image updated successfully"))
+	}
+}
@@ -0,0 +1,351 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/alex-1900/wishlist/src/scraper"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionImportWishlist queues the import of the items of an uploaded CSV file or of a public
+// Amazon wish list into a wishlist the authenticated user owns or edits, or into a new one. The
+// rows are imported in the background; the response links to the status of the import.
+func ActionImportWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		config := app.GetConfig().Imports
+		maxBytes := int64(config.MaxFileKB) * 1024
+		// Leave room for the multipart envelope around the file
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytes+64*1024)
+
+		var req model.WishlistImportRequest
+
+		// Bind form or JSON request to struct
+		if err := ctx.ShouldBind(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				ctx.Error(apperror.PayloadTooLarge(fmt.Sprintf("File must not exceed %d KB", config.MaxFileKB)))
+				return
+			}
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		imp := &model.WishlistImport{UserID: userID}
+		data, hasFile, ok := readImportFile(ctx, maxBytes)
+		if !ok {
+			return
+		}
+		switch {
+		case hasFile && req.URL != "":
+			ctx.Error(apperror.BadRequest("Upload a CSV file or give an Amazon wish list url, not both"))
+			return
+		case hasFile:
+			rows, err := model.ParseImportCSV(data)
+			if err == nil && len(rows) == 0 {
+				err = errors.New("the file has no rows")
+			} else if err == nil && len(rows) > config.MaxRows {
+				err = fmt.Errorf("the file has more than %d rows", config.MaxRows)
+			}
+			if err != nil {
+				ctx.Error(apperror.Validation(fmt.Errorf("file validation failed: %w", err)))
+				return
+			}
+			imp.Source = model.ImportSourceCSV
+			imp.Payload = data
+			imp.TotalRows = len(rows)
+		case req.URL != "":
+			if err := scraper.CheckAmazonWishlistURL(req.URL); err != nil {
+				ctx.Error(apperror.BadRequest(err.Error()))
+				return
+			}
+			imp.Source = model.ImportSourceAmazon
+			imp.SourceURL = req.URL
+		default:
+			ctx.Error(apperror.BadRequest("A CSV file in the \"file\" field or an Amazon wish list url is required"))
+			return
+		}
+
+		wishlist, created, ok := importTarget(ctx, &req, userID)
+		if !ok {
+			return
+		}
+
+		imp.WishlistID = wishlist.ID
+		imp.BeforeCreate()
+		var newWishlist *model.Wishlist
+		if created {
+			newWishlist = wishlist
+		}
+		if err := app.GetRepository().WishlistImport().Create(imp, newWishlist); err != nil {
+			if errors.Is(err, model.ErrImportInProgress) {
+				ctx.Error(apperror.Conflict("Another import is still in progress").WithCode(apperror.CodeImportInProgress))
+				return
+			}
+			ctx.Error(apperror.Internal("Failed to start wishlist import", err))
+			return
+		}
+		if created {
+			publishWishlistChange(wishlist, 0, "created")
+		}
+
+		response.Accepted(ctx, imp.ToResponse(wishlist.PublicID),
+			response.Message("Wishlist import started"),
+			response.Link("status", fmt.Sprintf("/wishlists/import/%d", imp.ID)),
+			response.WishlistLinks(wishlist.PublicID))
+	}
+}
+
+// ActionGetWishlistImport returns the progress of an import started by the authenticated user,
+// with the errors of the rows that were not imported
+func ActionGetWishlistImport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistImportStatusRequest
+
+		// Bind route parameters to struct
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		imp, err := app.GetRepository().WishlistImport().GetByID(req.ID)
+		if err != nil || imp.UserID != userID {
+			ctx.Error(apperror.NotFound("Wishlist import not found"))
+			return
+		}
+
+		wishlist, err := app.GetRepository().Wishlist().GetByID(imp.WishlistID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wishlist import not found"))
+			return
+		}
+
+		response.OK(ctx, imp.ToResponse(wishlist.PublicID), response.Message("Wishlist import status retrieved successfully"))
+	}
+}
+
+// readImportFile reads the CSV file uploaded in the "file" field, reporting whether there is
+// one. It writes the error response and returns false when the file is too large or not text.
+func readImportFile(ctx *gin.Context, maxBytes int64) (string, bool, bool) {
+	file, _, err := ctx.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.Error(apperror.PayloadTooLarge(fmt.Sprintf("File must not exceed %d KB", maxBytes/1024)))
+			return "", false, false
+		}
+		// Not a multipart form, or no file in it
+		return "", false, true
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		ctx.Error(apperror.BadRequest("Failed to read the uploaded file"))
+		return "", false, false
+	}
+	if int64(len(data)) > maxBytes {
+		ctx.Error(apperror.PayloadTooLarge(fmt.Sprintf("File must not exceed %d KB", maxBytes/1024)))
+		return "", false, false
+	}
+	if !utf8.Valid(data) {
+		ctx.Error(apperror.UnsupportedMediaType("File must be a CSV file encoded in UTF-8"))
+		return "", false, false
+	}
+	return string(data), true, true
+}
+
+// importTarget returns the wishlist an import fills: the one named by the request, which the
+// user must be allowed to edit, or a new private wishlist of the user, reported as created. The
+// new wishlist is only stored along with the import.
+func importTarget(ctx *gin.Context, req *model.WishlistImportRequest, userID int) (*model.Wishlist, bool, bool) {
+	if req.WishlistID != "" {
+		wishlist, ok := findEditableWishlist(ctx, req.WishlistID, userID)
+		return wishlist, false, ok
+	}
+
+	wishlist := &model.Wishlist{UserID: userID, Title: req.Title}
+	wishlist.BeforeCreate()
+	return wishlist, true, true
+}
+
+// importEntry is a row of an import, with what the Amazon wish list tells about its item
+type importEntry struct {
+	*model.ImportRow
+	listed *scraper.AmazonItem
+}
+
+// RunNextImport claims the oldest waiting import and imports its rows, up to maxRows of them,
+// resuming after the rows a stopped run already processed. It reports whether there was an
+// import to run. Rows that cannot be imported are recorded with their error, the others are
+// imported anyway; an import fails as a whole only when its source cannot be read.
+func RunNextImport(maxRows int) (bool, error) {
+	importRepo := app.GetRepository().WishlistImport()
+	now := time.Now().UTC()
+
+	imp, err := importRepo.ClaimNext(now, now.Add(-model.ImportStaleAfter))
+	if err != nil {
+		return false, err
+	}
+	if imp == nil {
+		return false, nil
+	}
+
+	wishlist, err := app.GetRepository().Wishlist().GetByID(imp.WishlistID)
+	if err != nil {
+		return true, finishImport(imp, fmt.Errorf("failed to load wishlist: %w", err))
+	}
+	entries, err := readImportEntries(imp, maxRows)
+	if err != nil {
+		return true, finishImport(imp, err)
+	}
+
+	imp.TotalRows = len(entries)
+	for _, entry := range entries[min(imp.ProcessedRows, len(entries)):] {
+		if err := importEntryItem(wishlist, entry); err != nil {
+			imp.RowErrors = append(imp.RowErrors, model.ImportRowError{Row: entry.Row, Error: err.Error()})
+		} else {
+			imp.ImportedRows++
+		}
+		imp.ProcessedRows++
+
+		// A run stopped midway resumes from the last saved row
+		if err := importRepo.UpdateProgress(imp); err != nil {
+			return true, err
+		}
+	}
+
+	if imp.ImportedRows > 0 {
+		publishWishlistChange(wishlist, 0, "items_imported")
+	}
+	return true, finishImport(imp, nil)
+}
+
+// readImportEntries reads the rows of an import from its CSV file or its Amazon wish list
+func readImportEntries(imp *model.WishlistImport, maxRows int) ([]*importEntry, error) {
+	var entries []*importEntry
+
+	switch imp.Source {
+	case model.ImportSourceCSV:
+		rows, err := model.ParseImportCSV(imp.Payload)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			entries = append(entries, &importEntry{ImportRow: row})
+		}
+	case model.ImportSourceAmazon:
+		list, err := app.GetScraper().FetchAmazonWishlist(context.Background(), imp.SourceURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the Amazon wish list: %w", err)
+		}
+		for i, listed := range list.Items {
+			row := &model.ImportRow{Row: i + 1, Item: &model.WishItemCreateRequest{URL: listed.URL}}
+			entries = append(entries, &importEntry{ImportRow: row, listed: listed})
+		}
+	default:
+		return nil, fmt.Errorf("unknown import source %q", imp.Source)
+	}
+
+	if len(entries) > maxRows {
+		entries = entries[:maxRows]
+	}
+	return entries, nil
+}
+
+// importEntryItem adds the item of a row to the wishlist. CSV rows with a URL but no title get
+// it from the linked page; subscribers are not notified of imported items, which come in bulk.
+func importEntryItem(wishlist *model.Wishlist, entry *importEntry) error {
+	if entry.Err != nil {
+		return entry.Err
+	}
+	req := entry.Item
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	item := &model.WishItem{
+		WishlistID:  wishlist.ID,
+		Title:       req.Title,
+		Description: req.Description,
+		URL:         req.URL,
+		Currency:    req.Currency,
+		Priority:    req.Priority,
+		Quantity:    req.Quantity,
+		Category:    model.WishItemCategory(req.Category),
+		Tags:        req.Tags,
+	}
+	if req.Price > 0 {
+		item.ApplyDefaults()
+		item.SetPrices(req.Price, nil)
+	}
+
+	switch {
+	case entry.listed != nil:
+		item.Prefill(entry.listed.Title, "", entry.listed.Price, entry.listed.Currency)
+	case item.URL != "" && item.Title == "":
+		if metadata, err := app.GetScraper().Fetch(context.Background(), item.URL); err != nil {
+			log.Printf("Failed to read metadata of %s: %v", item.URL, err)
+		} else {
+			item.Prefill(metadata.Title, metadata.Description, metadata.Price, metadata.Currency)
+		}
+	}
+	if item.Title == "" {
+		return errors.New("title validation failed: title is required when it cannot be read from the url")
+	}
+
+	item.ApplyDefaults()
+	item.BeforeCreate()
+
+	if err := app.GetRepository().WishItem().Create(item); err != nil {
+		return errors.New("failed to save the item")
+	}
+	if len(item.Tags) > 0 {
+		if err := app.GetRepository().WishItem().SetTags(item.ID, item.Tags); err != nil {
+			return errors.New("the item was saved without its tags")
+		}
+	}
+	return nil
+}
+
+// finishImport records the end of an import, failed with cause when it is not nil
+func finishImport(imp *model.WishlistImport, cause error) error {
+	finishedAt := time.Now().UTC()
+	imp.FinishedAt = &finishedAt
+	imp.Status = model.ImportCompleted
+	if cause != nil {
+		log.Printf("Wishlist import %d failed: %v", imp.ID, cause)
+		imp.Status = model.ImportFailed
+		imp.Error = cause.Error()
+	}
+	return app.GetRepository().WishlistImport().Finish(imp)
+}
@@ -0,0 +1,364 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/alex-1900/wishlist/src/scraper"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionListWishItems returns the items of a wishlist the authenticated user owns or edits
+func ActionListWishItems() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		wishlist, ok := findEditableWishlist(ctx, ctx.Query("wishlist_id"), userID)
+		if !ok {
+			return
+		}
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		opts := listReq.ToOptions(ctx.Request.URL.Query(), "title", "currency", "priority", "category", "tag")
+
+		items, page, err := app.GetRepository().WishItem().ListByWishlist(wishlist.ID, opts)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wish items", err))
+			return
+		}
+
+		responses := make([]*model.WishItemResponse, len(items))
+		for i, item := range items {
+			responses[i] = item.ToResponse()
+		}
+		withItemReactions(responses...)
+		withDisplayPrices(ctx, responses...)
+
+		response.OK(ctx, responses, response.Message(fmt.Sprintf("Retrieved %d wish items", len(items))), response.Page(page))
+	}
+}
+
+// ActionAddWishItem adds an item to a wishlist the authenticated user owns or edits
+func ActionAddWishItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishItemCreateRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlist, ok := findEditableWishlist(ctx, req.WishlistID, userID)
+		if !ok {
+			return
+		}
+
+		item := &model.WishItem{
+			WishlistID:  wishlist.ID,
+			Title:       req.Title,
+			Description: req.Description,
+			URL:         req.URL,
+			Currency:    req.Currency,
+			Priority:    req.Priority,
+			Quantity:    req.Quantity,
+			Category:    model.WishItemCategory(req.Category),
+			Tags:        req.Tags,
+
+			PreferredVariant: req.PreferredVariant,
+			ShipTo:           model.ShipTo(req.ShipTo),
+			GiftWrap:         req.GiftWrap,
+
+			Translations: req.Translations,
+		}
+
+		// Prices given in the request fix the currency, which otherwise may come from the page
+		if req.Price > 0 || req.PriceAlertBelow != nil {
+			item.ApplyDefaults()
+			item.SetPrices(req.Price, req.PriceAlertBelow)
+		}
+
+		// Pre-fill missing details from the linked page; a page that can't be read only
+		// matters when the title had to come from it
+		if item.URL != "" && (item.Title == "" || item.Description == "" || item.PriceMinor == 0) {
+			if metadata, err := app.GetScraper().Fetch(ctx.Request.Context(), item.URL); err != nil {
+				log.Printf("Failed to read metadata of %s: %v", item.URL, err)
+			} else {
+				item.Prefill(metadata.Title, metadata.Description, metadata.Price, metadata.Currency)
+			}
+		}
+		if item.Title == "" {
+			ctx.Error(apperror.Validation(errors.New("title validation failed: title is required when it cannot be read from the url")))
+			return
+		}
+
+		item.ApplyDefaults()
+		item.BeforeCreate()
+
+		if err := app.GetRepository().WishItem().Create(item); err != nil {
+			ctx.Error(apperror.Internal("Failed to add wish item", err))
+			return
+		}
+		if len(item.Tags) > 0 {
+			if err := app.GetRepository().WishItem().SetTags(item.ID, item.Tags); err != nil {
+				ctx.Error(apperror.Internal("Failed to tag wish item", err))
+				return
+			}
+		}
+
+		publishWishlistChange(wishlist, item.ID, "item_added")
+		notifySubscribers(wishlist, item)
+		response.Created(ctx, item.ToResponse(), response.Message("Wish item added successfully"))
+	}
+}
+
+// ActionPreviewWishItemURL reads the title, image, price and site name of a product page
+// so a client can pre-fill a new wish item
+func ActionPreviewWishItemURL() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rawURL := ctx.Query("url")
+		if rawURL == "" {
+			ctx.Error(apperror.BadRequest("url is required"))
+			return
+		}
+
+		metadata, err := app.GetScraper().Fetch(ctx.Request.Context(), rawURL)
+		if errors.Is(err, scraper.ErrInvalidURL) || errors.Is(err, scraper.ErrBlockedAddress) {
+			ctx.Error(apperror.BadRequest(err.Error()))
+			return
+		} else if err != nil {
+			ctx.Error(apperror.Upstream("Failed to read the linked page", err))
+			return
+		}
+
+		response.OK(ctx, metadata, response.Message("Link preview retrieved successfully"))
+	}
+}
+
+// ActionEditWishItem updates an item on a wishlist the authenticated user owns or edits,
+// merging the body into the item identified by its "id" member
+func ActionEditWishItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishItemEditRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		patchWishItem(ctx, req.ID, userID, &req.WishItemPatch)
+	}
+}
+
+// ActionPatchWishItem applies a JSON Merge Patch to an item on a wishlist the authenticated user
+// owns or edits
+func ActionPatchWishItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		itemID, err := strconv.Atoi(ctx.Query("id"))
+		if err != nil {
+			ctx.Error(apperror.BadRequest("Invalid id"))
+			return
+		}
+
+		var patch model.WishItemPatch
+
+		// Bind JSON merge patch to struct
+		if err := ctx.ShouldBindJSON(&patch); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		patchWishItem(ctx, itemID, userID, &patch)
+	}
+}
+
+// patchWishItem validates a merge patch, applies it to an editable wish item and writes the response
+func patchWishItem(ctx *gin.Context, itemID, userID int, patch *model.WishItemPatch) {
+	// Validate the patch
+	if err := patch.Validate(); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+
+	item, wishlist, ok := findEditableWishItem(ctx, itemID, userID)
+	if !ok {
+		return
+	}
+
+	patch.ApplyTo(item)
+	if err := app.GetRepository().WishItem().Update(item); err != nil {
+		ctx.Error(apperror.Internal("Failed to update wish item", err))
+		return
+	}
+	if patch.Tags.Set {
+		if err := app.GetRepository().WishItem().SetTags(item.ID, item.Tags); err != nil {
+			ctx.Error(apperror.Internal("Failed to tag wish item", err))
+			return
+		}
+	}
+
+	publishWishlistChange(wishlist, item.ID, "item_updated")
+	response.OK(ctx, item.ToResponse(), response.Message("Wish item updated successfully"))
+}
+
+// ActionReorderWishItems changes the display order of the items of a wishlist
+func ActionReorderWishItems() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishItemReorderRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlist, ok := findEditableWishlist(ctx, req.WishlistID, userID)
+		if !ok {
+			return
+		}
+
+		itemRepo := app.GetRepository().WishItem()
+		if err := itemRepo.Reorder(wishlist.ID, req.ItemIDs); err != nil {
+			ctx.Error(apperror.BadRequest("Failed to reorder wish items").WithDetails(err.Error()))
+			return
+		}
+
+		items, _, err := itemRepo.ListByWishlist(wishlist.ID, model.ListOptions{})
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wish items", err))
+			return
+		}
+
+		responses := make([]*model.WishItemResponse, len(items))
+		for i, item := range items {
+			responses[i] = item.ToResponse()
+		}
+
+		publishWishlistChange(wishlist, 0, "items_reordered")
+		response.OK(ctx, responses, response.Message("Wish items reordered successfully"))
+	}
+}
+
+// ActionRemoveWishItem removes an item from a wishlist the authenticated user owns or edits
+func ActionRemoveWishItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishItemDeleteRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		item, wishlist, ok := findEditableWishItem(ctx, req.ID, userID)
+		if !ok {
+			return
+		}
+
+		if err := app.GetRepository().WishItem().Delete(req.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to remove wish item", err))
+			return
+		}
+
+		if err := app.GetRepository().Reaction().DeleteForItem(item.ID); err != nil {
+			log.Printf("Failed to delete reactions of removed wish item %d: %v", item.ID, err)
+		}
+
+		if item.ImageKey != "" {
+			if err := app.GetImages().Remove(ctx.Request.Context(), item.ImageKey); err != nil {
+				log.Printf("Failed to delete image %s of removed wish item: %v", item.ImageKey, err)
+			}
+		}
+
+		publishWishlistChange(wishlist, item.ID, "item_removed")
+		response.OK(ctx, nil, response.Message("Wish item removed successfully"))
+	}
+}
+
+// findEditableWishItem loads a wish item and its wishlist, and checks the user may edit the items
+// of the wishlist like findEditableWishlist. It writes an error response and returns false otherwise.
+func findEditableWishItem(ctx *gin.Context, itemID, userID int) (*model.WishItem, *model.Wishlist, bool) {
+	item, err := app.GetRepository().WishItem().GetByID(itemID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Wish item not found"))
+		return nil, nil, false
+	}
+
+	wishlist, err := app.GetRepository().Wishlist().GetByID(item.WishlistID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, nil, false
+	}
+	if _, ok := checkWishlistRole(ctx, wishlist, userID, model.WishlistRole.CanEditItems); !ok {
+		return nil, nil, false
+	}
+
+	return item, wishlist, true
+}
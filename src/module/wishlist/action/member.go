@@ -0,0 +1,257 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionListWishlistMembers returns the creator, members and pending invitations of a wishlist
+// the authenticated user owns or edits
+func ActionListWishlistMembers() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		wishlist, ok := findEditableWishlist(ctx, ctx.Query("wishlist_id"), userID)
+		if !ok {
+			return
+		}
+
+		creator, err := app.GetRepository().User().GetByID(wishlist.UserID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wishlist creator", err))
+			return
+		}
+
+		members, err := app.GetRepository().WishlistMember().ListByWishlist(wishlist.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wishlist members", err))
+			return
+		}
+
+		members = append([]*model.WishlistMember{{
+			WishlistID: wishlist.ID,
+			UserID:     creator.ID,
+			Username:   creator.Username,
+			Role:       model.WishlistRoleOwner,
+			InvitedAt:  wishlist.CreatedAt,
+			AcceptedAt: &wishlist.CreatedAt,
+			Creator:    true,
+		}}, members...)
+
+		response.OK(ctx, members, response.Message(fmt.Sprintf("Retrieved %d wishlist members", len(members))))
+	}
+}
+
+// ActionInviteWishlistMember invites a user to own or edit a wishlist the authenticated user owns.
+// Inviting a member again changes their role.
+func ActionInviteWishlistMember() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistMemberInviteRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlist, ok := findOwnedWishlist(ctx, req.WishlistID, userID)
+		if !ok {
+			return
+		}
+
+		invitee, err := app.GetRepository().User().GetByUsername(req.Username)
+		if err != nil {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		if invitee.ID == userID || wishlist.IsOwnedBy(invitee.ID) {
+			ctx.Error(apperror.BadRequest("The user already owns this wishlist"))
+			return
+		}
+
+		for _, ownerID := range []int{userID, wishlist.UserID} {
+			blocked, err := app.GetRepository().Block().IsBlockedEither(ownerID, invitee.ID)
+			if err != nil {
+				ctx.Error(apperror.Internal("Failed to check block", err))
+				return
+			}
+			if blocked {
+				ctx.Error(apperror.Forbidden("You cannot invite this user").WithCode(apperror.CodeUserBlocked))
+				return
+			}
+		}
+
+		member := &model.WishlistMember{
+			WishlistID: wishlist.ID,
+			UserID:     invitee.ID,
+			Username:   invitee.Username,
+			Role:       model.WishlistRole(req.Role),
+			InvitedBy:  &userID,
+		}
+		member.BeforeCreate()
+
+		if err := app.GetRepository().WishlistMember().Invite(member); err != nil {
+			ctx.Error(apperror.Internal("Failed to invite wishlist member", err))
+			return
+		}
+
+		if member.AcceptedAt == nil {
+			if inviter, err := app.GetRepository().User().GetByID(userID); err == nil {
+				notify(model.NewWishlistInvitationNotification(invitee.ID, wishlist, inviter, member.Role))
+			}
+		}
+
+		response.OK(ctx, member, response.Message("Wishlist member invited successfully"))
+	}
+}
+
+// ActionRemoveWishlistMember removes a member or a pending invitation from a wishlist the
+// authenticated user owns. The creator cannot be removed.
+func ActionRemoveWishlistMember() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistMemberRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, ok := findOwnedWishlist(ctx, req.WishlistID, userID)
+		if !ok {
+			return
+		}
+
+		member, err := app.GetRepository().User().GetByUsername(req.Username)
+		if err != nil {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		if wishlist.IsOwnedBy(member.ID) {
+			ctx.Error(apperror.BadRequest("The creator of the wishlist cannot be removed"))
+			return
+		}
+
+		if err := app.GetRepository().WishlistMember().Remove(wishlist.ID, member.ID); err != nil {
+			ctx.Error(apperror.NotFound("Wishlist member not found"))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Wishlist member removed successfully"))
+	}
+}
+
+// ActionListWishlistInvitations returns the authenticated user's pending invitations to wishlists
+func ActionListWishlistInvitations() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		invitations, err := app.GetRepository().WishlistMember().ListInvitations(userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wishlist invitations", err))
+			return
+		}
+
+		response.OK(ctx, invitations, response.Message(fmt.Sprintf("Retrieved %d wishlist invitations", len(invitations))))
+	}
+}
+
+// ActionAcceptWishlistInvitation accepts the authenticated user's invitation to a wishlist, which
+// then shows in their wishlists
+func ActionAcceptWishlistInvitation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistInvitationRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, err := app.GetRepository().Wishlist().GetByPublicID(req.WishlistID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("Invitation not found"))
+			return
+		}
+
+		if err := app.GetRepository().WishlistMember().Accept(wishlist.ID, userID); err != nil {
+			ctx.Error(apperror.NotFound("Invitation not found"))
+			return
+		}
+
+		response.OK(ctx, wishlist.ToOwnerResponse(), response.Message("Invitation accepted successfully"), response.WishlistLinks(wishlist.PublicID))
+	}
+}
+
+// ActionLeaveWishlist removes the authenticated user from a wishlist they are a member of, or
+// declines their pending invitation to it
+func ActionLeaveWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistInvitationRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, err := app.GetRepository().Wishlist().GetByPublicID(req.WishlistID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wishlist membership not found"))
+			return
+		}
+
+		if err := app.GetRepository().WishlistMember().Remove(wishlist.ID, userID); err != nil {
+			ctx.Error(apperror.NotFound("Wishlist membership not found"))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Wishlist left successfully"))
+	}
+}
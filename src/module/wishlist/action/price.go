@@ -0,0 +1,161 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionListPriceHistory handles listing the prices read from the page of a wish item, for the
+// owner and for givers who may view the wishlist
+func ActionListPriceHistory() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.PriceHistoryRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		item, err := app.GetRepository().WishItem().GetByID(req.ItemID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wish item not found"))
+			return
+		}
+
+		wishlist, err := app.GetRepository().Wishlist().GetByID(item.WishlistID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wish item not found"))
+			return
+		}
+		role, err := access.WishlistRole(app.GetRepository(), wishlist, userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+			return
+		}
+		if role == "" {
+			if _, ok := checkViewableWishlist(ctx, wishlist, userID); !ok {
+				return
+			}
+		}
+
+		history, err := app.GetRepository().PriceHistory().ListByItem(item.ID, model.PriceHistoryLimit)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve price history", err))
+			return
+		}
+
+		responses := make([]*model.PricePointResponse, len(history))
+		for i, point := range history {
+			responses[i] = point.ToResponse()
+		}
+
+		response.OK(ctx, responses, response.Message("Price history retrieved successfully"))
+	}
+}
+
+// TrackPrices reads again the prices of the items whose price is older than recheckAfter, up to
+// batchSize of them, and drops the history older than retention. An item that cannot be read waits
+// for its next turn, so broken pages do not hold up the others.
+func TrackPrices(batchSize int, recheckAfter, retention time.Duration) error {
+	priceRepo := app.GetRepository().PriceHistory()
+	now := time.Now().UTC()
+
+	items, err := priceRepo.ListDue(now.Add(-recheckAfter), batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list items to track: %w", err)
+	}
+
+	for _, item := range items {
+		if err := trackPrice(item, now); err != nil {
+			log.Printf("Failed to track price of wish item %d: %v", item.ID, err)
+		}
+	}
+
+	if _, err := priceRepo.Prune(now.Add(-retention)); err != nil {
+		return fmt.Errorf("failed to prune price history: %w", err)
+	}
+	return nil
+}
+
+// trackPrice reads the price of an item from its page, records it and announces a drop under
+// the item's alert
+func trackPrice(item *model.WishItem, now time.Time) error {
+	priceRepo := app.GetRepository().PriceHistory()
+
+	metadata, err := app.GetScraper().Fetch(context.Background(), item.URL)
+	if err != nil || metadata.Price <= 0 {
+		if markErr := priceRepo.MarkChecked(item.ID, now); markErr != nil {
+			return markErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", item.URL, err)
+		}
+		return nil
+	}
+
+	point := item.NewPricePoint(metadata.Price, metadata.Currency, now)
+	if err := priceRepo.Record(point); err != nil {
+		return err
+	}
+
+	changed := point.Currency == item.Currency && point.PriceMinor != item.PriceMinor
+	dropped := item.PriceDropped(point)
+	if !changed && !dropped {
+		return nil
+	}
+
+	wishlist, err := app.GetRepository().Wishlist().GetByID(item.WishlistID)
+	if err != nil {
+		return fmt.Errorf("failed to load wishlist %d: %w", item.WishlistID, err)
+	}
+	if changed {
+		publishWishlistChange(wishlist, item.ID, "item_updated")
+	}
+	if dropped {
+		notifyPriceDrop(wishlist, item, point)
+	}
+	return nil
+}
+
+// notifyPriceDrop tells the owner and the givers who reserved an item that its price dropped.
+// Givers who may no longer view the wishlist are skipped.
+func notifyPriceDrop(wishlist *model.Wishlist, item *model.WishItem, point *model.PricePoint) {
+	notify(model.NewPriceDropNotification(wishlist.UserID, wishlist, item, point))
+
+	reservations, err := app.GetRepository().Reservation().ListByWishlist(wishlist.ID)
+	if err != nil {
+		log.Printf("Failed to notify givers of the price drop of wish item %d: %v", item.ID, err)
+		return
+	}
+
+	for _, reservation := range reservations {
+		if reservation.ItemID != item.ID {
+			continue
+		}
+		visible, err := access.CanViewWishlist(app.GetRepository(), wishlist, reservation.UserID)
+		if err != nil {
+			log.Printf("Failed to check wishlist access before notifying user %d: %v", reservation.UserID, err)
+			continue
+		}
+		if visible {
+			notify(model.NewPriceDropNotification(reservation.UserID, wishlist, item, point))
+		}
+	}
+}
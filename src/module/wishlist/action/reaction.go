@@ -0,0 +1,175 @@
+package action
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionAddReaction adds the authenticated user's reaction to a wishlist or wish item they may view
+func ActionAddReaction() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reaction, wishlist, ok := bindReactionRequest(ctx)
+		if !ok {
+			return
+		}
+		reaction.BeforeCreate()
+
+		if err := app.GetRepository().Reaction().Add(reaction); err != nil {
+			ctx.Error(apperror.Internal("Failed to add reaction", err))
+			return
+		}
+
+		respondWithReactionCounts(ctx, reaction, wishlist, "Reaction added successfully")
+	}
+}
+
+// ActionRemoveReaction removes the authenticated user's reaction from a wishlist or wish item
+func ActionRemoveReaction() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reaction, wishlist, ok := bindReactionRequest(ctx)
+		if !ok {
+			return
+		}
+
+		if err := app.GetRepository().Reaction().Remove(reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Kind); err != nil {
+			ctx.Error(apperror.Internal("Failed to remove reaction", err))
+			return
+		}
+
+		respondWithReactionCounts(ctx, reaction, wishlist, "Reaction removed successfully")
+	}
+}
+
+// bindReactionRequest binds and validates a reaction request and checks the user may view its target.
+// Wishlists are named by their public ID. It returns the reaction of the user and the wishlist of
+// its target, or writes an error response and returns false.
+func bindReactionRequest(ctx *gin.Context) (*model.Reaction, *model.Wishlist, bool) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.Error(apperror.Unauthorized("User not authenticated"))
+		return nil, nil, false
+	}
+
+	var req model.ReactionRequest
+
+	// Bind JSON request to struct
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidRequest(err))
+		return nil, nil, false
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return nil, nil, false
+	}
+
+	reaction := &model.Reaction{UserID: userID, TargetType: req.TargetType, Kind: req.Kind}
+
+	var wishlist *model.Wishlist
+	var err error
+	if req.TargetType == model.ReactionTargetItem {
+		// A malformed item ID reads as 0, which names no item
+		itemID, _ := strconv.Atoi(string(req.TargetID))
+		item, itemErr := app.GetRepository().WishItem().GetByID(itemID)
+		if itemErr != nil {
+			ctx.Error(apperror.NotFound("Wish item not found"))
+			return nil, nil, false
+		}
+		reaction.TargetID = item.ID
+		wishlist, err = app.GetRepository().Wishlist().GetByID(item.WishlistID)
+	} else {
+		wishlist, err = app.GetRepository().Wishlist().GetByPublicID(string(req.TargetID))
+		if err == nil {
+			reaction.TargetID = wishlist.ID
+		}
+	}
+	if err != nil {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, nil, false
+	}
+
+	visible, err := access.CanViewWishlist(app.GetRepository(), wishlist, userID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+		return nil, nil, false
+	}
+	if !visible {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, nil, false
+	}
+
+	return reaction, wishlist, true
+}
+
+// respondWithReactionCounts writes the reaction counts of a target after they changed. Wishlist
+// targets are named by the public ID of wishlist.
+func respondWithReactionCounts(ctx *gin.Context, reaction *model.Reaction, wishlist *model.Wishlist, message string) {
+	counts, err := app.GetRepository().Reaction().Counts(reaction.TargetType, []int{reaction.TargetID})
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to count reactions", err))
+		return
+	}
+
+	reactions := counts[reaction.TargetID]
+	if reactions == nil {
+		reactions = map[model.ReactionKind]int{}
+	}
+
+	var targetID interface{} = reaction.TargetID
+	if reaction.TargetType == model.ReactionTargetWishlist {
+		targetID = wishlist.PublicID
+	}
+
+	response.OK(ctx, gin.H{
+		"target_type": reaction.TargetType,
+		"target_id":   targetID,
+		"reactions":   reactions,
+	}, response.Message(message))
+}
+
+// withWishlistReactions fills in the reaction counts of wishlist responses,
+// logging rather than failing the request on error
+func withWishlistReactions(responses ...*model.WishlistResponse) {
+	ids := make([]int, len(responses))
+	for i, resp := range responses {
+		ids[i] = resp.ID
+	}
+
+	counts, err := app.GetRepository().Reaction().Counts(model.ReactionTargetWishlist, ids)
+	if err != nil {
+		log.Printf("Failed to load wishlist reactions: %v", err)
+		return
+	}
+
+	for _, resp := range responses {
+		resp.Reactions = counts[resp.ID]
+	}
+}
+
+// withItemReactions fills in the reaction counts of wish item responses,
+// logging rather than failing the request on error
+func withItemReactions(responses ...*model.WishItemResponse) {
+	ids := make([]int, len(responses))
+	for i, resp := range responses {
+		ids[i] = resp.ID
+	}
+
+	counts, err := app.GetRepository().Reaction().Counts(model.ReactionTargetItem, ids)
+	if err != nil {
+		log.Printf("Failed to load wish item reactions: %v", err)
+		return
+	}
+
+	for _, resp := range responses {
+		resp.Reactions = counts[resp.ID]
+	}
+}
@@ -0,0 +1,374 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/realtime"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// ActionListSharedWishItems returns the items of someone else's wishlist the viewer may see.
+// Signed-in givers also get the reservation state; anonymous visitors only see public lists
+// and no reservations.
+func ActionListSharedWishItems() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Anonymous visitors have no user ID
+		userID, _ := auth.GetUserID(ctx)
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		filters, ok := bindWishItemFilters(ctx, userID)
+		if !ok {
+			return
+		}
+
+		wishlist, ok := findViewableWishlist(ctx, ctx.Query("wishlist_id"), userID)
+		if !ok {
+			return
+		}
+
+		opts := listReq.ToOptions(ctx.Request.URL.Query(), "title", "currency", "priority", "category", "tag")
+		filters.Apply(&opts)
+
+		items, page, err := app.GetRepository().WishItem().ListByWishlist(wishlist.ID, opts)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wish items", err))
+			return
+		}
+		localizeItems(items, response.Languages(ctx), wishlist.Language)
+
+		if userID == 0 {
+			responses := make([]*model.WishItemResponse, len(items))
+			for i, item := range items {
+				responses[i] = item.ToPublicResponse()
+			}
+			withItemReactions(responses...)
+			withDisplayPrices(ctx, responses...)
+
+			response.OK(ctx, responses, response.Message(fmt.Sprintf("Retrieved %d wish items", len(items))), response.Page(page))
+			return
+		}
+
+		responses, ok := giverItemResponses(ctx, wishlist, items, userID)
+		if !ok {
+			return
+		}
+		withItemReactions(responses...)
+		withDisplayPrices(ctx, responses...)
+
+		response.OK(ctx, responses, response.Message(fmt.Sprintf("Retrieved %d wish items", len(items))), response.Page(page))
+	}
+}
+
+// ActionReserveWishItem reserves units of an item on a wishlist shared with the authenticated user
+func ActionReserveWishItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.ReservationRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		item, wishlist, ok := findViewableWishItem(ctx, req.ItemID, userID)
+		if !ok || !checkNotArchived(ctx, wishlist) {
+			return
+		}
+
+		reservation := &model.Reservation{
+			ItemID:   item.ID,
+			UserID:   userID,
+			Quantity: req.Quantity,
+		}
+		if reservation.Quantity == 0 {
+			reservation.Quantity = 1
+		}
+		reservation.BeforeCreate()
+
+		if err := app.GetRepository().Reservation().Reserve(reservation); err != nil {
+			switch {
+			case errors.Is(err, model.ErrItemFullyReserved):
+				ctx.Error(apperror.Conflict("Item is already fully reserved").WithCode(apperror.CodeItemFullyReserved))
+			case errors.Is(err, model.ErrReservationPurchased):
+				ctx.Error(apperror.Conflict("Reservation is already purchased").WithCode(apperror.CodeReservationPurchased))
+			default:
+				ctx.Error(apperror.Internal("Failed to reserve wish item", err))
+			}
+			return
+		}
+
+		respondWithGiverItem(ctx, wishlist, item, userID, "Wish item reserved successfully")
+	}
+}
+
+// ActionReleaseWishItem releases the authenticated user's reservation of an item
+func ActionReleaseWishItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.ReservationReleaseRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		item, wishlist, ok := findViewableWishItem(ctx, req.ItemID, userID)
+		if !ok {
+			return
+		}
+
+		if err := app.GetRepository().Reservation().Release(item.ID, userID); err != nil {
+			switch {
+			case errors.Is(err, model.ErrNoReservation):
+				ctx.Error(apperror.NotFound("Reservation not found"))
+			case errors.Is(err, model.ErrReservationPurchased):
+				ctx.Error(apperror.Conflict("Reservation is already purchased").WithCode(apperror.CodeReservationPurchased))
+			default:
+				ctx.Error(apperror.Internal("Failed to release reservation", err))
+			}
+			return
+		}
+
+		respondWithGiverItem(ctx, wishlist, item, userID, "Reservation released successfully")
+	}
+}
+
+// notify adds a notification to a feed and pushes it to the user's open connections,
+// logging rather than failing the request on error
+func notify(notification *model.Notification) {
+	if notification.ActorID != nil {
+		kind, err := app.GetRepository().Block().Get(notification.UserID, *notification.ActorID)
+		if err != nil {
+			log.Printf("Failed to check block before notifying user %d: %v", notification.UserID, err)
+			return
+		}
+		// Blocked and muted users stay out of the feed
+		if kind != "" {
+			return
+		}
+	}
+
+	if err := app.GetRepository().Notification().Create(notification); err != nil {
+		log.Printf("Failed to send %s notification to user %d: %v", notification.Type, notification.UserID, err)
+		return
+	}
+	app.GetRealtimeHub().Publish(notification.UserID, realtime.Event{Type: realtime.EventNotification, Data: notification})
+}
+
+// publishWishlistChange pushes a change to a wishlist to the open sessions of its creator and of
+// its members, and purges the CDN copies of its public views
+func publishWishlistChange(wishlist *model.Wishlist, itemID int, action string) {
+	event := realtime.Event{
+		Type: realtime.EventWishlistChanged,
+		Data: realtime.WishlistChange{WishlistID: wishlist.PublicID, ItemID: itemID, Action: action},
+	}
+	app.GetRealtimeHub().Publish(wishlist.UserID, event)
+
+	members, err := app.GetRepository().WishlistMember().ListByWishlist(wishlist.ID)
+	if err != nil {
+		log.Printf("Failed to publish change of wishlist %d to its members: %v", wishlist.ID, err)
+	}
+	for _, member := range members {
+		if member.AcceptedAt != nil {
+			app.GetRealtimeHub().Publish(member.UserID, event)
+		}
+	}
+
+	purgeWishlistCache(wishlist)
+}
+
+// purgeWishlistCache purges the CDN copies of a wishlist and of its owner's profile and wishlist list
+func purgeWishlistCache(wishlist *model.Wishlist) {
+	if !app.GetCDN().Purging() {
+		return
+	}
+
+	keys := []string{cdn.WishlistKey(wishlist.PublicID)}
+	if owner, err := app.GetRepository().User().GetByID(wishlist.UserID); err == nil {
+		keys = append(keys, cdn.UserKey(owner.PublicID))
+	}
+	app.GetCDN().Purge(keys...)
+}
+
+// respondWithGiverItem writes the giver view of an item of wishlist after its reservations or
+// contributions changed
+func respondWithGiverItem(ctx *gin.Context, wishlist *model.Wishlist, item *model.WishItem, userID int, message string) {
+	responses, ok := giverItemResponses(ctx, wishlist, []*model.WishItem{item}, userID)
+	if !ok {
+		return
+	}
+
+	withDisplayPrices(ctx, responses[0])
+	response.OK(ctx, responses[0], response.Message(message))
+}
+
+// localizeItems shows the items in the viewer's preferred languages; original is the language of
+// their wishlist
+func localizeItems(items []*model.WishItem, languages []language.Tag, original string) {
+	for _, item := range items {
+		item.Localize(languages, original)
+	}
+}
+
+// giverItemResponses converts items of a wishlist to the giver view, summing their reservations
+// and contributions. It writes an error response and returns false when they cannot be loaded.
+func giverItemResponses(ctx *gin.Context, wishlist *model.Wishlist, items []*model.WishItem, userID int) ([]*model.WishItemResponse, bool) {
+	reservations, err := app.GetRepository().Reservation().ListByWishlist(wishlist.ID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to retrieve reservations", err))
+		return nil, false
+	}
+
+	contributions, err := app.GetRepository().Contribution().ListByWishlist(wishlist.ID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to retrieve contributions", err))
+		return nil, false
+	}
+
+	states := model.ReservationStates(reservations, userID)
+
+	responses := make([]*model.WishItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = item.ToGiverResponse(states[item.ID], item.ContributionState(contributions, userID))
+		// Givers who may see the gift preferences learn where to send the gift
+		if responses[i].GiftPreferences != nil && item.ShipTo == model.ShipToOwner {
+			responses[i].GiftPreferences.ShippingAddress = wishlist.ShippingAddress
+		}
+	}
+	return responses, true
+}
+
+// bindWishItemFilters binds and validates the price and availability filters of a giver's item
+// listing. Anonymous visitors (userID 0) may not filter on reservations they cannot see.
+// It writes an error response and returns false otherwise.
+func bindWishItemFilters(ctx *gin.Context, userID int) (*model.WishItemFilterRequest, bool) {
+	var filters model.WishItemFilterRequest
+
+	// Bind query parameters to struct
+	if err := ctx.ShouldBindQuery(&filters); err != nil {
+		ctx.Error(apperror.InvalidRequest(err))
+		return nil, false
+	}
+
+	// Validate the request
+	if err := filters.Validate(); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return nil, false
+	}
+
+	if userID == 0 && filters.FiltersAvailability() {
+		ctx.Error(apperror.Unauthorized("Sign in to filter by availability"))
+		return nil, false
+	}
+
+	return &filters, true
+}
+
+// findViewableWishlist loads a wishlist by its public ID the user may view as a giver: visible
+// to them and not their own, so owners never see reservations. userID is 0 for anonymous
+// visitors. It writes a 404 response and returns false otherwise.
+func findViewableWishlist(ctx *gin.Context, publicID string, userID int) (*model.Wishlist, bool) {
+	wishlist, err := app.GetRepository().Wishlist().GetByPublicID(publicID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, false
+	}
+	return checkViewableWishlist(ctx, wishlist, userID)
+}
+
+// checkViewableWishlist checks the user may view the loaded wishlist as a giver. Its creator and
+// members are no givers, so they never see reservations.
+func checkViewableWishlist(ctx *gin.Context, wishlist *model.Wishlist, userID int) (*model.Wishlist, bool) {
+	role, err := access.WishlistRole(app.GetRepository(), wishlist, userID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+		return nil, false
+	}
+	if role != "" {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, false
+	}
+
+	visible, err := access.CanViewWishlist(app.GetRepository(), wishlist, userID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+		return nil, false
+	}
+	if !visible {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, false
+	}
+
+	return wishlist, true
+}
+
+// findViewableWishItem loads an item, and its wishlist, the user may view as a giver
+func findViewableWishItem(ctx *gin.Context, itemID, userID int) (*model.WishItem, *model.Wishlist, bool) {
+	item, err := app.GetRepository().WishItem().GetByID(itemID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Wish item not found"))
+		return nil, nil, false
+	}
+
+	wishlist, err := app.GetRepository().Wishlist().GetByID(item.WishlistID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, nil, false
+	}
+	if _, ok := checkViewableWishlist(ctx, wishlist, userID); !ok {
+		return nil, nil, false
+	}
+
+	return item, wishlist, true
+}
+
+// checkNotArchived refuses giving towards a wishlist whose event is over.
+// It writes a 409 response and returns false otherwise.
+func checkNotArchived(ctx *gin.Context, wishlist *model.Wishlist) bool {
+	if wishlist.IsArchived() {
+		ctx.Error(apperror.Conflict("Wishlist is archived, its event is over").WithCode(apperror.CodeWishlistArchived))
+		return false
+	}
+	return true
+}
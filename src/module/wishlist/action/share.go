@@ -0,0 +1,148 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionRegenerateShareLink creates a new share link for a wishlist owned by the authenticated user,
+// invalidating any previous link
+func ActionRegenerateShareLink() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistShareLinkRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, ok := findOwnedWishlist(ctx, req.ID, userID)
+		if !ok {
+			return
+		}
+
+		token, err := auth.GenerateShareToken()
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to generate share link", err))
+			return
+		}
+
+		if err := app.GetRepository().Wishlist().SetShareToken(wishlist.ID, &token); err != nil {
+			ctx.Error(apperror.Internal("Failed to save share link", err))
+			return
+		}
+		// The CDN may still hold the view of the previous link
+		app.GetCDN().Purge(cdn.WishlistKey(wishlist.PublicID))
+
+		options := []response.Option{response.Message("Share link generated successfully")}
+		if wishlist.Visibility != model.VisibilityLinkOnly && wishlist.Visibility != model.VisibilityPublic {
+			options = append(options, response.Meta("warning", fmt.Sprintf("The link only works once the wishlist is %s or %s", model.VisibilityLinkOnly, model.VisibilityPublic)))
+		}
+
+		response.OK(ctx, &model.WishlistShareLinkResponse{
+			WishlistID: wishlist.PublicID,
+			Token:      token,
+			Path:       "/shared/" + token,
+		}, options...)
+	}
+}
+
+// ActionRevokeShareLink removes the share link of a wishlist owned by the authenticated user
+func ActionRevokeShareLink() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistShareLinkRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, ok := findOwnedWishlist(ctx, req.ID, userID)
+		if !ok {
+			return
+		}
+
+		if err := app.GetRepository().Wishlist().SetShareToken(wishlist.ID, nil); err != nil {
+			ctx.Error(apperror.Internal("Failed to revoke share link", err))
+			return
+		}
+		app.GetCDN().Purge(cdn.WishlistKey(wishlist.PublicID))
+
+		response.OK(ctx, nil, response.Message("Share link revoked successfully"))
+	}
+}
+
+// ActionViewSharedLink returns the read-only view of a wishlist reached through its share link
+func ActionViewSharedLink() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		wishlist, err := app.GetRepository().Wishlist().GetByShareToken(ctx.Param("token"))
+		if err != nil || !access.CanViewThroughLink(wishlist) {
+			ctx.Error(apperror.NotFound("Shared wishlist not found"))
+			return
+		}
+
+		owner, err := app.GetRepository().User().GetByID(wishlist.UserID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("Shared wishlist not found"))
+			return
+		}
+
+		// Share links are anonymous, so only the price filters apply
+		filters, ok := bindWishItemFilters(ctx, 0)
+		if !ok {
+			return
+		}
+
+		var opts model.ListOptions
+		filters.Apply(&opts)
+
+		items, _, err := app.GetRepository().WishItem().ListByWishlist(wishlist.ID, opts)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wish items", err))
+			return
+		}
+
+		languages := response.Languages(ctx)
+		localizeItems(items, languages, wishlist.Language)
+		wishlist.Localize(languages)
+
+		responses := make([]*model.WishItemResponse, len(items))
+		for i, item := range items {
+			responses[i] = item.ToPublicResponse()
+		}
+		withItemReactions(responses...)
+		withDisplayPrices(ctx, responses...)
+
+		wishlistResponse := wishlist.ToResponse()
+		withWishlistReactions(wishlistResponse)
+
+		cdn.Tag(ctx, cdn.WishlistKey(wishlist.PublicID), cdn.UserKey(owner.PublicID))
+		response.OK(ctx, gin.H{
+			"wishlist":       wishlistResponse,
+			"owner_username": owner.Username,
+			"items":          responses,
+		}, response.Message("Shared wishlist retrieved successfully"), response.Robots(access.CanIndexWishlist(wishlist, owner)))
+	}
+}
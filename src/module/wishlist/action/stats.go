@@ -0,0 +1,36 @@
+package action
+
+import (
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionWishlistStats returns item counts by priority and the total value and average price per
+// currency of a wishlist the authenticated user owns or edits
+func ActionWishlistStats() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		wishlist, ok := findEditableWishlist(ctx, ctx.Param("id"), userID)
+		if !ok {
+			return
+		}
+
+		stats, err := app.GetRepository().WishItem().Stats(wishlist.ID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to compute wishlist statistics", err))
+			return
+		}
+
+		stats.DisplayTotal = displayTotal(ctx, stats)
+
+		response.OK(ctx, stats, response.Message("Wishlist statistics retrieved successfully"))
+	}
+}
@@ -0,0 +1,133 @@
+package action
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionSubscribeWishlist subscribes the authenticated user to a wishlist of someone else they
+// may view, so they are notified of new items without following its owner
+func ActionSubscribeWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.SubscriptionRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, ok := findViewableWishlist(ctx, req.WishlistID, userID)
+		if !ok {
+			return
+		}
+
+		if _, err := app.GetRepository().Subscription().Subscribe(userID, wishlist.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to subscribe to wishlist", err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message(fmt.Sprintf("You are now subscribed to %s", wishlist.Title)))
+	}
+}
+
+// ActionUnsubscribeWishlist removes the authenticated user's subscription to a wishlist.
+// It works even once the wishlist is no longer visible to them.
+func ActionUnsubscribeWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.SubscriptionRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, err := app.GetRepository().Wishlist().GetByPublicID(req.WishlistID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+
+		if err := app.GetRepository().Subscription().Unsubscribe(userID, wishlist.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to unsubscribe from wishlist", err))
+			return
+		}
+
+		response.OK(ctx, nil, response.Message("Unsubscribed from wishlist successfully"))
+	}
+}
+
+// ActionListWishlistSubscriptions retrieves a page of the wishlists the authenticated user is subscribed to
+func ActionListWishlistSubscriptions() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlists, page, err := app.GetRepository().Subscription().ListByUser(userID, listReq.ToOptions(ctx.Request.URL.Query(), "title"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve subscriptions", err))
+			return
+		}
+
+		response.OK(ctx, wishlists, response.Message(fmt.Sprintf("Retrieved %d subscriptions", len(wishlists))), response.Page(page))
+	}
+}
+
+// notifySubscribers tells the subscribers of a wishlist that an item was added to it.
+// Subscribers who may no longer view the wishlist are skipped.
+func notifySubscribers(wishlist *model.Wishlist, item *model.WishItem) {
+	subscribers, err := app.GetRepository().Subscription().ListSubscribers(wishlist.ID)
+	if err != nil {
+		log.Printf("Failed to notify subscribers of wishlist %d: %v", wishlist.ID, err)
+		return
+	}
+
+	for _, subscriberID := range subscribers {
+		visible, err := access.CanViewWishlist(app.GetRepository(), wishlist, subscriberID)
+		if err != nil {
+			log.Printf("Failed to check wishlist access before notifying user %d: %v", subscriberID, err)
+			continue
+		}
+		if visible {
+			notify(model.NewItemAddedNotification(subscriberID, wishlist, item))
+		}
+	}
+}
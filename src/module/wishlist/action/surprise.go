@@ -0,0 +1,77 @@
+package action
+
+import (
+	"errors"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionSurpriseMe picks a random unclaimed item within the budget from a wishlist of someone
+// else, favouring higher priorities. With claim=true one unit of the item is reserved for the
+// authenticated user in the same step.
+func ActionSurpriseMe() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.SurpriseRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlist, ok := findViewableWishlist(ctx, ctx.Param("id"), userID)
+		if !ok || (req.Claim && !checkNotArchived(ctx, wishlist)) {
+			return
+		}
+
+		var itemID int
+		var err error
+		if req.Claim {
+			var reservation *model.Reservation
+			reservation, err = app.GetRepository().Reservation().ClaimRandom(wishlist.ID, userID, req.BudgetAmount())
+			if reservation != nil {
+				itemID = reservation.ItemID
+			}
+		} else {
+			itemID, err = app.GetRepository().Reservation().PickUnclaimed(wishlist.ID, req.BudgetAmount())
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, model.ErrNoUnclaimedItem):
+				ctx.Error(apperror.NotFound("No unclaimed item within budget").WithCode(apperror.CodeNoUnclaimedItem))
+			case errors.Is(err, model.ErrItemFullyReserved):
+				ctx.Error(apperror.Conflict("The picked item was just reserved by someone else, please try again").WithCode(apperror.CodeItemFullyReserved))
+			default:
+				ctx.Error(apperror.Internal("Failed to pick a wish item", err))
+			}
+			return
+		}
+
+		item, err := app.GetRepository().WishItem().GetByID(itemID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wish item", err))
+			return
+		}
+
+		if !req.Claim {
+			respondWithGiverItem(ctx, wishlist, item, userID, "Wish item picked successfully")
+			return
+		}
+
+		respondWithGiverItem(ctx, wishlist, item, userID, "Wish item picked and reserved successfully")
+	}
+}
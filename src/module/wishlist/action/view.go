@@ -0,0 +1,110 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionViewWishlist returns a wishlist the viewer may see, signed in or not
+func ActionViewWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Anonymous visitors have no user ID
+		userID, _ := auth.GetUserID(ctx)
+
+		wishlist, err := app.GetRepository().Wishlist().GetByPublicID(ctx.Query("wishlist_id"))
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+
+		visible, err := access.CanViewWishlist(app.GetRepository(), wishlist, userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+			return
+		}
+		if !visible {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+
+		owner, err := app.GetRepository().User().GetByID(wishlist.UserID)
+		if err != nil {
+			ctx.Error(apperror.NotFound("Wishlist not found"))
+			return
+		}
+
+		wishlist.Localize(response.Languages(ctx))
+		resp := wishlist.ToResponse()
+		withWishlistReactions(resp)
+
+		response.OK(ctx, resp, response.Message("Wishlist retrieved successfully"), response.WishlistLinks(wishlist.PublicID),
+			response.Robots(access.CanIndexWishlist(wishlist, owner)))
+	}
+}
+
+// ActionListUserWishlists returns the wishlists of a user that the viewer may browse
+func ActionListUserWishlists() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Anonymous visitors have no user ID
+		userID, _ := auth.GetUserID(ctx)
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		owner, err := app.GetRepository().User().GetByUsername(ctx.Query("username"))
+		if err != nil {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		browsable, err := access.CanBrowseUser(app.GetRepository(), owner.ID, userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+			return
+		}
+		if !browsable {
+			ctx.Error(apperror.NotFound("User not found"))
+			return
+		}
+
+		levels, err := access.VisibleLevels(app.GetRepository(), owner.ID, userID)
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+			return
+		}
+
+		wishlists, page, err := app.GetRepository().Wishlist().ListVisibleByUser(owner.ID, userID, levels, listReq.ToOptions(ctx.Request.URL.Query(), "title"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wishlists", err))
+			return
+		}
+
+		languages := response.Languages(ctx)
+		responses := make([]*model.WishlistResponse, len(wishlists))
+		for i, wishlist := range wishlists {
+			wishlist.Localize(languages)
+			responses[i] = wishlist.ToResponse()
+		}
+		withWishlistReactions(responses...)
+
+		response.OK(ctx, responses, response.Message(fmt.Sprintf("Retrieved %d wishlists", len(wishlists))), response.Page(page))
+	}
+}
@@ -0,0 +1,296 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/access"
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActionCreateWishlist creates a new wishlist owned by the authenticated user
+func ActionCreateWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistCreateRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlist := &model.Wishlist{
+			UserID:       userID,
+			Title:        req.Title,
+			Visibility:   model.WishlistVisibility(req.Visibility),
+			Language:     req.Language,
+			Translations: req.Translations,
+		}
+		wishlist.SetEvent(req.EventType, req.EventDate, req.ShippingAddress)
+		wishlist.BeforeCreate()
+
+		if err := app.GetRepository().Wishlist().Create(wishlist); err != nil {
+			ctx.Error(apperror.Internal("Failed to create wishlist", err))
+			return
+		}
+
+		publishWishlistChange(wishlist, 0, "created")
+		response.Created(ctx, wishlist.ToOwnerResponse(), response.Message("Wishlist created successfully"), response.WishlistLinks(wishlist.PublicID))
+	}
+}
+
+// ActionListWishlists returns all wishlists owned by the authenticated user
+func ActionListWishlists() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var listReq model.ListRequest
+
+		// Bind query parameters to struct
+		if err := ctx.ShouldBindQuery(&listReq); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := listReq.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlists, page, err := app.GetRepository().Wishlist().ListByUser(userID, listReq.ToOptions(ctx.Request.URL.Query(), "title"))
+		if err != nil {
+			ctx.Error(apperror.Internal("Failed to retrieve wishlists", err))
+			return
+		}
+
+		responses := make([]*model.WishlistResponse, len(wishlists))
+		for i, wishlist := range wishlists {
+			responses[i] = wishlist.ToOwnerResponse()
+		}
+		withWishlistReactions(responses...)
+
+		response.OK(ctx, responses, response.Message(fmt.Sprintf("Retrieved %d wishlists", len(wishlists))), response.Page(page))
+	}
+}
+
+// ActionRenameWishlist renames a wishlist owned by the authenticated user
+func ActionRenameWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistRenameRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlist, ok := findOwnedWishlist(ctx, req.ID, userID)
+		if !ok {
+			return
+		}
+
+		wishlist.Title = req.Title
+		if err := app.GetRepository().Wishlist().Update(wishlist); err != nil {
+			ctx.Error(apperror.Internal("Failed to rename wishlist", err))
+			return
+		}
+
+		publishWishlistChange(wishlist, 0, "updated")
+		response.OK(ctx, wishlist.ToOwnerResponse(), response.Message("Wishlist renamed successfully"), response.WishlistLinks(wishlist.PublicID))
+	}
+}
+
+// ActionPatchWishlist applies a JSON Merge Patch to a wishlist owned by the authenticated user
+func ActionPatchWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var patch model.WishlistPatch
+
+		// Bind JSON merge patch to struct
+		if err := ctx.ShouldBindJSON(&patch); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the patch
+		if err := patch.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlist, ok := findOwnedWishlist(ctx, ctx.Query("id"), userID)
+		if !ok {
+			return
+		}
+
+		patch.ApplyTo(wishlist)
+		if err := app.GetRepository().Wishlist().Update(wishlist); err != nil {
+			ctx.Error(apperror.Internal("Failed to update wishlist", err))
+			return
+		}
+
+		publishWishlistChange(wishlist, 0, "updated")
+		response.OK(ctx, wishlist.ToOwnerResponse(), response.Message("Wishlist updated successfully"), response.WishlistLinks(wishlist.PublicID))
+	}
+}
+
+// ActionUpdateWishlistVisibility changes who may view a wishlist owned by the authenticated user
+func ActionUpdateWishlistVisibility() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistVisibilityRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		// Validate the request
+		if err := req.Validate(); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+
+		wishlist, ok := findOwnedWishlist(ctx, req.ID, userID)
+		if !ok {
+			return
+		}
+
+		wishlist.Visibility = model.WishlistVisibility(req.Visibility)
+		if err := app.GetRepository().Wishlist().Update(wishlist); err != nil {
+			ctx.Error(apperror.Internal("Failed to update wishlist visibility", err))
+			return
+		}
+
+		publishWishlistChange(wishlist, 0, "updated")
+		response.OK(ctx, wishlist.ToOwnerResponse(), response.Message("Wishlist visibility updated successfully"), response.WishlistLinks(wishlist.PublicID))
+	}
+}
+
+// ActionDeleteWishlist deletes a wishlist created by the authenticated user; co-owners may not
+func ActionDeleteWishlist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, exists := auth.GetUserID(ctx)
+		if !exists {
+			ctx.Error(apperror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		var req model.WishlistDeleteRequest
+
+		// Bind JSON request to struct
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(apperror.InvalidRequest(err))
+			return
+		}
+
+		wishlist, ok := findOwnedWishlist(ctx, req.ID, userID)
+		if !ok {
+			return
+		}
+		if !wishlist.IsOwnedBy(userID) {
+			ctx.Error(apperror.Forbidden("Only the creator of the wishlist can delete it"))
+			return
+		}
+
+		// Reactions are not tied to the wishlist by a foreign key, so clear them while its items still exist
+		if err := app.GetRepository().Reaction().DeleteForWishlist(wishlist.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to delete wishlist", err))
+			return
+		}
+
+		if err := app.GetRepository().Wishlist().Delete(wishlist.ID); err != nil {
+			ctx.Error(apperror.Internal("Failed to delete wishlist", err))
+			return
+		}
+
+		publishWishlistChange(wishlist, 0, "deleted")
+		response.OK(ctx, nil, response.Message("Wishlist deleted successfully"))
+	}
+}
+
+// findOwnedWishlist loads a wishlist by its public ID and checks the user owns it, as its
+// creator or a co-owner. It writes an error response and returns false when the wishlist is
+// missing or the user may not manage it.
+func findOwnedWishlist(ctx *gin.Context, publicID string, userID int) (*model.Wishlist, bool) {
+	wishlist, err := app.GetRepository().Wishlist().GetByPublicID(publicID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, false
+	}
+	return checkWishlistRole(ctx, wishlist, userID, model.WishlistRole.CanManage)
+}
+
+// findEditableWishlist loads a wishlist by its public ID and checks the user may edit its items,
+// as its creator, a co-owner or an editor
+func findEditableWishlist(ctx *gin.Context, publicID string, userID int) (*model.Wishlist, bool) {
+	wishlist, err := app.GetRepository().Wishlist().GetByPublicID(publicID)
+	if err != nil {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, false
+	}
+	return checkWishlistRole(ctx, wishlist, userID, model.WishlistRole.CanEditItems)
+}
+
+// checkWishlistRole checks the role of the user on the loaded wishlist grants a permission.
+// Non-members get a 404, so wishlists cannot be probed; members lacking the permission a 403.
+func checkWishlistRole(ctx *gin.Context, wishlist *model.Wishlist, userID int, allowed func(model.WishlistRole) bool) (*model.Wishlist, bool) {
+	role, err := access.WishlistRole(app.GetRepository(), wishlist, userID)
+	if err != nil {
+		ctx.Error(apperror.Internal("Failed to check wishlist access", err))
+		return nil, false
+	}
+	if role == "" {
+		ctx.Error(apperror.NotFound("Wishlist not found"))
+		return nil, false
+	}
+	if !allowed(role) {
+		ctx.Error(apperror.Forbidden("Your role on this wishlist does not allow this"))
+		return nil, false
+	}
+	return wishlist, true
+}
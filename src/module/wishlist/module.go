@@ -0,0 +1,354 @@
+package wishlist
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/app"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/alex-1900/wishlist/src/cdn"
+	"github.com/alex-1900/wishlist/src/database"
+	"github.com/alex-1900/wishlist/src/module/wishlist/action"
+	"github.com/gin-gonic/gin"
+)
+
+// Module is the wishlist module handling wishlists owned by users. It also runs the jobs
+// tracking the prices of items with a URL, archiving wishlists whose event is over and
+// importing items from CSV files and Amazon wish lists.
+type Module struct {
+	stop chan struct{}
+	jobs sync.WaitGroup
+}
+
+func init() {
+	app.RegisterModule(&Module{})
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return "wishlist"
+}
+
+// Migrations returns the wishlist schema migrations
+func (m *Module) Migrations() []database.Migration {
+	return []database.Migration{
+		{
+			Version: database.VersionCreateWishlists,
+			Name:    "create_wishlists_table",
+			Up:      database.CreateWishlistsTable,
+			Down:    database.DropWishlistsTable,
+		},
+		{
+			Version: database.VersionCreateWishItems,
+			Name:    "create_wish_items_table",
+			Up:      database.CreateWishItemsTable,
+			Down:    database.DropWishItemsTable,
+		},
+		{
+			Version: database.VersionAddWishItemGiftPreferences,
+			Name:    "add_wish_item_gift_preferences",
+			Up:      database.AddWishItemGiftPreferences,
+			Down:    database.DropWishItemGiftPreferences,
+		},
+		{
+			Version: database.VersionCreateReservations,
+			Name:    "create_reservations_table",
+			Up:      database.CreateReservationsTable,
+			Down:    database.DropReservationsTable,
+		},
+		{
+			Version: database.VersionAddWishlistVisibility,
+			Name:    "add_wishlist_visibility",
+			Up:      database.AddWishlistVisibility,
+			Down:    database.DropWishlistVisibility,
+		},
+		{
+			Version: database.VersionAddWishlistShareToken,
+			Name:    "add_wishlist_share_token",
+			Up:      database.AddWishlistShareToken,
+			Down:    database.DropWishlistShareToken,
+		},
+		{
+			Version: database.VersionAddWishItemImage,
+			Name:    "add_wish_item_image",
+			Up:      database.AddWishItemImage,
+			Down:    database.DropWishItemImage,
+		},
+		{
+			Version: database.VersionCreateReactions,
+			Name:    "create_reactions_table",
+			Up:      database.CreateReactionsTable,
+			Down:    database.DropReactionsTable,
+		},
+		{
+			Version: database.VersionCreateSubscriptions,
+			Name:    "create_wishlist_subscriptions_table",
+			Up:      database.CreateWishlistSubscriptionsTable,
+			Down:    database.DropWishlistSubscriptionsTable,
+		},
+		{
+			Version: database.VersionAddWishItemPriceIndex,
+			Name:    "add_wish_item_price_index",
+			Up:      database.AddWishItemPriceIndex,
+			Down:    database.DropWishItemPriceIndex,
+		},
+		{
+			Version:  database.VersionAddWishlistPublicID,
+			Name:     "add_wishlist_public_id",
+			Up:       database.AddWishlistPublicID,
+			Backfill: database.BackfillWishlistPublicID,
+			Down:     database.DropWishlistPublicID,
+		},
+		{
+			Version: database.VersionAddWishlistTranslations,
+			Name:    "add_translations",
+			Up:      database.AddTranslations,
+			Down:    database.DropTranslations,
+		},
+		{
+			Version: database.VersionCreatePriceHistory,
+			Name:    "create_price_history_table",
+			Up:      database.CreatePriceHistoryTable,
+			Down:    database.DropPriceHistoryTable,
+		},
+		{
+			Version: database.VersionAddWishItemImageAlt,
+			Name:    "add_wish_item_image_alt",
+			Up:      database.AddWishItemImageAlt,
+			Down:    database.DropWishItemImageAlt,
+		},
+		{
+			Version:  database.VersionConvertPricesToMinor,
+			Name:     "convert_prices_to_minor",
+			Up:       database.ConvertPricesToMinor,
+			Backfill: database.BackfillPricesToMinor,
+			Down:     database.RevertPricesToMinor,
+		},
+		{
+			Version: database.VersionCreateContributions,
+			Name:    "create_contributions_table",
+			Up:      database.CreateContributionsTable,
+			Down:    database.DropContributionsTable,
+		},
+		{
+			Version: database.VersionCreateWishlistMembers,
+			Name:    "create_wishlist_members_table",
+			Up:      database.CreateWishlistMembersTable,
+			Down:    database.DropWishlistMembersTable,
+		},
+		{
+			Version: database.VersionAddWishlistEvents,
+			Name:    "add_wishlist_events",
+			Up:      database.AddWishlistEvents,
+			Down:    database.DropWishlistEvents,
+		},
+		{
+			Version: database.VersionCreateGiftHistory,
+			Name:    "create_gift_history",
+			Up:      database.CreateGiftHistory,
+			Down:    database.DropGiftHistory,
+		},
+		{
+			Version: database.VersionAddWishItemTags,
+			Name:    "add_wish_item_tags",
+			Up:      database.AddWishItemTags,
+			Down:    database.DropWishItemTags,
+		},
+		{
+			Version: database.VersionCreateWishlistImports,
+			Name:    "create_wishlist_imports",
+			Up:      database.CreateWishlistImports,
+			Down:    database.DropWishlistImports,
+		},
+		{
+			Version: database.VersionAddWishlistImportOneActive,
+			Name:    "add_wishlist_import_one_active",
+			Up:      database.AddWishlistImportOneActive,
+			Down:    database.DropWishlistImportOneActive,
+		},
+	}
+}
+
+// Start launches the enabled jobs, tracking the prices of wish items and archiving wishlists,
+// which run right away
+func (m *Module) Start(a *app.App) error {
+	priceTracking := a.Config.PriceTracking
+	if priceTracking.Enabled {
+		if priceTracking.CheckInterval <= 0 || priceTracking.RecheckAfter <= 0 {
+			return errors.New("price tracking CheckInterval and RecheckAfter must be positive")
+		}
+		if priceTracking.BatchSize <= 0 || priceTracking.RetentionDays <= 0 {
+			return errors.New("price tracking BatchSize and RetentionDays must be positive")
+		}
+	}
+
+	archival := a.Config.Archival
+	if archival.Enabled {
+		if archival.CheckInterval <= 0 || archival.ArchiveAfterDays < 0 {
+			return errors.New("archival CheckInterval must be positive and ArchiveAfterDays not negative")
+		}
+	}
+
+	imports := a.Config.Imports
+	if imports.Enabled {
+		if imports.PollInterval <= 0 || imports.MaxRows <= 0 || imports.MaxFileKB <= 0 {
+			return errors.New("imports PollInterval, MaxRows and MaxFileKB must be positive")
+		}
+	}
+
+	m.stop = make(chan struct{})
+	if priceTracking.Enabled {
+		m.every(time.Duration(priceTracking.CheckInterval)*time.Minute, trackPrices(priceTracking))
+	}
+	if archival.Enabled {
+		m.every(time.Duration(archival.CheckInterval)*time.Minute, archiveEndedWishlists(archival))
+	}
+	if imports.Enabled {
+		m.every(time.Duration(imports.PollInterval)*time.Second, runImports(imports))
+	}
+	return nil
+}
+
+// Stop stops the jobs, waiting for the runs in progress to finish
+func (m *Module) Stop(a *app.App) error {
+	if m.stop == nil {
+		return nil
+	}
+	close(m.stop)
+	m.jobs.Wait()
+	return nil
+}
+
+// RegisterRoutes registers all wishlist-related routes
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	// Read-only views of other users' wishlists, open to anonymous visitors for public lists
+	viewer := router.Group("/")
+	viewer.Use(auth.OptionalAuthMiddleware(app.GetJWTManager()))
+	{
+		viewer.GET("/view-wishlist", action.ActionViewWishlist())
+		viewer.GET("/list-user-wishlists", action.ActionListUserWishlists())
+		viewer.GET("/list-shared-wish-items", action.ActionListSharedWishItems())
+	}
+
+	// Share links need no authentication at all
+	router.GET("/shared/:token", app.GetCDN().Middleware(cdn.GroupShared), action.ActionViewSharedLink())
+
+	// All wishlist routes require authentication
+	protected := router.Group("/")
+	protected.Use(auth.AuthMiddleware(app.GetJWTManager()))
+	{
+		protected.POST("/create-wishlist", action.ActionCreateWishlist())
+		protected.GET("/list-wishlists", action.ActionListWishlists())
+		protected.POST("/rename-wishlist", action.ActionRenameWishlist())
+		protected.POST("/update-wishlist-visibility", action.ActionUpdateWishlistVisibility())
+		protected.PATCH("/wishlist", action.ActionPatchWishlist())
+		protected.POST("/regenerate-wishlist-share-link", action.ActionRegenerateShareLink())
+		protected.POST("/revoke-wishlist-share-link", action.ActionRevokeShareLink())
+		protected.POST("/delete-wishlist", action.ActionDeleteWishlist())
+		protected.GET("/wishlists/:id/stats", action.ActionWishlistStats())
+		protected.GET("/wishlists/:id/export", action.ActionExportWishlist())
+
+		// Imports of items from CSV files and Amazon wish lists, run in the background
+		if app.GetConfig().Imports.Enabled {
+			protected.POST("/wishlists/import", action.ActionImportWishlist())
+			protected.GET("/wishlists/import/:id", action.ActionGetWishlistImport())
+		}
+
+		// Shared ownership of wishlists
+		protected.GET("/list-wishlist-members", action.ActionListWishlistMembers())
+		protected.POST("/invite-wishlist-member", action.ActionInviteWishlistMember())
+		protected.POST("/remove-wishlist-member", action.ActionRemoveWishlistMember())
+		protected.GET("/list-wishlist-invitations", action.ActionListWishlistInvitations())
+		protected.POST("/accept-wishlist-invitation", action.ActionAcceptWishlistInvitation())
+		protected.POST("/leave-wishlist", action.ActionLeaveWishlist())
+
+		// Wish items
+		protected.GET("/list-wish-items", action.ActionListWishItems())
+		protected.POST("/add-wish-item", action.ActionAddWishItem())
+		protected.GET("/preview-wish-item-url", action.ActionPreviewWishItemURL())
+		protected.POST("/edit-wish-item", action.ActionEditWishItem())
+		protected.PATCH("/wish-item", action.ActionPatchWishItem())
+		protected.POST("/reorder-wish-items", action.ActionReorderWishItems())
+		protected.POST("/remove-wish-item", action.ActionRemoveWishItem())
+		protected.POST("/wishlists/:id/items/:itemID/image", action.ActionUploadWishItemImage())
+		protected.GET("/wish-item-price-history", action.ActionListPriceHistory())
+
+		// Reserving items on someone else's wishlist
+		protected.POST("/reserve-wish-item", action.ActionReserveWishItem())
+		protected.POST("/release-wish-item", action.ActionReleaseWishItem())
+		protected.GET("/wishlists/:id/surprise-me", action.ActionSurpriseMe())
+		protected.POST("/pledge-contribution", action.ActionPledgeContribution())
+		protected.POST("/withdraw-contribution", action.ActionWithdrawContribution())
+
+		// Gift history
+		protected.POST("/mark-wish-item-purchased", action.ActionMarkWishItemPurchased())
+		protected.POST("/mark-wish-item-received", action.ActionMarkWishItemReceived())
+		protected.GET("/list-gift-history", action.ActionListGiftHistory())
+		protected.GET("/gift-history-summary", action.ActionGiftHistorySummary())
+
+		// Reactions
+		protected.POST("/add-reaction", action.ActionAddReaction())
+		protected.POST("/remove-reaction", action.ActionRemoveReaction())
+
+		// Subscriptions to someone else's wishlist
+		protected.POST("/subscribe-wishlist", action.ActionSubscribeWishlist())
+		protected.POST("/unsubscribe-wishlist", action.ActionUnsubscribeWishlist())
+		protected.GET("/list-wishlist-subscriptions", action.ActionListWishlistSubscriptions())
+	}
+}
+
+// every runs job now and then every interval until the module is stopped
+func (m *Module) every(interval time.Duration, job func()) {
+	m.jobs.Add(1)
+	go func() {
+		defer m.jobs.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			job()
+
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// trackPrices returns the job tracking the prices of wish items
+func trackPrices(config app.PriceTrackingConfig) func() {
+	recheckAfter := time.Duration(config.RecheckAfter) * time.Hour
+	retention := time.Duration(config.RetentionDays) * 24 * time.Hour
+
+	return func() {
+		if err := action.TrackPrices(config.BatchSize, recheckAfter, retention); err != nil {
+			// Items not checked yet are picked up by the next run
+			log.Printf("Failed to track prices: %v", err)
+		}
+	}
+}
+
+// archiveEndedWishlists returns the job archiving the wishlists whose event is over
+func archiveEndedWishlists(config app.ArchivalConfig) func() {
+	return func() {
+		if err := action.ArchiveEndedWishlists(config.ArchiveAfterDays); err != nil {
+			// Wishlists left over are archived by the next run
+			log.Printf("Failed to archive wishlists: %v", err)
+		}
+	}
+}
+
+// runImports returns the job running the waiting imports, one per run
+func runImports(config app.ImportsConfig) func() {
+	return func() {
+		if _, err := action.RunNextImport(config.MaxRows); err != nil {
+			// An import left running is resumed once it is taken for abandoned
+			log.Printf("Failed to run wishlist import: %v", err)
+		}
+	}
+}
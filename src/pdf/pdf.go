@@ -0,0 +1,233 @@
+// Package pdf writes simple printable documents as PDF: A4 pages of wrapped text in the standard
+// Helvetica fonts, which every reader ships, so no font is embedded. Text is encoded as WinAnsi;
+// characters outside it are replaced with "?".
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// A4 page size and margins, in points
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 50
+)
+
+// Style is the font and size a paragraph is written in
+type Style struct {
+	Size float64
+	Bold bool
+	Gray bool
+}
+
+// Paragraph styles
+var (
+	Heading = Style{Size: 18, Bold: true}
+	Strong  = Style{Size: 11, Bold: true}
+	Body    = Style{Size: 11}
+	Note    = Style{Size: 9, Gray: true}
+)
+
+// Document is a PDF document being written, page by page
+type Document struct {
+	title string
+	pages []*bytes.Buffer
+	y     float64
+}
+
+// New creates an empty document; title is shown by readers in the window title
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// Write adds a paragraph, wrapped to the page width and continued on a new page when the
+// current one is full. Line breaks in text start new lines.
+func (d *Document) Write(style Style, text string) {
+	leading := style.Size * 1.3
+	for _, line := range strings.Split(text, "\n") {
+		for _, wrapped := range wrap(encode(line), style, pageWidth-2*margin) {
+			if d.pages == nil || d.y-leading < margin {
+				d.newPage()
+			}
+			d.y -= leading
+			d.writeLine(style, wrapped)
+		}
+	}
+}
+
+// Space adds vertical space, in points, dropped at the top of a page
+func (d *Document) Space(height float64) {
+	if d.pages != nil && d.y != pageHeight-margin {
+		d.y -= height
+	}
+}
+
+// newPage starts a page
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+// writeLine draws an encoded line at the current position
+func (d *Document) writeLine(style Style, line []byte) {
+	font := "F1"
+	if style.Bold {
+		font = "F2"
+	}
+	gray := 0.0
+	if style.Gray {
+		gray = 0.4
+	}
+	fmt.Fprintf(d.pages[len(d.pages)-1], "BT %.1f g /%s %.1f Tf %d %.1f Td (%s) Tj ET\n",
+		gray, font, style.Size, margin, d.y, escape(line))
+}
+
+// WriteTo writes the document as a PDF file
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if d.pages == nil {
+		d.newPage()
+	}
+
+	out := &bytes.Buffer{}
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 to 5 are fixed, then each page is followed by its content stream
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s >>", encodeText(d.title)))
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.WriteTo(w)
+}
+
+// encode converts text to WinAnsi, the encoding of the standard fonts, turning tabs and other
+// control characters into spaces
+func encode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch b, ok := charmap.Windows1252.EncodeRune(r); {
+		case r < ' ' || r == 0x7f:
+			encoded = append(encoded, ' ')
+		case ok:
+			encoded = append(encoded, b)
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+// encodeText encodes text as a PDF hexadecimal string in UTF-16, for the document information,
+// which is not limited to WinAnsi
+func encodeText(text string) string {
+	var encoded strings.Builder
+	encoded.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&encoded, "%04X", unit)
+	}
+	encoded.WriteString(">")
+	return encoded.String()
+}
+
+// escape escapes the delimiters of a PDF literal string
+func escape(text []byte) string {
+	var escaped strings.Builder
+	for _, c := range text {
+		if c == '\\' || c == '(' || c == ')' {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteByte(c)
+	}
+	return escaped.String()
+}
+
+// wrap splits an encoded line into lines fitting width, breaking at spaces, and inside words,
+// such as long links, that do not fit on a line of their own
+func wrap(text []byte, style Style, width float64) [][]byte {
+	var lines [][]byte
+	var line []byte
+	for _, word := range bytes.Fields(text) {
+		candidate := word
+		if len(line) > 0 {
+			candidate = append(append(append([]byte{}, line...), ' '), word...)
+		}
+		if measure(candidate, style) <= width {
+			line = candidate
+			continue
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		for measure(word, style) > width {
+			cut := 1
+			for cut < len(word) && measure(word[:cut+1], style) <= width {
+				cut++
+			}
+			lines = append(lines, word[:cut])
+			word = word[cut:]
+		}
+		line = word
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// measure returns the width of an encoded text in points. Bold text is measured with the
+// regular widths, enlarged to stay on the safe side.
+func measure(text []byte, style Style) float64 {
+	units := 0
+	for _, c := range text {
+		if c >= ' ' && int(c-' ') < len(helveticaWidths) {
+			units += helveticaWidths[c-' ']
+		} else {
+			units += 556
+		}
+	}
+	width := float64(units) * style.Size / 1000
+	if style.Bold {
+		width *= 1.1
+	}
+	return width
+}
+
+// helveticaWidths are the widths of the printable ASCII characters of Helvetica, from space to
+// tilde, in thousandths of the font size
+var helveticaWidths = []int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
@@ -0,0 +1,169 @@
+// Package pii keeps personal data out of logs, error reports and analytics payloads.
+//
+// Model fields holding personal data or secrets are tagged `pii:"true"`. Redact turns a
+// value into a copy safe to log or ship elsewhere, and Writer masks email addresses and
+// query string tokens in the process logs as a safety net for values logged by hand.
+package pii
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the value of a tagged field
+const Redacted = "[REDACTED]"
+
+// Redact returns a copy of v safe to log or serialize: structs become maps keyed by their
+// JSON field names with `pii:"true"` fields replaced by Redacted, and fields hidden from
+// JSON are dropped. Slices, maps and pointers are redacted element by element; other values
+// are returned unchanged.
+func Redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return redactValue(reflect.ValueOf(v))
+}
+
+func redactValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	case reflect.Struct:
+		return redactStruct(v)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[toString(iter.Key())] = redactValue(iter.Value())
+		}
+		return out
+	default:
+		if !v.CanInterface() {
+			return nil
+		}
+		return v.Interface()
+	}
+}
+
+func redactStruct(v reflect.Value) interface{} {
+	t := v.Type()
+
+	// Types with their own text form, such as time.Time, are kept as is
+	if t.NumField() == 0 || !hasExportedField(t) {
+		return v.Interface()
+	}
+
+	out := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			jsonName, _, _ := strings.Cut(tag, ",")
+			if jsonName == "-" {
+				continue
+			}
+			if jsonName != "" {
+				name = jsonName
+			}
+		}
+
+		if IsTagged(field) {
+			out[name] = Redacted
+			continue
+		}
+		out[name] = redactValue(v.Field(i))
+	}
+	return out
+}
+
+// IsTagged reports whether a struct field is marked as personal data
+func IsTagged(field reflect.StructField) bool {
+	return field.Tag.Get("pii") == "true"
+}
+
+func hasExportedField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+func toString(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	if s, ok := v.Interface().(interface{ String() string }); ok {
+		return s.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// emailPattern matches email addresses, including URL-encoded ones in logged request paths
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+(?:@|%40)[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// Email masks an email address for logs, keeping the first character and the domain:
+// alice@example.com becomes a***@example.com
+func Email(address string) string {
+	local, domain, ok := strings.Cut(address, "@")
+	if !ok {
+		local, domain, ok = strings.Cut(address, "%40")
+	}
+	if !ok || local == "" {
+		return Redacted
+	}
+	return local[:1] + "***@" + domain
+}
+
+// tokenPattern matches access tokens passed in logged query strings
+var tokenPattern = regexp.MustCompile(`(access_token=)[^&\s"]+`)
+
+// Writer masks email addresses and query string access tokens in everything written through it
+type Writer struct {
+	out io.Writer
+}
+
+// NewWriter creates a Writer passing masked output on to out
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{out: out}
+}
+
+// Write masks the email addresses and tokens in p and writes it to the underlying writer.
+// It reports len(p) on success so callers do not treat the masking as a short write.
+func (w *Writer) Write(p []byte) (int, error) {
+	masked := emailPattern.ReplaceAllFunc(p, func(match []byte) []byte {
+		return []byte(Email(string(match)))
+	})
+	masked = tokenPattern.ReplaceAll(masked, []byte("${1}"+Redacted))
+	if bytes.Equal(masked, p) {
+		return w.out.Write(p)
+	}
+	if _, err := w.out.Write(masked); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,154 @@
+package pii_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/pii"
+)
+
+func TestRedact(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	user := model.UserResponse{
+		PublicID:  "8f14e45f-ceea-467f-a8b5-3c5f1e5c7d21",
+		Username:  "alice",
+		Email:     "alice@example.com",
+		Gender:    model.GenderFemale,
+		CreatedAt: createdAt,
+		Birthday:  "1990-05-17",
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		check func(t *testing.T, got interface{})
+	}{
+		{
+			name:  "tagged fields of a struct are masked",
+			value: user,
+			check: func(t *testing.T, got interface{}) {
+				fields := got.(map[string]interface{})
+				for _, name := range []string{"email", "gender", "birthday"} {
+					if fields[name] != pii.Redacted {
+						t.Errorf("%s = %v, want %s", name, fields[name], pii.Redacted)
+					}
+				}
+				if fields["username"] != "alice" {
+					t.Errorf("username = %v, want it kept", fields["username"])
+				}
+				if fields["created_at"] != createdAt {
+					t.Errorf("created_at = %v, want the time kept as is", fields["created_at"])
+				}
+			},
+		},
+		{
+			name:  "pointers and slices are redacted element by element",
+			value: []*model.UserResponse{&user, nil},
+			check: func(t *testing.T, got interface{}) {
+				items := got.([]interface{})
+				if email := items[0].(map[string]interface{})["email"]; email != pii.Redacted {
+					t.Errorf("email = %v, want %s", email, pii.Redacted)
+				}
+				if items[1] != nil {
+					t.Errorf("nil pointer = %v, want nil", items[1])
+				}
+			},
+		},
+		{
+			name:  "maps are redacted value by value",
+			value: map[string]interface{}{"user": user, "attempts": 3},
+			check: func(t *testing.T, got interface{}) {
+				fields := got.(map[string]interface{})
+				if email := fields["user"].(map[string]interface{})["email"]; email != pii.Redacted {
+					t.Errorf("email = %v, want %s", email, pii.Redacted)
+				}
+				if fields["attempts"] != 3 {
+					t.Errorf("attempts = %v, want 3", fields["attempts"])
+				}
+			},
+		},
+		{
+			name:  "fields hidden from JSON are dropped",
+			value: model.User{Username: "alice", PasswordHash: "secret-hash"},
+			check: func(t *testing.T, got interface{}) {
+				if _, ok := got.(map[string]interface{})["PasswordHash"]; ok {
+					t.Error("PasswordHash is present, want it dropped")
+				}
+			},
+		},
+		{
+			name:  "plain values are kept",
+			value: "alice@example.com",
+			check: func(t *testing.T, got interface{}) {
+				if got != "alice@example.com" {
+					t.Errorf("got %v, want the string unchanged", got)
+				}
+			},
+		},
+		{
+			name:  "nil stays nil",
+			value: nil,
+			check: func(t *testing.T, got interface{}) {
+				if got != nil {
+					t.Errorf("got %v, want nil", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, pii.Redact(tt.value))
+		})
+	}
+}
+
+func TestIsTagged(t *testing.T) {
+	typ := reflect.TypeOf(model.UserResponse{})
+	tests := []struct {
+		field string
+		want  bool
+	}{
+		{"Email", true},
+		{"Birthday", true},
+		{"Username", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, _ := typ.FieldByName(tt.field)
+			if got := pii.IsTagged(field); got != tt.want {
+				t.Errorf("IsTagged(%s) = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"email address", "login by alice@example.com failed", "login by a***@example.com failed"},
+		{"URL-encoded email address", "GET /users?email=bob%40example.org", "GET /users?email=b***@example.org"},
+		{"access token", "GET /ws?access_token=abc.def&x=1", "GET /ws?access_token=[REDACTED]&x=1"},
+		{"nothing to mask", "GET /wishlists", "GET /wishlists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			n, err := pii.NewWriter(&out).Write([]byte(tt.in))
+			if err != nil || n != len(tt.in) {
+				t.Fatalf("Write() = %d, %v, want %d, nil", n, err, len(tt.in))
+			}
+			if out.String() != tt.want {
+				t.Errorf("wrote %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Package ratelimit limits how many requests an API key may make to a route within a window.
+// Past a soft limit, responses carry warning headers and an alert is raised, so integrators can
+// fix their clients before the hard limit refuses them.
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// maxTracked bounds the number of counters kept in memory; expired ones are dropped first, then
+// the one whose window started first
+const maxTracked = 10000
+
+// Limit is the number of requests allowed per window
+type Limit struct {
+	Soft int // requests after which responses warn, 0 for no warning band
+	Hard int // requests after which requests are refused, 0 for no limit
+}
+
+// Config configures the Limiter
+type Config struct {
+	Window  time.Duration
+	Default Limit
+	Routes  map[string]Limit // by route, as in "/api/v1/wishlists/:id"
+	Keys    map[int]Limit    // by API key ID, taking precedence over Routes and Default
+}
+
+// Alerter raises alerts, such as a bruteforce.Alerter
+type Alerter interface {
+	Alert(event string, fields map[string]interface{})
+}
+
+// Decision is the outcome of counting a request
+type Decision struct {
+	Limit     Limit
+	Count     int           // requests in the current window, this one included
+	Remaining int           // requests left before the hard limit
+	Reset     time.Duration // until the window ends
+}
+
+// Allowed reports whether the request is under the hard limit
+func (d Decision) Allowed() bool {
+	return d.Limit.Hard <= 0 || d.Count <= d.Limit.Hard
+}
+
+// Warned reports whether the request is in the soft band, past the soft limit
+func (d Decision) Warned() bool {
+	return d.Limit.Soft > 0 && d.Count > d.Limit.Soft
+}
+
+type counterKey struct {
+	keyID int
+	route string
+}
+
+type counter struct {
+	requests    int
+	windowStart time.Time
+}
+
+// Limiter counts the requests of each API key and route in fixed windows, in memory. Counts are
+// per instance and are lost on restart.
+type Limiter struct {
+	config  Config
+	alerter Alerter
+
+	mu       sync.Mutex
+	counters map[counterKey]*counter
+
+	// now is time.Now, replaced in tests
+	now func() time.Time
+}
+
+// NewLimiter creates a new Limiter sending its alerts through alerter
+func NewLimiter(config Config, alerter Alerter) *Limiter {
+	return &Limiter{
+		config:   config,
+		alerter:  alerter,
+		counters: make(map[counterKey]*counter),
+		now:      time.Now,
+	}
+}
+
+// limit returns the limit applying to an API key on a route
+func (l *Limiter) limit(keyID int, route string) Limit {
+	if limit, ok := l.config.Keys[keyID]; ok {
+		return limit
+	}
+	if limit, ok := l.config.Routes[route]; ok {
+		return limit
+	}
+	return l.config.Default
+}
+
+// Take counts a request of an API key to a route. Crossing the soft limit and reaching the hard
+// one each raise an alert once per window.
+func (l *Limiter) Take(keyID int, route string) Decision {
+	limit := l.limit(keyID, route)
+	if limit.Soft <= 0 && limit.Hard <= 0 {
+		return Decision{Limit: limit}
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	key := counterKey{keyID: keyID, route: route}
+	state, ok := l.counters[key]
+	if !ok {
+		if len(l.counters) >= maxTracked {
+			l.prune(now)
+		}
+		if len(l.counters) >= maxTracked {
+			l.evictOldest()
+		}
+		state = &counter{windowStart: now}
+		l.counters[key] = state
+	}
+	if now.Sub(state.windowStart) >= l.config.Window {
+		state.requests = 0
+		state.windowStart = now
+	}
+	state.requests++
+
+	decision := Decision{
+		Limit: limit,
+		Count: state.requests,
+		Reset: state.windowStart.Add(l.config.Window).Sub(now),
+	}
+	if limit.Hard > 0 {
+		decision.Remaining = max(limit.Hard-state.requests, 0)
+	}
+	l.mu.Unlock()
+
+	switch {
+	case limit.Soft > 0 && decision.Count == limit.Soft+1:
+		l.alert("rate_limit_soft_exceeded", keyID, route, decision)
+	case limit.Hard > 0 && decision.Count == limit.Hard+1:
+		l.alert("rate_limit_exceeded", keyID, route, decision)
+	}
+	return decision
+}
+
+// alert raises an alert about an API key reaching one of its limits
+func (l *Limiter) alert(event string, keyID int, route string, decision Decision) {
+	l.alerter.Alert(event, map[string]interface{}{
+		"api_key_id": keyID,
+		"route":      route,
+		"soft_limit": decision.Limit.Soft,
+		"hard_limit": decision.Limit.Hard,
+		"window":     int(l.config.Window.Seconds()),
+	})
+}
+
+// prune drops the counters whose window ended
+func (l *Limiter) prune(now time.Time) {
+	for key, state := range l.counters {
+		if now.Sub(state.windowStart) >= l.config.Window {
+			delete(l.counters, key)
+		}
+	}
+}
+
+// evictOldest drops the counter whose window started first, when every tracked window is still
+// running, so the map never grows past maxTracked
+func (l *Limiter) evictOldest() {
+	var oldest counterKey
+	var oldestStart time.Time
+	for key, state := range l.counters {
+		if oldestStart.IsZero() || state.windowStart.Before(oldestStart) {
+			oldest, oldestStart = key, state.windowStart
+		}
+	}
+	delete(l.counters, oldest)
+}
+
+// Middleware counts the requests of the API key authenticated by auth.APIKeyMiddleware to the
+// matched route. Limited routes carry the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers; in the soft band a Warning header is added, and past the hard limit requests are
+// refused with a 429 and Retry-After.
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		keyID, ok := auth.GetAPIKeyID(ctx)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		decision := l.Take(keyID, ctx.FullPath())
+		seconds := int(decision.Reset.Truncate(time.Second).Seconds()) + 1
+		if decision.Limit.Hard > 0 {
+			ctx.Header("RateLimit-Limit", strconv.Itoa(decision.Limit.Hard))
+			ctx.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			ctx.Header("RateLimit-Reset", strconv.Itoa(seconds))
+		}
+
+		if !decision.Allowed() {
+			ctx.Header("Retry-After", strconv.Itoa(seconds))
+			ctx.Error(apperror.TooManyRequests("Rate limit exceeded for this API key").
+				WithCode(apperror.CodeRateLimited).
+				With("retry_after", seconds))
+			ctx.Abort()
+			return
+		}
+
+		if decision.Warned() {
+			ctx.Header("Warning", fmt.Sprintf(`199 - "Soft rate limit of %d requests per %d seconds exceeded"`,
+				decision.Limit.Soft, int(l.config.Window.Seconds())))
+			ctx.Header("RateLimit-Soft-Limit", strconv.Itoa(decision.Limit.Soft))
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// recordingAlerter keeps the events of the alerts raised
+type recordingAlerter struct {
+	events []string
+}
+
+func (a *recordingAlerter) Alert(event string, fields map[string]interface{}) {
+	a.events = append(a.events, event)
+}
+
+// fakeClock is a clock moved forward by hand
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestLimiter(config Config) (*Limiter, *recordingAlerter, *fakeClock) {
+	alerter := &recordingAlerter{}
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	limiter := NewLimiter(config, alerter)
+	limiter.now = clock.Now
+	return limiter, alerter, clock
+}
+
+func TestTake(t *testing.T) {
+	const route = "/api/v1/wishlists/:id"
+
+	// step is one request, taken after moving the clock forward by advance
+	type step struct {
+		advance       time.Duration
+		keyID         int
+		wantCount     int
+		wantRemaining int
+		wantAllowed   bool
+		wantWarned    bool
+	}
+
+	tests := []struct {
+		name       string
+		config     Config
+		steps      []step
+		wantAlerts []string
+	}{
+		{
+			name:   "unlimited routes are not counted",
+			config: Config{Window: time.Minute},
+			steps: []step{
+				{keyID: 1, wantCount: 0, wantAllowed: true},
+				{keyID: 1, wantCount: 0, wantAllowed: true},
+			},
+		},
+		{
+			name:   "soft band warns and the hard limit refuses",
+			config: Config{Window: time.Minute, Default: Limit{Soft: 1, Hard: 2}},
+			steps: []step{
+				{keyID: 1, wantCount: 1, wantRemaining: 1, wantAllowed: true},
+				{keyID: 1, wantCount: 2, wantRemaining: 0, wantAllowed: true, wantWarned: true},
+				{keyID: 1, wantCount: 3, wantRemaining: 0, wantAllowed: false, wantWarned: true},
+				{keyID: 1, wantCount: 4, wantRemaining: 0, wantAllowed: false, wantWarned: true},
+			},
+			wantAlerts: []string{"rate_limit_soft_exceeded", "rate_limit_exceeded"},
+		},
+		{
+			name:   "a new window starts the count again",
+			config: Config{Window: time.Minute, Default: Limit{Hard: 1}},
+			steps: []step{
+				{keyID: 1, wantCount: 1, wantRemaining: 0, wantAllowed: true},
+				{advance: 30 * time.Second, keyID: 1, wantCount: 2, wantRemaining: 0, wantAllowed: false},
+				{advance: 30 * time.Second, keyID: 1, wantCount: 1, wantRemaining: 0, wantAllowed: true},
+			},
+			wantAlerts: []string{"rate_limit_exceeded"},
+		},
+		{
+			name: "keys are counted apart and key limits take precedence",
+			config: Config{
+				Window:  time.Minute,
+				Default: Limit{Hard: 1},
+				Routes:  map[string]Limit{route: {Hard: 2}},
+				Keys:    map[int]Limit{2: {Hard: 3}},
+			},
+			steps: []step{
+				{keyID: 1, wantCount: 1, wantRemaining: 1, wantAllowed: true},
+				{keyID: 2, wantCount: 1, wantRemaining: 2, wantAllowed: true},
+				{keyID: 1, wantCount: 2, wantRemaining: 0, wantAllowed: true},
+				{keyID: 2, wantCount: 2, wantRemaining: 1, wantAllowed: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, alerter, clock := newTestLimiter(tt.config)
+
+			for i, s := range tt.steps {
+				clock.now = clock.now.Add(s.advance)
+				decision := limiter.Take(s.keyID, route)
+				if decision.Count != s.wantCount || decision.Remaining != s.wantRemaining {
+					t.Errorf("request %d: count = %d, remaining = %d, want %d, %d",
+						i+1, decision.Count, decision.Remaining, s.wantCount, s.wantRemaining)
+				}
+				if decision.Allowed() != s.wantAllowed || decision.Warned() != s.wantWarned {
+					t.Errorf("request %d: allowed = %t, warned = %t, want %t, %t",
+						i+1, decision.Allowed(), decision.Warned(), s.wantAllowed, s.wantWarned)
+				}
+			}
+
+			if len(alerter.events) != len(tt.wantAlerts) {
+				t.Fatalf("alerts = %v, want %v", alerter.events, tt.wantAlerts)
+			}
+			for i, event := range tt.wantAlerts {
+				if alerter.events[i] != event {
+					t.Errorf("alerts = %v, want %v", alerter.events, tt.wantAlerts)
+				}
+			}
+		})
+	}
+}
+
+func TestTakeBoundsCounters(t *testing.T) {
+	tests := []struct {
+		name string
+		// elapsed is the time between filling the counters and the next new key
+		elapsed time.Duration
+	}{
+		{"expired windows are pruned", 2 * time.Minute},
+		{"the oldest window is evicted when none expired", time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, _, clock := newTestLimiter(Config{Window: time.Minute, Default: Limit{Hard: 10}})
+
+			for keyID := 1; keyID <= maxTracked; keyID++ {
+				limiter.Take(keyID, "/")
+				clock.now = clock.now.Add(time.Microsecond)
+			}
+
+			clock.now = clock.now.Add(tt.elapsed)
+			if decision := limiter.Take(maxTracked+1, "/"); decision.Count != 1 {
+				t.Fatalf("count of the new key = %d, want 1", decision.Count)
+			}
+			if len(limiter.counters) > maxTracked {
+				t.Errorf("tracked counters = %d, want at most %d", len(limiter.counters), maxTracked)
+			}
+			if _, ok := limiter.counters[counterKey{keyID: maxTracked + 1, route: "/"}]; !ok {
+				t.Error("the new key is not tracked")
+			}
+			if _, ok := limiter.counters[counterKey{keyID: 1, route: "/"}]; ok {
+				t.Error("the oldest key is still tracked")
+			}
+		})
+	}
+}
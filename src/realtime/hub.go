@@ -0,0 +1,219 @@
+// Package realtime pushes events to users over WebSocket connections.
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// Event types pushed to clients
+const (
+	EventNotification    = "notification"
+	EventWishlistChanged = "wishlist_changed"
+	EventHeartbeat       = "heartbeat"
+)
+
+const (
+	// sendBuffer is the number of events queued per connection before it is considered stuck
+	sendBuffer = 32
+	// writeTimeout bounds a single write to a client
+	writeTimeout = 10 * time.Second
+	// heartbeatInterval keeps idle connections open through proxies and detects dead peers
+	heartbeatInterval = 30 * time.Second
+)
+
+// Event is a message pushed to a user's connections
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// WishlistChange describes a change to a wishlist, named by its public ID, or to its items
+type WishlistChange struct {
+	WishlistID string `json:"wishlist_id"`
+	ItemID     int    `json:"item_id,omitempty"`
+	Action     string `json:"action"`
+}
+
+// client is one WebSocket connection of a user, opened with the access token tokenID
+type client struct {
+	conn    *websocket.Conn
+	send    chan []byte
+	once    sync.Once
+	tokenID string
+}
+
+// close closes the connection once; the writer loop exits when send is closed
+func (c *client) close() {
+	c.once.Do(func() {
+		close(c.send)
+		c.conn.Close()
+	})
+}
+
+// Hub tracks the open connections of every user and fans events out to them
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[int]map[*client]struct{}
+}
+
+// NewHub creates a new Hub without connections
+func NewHub() *Hub {
+	return &Hub{clients: make(map[int]map[*client]struct{})}
+}
+
+// Publish sends an event to every connection of a user. It never blocks: connections
+// that cannot keep up are dropped, and clients reconnect and refetch.
+func (h *Hub) Publish(userID int, event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", event.Type, err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.clients[userID] {
+		select {
+		case c.send <- payload:
+		default:
+			log.Printf("Dropping stuck realtime connection of user %d", userID)
+			go h.unregister(userID, c)
+		}
+	}
+}
+
+// Handler upgrades the request to a WebSocket connection receiving the user's events.
+// Authentication is done beforehand with the JWT, so the Origin header is not checked. The
+// connection is closed when the token, identified by tokenID, expires at expiresAt (never when
+// zero), or when DisconnectToken or DisconnectUser end it earlier.
+func (h *Hub) Handler(userID int, tokenID string, expiresAt time.Time) http.Handler {
+	return websocket.Server{
+		Handshake: func(config *websocket.Config, req *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			h.serve(userID, &client{conn: conn, send: make(chan []byte, sendBuffer), tokenID: tokenID}, expiresAt)
+		},
+	}
+}
+
+// DisconnectUser closes every connection of a user, whose sessions ended
+func (h *Hub) DisconnectUser(userID int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.clients[userID] {
+		c.close()
+	}
+	delete(h.clients, userID)
+}
+
+// DisconnectToken closes the connections of a user opened with an access token that was revoked
+func (h *Hub) DisconnectToken(userID int, tokenID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.clients[userID] {
+		if c.tokenID == tokenID {
+			c.close()
+			delete(h.clients[userID], c)
+		}
+	}
+	if len(h.clients[userID]) == 0 {
+		delete(h.clients, userID)
+	}
+}
+
+// Close disconnects every client, used on shutdown since hijacked connections are not drained
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for userID, clients := range h.clients {
+		for c := range clients {
+			c.close()
+		}
+		delete(h.clients, userID)
+	}
+}
+
+// serve registers the connection and pumps events to it until either side closes it or its
+// token expires
+func (h *Hub) serve(userID int, c *client, expiresAt time.Time) {
+	conn := c.conn
+	// The HTTP server's deadlines still apply to the hijacked connection
+	conn.SetDeadline(time.Time{})
+
+	h.register(userID, c)
+	defer h.unregister(userID, c)
+
+	var expired <-chan time.Time
+	if !expiresAt.IsZero() {
+		expiry := time.NewTimer(time.Until(expiresAt))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	// The channel is push only; reading detects the client going away
+	go func() {
+		defer h.unregister(userID, c)
+		var discard []byte
+		for {
+			if err := websocket.Message.Receive(conn, &discard); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat, _ := json.Marshal(Event{Type: EventHeartbeat})
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		var payload []byte
+		select {
+		case message, ok := <-c.send:
+			if !ok {
+				return
+			}
+			payload = message
+		case <-ticker.C:
+			payload = heartbeat
+		case <-expired:
+			return
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := websocket.Message.Send(conn, string(payload)); err != nil {
+			return
+		}
+	}
+}
+
+func (h *Hub) register(userID int, c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*client]struct{})
+	}
+	h.clients[userID][c] = struct{}{}
+}
+
+func (h *Hub) unregister(userID int, c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if clients, ok := h.clients[userID]; ok {
+		delete(clients, c)
+		if len(clients) == 0 {
+			delete(h.clients, userID)
+		}
+	}
+	c.close()
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// APIKeyRepository implements the model.APIKeyRepository interface
+type APIKeyRepository struct {
+	db *DB
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository
+func NewAPIKeyRepository(db *DB) model.APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+// apiKeyColumns lists the columns selected for an API key
+const apiKeyColumns = `id, name, prefix, key_hash, created_by, created_at, last_used_at, revoked_at`
+
+// apiKeyTouchInterval is how stale last_used_at may get, so busy keys do not write on every request
+const apiKeyTouchInterval = time.Minute
+
+// scanAPIKey scans an API key row into a model
+func scanAPIKey(scanner interface{ Scan(...interface{}) error }) (*model.APIKey, error) {
+	key := &model.APIKey{}
+	err := scanner.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+	return key, err
+}
+
+// apiKeyListSpec describes the sorting and filtering accepted when listing API keys
+var apiKeyListSpec = listSpec{
+	sorts: map[string]string{
+		"created_at":   "created_at",
+		"last_used_at": "last_used_at",
+		"name":         "name",
+	},
+	defaultSort: "created_at",
+	defaultDesc: true,
+	tieBreaker:  "id",
+	filters: map[string]string{
+		"name":   "name ILIKE '%' || ? || '%'",
+		"status": "(CASE WHEN revoked_at IS NULL THEN 'active' ELSE 'revoked' END) = ?",
+	},
+}
+
+// Create stores a new API key
+func (r *APIKeyRepository) Create(key *model.APIKey) error {
+	query := `
+		INSERT INTO api_keys (name, prefix, key_hash, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	if err := r.db.QueryRowWrite(query, key.Name, key.Prefix, key.KeyHash, key.CreatedBy, key.CreatedAt).Scan(&key.ID); err != nil {
+		log.Printf("Error creating API key %q: %v", key.Name, err)
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+// Authenticate returns the ID of the active key with the hash and records that it was used
+func (r *APIKeyRepository) Authenticate(keyHash string) (int, bool, error) {
+	query := `
+		WITH key AS (
+			SELECT id FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL
+		), touched AS (
+			UPDATE api_keys SET last_used_at = $2
+			WHERE id IN (SELECT id FROM key) AND (last_used_at IS NULL OR last_used_at < $3)
+		)
+		SELECT id FROM key
+	`
+
+	now := time.Now().UTC()
+	var id int
+	err := r.db.QueryRowWrite(query, keyHash, now, now.Add(-apiKeyTouchInterval)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		log.Printf("Error authenticating API key: %v", err)
+		return 0, false, fmt.Errorf("failed to authenticate API key: %w", err)
+	}
+
+	return id, true, nil
+}
+
+// List retrieves a page of API keys, active and revoked
+func (r *APIKeyRepository) List(opts model.ListOptions) ([]*model.APIKey, *model.PageInfo, error) {
+	q := apiKeyListSpec.apply(selectFrom("api_keys", apiKeyColumns), opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting API keys: %v", err)
+		return nil, nil, fmt.Errorf("failed to count API keys: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing API keys: %v", err)
+		return nil, nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var keys []*model.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			log.Printf("Error scanning API key row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over API key rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over API keys: %w", err)
+	}
+
+	return keys, model.NewPageInfo(opts, total, len(keys)), nil
+}
+
+// Revoke revokes an active API key
+func (r *APIKeyRepository) Revoke(id int) error {
+	result, err := r.db.Exec(`UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`, id, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error revoking API key %d: %v", id, err)
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for API key revocation: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("active API key with ID %d not found", id)
+	}
+
+	return nil
+}
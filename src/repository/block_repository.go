@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// BlockRepository implements the model.BlockRepository interface
+type BlockRepository struct {
+	db *DB
+}
+
+// NewBlockRepository creates a new instance of BlockRepository
+func NewBlockRepository(db *DB) model.BlockRepository {
+	return &BlockRepository{
+		db: db,
+	}
+}
+
+// blockListSpec describes the sorting and filtering accepted when listing blocked and muted users
+var blockListSpec = listSpec{
+	sorts: map[string]string{
+		"blocked_at": "b.created_at",
+		"username":   "u.username",
+	},
+	defaultSort: "blocked_at",
+	defaultDesc: true,
+	tieBreaker:  "u.id",
+	filters: map[string]string{
+		"kind":     "b.kind = ?",
+		"username": "u.username ILIKE '%' || ? || '%'",
+	},
+}
+
+// Set blocks or mutes a user. A block replaces a mute but a mute never weakens a block.
+// Blocking also removes the follow relationships between both users.
+func (r *BlockRepository) Set(blockerID, blockedID int, kind model.BlockKind) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting block of user %d by user %d: %v", blockedID, blockerID, err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back block: %v", rollbackErr)
+		}
+	}()
+
+	query := `
+		INSERT INTO blocks (blocker_id, blocked_id, kind, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (blocker_id, blocked_id)
+		DO UPDATE SET kind = EXCLUDED.kind, created_at = EXCLUDED.created_at
+		WHERE blocks.kind = 'mute'
+	`
+
+	if _, err := tx.Exec(query, blockerID, blockedID, kind, time.Now().UTC()); err != nil {
+		log.Printf("Error setting %s of user %d by user %d: %v", kind, blockedID, blockerID, err)
+		return fmt.Errorf("failed to %s user: %w", kind, err)
+	}
+
+	if kind == model.BlockKindBlock {
+		_, err := tx.Exec(
+			`DELETE FROM follows WHERE (follower_id = $1 AND followee_id = $2) OR (follower_id = $2 AND followee_id = $1)`,
+			blockerID, blockedID,
+		)
+		if err != nil {
+			log.Printf("Error removing follows between users %d and %d: %v", blockerID, blockedID, err)
+			return fmt.Errorf("failed to remove follows: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing block: %v", err)
+		return fmt.Errorf("failed to commit block: %w", err)
+	}
+
+	return nil
+}
+
+// Remove undoes a block or a mute; undoing one that does not exist is a no-op
+func (r *BlockRepository) Remove(blockerID, blockedID int, kind model.BlockKind) error {
+	query := `DELETE FROM blocks WHERE blocker_id = $1 AND blocked_id = $2 AND kind = $3`
+
+	if _, err := r.db.Exec(query, blockerID, blockedID, kind); err != nil {
+		log.Printf("Error removing %s of user %d by user %d: %v", kind, blockedID, blockerID, err)
+		return fmt.Errorf("failed to un%s user: %w", kind, err)
+	}
+
+	return nil
+}
+
+// Get returns how the blocker shuts out the other user, or an empty kind when they do not
+func (r *BlockRepository) Get(blockerID, blockedID int) (model.BlockKind, error) {
+	var kind model.BlockKind
+	err := r.db.QueryRow(
+		`SELECT kind FROM blocks WHERE blocker_id = $1 AND blocked_id = $2`,
+		blockerID, blockedID,
+	).Scan(&kind)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		log.Printf("Error getting block of user %d by user %d: %v", blockedID, blockerID, err)
+		return "", fmt.Errorf("failed to get block: %w", err)
+	}
+
+	return kind, nil
+}
+
+// IsBlockedEither reports whether either user blocked the other. Mutes are not counted.
+func (r *BlockRepository) IsBlockedEither(userID, otherID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM blocks
+			WHERE kind = 'block'
+				AND ((blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1))
+		)`,
+		userID, otherID,
+	).Scan(&exists)
+	if err != nil {
+		log.Printf("Error checking block between users %d and %d: %v", userID, otherID, err)
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+
+	return exists, nil
+}
+
+// List retrieves a page of the users the user blocked or muted
+func (r *BlockRepository) List(userID int, opts model.ListOptions) ([]*model.BlockedUser, *model.PageInfo, error) {
+	q := selectFrom(`blocks b JOIN users u ON u.id = b.blocked_id`, `u.id, u.username, b.kind, b.created_at`).
+		Where("b.blocker_id = ?", userID).Where("u.deleted_at IS NULL")
+	blockListSpec.apply(q, opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting blocks for user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to count blocks: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing blocks for user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to list blocks: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var users []*model.BlockedUser
+	for rows.Next() {
+		user := &model.BlockedUser{}
+		if err := rows.Scan(&user.UserID, &user.Username, &user.Kind, &user.BlockedAt); err != nil {
+			log.Printf("Error scanning block row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan block: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over block rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over blocks: %w", err)
+	}
+
+	return users, model.NewPageInfo(opts, total, len(users)), nil
+}
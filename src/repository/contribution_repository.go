@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// ContributionRepository implements the model.ContributionRepository interface
+type ContributionRepository struct {
+	db *DB
+}
+
+// NewContributionRepository creates a new instance of ContributionRepository
+func NewContributionRepository(db *DB) model.ContributionRepository {
+	return &ContributionRepository{
+		db: db,
+	}
+}
+
+// Pledge creates or updates the user's contribution to an item and reports whether it made the
+// contributions reach the item's price. The item row is locked so concurrent givers cannot
+// together pledge more than the price.
+func (r *ContributionRepository) Pledge(contribution *model.Contribution) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting contribution: %v", err)
+		return false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back contribution: %v", rollbackErr)
+		}
+	}()
+
+	var target int64
+	var itemCurrency string
+	err = tx.QueryRow(
+		`SELECT price_minor * quantity, currency FROM wish_items WHERE id = $1 FOR UPDATE`,
+		contribution.ItemID,
+	).Scan(&target, &itemCurrency)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("wish item with ID %d not found", contribution.ItemID)
+		}
+		log.Printf("Error locking wish item %d: %v", contribution.ItemID, err)
+		return false, fmt.Errorf("failed to lock wish item: %w", err)
+	}
+
+	if target == 0 {
+		return false, model.ErrItemNotPriced
+	}
+	if contribution.Currency != itemCurrency {
+		return false, fmt.Errorf("wish item %d is priced in %s, not %s", contribution.ItemID, itemCurrency, contribution.Currency)
+	}
+
+	var pledgedByOthers, pledgedBefore int64
+	err = tx.QueryRow(`
+		SELECT COALESCE(SUM(amount_minor) FILTER (WHERE user_id <> $2), 0), COALESCE(SUM(amount_minor), 0)
+		FROM contributions
+		WHERE item_id = $1 AND currency = $3
+	`, contribution.ItemID, contribution.UserID, itemCurrency).Scan(&pledgedByOthers, &pledgedBefore)
+	if err != nil {
+		log.Printf("Error summing contributions to wish item %d: %v", contribution.ItemID, err)
+		return false, fmt.Errorf("failed to sum contributions: %w", err)
+	}
+
+	pledged := pledgedByOthers + contribution.AmountMinor
+	if pledged > target {
+		return false, model.ErrPledgeTooLarge
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO contributions (item_id, user_id, amount_minor, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (item_id, user_id) DO UPDATE
+		SET amount_minor = EXCLUDED.amount_minor, currency = EXCLUDED.currency, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`, contribution.ItemID, contribution.UserID, contribution.AmountMinor, contribution.Currency,
+		contribution.CreatedAt, contribution.UpdatedAt).Scan(&contribution.ID, &contribution.CreatedAt)
+	if err != nil {
+		log.Printf("Error pledging towards wish item %d for user %d: %v", contribution.ItemID, contribution.UserID, err)
+		return false, fmt.Errorf("failed to pledge towards wish item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing contribution: %v", err)
+		return false, fmt.Errorf("failed to commit contribution: %w", err)
+	}
+
+	return pledgedBefore < target && pledged >= target, nil
+}
+
+// Withdraw removes the user's contribution to an item
+func (r *ContributionRepository) Withdraw(itemID, userID int) error {
+	result, err := r.db.Exec(`DELETE FROM contributions WHERE item_id = $1 AND user_id = $2`, itemID, userID)
+	if err != nil {
+		log.Printf("Error withdrawing contribution to wish item %d for user %d: %v", itemID, userID, err)
+		return fmt.Errorf("failed to withdraw contribution: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for contribution withdrawal: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no contribution to wish item %d by user %d", itemID, userID)
+	}
+
+	return nil
+}
+
+// ListByWishlist retrieves every contribution to the items of a wishlist
+func (r *ContributionRepository) ListByWishlist(wishlistID int) ([]*model.Contribution, error) {
+	query := `
+		SELECT c.id, c.item_id, c.user_id, c.amount_minor, c.currency, c.created_at, c.updated_at
+		FROM contributions c
+		JOIN wish_items i ON i.id = c.item_id
+		WHERE i.wishlist_id = $1
+		ORDER BY c.created_at
+	`
+
+	rows, err := r.db.Query(query, wishlistID)
+	if err != nil {
+		log.Printf("Error listing contributions for wishlist %d: %v", wishlistID, err)
+		return nil, fmt.Errorf("failed to list contributions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var contributions []*model.Contribution
+	for rows.Next() {
+		contribution := &model.Contribution{}
+		if err := rows.Scan(&contribution.ID, &contribution.ItemID, &contribution.UserID, &contribution.AmountMinor,
+			&contribution.Currency, &contribution.CreatedAt, &contribution.UpdatedAt); err != nil {
+			log.Printf("Error scanning contribution row: %v", err)
+			return nil, fmt.Errorf("failed to scan contribution: %w", err)
+		}
+		contributions = append(contributions, contribution)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over contribution rows: %v", err)
+		return nil, fmt.Errorf("error iterating over contributions: %w", err)
+	}
+
+	return contributions, nil
+}
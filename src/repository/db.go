@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// QueryConfig configures the timeout and retries applied to each repository query
+type QueryConfig struct {
+	Timeout        int // in seconds, 0 disables the timeout
+	MaxRetries     int // retries after a transient error, 0 disables retrying
+	RetryBaseDelay int // in milliseconds, doubled on each retry before jitter
+	RetryMaxDelay  int // in milliseconds, upper bound of a single wait
+}
+
+// DB wraps the connection pool so that Exec, Query, QueryRow and QueryRowWrite run with a
+// per-query timeout and are retried with jittered backoff on transient errors. Transactions
+// started with Begin are left alone: a failed statement aborts the whole transaction, so
+// retrying it alone would be wrong.
+type DB struct {
+	*sql.DB
+	config QueryConfig
+}
+
+// NewDB wraps db with the timeout and retry policy of config
+func NewDB(db *sql.DB, config QueryConfig) *DB {
+	return &DB{DB: db, config: config}
+}
+
+// Exec executes a statement. Only errors raised before the statement could take effect are
+// retried, since it may not be safe to run it twice.
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := db.retry(false, func() error {
+		ctx, cancel := db.context()
+		defer cancel()
+
+		var err error
+		result, err = db.DB.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// Query executes a query returning rows. The timeout covers reading the rows too and ends
+// when they are closed.
+func (db *DB) Query(query string, args ...interface{}) (*Rows, error) {
+	var rows *Rows
+	err := db.retry(true, func() error {
+		ctx, cancel := db.context()
+
+		sqlRows, err := db.DB.QueryContext(ctx, query, args...)
+		if err != nil {
+			cancel()
+			return err
+		}
+		rows = &Rows{Rows: sqlRows, cancel: cancel}
+		return nil
+	})
+	return rows, err
+}
+
+// QueryRow executes a query expected to return at most one row. The query runs when Scan is
+// called on the result, so that errors surfacing only then are retried as well.
+func (db *DB) QueryRow(query string, args ...interface{}) *Row {
+	return &Row{db: db, query: query, args: args, idempotent: true}
+}
+
+// QueryRowWrite executes an INSERT, UPDATE or DELETE ... RETURNING expected to return at most
+// one row. Like Exec, only errors raised before the statement could take effect are retried.
+func (db *DB) QueryRowWrite(query string, args ...interface{}) *Row {
+	return &Row{db: db, query: query, args: args}
+}
+
+// context returns the context a single attempt runs with
+func (db *DB) context() (context.Context, context.CancelFunc) {
+	if db.config.Timeout <= 0 {
+		return context.Background(), func() {}
+	}
+	return context.WithTimeout(context.Background(), time.Duration(db.config.Timeout)*time.Second)
+}
+
+// retry calls fn until it succeeds, fails with an error that is not transient or runs out of
+// retries, waiting a random time up to an exponentially growing bound between attempts
+func (db *DB) retry(idempotent bool, fn func() error) error {
+	err := fn()
+	for attempt := 0; attempt < db.config.MaxRetries && isTransient(err, idempotent); attempt++ {
+		delay := db.backoff(attempt)
+		log.Printf("Retrying query after transient error in %v (retry %d of %d): %v", delay, attempt+1, db.config.MaxRetries, err)
+		time.Sleep(delay)
+		err = fn()
+	}
+	return err
+}
+
+// backoff returns the wait before the given retry, using full jitter
+func (db *DB) backoff(attempt int) time.Duration {
+	bound := time.Duration(db.config.RetryBaseDelay) * time.Millisecond << attempt
+	if maxDelay := time.Duration(db.config.RetryMaxDelay) * time.Millisecond; maxDelay > 0 && (bound > maxDelay || bound <= 0) {
+		bound = maxDelay
+	}
+	if bound <= 0 {
+		return 0
+	}
+	return rand.N(bound)
+}
+
+// isTransient reports whether err is worth retrying. Serialization failures, deadlocks and
+// server shutdowns roll the statement back, so any statement may run again. A connection
+// dropped mid-statement leaves its outcome unknown, so only idempotent reads are retried then.
+func isTransient(err error, idempotent bool) bool {
+	if err == nil {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"57P01", // admin_shutdown
+			"57P03", // cannot_connect_now
+			"53300": // too_many_connections
+			return true
+		}
+		return idempotent && pqErr.Code.Class() == "08" // connection_exception
+	}
+
+	return idempotent && (errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF))
+}
+
+// Rows is the result of DB.Query; closing it also releases the query's timeout
+type Rows struct {
+	*sql.Rows
+	cancel context.CancelFunc
+}
+
+// Close closes the rows and releases the timeout
+func (r *Rows) Close() error {
+	defer r.cancel()
+	return r.Rows.Close()
+}
+
+// Row is the result of DB.QueryRow or DB.QueryRowWrite
+type Row struct {
+	db         *DB
+	query      string
+	args       []interface{}
+	idempotent bool
+}
+
+// Scan runs the query and copies the columns of the first row into dest, returning
+// sql.ErrNoRows when there is none
+func (r *Row) Scan(dest ...interface{}) error {
+	return r.db.retry(r.idempotent, func() error {
+		ctx, cancel := r.db.context()
+		defer cancel()
+
+		return r.db.DB.QueryRowContext(ctx, r.query, r.args...).Scan(dest...)
+	})
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+// fakeStep is the outcome of one statement run against the fake driver: an error, the single
+// row of a query (none when row is nil) or the rows affected by an exec
+type fakeStep struct {
+	err          error
+	row          []driver.Value
+	rowsAffected int64
+}
+
+// fakeScript is the sequence of outcomes a test database answers statements with. It records
+// each statement run, along with BEGIN, COMMIT and ROLLBACK.
+type fakeScript struct {
+	mu    sync.Mutex
+	steps []fakeStep
+	ran   []string
+}
+
+func (s *fakeScript) record(statement string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ran = append(s.ran, statement)
+}
+
+func (s *fakeScript) next(query string) (fakeStep, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ran = append(s.ran, query)
+	if len(s.steps) == 0 {
+		return fakeStep{}, fmt.Errorf("unexpected statement: %s", query)
+	}
+	step := s.steps[0]
+	s.steps = s.steps[1:]
+	return step, nil
+}
+
+// fakeScripts maps the data source names of open test databases to their scripts
+var fakeScripts sync.Map
+
+func init() {
+	sql.Register("fake", fakeDriver{})
+}
+
+// fakeDriver is a database/sql driver answering statements from a fakeScript
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	script, ok := fakeScripts.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("no script for %s", name)
+	}
+	return &fakeConn{script: script.(*fakeScript)}, nil
+}
+
+type fakeConn struct {
+	script *fakeScript
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.script.record("BEGIN")
+	return fakeTx{script: c.script}, nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	step, err := c.script.next(query)
+	if err != nil {
+		return nil, err
+	}
+	if step.err != nil {
+		return nil, step.err
+	}
+	return &fakeRows{row: step.row}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	step, err := c.script.next(query)
+	if err != nil {
+		return nil, err
+	}
+	if step.err != nil {
+		return nil, step.err
+	}
+	return driver.RowsAffected(step.rowsAffected), nil
+}
+
+type fakeTx struct {
+	script *fakeScript
+}
+
+func (tx fakeTx) Commit() error {
+	tx.script.record("COMMIT")
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.script.record("ROLLBACK")
+	return nil
+}
+
+// fakeRows returns row once, its columns being named after their position
+type fakeRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	columns := make([]string, len(r.row))
+	for i := range columns {
+		columns[i] = fmt.Sprintf("column%d", i+1)
+	}
+	return columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done || r.row == nil {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.row)
+	return nil
+}
+
+// newTestDB returns a DB retrying up to maxRetries times without waiting, whose statements are
+// answered by steps in order
+func newTestDB(t *testing.T, maxRetries int, steps ...fakeStep) (*DB, *fakeScript) {
+	t.Helper()
+
+	script := &fakeScript{steps: steps}
+	fakeScripts.Store(t.Name(), script)
+	t.Cleanup(func() { fakeScripts.Delete(t.Name()) })
+
+	sqlDB, err := sql.Open("fake", t.Name())
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return NewDB(sqlDB, QueryConfig{MaxRetries: maxRetries}), script
+}
+
+func TestRowScan(t *testing.T) {
+	row := fakeStep{row: []driver.Value{int64(7)}}
+
+	tests := []struct {
+		name         string
+		write        bool
+		maxRetries   int
+		steps        []fakeStep
+		want         int
+		wantErr      error
+		wantAttempts int
+	}{
+		{
+			name:         "read succeeds at once",
+			maxRetries:   2,
+			steps:        []fakeStep{row},
+			want:         7,
+			wantAttempts: 1,
+		},
+		{
+			name:         "read is retried after a dropped connection",
+			maxRetries:   2,
+			steps:        []fakeStep{{err: syscall.ECONNRESET}, {err: &pq.Error{Code: "08006"}}, row},
+			want:         7,
+			wantAttempts: 3,
+		},
+		{
+			name:         "write is not retried after a dropped connection",
+			write:        true,
+			maxRetries:   2,
+			steps:        []fakeStep{{err: syscall.ECONNRESET}},
+			wantErr:      syscall.ECONNRESET,
+			wantAttempts: 1,
+		},
+		{
+			name:         "write is retried after a serialization failure",
+			write:        true,
+			maxRetries:   2,
+			steps:        []fakeStep{{err: &pq.Error{Code: "40001"}}, row},
+			want:         7,
+			wantAttempts: 2,
+		},
+		{
+			name:         "errors that are not transient are not retried",
+			maxRetries:   2,
+			steps:        []fakeStep{{err: &pq.Error{Code: "23505"}}},
+			wantErr:      &pq.Error{Code: "23505"},
+			wantAttempts: 1,
+		},
+		{
+			name:         "retries stop after the configured number",
+			maxRetries:   2,
+			steps:        []fakeStep{{err: io.ErrUnexpectedEOF}, {err: io.ErrUnexpectedEOF}, {err: io.ErrUnexpectedEOF}},
+			wantErr:      io.ErrUnexpectedEOF,
+			wantAttempts: 3,
+		},
+		{
+			name:         "retrying can be disabled",
+			maxRetries:   0,
+			steps:        []fakeStep{{err: &pq.Error{Code: "40P01"}}},
+			wantErr:      &pq.Error{Code: "40P01"},
+			wantAttempts: 1,
+		},
+		{
+			name:         "no row is not an error worth retrying",
+			maxRetries:   2,
+			steps:        []fakeStep{{}},
+			wantErr:      sql.ErrNoRows,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, script := newTestDB(t, tt.maxRetries, tt.steps...)
+
+			var r *Row
+			if tt.write {
+				r = db.QueryRowWrite("INSERT INTO t DEFAULT VALUES RETURNING id")
+			} else {
+				r = db.QueryRow("SELECT id FROM t")
+			}
+
+			var got int
+			err := r.Scan(&got)
+			if tt.wantErr != nil {
+				var pqErr, wantPQErr *pq.Error
+				if errors.As(tt.wantErr, &wantPQErr) {
+					if !errors.As(err, &pqErr) || pqErr.Code != wantPQErr.Code {
+						t.Errorf("Scan() error = %v, want %v", err, tt.wantErr)
+					}
+				} else if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Scan() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("Scan() error = %v", err)
+			} else if got != tt.want {
+				t.Errorf("Scan() = %d, want %d", got, tt.want)
+			}
+
+			if len(script.ran) != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", len(script.ran), tt.wantAttempts)
+			}
+		})
+	}
+}
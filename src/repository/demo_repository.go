@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// DemoRepository implements the model.DemoRepository interface
+type DemoRepository struct {
+	db *DB
+}
+
+// NewDemoRepository creates a new instance of DemoRepository
+func NewDemoRepository(db *DB) model.DemoRepository {
+	return &DemoRepository{
+		db: db,
+	}
+}
+
+// Reset deletes the sandbox accounts and seeds the dataset again. Everything the accounts own
+// goes with them through the foreign keys, and visitors never see a half-seeded sandbox.
+func (r *DemoRepository) Reset(dataset *model.DemoDataset, passwordHash string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting demo sandbox reset: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back demo sandbox reset: %v", rollbackErr)
+		}
+	}()
+
+	if _, err := tx.Exec(`DELETE FROM users WHERE sandbox`); err != nil {
+		log.Printf("Error deleting demo sandbox accounts: %v", err)
+		return fmt.Errorf("failed to delete sandbox accounts: %w", err)
+	}
+
+	now := time.Now().UTC()
+	userIDs := make(map[string]int, len(dataset.Users))
+	for _, user := range dataset.Users {
+		var userID int
+		err := tx.QueryRow(`
+			INSERT INTO users (username, email, password_hash, email_verified_at, sandbox, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, TRUE, $4, $4)
+			RETURNING id
+		`, user.Username, user.Email, passwordHash, now).Scan(&userID)
+		if err != nil {
+			log.Printf("Error seeding demo account %s: %v", user.Username, err)
+			return fmt.Errorf("failed to seed sandbox account %s: %w", user.Username, err)
+		}
+		userIDs[user.Username] = userID
+	}
+
+	type reservation struct{ itemID, userID int }
+	var reservations []reservation
+	for _, user := range dataset.Users {
+		for _, wishlist := range user.Wishlists {
+			var wishlistID int
+			err := tx.QueryRow(`
+				INSERT INTO wishlists (user_id, title, visibility, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $4)
+				RETURNING id
+			`, userIDs[user.Username], wishlist.Title, wishlist.Visibility, now).Scan(&wishlistID)
+			if err != nil {
+				log.Printf("Error seeding demo wishlist %q: %v", wishlist.Title, err)
+				return fmt.Errorf("failed to seed sandbox wishlist: %w", err)
+			}
+
+			for position, item := range wishlist.Items {
+				var itemID int
+				err := tx.QueryRow(`
+					INSERT INTO wish_items (wishlist_id, title, description, url, price_minor, currency, priority, quantity,
+						position, created_at, updated_at, price)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10, $11)
+					RETURNING id
+				`, wishlistID, item.Title, item.Description, item.URL, item.PriceMinor, item.Currency, item.Priority,
+					item.Quantity, position, now, currency.FromMinor(item.PriceMinor, item.Currency)).Scan(&itemID)
+				if err != nil {
+					log.Printf("Error seeding demo wish item %q: %v", item.Title, err)
+					return fmt.Errorf("failed to seed sandbox wish item: %w", err)
+				}
+
+				for _, username := range item.ReservedBy {
+					reservations = append(reservations, reservation{itemID: itemID, userID: userIDs[username]})
+				}
+			}
+		}
+	}
+
+	for _, follow := range dataset.Follows {
+		_, err := tx.Exec(`INSERT INTO follows (follower_id, followee_id, created_at) VALUES ($1, $2, $3)`,
+			userIDs[follow[0]], userIDs[follow[1]], now)
+		if err != nil {
+			log.Printf("Error seeding demo follow of %s by %s: %v", follow[1], follow[0], err)
+			return fmt.Errorf("failed to seed sandbox follow: %w", err)
+		}
+	}
+
+	for _, reserved := range reservations {
+		_, err := tx.Exec(`INSERT INTO reservations (item_id, user_id, quantity, created_at) VALUES ($1, $2, 1, $3)`,
+			reserved.itemID, reserved.userID, now)
+		if err != nil {
+			log.Printf("Error seeding demo reservation of wish item %d: %v", reserved.itemID, err)
+			return fmt.Errorf("failed to seed sandbox reservation: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing demo sandbox reset: %v", err)
+		return fmt.Errorf("failed to commit sandbox reset: %w", err)
+	}
+
+	log.Printf("Demo sandbox reset with %d accounts", len(dataset.Users))
+	return nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// FollowRepository implements the model.FollowRepository interface
+type FollowRepository struct {
+	db *DB
+}
+
+// NewFollowRepository creates a new instance of FollowRepository
+func NewFollowRepository(db *DB) model.FollowRepository {
+	return &FollowRepository{
+		db: db,
+	}
+}
+
+// followListSpec describes the sorting and filtering accepted when listing followers or followed users
+var followListSpec = listSpec{
+	sorts: map[string]string{
+		"followed_at": "f.created_at",
+		"username":    "u.username",
+	},
+	defaultSort: "followed_at",
+	defaultDesc: true,
+	tieBreaker:  "u.id",
+	filters: map[string]string{
+		"username": "u.username ILIKE '%' || ? || '%'",
+	},
+}
+
+// Follow makes the follower follow the followee, reporting whether the relationship is new;
+// following twice is a no-op
+func (r *FollowRepository) Follow(followerID, followeeID int) (bool, error) {
+	query := `
+		INSERT INTO follows (follower_id, followee_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`
+
+	result, err := r.db.Exec(query, followerID, followeeID, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error following user %d by user %d: %v", followeeID, followerID, err)
+		return false, fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for follow: %v", err)
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Unfollow removes the follow relationship; unfollowing a user not followed is a no-op
+func (r *FollowRepository) Unfollow(followerID, followeeID int) error {
+	if _, err := r.db.Exec(`DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID); err != nil {
+		log.Printf("Error unfollowing user %d by user %d: %v", followeeID, followerID, err)
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+
+	return nil
+}
+
+// IsFollowing reports whether the follower follows the followee
+func (r *FollowRepository) IsFollowing(followerID, followeeID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = $2)`,
+		followerID, followeeID,
+	).Scan(&exists)
+	if err != nil {
+		log.Printf("Error checking whether user %d follows user %d: %v", followerID, followeeID, err)
+		return false, fmt.Errorf("failed to check follow: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ListFollowers retrieves a page of the users following the user
+func (r *FollowRepository) ListFollowers(userID int, opts model.ListOptions) ([]*model.FollowUser, *model.PageInfo, error) {
+	return r.list(`JOIN users u ON u.id = f.follower_id`, `f.followee_id = ?`, userID, opts)
+}
+
+// ListFollowing retrieves a page of the users the user follows
+func (r *FollowRepository) ListFollowing(userID int, opts model.ListOptions) ([]*model.FollowUser, *model.PageInfo, error) {
+	return r.list(`JOIN users u ON u.id = f.followee_id`, `f.follower_id = ?`, userID, opts)
+}
+
+// list retrieves a page of follow relationships joined to the user on the other side
+func (r *FollowRepository) list(join, condition string, userID int, opts model.ListOptions) ([]*model.FollowUser, *model.PageInfo, error) {
+	q := selectFrom(`follows f `+join, `u.id, u.username, f.created_at`).Where(condition, userID).Where("u.deleted_at IS NULL")
+	followListSpec.apply(q, opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting follows for user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to count follows: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing follows for user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to list follows: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var users []*model.FollowUser
+	for rows.Next() {
+		user := &model.FollowUser{}
+		if err := rows.Scan(&user.UserID, &user.Username, &user.FollowedAt); err != nil {
+			log.Printf("Error scanning follow row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan follow: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over follow rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over follows: %w", err)
+	}
+
+	return users, model.NewPageInfo(opts, total, len(users)), nil
+}
+
+// Counts returns the number of followers and followed users of a user
+func (r *FollowRepository) Counts(userID int) (*model.FollowCounts, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM follows WHERE followee_id = $1),
+			(SELECT COUNT(*) FROM follows WHERE follower_id = $1)
+	`
+
+	counts := &model.FollowCounts{}
+	if err := r.db.QueryRow(query, userID).Scan(&counts.Followers, &counts.Following); err != nil {
+		log.Printf("Error counting follows for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to count follows: %w", err)
+	}
+
+	return counts, nil
+}
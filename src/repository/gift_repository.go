@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// GiftRepository implements the model.GiftRepository interface
+type GiftRepository struct {
+	db *DB
+}
+
+// NewGiftRepository creates a new instance of GiftRepository
+func NewGiftRepository(db *DB) model.GiftRepository {
+	return &GiftRepository{
+		db: db,
+	}
+}
+
+// giftColumns lists the columns returned for a gift history entry
+const giftColumns = `id, user_id, direction, recipient_id, item_id, title, quantity, price_minor, currency, occurred_at`
+
+// giftListSpec describes the sorting and filtering accepted when listing the gift history
+var giftListSpec = listSpec{
+	sorts: map[string]string{
+		"occurred_at": "g.occurred_at",
+		"title":       "g.title",
+	},
+	defaultSort: "occurred_at",
+	defaultDesc: true,
+	tieBreaker:  "g.id",
+	filters: map[string]string{
+		"direction": "g.direction = ?",
+		"year":      "to_char(g.occurred_at, 'YYYY') = ?",
+	},
+}
+
+// RecordPurchase marks the user's reservation of an item purchased and adds it to the user's
+// history as given to the owner of the wishlist. It returns model.ErrReservationPurchased when
+// the reservation already is, and model.ErrNoReservation when there is none.
+func (r *GiftRepository) RecordPurchase(itemID, userID int, purchasedAt time.Time) (*model.Gift, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting gift purchase: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back gift purchase: %v", rollbackErr)
+		}
+	}()
+
+	var quantity int
+	err = tx.QueryRow(`
+		UPDATE reservations SET purchased_at = $3
+		WHERE item_id = $1 AND user_id = $2 AND purchased_at IS NULL
+		RETURNING quantity
+	`, itemID, userID, purchasedAt).Scan(&quantity)
+	if err == sql.ErrNoRows {
+		var purchased bool
+		err = tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM reservations WHERE item_id = $1 AND user_id = $2)`, itemID, userID).Scan(&purchased)
+		if err == nil && purchased {
+			return nil, model.ErrReservationPurchased
+		}
+		if err == nil {
+			return nil, model.ErrNoReservation
+		}
+	}
+	if err != nil {
+		log.Printf("Error marking reservation of wish item %d by user %d purchased: %v", itemID, userID, err)
+		return nil, fmt.Errorf("failed to mark reservation purchased: %w", err)
+	}
+
+	gift, err := scanGift(tx.QueryRow(`
+		INSERT INTO gift_history (user_id, direction, recipient_id, item_id, title, quantity, price_minor, currency, occurred_at)
+		SELECT $2, 'given', w.user_id, i.id, i.title, $3, i.price_minor, i.currency, $4
+		FROM wish_items i JOIN wishlists w ON w.id = i.wishlist_id
+		WHERE i.id = $1
+		RETURNING `+giftColumns,
+		itemID, userID, quantity, purchasedAt))
+	if err != nil {
+		log.Printf("Error recording gift of wish item %d by user %d: %v", itemID, userID, err)
+		return nil, fmt.Errorf("failed to record gift: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing gift purchase: %v", err)
+		return nil, fmt.Errorf("failed to commit gift purchase: %w", err)
+	}
+
+	return gift, nil
+}
+
+// RecordReceipt adds units to the received quantity of an item and to the history of the
+// wishlist's owner as received, without naming the givers. It returns
+// model.ErrItemFullyReceived when more units would be received than wished for.
+func (r *GiftRepository) RecordReceipt(itemID, quantity int, receivedAt time.Time) (*model.Gift, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting gift receipt: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back gift receipt: %v", rollbackErr)
+		}
+	}()
+
+	result, err := tx.Exec(`
+		UPDATE wish_items SET received_quantity = received_quantity + $2, updated_at = $3
+		WHERE id = $1 AND received_quantity + $2 <= quantity
+	`, itemID, quantity, receivedAt)
+	if err != nil {
+		log.Printf("Error marking wish item %d received: %v", itemID, err)
+		return nil, fmt.Errorf("failed to mark wish item received: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for gift receipt: %v", err)
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, model.ErrItemFullyReceived
+	}
+
+	gift, err := scanGift(tx.QueryRow(`
+		INSERT INTO gift_history (user_id, direction, item_id, title, quantity, price_minor, currency, occurred_at)
+		SELECT w.user_id, 'received', i.id, i.title, $2, i.price_minor, i.currency, $3
+		FROM wish_items i JOIN wishlists w ON w.id = i.wishlist_id
+		WHERE i.id = $1
+		RETURNING `+giftColumns,
+		itemID, quantity, receivedAt))
+	if err != nil {
+		log.Printf("Error recording receipt of wish item %d: %v", itemID, err)
+		return nil, fmt.Errorf("failed to record gift: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing gift receipt: %v", err)
+		return nil, fmt.Errorf("failed to commit gift receipt: %w", err)
+	}
+
+	return gift, nil
+}
+
+// ListByUser retrieves a page of the gifts the user gave and received, with the username of the
+// recipients of given gifts who are not deleted
+func (r *GiftRepository) ListByUser(userID int, opts model.ListOptions) ([]*model.Gift, *model.PageInfo, error) {
+	q := selectFrom(
+		`gift_history g LEFT JOIN users u ON u.id = g.recipient_id AND u.deleted_at IS NULL`,
+		`g.id, g.user_id, g.direction, g.recipient_id, g.item_id, g.title, g.quantity, g.price_minor, g.currency, g.occurred_at,
+			COALESCE(u.username, '')`,
+	).Where(`g.user_id = ?`, userID)
+	giftListSpec.apply(q, opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting gift history of user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to count gift history: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing gift history of user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to list gift history: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var gifts []*model.Gift
+	for rows.Next() {
+		gift := &model.Gift{}
+		if err := rows.Scan(
+			&gift.ID, &gift.UserID, &gift.Direction, &gift.RecipientID, &gift.ItemID, &gift.Title,
+			&gift.Quantity, &gift.PriceMinor, &gift.Currency, &gift.OccurredAt, &gift.RecipientUsername,
+		); err != nil {
+			log.Printf("Error scanning gift history row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan gift: %w", err)
+		}
+		gifts = append(gifts, gift)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over gift history rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over gift history: %w", err)
+	}
+
+	return gifts, model.NewPageInfo(opts, total, len(gifts)), nil
+}
+
+// SummarizeByYear sums up the gift history of the user per year, the most recent first
+func (r *GiftRepository) SummarizeByYear(userID int) ([]*model.GiftYearSummary, error) {
+	rows, err := r.db.Query(`
+		SELECT EXTRACT(YEAR FROM occurred_at)::INTEGER, direction, currency, SUM(quantity), SUM(price_minor * quantity)
+		FROM gift_history
+		WHERE user_id = $1
+		GROUP BY 1, direction, currency
+		ORDER BY 1 DESC, direction, currency
+	`, userID)
+	if err != nil {
+		log.Printf("Error summarizing gift history of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to summarize gift history: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	summaries := []*model.GiftYearSummary{}
+	for rows.Next() {
+		var year, units int
+		var direction model.GiftDirection
+		var amount currency.Amount
+		if err := rows.Scan(&year, &direction, &amount.Currency, &units, &amount.Minor); err != nil {
+			log.Printf("Error scanning gift history summary row: %v", err)
+			return nil, fmt.Errorf("failed to scan gift history summary: %w", err)
+		}
+
+		if len(summaries) == 0 || summaries[len(summaries)-1].Year != year {
+			summaries = append(summaries, &model.GiftYearSummary{
+				Year:          year,
+				GivenValue:    []*model.Money{},
+				ReceivedValue: []*model.Money{},
+			})
+		}
+		summary := summaries[len(summaries)-1]
+
+		// Unpriced gifts count as units but add no value
+		if direction == model.GiftGiven {
+			summary.GivenUnits += units
+			if amount.Minor > 0 {
+				summary.GivenValue = append(summary.GivenValue, model.NewMoney(amount))
+			}
+		} else {
+			summary.ReceivedUnits += units
+			if amount.Minor > 0 {
+				summary.ReceivedValue = append(summary.ReceivedValue, model.NewMoney(amount))
+			}
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over gift history summary rows: %v", err)
+		return nil, fmt.Errorf("error iterating over gift history summary: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// scanGift scans a gift history row into a model
+func scanGift(scanner interface{ Scan(...interface{}) error }) (*model.Gift, error) {
+	gift := &model.Gift{}
+	err := scanner.Scan(
+		&gift.ID,
+		&gift.UserID,
+		&gift.Direction,
+		&gift.RecipientID,
+		&gift.ItemID,
+		&gift.Title,
+		&gift.Quantity,
+		&gift.PriceMinor,
+		&gift.Currency,
+		&gift.OccurredAt,
+	)
+	return gift, err
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// GroupNoteRepository implements the model.GroupNoteRepository interface
+type GroupNoteRepository struct {
+	db *DB
+}
+
+// NewGroupNoteRepository creates a new instance of GroupNoteRepository
+func NewGroupNoteRepository(db *DB) model.GroupNoteRepository {
+	return &GroupNoteRepository{
+		db: db,
+	}
+}
+
+// Get retrieves the note of a group for a wishlist.
+// A missing note is returned as an empty note rather than an error.
+func (r *GroupNoteRepository) Get(groupID, wishlistID int) (*model.GroupNote, error) {
+	query := `
+		SELECT group_id, wishlist_id, content, updated_by, updated_at
+		FROM group_notes
+		WHERE group_id = $1 AND wishlist_id = $2
+	`
+
+	note := &model.GroupNote{}
+	err := r.db.QueryRow(query, groupID, wishlistID).Scan(
+		&note.GroupID,
+		&note.WishlistID,
+		&note.Content,
+		&note.UpdatedBy,
+		&note.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &model.GroupNote{GroupID: groupID, WishlistID: wishlistID}, nil
+		}
+		log.Printf("Error getting note of group %d for wishlist %d: %v", groupID, wishlistID, err)
+		return nil, fmt.Errorf("failed to get group note: %w", err)
+	}
+
+	return note, nil
+}
+
+// Upsert creates or replaces the note of a group for a wishlist
+func (r *GroupNoteRepository) Upsert(note *model.GroupNote) error {
+	query := `
+		INSERT INTO group_notes (group_id, wishlist_id, content, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (group_id, wishlist_id)
+		DO UPDATE SET content = EXCLUDED.content, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(query, note.GroupID, note.WishlistID, note.Content, note.UpdatedBy, note.UpdatedAt)
+	if err != nil {
+		log.Printf("Error saving note of group %d for wishlist %d: %v", note.GroupID, note.WishlistID, err)
+		return fmt.Errorf("failed to save group note: %w", err)
+	}
+
+	return nil
+}
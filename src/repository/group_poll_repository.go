@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// GroupPollRepository implements the model.GroupPollRepository interface
+type GroupPollRepository struct {
+	db *DB
+}
+
+// NewGroupPollRepository creates a new instance of GroupPollRepository
+func NewGroupPollRepository(db *DB) model.GroupPollRepository {
+	return &GroupPollRepository{
+		db: db,
+	}
+}
+
+// groupPollColumns lists the columns selected for a group poll
+const groupPollColumns = `id, group_id, wishlist_id, created_by, question, closes_at, created_at,
+	COALESCE((SELECT w.public_id::text FROM wishlists w WHERE w.id = group_polls.wishlist_id), '')`
+
+// groupPollOptionQuery selects poll options with their vote counts; callers append the WHERE clause
+const groupPollOptionQuery = `
+	SELECT o.id, o.poll_id, o.label, o.position, COUNT(v.user_id)
+	FROM group_poll_options o
+	JOIN group_polls p ON p.id = o.poll_id
+	LEFT JOIN group_poll_votes v ON v.option_id = o.id
+`
+
+// scanGroupPoll scans a group poll row into a model
+func scanGroupPoll(scanner interface{ Scan(...interface{}) error }) (*model.GroupPoll, error) {
+	poll := &model.GroupPoll{}
+	err := scanner.Scan(
+		&poll.ID,
+		&poll.GroupID,
+		&poll.WishlistID,
+		&poll.CreatedBy,
+		&poll.Question,
+		&poll.ClosesAt,
+		&poll.CreatedAt,
+		&poll.WishlistPublicID,
+	)
+	return poll, err
+}
+
+// Create creates a new poll together with its options
+func (r *GroupPollRepository) Create(poll *model.GroupPoll) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting poll creation: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back poll creation: %v", rollbackErr)
+		}
+	}()
+
+	err = tx.QueryRow(
+		`INSERT INTO group_polls (group_id, wishlist_id, created_by, question, closes_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		poll.GroupID, poll.WishlistID, poll.CreatedBy, poll.Question, poll.ClosesAt, poll.CreatedAt,
+	).Scan(&poll.ID)
+	if err != nil {
+		log.Printf("Error creating poll in group %d: %v", poll.GroupID, err)
+		return fmt.Errorf("failed to create poll: %w", err)
+	}
+
+	for i, option := range poll.Options {
+		option.PollID = poll.ID
+		option.Position = i
+		err = tx.QueryRow(
+			`INSERT INTO group_poll_options (poll_id, label, position) VALUES ($1, $2, $3) RETURNING id`,
+			option.PollID, option.Label, option.Position,
+		).Scan(&option.ID)
+		if err != nil {
+			log.Printf("Error creating option for poll %d: %v", poll.ID, err)
+			return fmt.Errorf("failed to create poll option: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing poll creation: %v", err)
+		return fmt.Errorf("failed to commit poll creation: %w", err)
+	}
+
+	log.Printf("Poll created successfully with ID: %d", poll.ID)
+	return nil
+}
+
+// GetByID retrieves a poll with its options and vote counts
+func (r *GroupPollRepository) GetByID(id int) (*model.GroupPoll, error) {
+	poll, err := scanGroupPoll(r.db.QueryRow(`SELECT `+groupPollColumns+` FROM group_polls WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("poll with ID %d not found", id)
+		}
+		log.Printf("Error getting poll by ID %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	options, err := r.listOptions(`WHERE p.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	poll.Options = options[poll.ID]
+
+	return poll, nil
+}
+
+// ListByGroup retrieves the polls of a group, newest first, with their options and vote counts
+func (r *GroupPollRepository) ListByGroup(groupID int) ([]*model.GroupPoll, error) {
+	query := `SELECT ` + groupPollColumns + ` FROM group_polls WHERE group_id = $1 ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Query(query, groupID)
+	if err != nil {
+		log.Printf("Error listing polls for group %d: %v", groupID, err)
+		return nil, fmt.Errorf("failed to list polls: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var polls []*model.GroupPoll
+	for rows.Next() {
+		poll, err := scanGroupPoll(rows)
+		if err != nil {
+			log.Printf("Error scanning poll row: %v", err)
+			return nil, fmt.Errorf("failed to scan poll: %w", err)
+		}
+		polls = append(polls, poll)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over poll rows: %v", err)
+		return nil, fmt.Errorf("error iterating over polls: %w", err)
+	}
+
+	options, err := r.listOptions(`WHERE p.group_id = $1`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, poll := range polls {
+		poll.Options = options[poll.ID]
+	}
+
+	return polls, nil
+}
+
+// listOptions retrieves poll options with vote counts, keyed by poll ID
+func (r *GroupPollRepository) listOptions(where string, args ...interface{}) (map[int][]*model.GroupPollOption, error) {
+	query := groupPollOptionQuery + where + ` GROUP BY o.id ORDER BY o.poll_id, o.position`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing poll options: %v", err)
+		return nil, fmt.Errorf("failed to list poll options: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	options := make(map[int][]*model.GroupPollOption)
+	for rows.Next() {
+		option := &model.GroupPollOption{}
+		if err := rows.Scan(&option.ID, &option.PollID, &option.Label, &option.Position, &option.Votes); err != nil {
+			log.Printf("Error scanning poll option row: %v", err)
+			return nil, fmt.Errorf("failed to scan poll option: %w", err)
+		}
+		options[option.PollID] = append(options[option.PollID], option)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over poll option rows: %v", err)
+		return nil, fmt.Errorf("error iterating over poll options: %w", err)
+	}
+
+	return options, nil
+}
+
+// Delete deletes a poll along with its options and votes
+func (r *GroupPollRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM group_polls WHERE id = $1`, id)
+	if err != nil {
+		log.Printf("Error deleting poll with ID %d: %v", id, err)
+		return fmt.Errorf("failed to delete poll: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for poll deletion: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("poll with ID %d not found", id)
+	}
+
+	log.Printf("Poll with ID %d deleted successfully", id)
+	return nil
+}
+
+// Vote records the user's choice in a poll, replacing any earlier vote
+func (r *GroupPollRepository) Vote(pollID, optionID, userID int) error {
+	query := `
+		INSERT INTO group_poll_votes (poll_id, option_id, user_id, voted_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (poll_id, user_id)
+		DO UPDATE SET option_id = EXCLUDED.option_id, voted_at = EXCLUDED.voted_at
+	`
+
+	if _, err := r.db.Exec(query, pollID, optionID, userID, time.Now().UTC()); err != nil {
+		log.Printf("Error recording vote of user %d in poll %d: %v", userID, pollID, err)
+		return fmt.Errorf("failed to record vote: %w", err)
+	}
+
+	return nil
+}
+
+// GetVote returns the option the user voted for, or nil if they have not voted
+func (r *GroupPollRepository) GetVote(pollID, userID int) (*int, error) {
+	var optionID int
+	err := r.db.QueryRow(
+		`SELECT option_id FROM group_poll_votes WHERE poll_id = $1 AND user_id = $2`,
+		pollID, userID,
+	).Scan(&optionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		log.Printf("Error getting vote of user %d in poll %d: %v", userID, pollID, err)
+		return nil, fmt.Errorf("failed to get vote: %w", err)
+	}
+
+	return &optionID, nil
+}
@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// GroupRepository implements the model.GroupRepository interface
+type GroupRepository struct {
+	db *DB
+}
+
+// NewGroupRepository creates a new instance of GroupRepository
+func NewGroupRepository(db *DB) model.GroupRepository {
+	return &GroupRepository{
+		db: db,
+	}
+}
+
+// Create creates a new group and adds its owner as the first member
+func (r *GroupRepository) Create(group *model.Group) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting group creation: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back group creation: %v", rollbackErr)
+		}
+	}()
+
+	err = tx.QueryRow(
+		`INSERT INTO groups (name, kind, owner_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		group.Name, group.Kind, group.OwnerID, group.CreatedAt, group.UpdatedAt,
+	).Scan(&group.ID)
+	if err != nil {
+		log.Printf("Error creating group: %v", err)
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO group_members (group_id, user_id, role, joined_at) VALUES ($1, $2, $3, $4)`,
+		group.ID, group.OwnerID, model.GroupRoleOwner, group.CreatedAt,
+	)
+	if err != nil {
+		log.Printf("Error adding owner to group %d: %v", group.ID, err)
+		return fmt.Errorf("failed to add group owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing group creation: %v", err)
+		return fmt.Errorf("failed to commit group creation: %w", err)
+	}
+
+	log.Printf("Group created successfully with ID: %d", group.ID)
+	return nil
+}
+
+// GetByID retrieves a group by its ID
+func (r *GroupRepository) GetByID(id int) (*model.Group, error) {
+	query := `
+		SELECT id, name, kind, owner_id, created_at, updated_at
+		FROM groups
+		WHERE id = $1
+	`
+
+	group := &model.Group{}
+	err := r.db.QueryRow(query, id).Scan(
+		&group.ID,
+		&group.Name,
+		&group.Kind,
+		&group.OwnerID,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("group with ID %d not found", id)
+		}
+		log.Printf("Error getting group by ID %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	return group, nil
+}
+
+// groupListSpec describes the sorting and filtering accepted when listing groups
+var groupListSpec = listSpec{
+	sorts: map[string]string{
+		"name":       "g.name",
+		"created_at": "g.created_at",
+	},
+	defaultSort: "name",
+	tieBreaker:  "g.id",
+	filters: map[string]string{
+		"kind": "g.kind = ?",
+		"name": "g.name ILIKE '%' || ? || '%'",
+	},
+}
+
+// ListByMember retrieves a page of the groups the user belongs to
+func (r *GroupRepository) ListByMember(userID int, opts model.ListOptions) ([]*model.Group, *model.PageInfo, error) {
+	q := selectFrom(`groups g JOIN group_members gm ON gm.group_id = g.id`, `g.id, g.name, g.kind, g.owner_id, g.created_at, g.updated_at`).
+		Where("gm.user_id = ?", userID)
+	groupListSpec.apply(q, opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting groups for user ID %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to count groups: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing groups for user ID %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var groups []*model.Group
+	for rows.Next() {
+		group := &model.Group{}
+		if err := rows.Scan(&group.ID, &group.Name, &group.Kind, &group.OwnerID, &group.CreatedAt, &group.UpdatedAt); err != nil {
+			log.Printf("Error scanning group row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over group rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over groups: %w", err)
+	}
+
+	return groups, model.NewPageInfo(opts, total, len(groups)), nil
+}
+
+// Delete deletes a group along with its memberships and shares
+func (r *GroupRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM groups WHERE id = $1`, id)
+	if err != nil {
+		log.Printf("Error deleting group with ID %d: %v", id, err)
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for group deletion: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("group with ID %d not found", id)
+	}
+
+	log.Printf("Group with ID %d deleted successfully", id)
+	return nil
+}
+
+// AddMember adds a user to a group; adding an existing member is a no-op
+func (r *GroupRepository) AddMember(groupID, userID int, role model.GroupRole) error {
+	query := `
+		INSERT INTO group_members (group_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (group_id, user_id) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(query, groupID, userID, role, time.Now().UTC()); err != nil {
+		log.Printf("Error adding user %d to group %d: %v", userID, groupID, err)
+		return fmt.Errorf("failed to add group member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember removes a user from a group, along with the wishlists they shared to it
+func (r *GroupRepository) RemoveMember(groupID, userID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back member removal: %v", rollbackErr)
+		}
+	}()
+
+	result, err := tx.Exec(`DELETE FROM group_members WHERE group_id = $1 AND user_id = $2`, groupID, userID)
+	if err != nil {
+		log.Printf("Error removing user %d from group %d: %v", userID, groupID, err)
+		return fmt.Errorf("failed to remove group member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user %d is not a member of group %d", userID, groupID)
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM group_wishlists
+		WHERE group_id = $1 AND wishlist_id IN (SELECT id FROM wishlists WHERE user_id = $2)
+	`, groupID, userID)
+	if err != nil {
+		log.Printf("Error unsharing wishlists of user %d from group %d: %v", userID, groupID, err)
+		return fmt.Errorf("failed to unshare member wishlists: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// IsMember reports whether the user belongs to the group
+func (r *GroupRepository) IsMember(groupID, userID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM group_members WHERE group_id = $1 AND user_id = $2)`,
+		groupID, userID,
+	).Scan(&exists)
+	if err != nil {
+		log.Printf("Error checking membership of user %d in group %d: %v", userID, groupID, err)
+		return false, fmt.Errorf("failed to check group membership: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ListMembers retrieves the members of a group
+func (r *GroupRepository) ListMembers(groupID int) ([]*model.GroupMember, error) {
+	query := `
+		SELECT gm.group_id, gm.user_id, u.username, gm.role, gm.joined_at
+		FROM group_members gm
+		JOIN users u ON u.id = gm.user_id
+		WHERE gm.group_id = $1 AND u.deleted_at IS NULL
+		ORDER BY gm.joined_at
+	`
+
+	rows, err := r.db.Query(query, groupID)
+	if err != nil {
+		log.Printf("Error listing members of group %d: %v", groupID, err)
+		return nil, fmt.Errorf("failed to list group members: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var members []*model.GroupMember
+	for rows.Next() {
+		member := &model.GroupMember{}
+		if err := rows.Scan(&member.GroupID, &member.UserID, &member.Username, &member.Role, &member.JoinedAt); err != nil {
+			log.Printf("Error scanning group member row: %v", err)
+			return nil, fmt.Errorf("failed to scan group member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over group member rows: %v", err)
+		return nil, fmt.Errorf("error iterating over group members: %w", err)
+	}
+
+	return members, nil
+}
+
+// ShareWishlist shares a wishlist with every member of a group
+func (r *GroupRepository) ShareWishlist(groupID, wishlistID, sharedBy int) error {
+	query := `
+		INSERT INTO group_wishlists (group_id, wishlist_id, shared_by, shared_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (group_id, wishlist_id) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(query, groupID, wishlistID, sharedBy, time.Now().UTC()); err != nil {
+		log.Printf("Error sharing wishlist %d to group %d: %v", wishlistID, groupID, err)
+		return fmt.Errorf("failed to share wishlist: %w", err)
+	}
+
+	return nil
+}
+
+// UnshareWishlist stops sharing a wishlist with a group
+func (r *GroupRepository) UnshareWishlist(groupID, wishlistID int) error {
+	if _, err := r.db.Exec(`DELETE FROM group_wishlists WHERE group_id = $1 AND wishlist_id = $2`, groupID, wishlistID); err != nil {
+		log.Printf("Error unsharing wishlist %d from group %d: %v", wishlistID, groupID, err)
+		return fmt.Errorf("failed to unshare wishlist: %w", err)
+	}
+
+	return nil
+}
+
+// IsWishlistShared reports whether a wishlist is shared to a group
+func (r *GroupRepository) IsWishlistShared(groupID, wishlistID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM group_wishlists WHERE group_id = $1 AND wishlist_id = $2)`,
+		groupID, wishlistID,
+	).Scan(&exists)
+	if err != nil {
+		log.Printf("Error checking share of wishlist %d in group %d: %v", wishlistID, groupID, err)
+		return false, fmt.Errorf("failed to check wishlist share: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ListWishlists retrieves the combined view of wishlists shared to a group
+func (r *GroupRepository) ListWishlists(groupID int) ([]*model.GroupWishlist, error) {
+	query := `
+		SELECT w.id, w.public_id, w.user_id, w.title, w.visibility, w.created_at, w.updated_at, w.language, w.translations,
+			w.event_type, w.event_date, w.archived_at, u.username, gw.shared_at
+		FROM group_wishlists gw
+		JOIN wishlists w ON w.id = gw.wishlist_id
+		JOIN users u ON u.id = w.user_id
+		WHERE gw.group_id = $1 AND u.deleted_at IS NULL
+		ORDER BY u.username, w.title
+	`
+
+	rows, err := r.db.Query(query, groupID)
+	if err != nil {
+		log.Printf("Error listing wishlists of group %d: %v", groupID, err)
+		return nil, fmt.Errorf("failed to list group wishlists: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var shared []*model.GroupWishlist
+	for rows.Next() {
+		entry := &model.GroupWishlist{Wishlist: &model.Wishlist{}}
+		err := rows.Scan(
+			&entry.Wishlist.ID,
+			&entry.Wishlist.PublicID,
+			&entry.Wishlist.UserID,
+			&entry.Wishlist.Title,
+			&entry.Wishlist.Visibility,
+			&entry.Wishlist.CreatedAt,
+			&entry.Wishlist.UpdatedAt,
+			&entry.Wishlist.Language,
+			&entry.Wishlist.Translations,
+			&entry.Wishlist.EventType,
+			&entry.Wishlist.EventDate,
+			&entry.Wishlist.ArchivedAt,
+			&entry.OwnerUsername,
+			&entry.SharedAt,
+		)
+		if err != nil {
+			log.Printf("Error scanning group wishlist row: %v", err)
+			return nil, fmt.Errorf("failed to scan group wishlist: %w", err)
+		}
+		shared = append(shared, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over group wishlist rows: %v", err)
+		return nil, fmt.Errorf("error iterating over group wishlists: %w", err)
+	}
+
+	return shared, nil
+}
+
+// SharesWishlistWith reports whether the wishlist is shared to any group the user belongs to
+func (r *GroupRepository) SharesWishlistWith(wishlistID, userID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1
+			FROM group_wishlists gw
+			JOIN group_members gm ON gm.group_id = gw.group_id
+			WHERE gw.wishlist_id = $1 AND gm.user_id = $2
+		)
+	`, wishlistID, userID).Scan(&exists)
+	if err != nil {
+		log.Printf("Error checking whether wishlist %d is shared with user %d: %v", wishlistID, userID, err)
+		return false, fmt.Errorf("failed to check wishlist share: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// IdentityRepository implements the model.IdentityRepository interface
+type IdentityRepository struct {
+	db *DB
+}
+
+// NewIdentityRepository creates a new instance of IdentityRepository
+func NewIdentityRepository(db *DB) model.IdentityRepository {
+	return &IdentityRepository{
+		db: db,
+	}
+}
+
+// identityColumns lists the identity columns in the order scanIdentity reads them
+const identityColumns = `id, user_id, provider, subject, email, created_at`
+
+// scanIdentity reads an identity row selected with identityColumns
+func scanIdentity(scanner interface{ Scan(...interface{}) error }) (*model.Identity, error) {
+	identity := &model.Identity{}
+	err := scanner.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email, &identity.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// Create links a provider account to a user
+func (r *IdentityRepository) Create(identity *model.Identity) error {
+	query := `
+		INSERT INTO identities (user_id, provider, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowWrite(query, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.CreatedAt).Scan(&identity.ID)
+	if err != nil {
+		log.Printf("Error linking %s identity to user %d: %v", identity.Provider, identity.UserID, err)
+		return fmt.Errorf("failed to create identity: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves the identity of a provider account, or nil when no user is linked to it
+func (r *IdentityRepository) Get(provider, subject string) (*model.Identity, error) {
+	query := `SELECT ` + identityColumns + ` FROM identities WHERE provider = $1 AND subject = $2`
+
+	identity, err := scanIdentity(r.db.QueryRow(query, provider, subject))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error getting %s identity: %v", provider, err)
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+// ListByUser retrieves the provider accounts linked to a user
+func (r *IdentityRepository) ListByUser(userID int) ([]*model.Identity, error) {
+	query := `SELECT ` + identityColumns + ` FROM identities WHERE user_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		log.Printf("Error listing identities of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var identities []*model.Identity
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			log.Printf("Error scanning identity row: %v", err)
+			return nil, fmt.Errorf("failed to scan identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over identity rows: %v", err)
+		return nil, fmt.Errorf("error iterating over identities: %w", err)
+	}
+
+	return identities, nil
+}
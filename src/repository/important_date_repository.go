@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// ImportantDateRepository implements the model.ImportantDateRepository interface
+type ImportantDateRepository struct {
+	db *DB
+}
+
+// NewImportantDateRepository creates a new instance of ImportantDateRepository
+func NewImportantDateRepository(db *DB) model.ImportantDateRepository {
+	return &ImportantDateRepository{
+		db: db,
+	}
+}
+
+// Create stores a new important date
+func (r *ImportantDateRepository) Create(date *model.ImportantDate) error {
+	query := `
+		INSERT INTO important_dates (user_id, label, month, day, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	if err := r.db.QueryRowWrite(query, date.UserID, date.Label, date.Month, date.Day, date.CreatedAt).Scan(&date.ID); err != nil {
+		log.Printf("Error creating important date for user %d: %v", date.UserID, err)
+		return fmt.Errorf("failed to create important date: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser retrieves the important dates of a user in calendar order
+func (r *ImportantDateRepository) ListByUser(userID int) ([]*model.ImportantDate, error) {
+	query := `
+		SELECT id, user_id, label, month, day, created_at
+		FROM important_dates
+		WHERE user_id = $1
+		ORDER BY month, day, id
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		log.Printf("Error listing important dates for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to list important dates: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	dates := []*model.ImportantDate{}
+	for rows.Next() {
+		date := &model.ImportantDate{}
+		if err := rows.Scan(&date.ID, &date.UserID, &date.Label, &date.Month, &date.Day, &date.CreatedAt); err != nil {
+			log.Printf("Error scanning important date row: %v", err)
+			return nil, fmt.Errorf("failed to scan important date: %w", err)
+		}
+		dates = append(dates, date)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over important date rows: %v", err)
+		return nil, fmt.Errorf("error iterating over important dates: %w", err)
+	}
+
+	return dates, nil
+}
+
+// CountByUser returns the number of important dates of a user
+func (r *ImportantDateRepository) CountByUser(userID int) (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM important_dates WHERE user_id = $1`, userID).Scan(&count); err != nil {
+		log.Printf("Error counting important dates for user %d: %v", userID, err)
+		return 0, fmt.Errorf("failed to count important dates: %w", err)
+	}
+
+	return count, nil
+}
+
+// Delete removes an important date of the user, reporting whether it existed
+func (r *ImportantDateRepository) Delete(userID, id int) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM important_dates WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		log.Printf("Error deleting important date %d of user %d: %v", id, userID, err)
+		return false, fmt.Errorf("failed to delete important date: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for important date deletion: %v", err)
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// listSpec maps the sort fields and filters a list endpoint accepts onto SQL.
+// Filter conditions use "?" as the placeholder for the filter value.
+type listSpec struct {
+	sorts       map[string]string
+	defaultSort string
+	defaultDesc bool
+	tieBreaker  string
+	filters     map[string]string
+}
+
+// apply adds the filters, sort order and page of opts to a query
+func (s listSpec) apply(q *selectQuery, opts model.ListOptions) *selectQuery {
+	names := make([]string, 0, len(opts.Filters))
+	for name := range opts.Filters {
+		if _, ok := s.filters[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		q.Where(s.filters[name], opts.Filters[name])
+	}
+
+	column, ok := s.sorts[opts.Sort]
+	desc := s.defaultDesc
+	if !ok {
+		column = s.sorts[s.defaultSort]
+	}
+	switch opts.Order {
+	case model.SortAsc:
+		desc = false
+	case model.SortDesc:
+		desc = true
+	}
+
+	return q.OrderBy(column, desc).OrderBy(s.tieBreaker, desc).Page(opts.Limit, opts.Offset())
+}
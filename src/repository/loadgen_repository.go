@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// LoadGenRepository implements the model.LoadGenRepository interface
+type LoadGenRepository struct {
+	db *DB
+}
+
+// NewLoadGenRepository creates a new instance of LoadGenRepository
+func NewLoadGenRepository(db *DB) model.LoadGenRepository {
+	return &LoadGenRepository{
+		db: db,
+	}
+}
+
+// InsertUsers adds verified users in one statement and sets their IDs
+func (r *LoadGenRepository) InsertUsers(users []*model.User) error {
+	rows := make([][]interface{}, len(users))
+	for i, user := range users {
+		rows[i] = []interface{}{user.Username, user.Email, user.Gender, user.PasswordHash, user.CreatedAt, user.CreatedAt, user.CreatedAt}
+	}
+
+	return r.insert("users",
+		[]string{"username", "email", "gender", "password_hash", "email_verified_at", "created_at", "updated_at"},
+		rows, func(i int, id int) { users[i].ID = id })
+}
+
+// InsertWishlists adds wishlists in one statement and sets their IDs
+func (r *LoadGenRepository) InsertWishlists(wishlists []*model.Wishlist) error {
+	rows := make([][]interface{}, len(wishlists))
+	for i, wishlist := range wishlists {
+		rows[i] = []interface{}{wishlist.UserID, wishlist.Title, wishlist.Visibility, wishlist.CreatedAt, wishlist.UpdatedAt}
+	}
+
+	return r.insert("wishlists",
+		[]string{"user_id", "title", "visibility", "created_at", "updated_at"},
+		rows, func(i int, id int) { wishlists[i].ID = id })
+}
+
+// InsertWishItems adds wish items in one statement and sets their IDs
+func (r *LoadGenRepository) InsertWishItems(items []*model.WishItem) error {
+	rows := make([][]interface{}, len(items))
+	for i, item := range items {
+		rows[i] = []interface{}{
+			item.WishlistID, item.Title, item.Description, item.PriceMinor, item.Currency, item.Priority,
+			item.Quantity, item.Position, item.Category, item.CreatedAt, item.UpdatedAt, item.Amount().Major(),
+		}
+	}
+
+	return r.insert("wish_items",
+		[]string{"wishlist_id", "title", "description", "price_minor", "currency", "priority", "quantity", "position",
+			"category", "created_at", "updated_at", "price"},
+		rows, func(i int, id int) { items[i].ID = id })
+}
+
+// InsertFollows adds follower and followee pairs in one statement, skipping those that exist
+func (r *LoadGenRepository) InsertFollows(follows [][2]int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting follow batch: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back follow batch: %v", rollbackErr)
+		}
+	}()
+
+	query, args := batchInsert("follows", []string{"follower_id", "followee_id"}, func(add func(...interface{})) {
+		for _, follow := range follows {
+			add(follow[0], follow[1])
+		}
+	})
+	if _, err := tx.Exec(query+` ON CONFLICT DO NOTHING`, args...); err != nil {
+		log.Printf("Error inserting %d follows: %v", len(follows), err)
+		return fmt.Errorf("failed to insert follows: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing follow batch: %v", err)
+		return fmt.Errorf("failed to commit follows: %w", err)
+	}
+	return nil
+}
+
+// DeleteUsers deletes the users whose email is at emailDomain, with everything they own
+func (r *LoadGenRepository) DeleteUsers(emailDomain string) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting generated user deletion: %v", err)
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back generated user deletion: %v", rollbackErr)
+		}
+	}()
+
+	result, err := tx.Exec(`DELETE FROM users WHERE email LIKE '%@' || $1`, emailDomain)
+	if err != nil {
+		log.Printf("Error deleting users at %s: %v", emailDomain, err)
+		return 0, fmt.Errorf("failed to delete generated users: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for generated user deletion: %v", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing generated user deletion: %v", err)
+		return 0, fmt.Errorf("failed to commit deletion: %w", err)
+	}
+	return deleted, nil
+}
+
+// insert adds rows to table in one statement and passes the ID of each row, in order, to setID
+func (r *LoadGenRepository) insert(table string, columns []string, rows [][]interface{}, setID func(i, id int)) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting %s batch: %v", table, err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back %s batch: %v", table, rollbackErr)
+		}
+	}()
+
+	query, args := batchInsert(table, columns, func(add func(...interface{})) {
+		for _, row := range rows {
+			add(row...)
+		}
+	})
+
+	// Postgres inserts the rows of a single statement in turn and returns them in VALUES order
+	result, err := tx.Query(query+` RETURNING id`, args...)
+	if err != nil {
+		log.Printf("Error inserting %d rows into %s: %v", len(rows), table, err)
+		return fmt.Errorf("failed to insert into %s: %w", table, err)
+	}
+
+	i := 0
+	for result.Next() {
+		var id int
+		if err := result.Scan(&id); err != nil {
+			result.Close()
+			log.Printf("Error scanning %s ID: %v", table, err)
+			return fmt.Errorf("failed to scan %s ID: %w", table, err)
+		}
+		setID(i, id)
+		i++
+	}
+	if err := result.Err(); err != nil {
+		result.Close()
+		log.Printf("Error iterating over %s IDs: %v", table, err)
+		return fmt.Errorf("error iterating over %s IDs: %w", table, err)
+	}
+	if err := result.Close(); err != nil {
+		log.Printf("Error closing rows: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing %s batch: %v", table, err)
+		return fmt.Errorf("failed to commit %s batch: %w", table, err)
+	}
+	return nil
+}
+
+// batchInsert builds a multi-row INSERT into table; rows calls add once per row with its values
+// in column order. Postgres takes at most 65535 parameters per statement.
+func batchInsert(table string, columns []string, rows func(add func(...interface{}))) (string, []interface{}) {
+	var query strings.Builder
+	var args []interface{}
+	fmt.Fprintf(&query, "INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+
+	rows(func(values ...interface{}) {
+		if len(args) > 0 {
+			query.WriteString(", ")
+		}
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		fmt.Fprintf(&query, "(%s)", strings.Join(placeholders, ", "))
+	})
+
+	return query.String(), args
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// LoginAttemptRepository implements the model.LoginAttemptRepository interface
+type LoginAttemptRepository struct {
+	db *DB
+}
+
+// NewLoginAttemptRepository creates a new instance of LoginAttemptRepository
+func NewLoginAttemptRepository(db *DB) model.LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: db,
+	}
+}
+
+// Get retrieves the failed login state of an account; accounts without failures get an empty state
+func (r *LoginAttemptRepository) Get(userID int) (*model.LoginAttempt, error) {
+	attempt := &model.LoginAttempt{UserID: userID}
+	err := r.db.QueryRow(
+		`SELECT failures, last_failed_at, locked_until FROM login_attempts WHERE user_id = $1`,
+		userID,
+	).Scan(&attempt.Failures, &attempt.LastFailedAt, &attempt.LockedUntil)
+
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error getting login attempts of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+
+	return attempt, nil
+}
+
+// RecordFailure counts a failed login. Failures older than the window are forgotten;
+// reaching maxFailures locks the account for lockDuration and starts the count over.
+func (r *LoginAttemptRepository) RecordFailure(userID, maxFailures int, window, lockDuration time.Duration) (*model.LoginAttempt, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting login failure tracking: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back login failure tracking: %v", rollbackErr)
+		}
+	}()
+
+	now := time.Now().UTC()
+	attempt := &model.LoginAttempt{UserID: userID}
+	err = tx.QueryRow(`
+		INSERT INTO login_attempts (user_id, failures, last_failed_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failed_at < $3 THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING failures, last_failed_at, locked_until
+	`, userID, now, now.Add(-window)).Scan(&attempt.Failures, &attempt.LastFailedAt, &attempt.LockedUntil)
+	if err != nil {
+		log.Printf("Error recording login failure of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	if maxFailures > 0 && attempt.Failures >= maxFailures {
+		lockedUntil := now.Add(lockDuration)
+		_, err = tx.Exec(
+			`UPDATE login_attempts SET failures = 0, locked_until = $2 WHERE user_id = $1`,
+			userID, lockedUntil,
+		)
+		if err != nil {
+			log.Printf("Error locking account of user %d: %v", userID, err)
+			return nil, fmt.Errorf("failed to lock account: %w", err)
+		}
+		attempt.Failures = 0
+		attempt.LockedUntil = &lockedUntil
+		log.Printf("Account of user %d locked until %s after repeated failed logins", userID, lockedUntil.Format(time.RFC3339))
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing login failure tracking: %v", err)
+		return nil, fmt.Errorf("failed to commit login failure: %w", err)
+	}
+
+	return attempt, nil
+}
+
+// Reset clears the failed login state of an account after a successful login
+func (r *LoginAttemptRepository) Reset(userID int) error {
+	if _, err := r.db.Exec(`DELETE FROM login_attempts WHERE user_id = $1`, userID); err != nil {
+		log.Printf("Error resetting login attempts of user %d: %v", userID, err)
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+
+	return nil
+}
+
+// ListLocked retrieves the accounts locked at the given time, the latest lock expiry first
+func (r *LoginAttemptRepository) ListLocked(now time.Time) ([]*model.LoginAttempt, error) {
+	query := `
+		SELECT a.user_id, u.username, a.failures, a.last_failed_at, a.locked_until
+		FROM login_attempts a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.locked_until > $1
+		ORDER BY a.locked_until DESC
+	`
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		log.Printf("Error listing locked accounts: %v", err)
+		return nil, fmt.Errorf("failed to list locked accounts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	attempts := []*model.LoginAttempt{}
+	for rows.Next() {
+		attempt := &model.LoginAttempt{}
+		if err := rows.Scan(&attempt.UserID, &attempt.Username, &attempt.Failures, &attempt.LastFailedAt, &attempt.LockedUntil); err != nil {
+			log.Printf("Error scanning locked account row: %v", err)
+			return nil, fmt.Errorf("failed to scan locked account: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over locked account rows: %v", err)
+		return nil, fmt.Errorf("error iterating over locked accounts: %w", err)
+	}
+
+	return attempts, nil
+}
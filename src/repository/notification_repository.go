@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/lib/pq"
+)
+
+// NotificationRepository implements the model.NotificationRepository interface
+type NotificationRepository struct {
+	db *DB
+}
+
+// NewNotificationRepository creates a new instance of NotificationRepository
+func NewNotificationRepository(db *DB) model.NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+// notificationColumns lists the columns selected for a notification, with the public ID of its wishlist
+const notificationColumns = `id, user_id, type, actor_id, wishlist_id, message, read_at, created_at,
+	COALESCE((SELECT w.public_id::text FROM wishlists w WHERE w.id = notifications.wishlist_id), '')`
+
+// notificationListSpec describes the sorting and filtering accepted when listing notifications
+var notificationListSpec = listSpec{
+	sorts: map[string]string{
+		"created_at": "created_at",
+	},
+	defaultSort: "created_at",
+	defaultDesc: true,
+	tieBreaker:  "id",
+	filters: map[string]string{
+		"type":   "type = ?",
+		"status": "CASE WHEN read_at IS NULL THEN 'unread' ELSE 'read' END = ?",
+	},
+}
+
+// scanNotification scans a notification row into a model
+func scanNotification(scanner interface{ Scan(...interface{}) error }) (*model.Notification, error) {
+	notification := &model.Notification{}
+	err := scanner.Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.Type,
+		&notification.ActorID,
+		&notification.WishlistID,
+		&notification.Message,
+		&notification.ReadAt,
+		&notification.CreatedAt,
+		&notification.WishlistPublicID,
+	)
+	return notification, err
+}
+
+// Create adds a notification to a user's feed
+func (r *NotificationRepository) Create(notification *model.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, type, actor_id, wishlist_id, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowWrite(
+		query,
+		notification.UserID,
+		notification.Type,
+		notification.ActorID,
+		notification.WishlistID,
+		notification.Message,
+		notification.CreatedAt,
+	).Scan(&notification.ID)
+	if err != nil {
+		log.Printf("Error creating %s notification for user %d: %v", notification.Type, notification.UserID, err)
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser retrieves a page of a user's notifications, newest first by default
+func (r *NotificationRepository) ListByUser(userID int, opts model.ListOptions) ([]*model.Notification, *model.PageInfo, error) {
+	q := selectFrom(`notifications`, notificationColumns).Where(`user_id = ?`, userID)
+	notificationListSpec.apply(q, opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting notifications for user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing notifications for user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var notifications []*model.Notification
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			log.Printf("Error scanning notification row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over notification rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over notifications: %w", err)
+	}
+
+	return notifications, model.NewPageInfo(opts, total, len(notifications)), nil
+}
+
+// CountUnread returns the number of notifications the user has not read yet
+func (r *NotificationRepository) CountUnread(userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		log.Printf("Error counting unread notifications for user %d: %v", userID, err)
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkRead marks the given notifications of a user as read, returning how many changed
+func (r *NotificationRepository) MarkRead(userID int, ids []int) (int64, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = $3
+		WHERE user_id = $1 AND id = ANY($2) AND read_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, userID, pq.Array(ids), time.Now().UTC())
+	if err != nil {
+		log.Printf("Error marking notifications of user %d as read: %v", userID, err)
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// MarkAllRead marks every notification of a user as read, returning how many changed
+func (r *NotificationRepository) MarkAllRead(userID int) (int64, error) {
+	result, err := r.db.Exec(
+		`UPDATE notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL`,
+		userID, time.Now().UTC(),
+	)
+	if err != nil {
+		log.Printf("Error marking all notifications of user %d as read: %v", userID, err)
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// Clear deletes every notification of a user, returning how many were removed
+func (r *NotificationRepository) Clear(userID int) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM notifications WHERE user_id = $1`, userID)
+	if err != nil {
+		log.Printf("Error clearing notifications of user %d: %v", userID, err)
+		return 0, fmt.Errorf("failed to clear notifications: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// PriceHistoryRepository implements the model.PriceHistoryRepository interface
+type PriceHistoryRepository struct {
+	db *DB
+}
+
+// NewPriceHistoryRepository creates a new instance of PriceHistoryRepository
+func NewPriceHistoryRepository(db *DB) model.PriceHistoryRepository {
+	return &PriceHistoryRepository{
+		db: db,
+	}
+}
+
+// ListDue retrieves up to limit items with a URL whose price was never read or last read before
+// checkedBefore, the longest unchecked first. Items of deleted users are not tracked.
+func (r *PriceHistoryRepository) ListDue(checkedBefore time.Time, limit int) ([]*model.WishItem, error) {
+	query := `
+		SELECT ` + wishItemColumns + `
+		FROM wish_items
+		WHERE url <> '' AND (price_checked_at IS NULL OR price_checked_at < $1)
+			AND wishlist_id IN (
+				SELECT w.id FROM wishlists w JOIN users u ON u.id = w.user_id WHERE u.deleted_at IS NULL
+			)
+		ORDER BY price_checked_at NULLS FIRST, id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, checkedBefore, limit)
+	if err != nil {
+		log.Printf("Error listing wish items due for a price check: %v", err)
+		return nil, fmt.Errorf("failed to list wish items due for a price check: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var items []*model.WishItem
+	for rows.Next() {
+		item, err := scanWishItem(rows)
+		if err != nil {
+			log.Printf("Error scanning wish item row: %v", err)
+			return nil, fmt.Errorf("failed to scan wish item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over wish item rows: %v", err)
+		return nil, fmt.Errorf("error iterating over wish items: %w", err)
+	}
+
+	return items, nil
+}
+
+// Record adds a price to the history of an item and marks the item as checked. The item takes
+// the new price when it is in the item's currency; a price in another currency is only kept
+// in the history. The decimal price columns are written too, like WishItemRepository.Create.
+func (r *PriceHistoryRepository) Record(point *model.PricePoint) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting price recording: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back price recording: %v", rollbackErr)
+		}
+	}()
+
+	err = tx.QueryRow(`
+		INSERT INTO price_history (item_id, price_minor, currency, checked_at, price)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, point.ItemID, point.PriceMinor, point.Currency, point.CheckedAt, point.Amount().Major()).Scan(&point.ID)
+	if err != nil {
+		log.Printf("Error recording price of wish item %d: %v", point.ItemID, err)
+		return fmt.Errorf("failed to record price: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE wish_items
+		SET price_checked_at = $2,
+			updated_at = CASE WHEN currency = $3 AND price_minor <> $4 THEN $2 ELSE updated_at END,
+			price_minor = CASE WHEN currency = $3 THEN $4 ELSE price_minor END,
+			price = CASE WHEN currency = $3 THEN $5 ELSE price END
+		WHERE id = $1
+	`, point.ItemID, point.CheckedAt, point.Currency, point.PriceMinor, point.Amount().Major())
+	if err != nil {
+		log.Printf("Error updating price of wish item %d: %v", point.ItemID, err)
+		return fmt.Errorf("failed to update wish item price: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing price of wish item %d: %v", point.ItemID, err)
+		return fmt.Errorf("failed to commit price: %w", err)
+	}
+	return nil
+}
+
+// MarkChecked records that the price of an item was looked for without being found, so the
+// item waits for its next turn like the others
+func (r *PriceHistoryRepository) MarkChecked(itemID int, checkedAt time.Time) error {
+	_, err := r.db.Exec(`UPDATE wish_items SET price_checked_at = $2 WHERE id = $1`, itemID, checkedAt)
+	if err != nil {
+		log.Printf("Error marking price of wish item %d as checked: %v", itemID, err)
+		return fmt.Errorf("failed to mark price as checked: %w", err)
+	}
+	return nil
+}
+
+// ListByItem retrieves the most recent prices of an item, up to limit, oldest first
+func (r *PriceHistoryRepository) ListByItem(itemID int, limit int) ([]*model.PricePoint, error) {
+	query := `
+		SELECT id, item_id, price_minor, currency, checked_at FROM (
+			SELECT id, item_id, price_minor, currency, checked_at
+			FROM price_history
+			WHERE item_id = $1
+			ORDER BY checked_at DESC, id DESC
+			LIMIT $2
+		) recent
+		ORDER BY checked_at, id
+	`
+
+	rows, err := r.db.Query(query, itemID, limit)
+	if err != nil {
+		log.Printf("Error listing price history of wish item %d: %v", itemID, err)
+		return nil, fmt.Errorf("failed to list price history: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	points := []*model.PricePoint{}
+	for rows.Next() {
+		point := &model.PricePoint{}
+		if err := rows.Scan(&point.ID, &point.ItemID, &point.PriceMinor, &point.Currency, &point.CheckedAt); err != nil {
+			log.Printf("Error scanning price history row: %v", err)
+			return nil, fmt.Errorf("failed to scan price history: %w", err)
+		}
+		points = append(points, point)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over price history rows: %v", err)
+		return nil, fmt.Errorf("error iterating over price history: %w", err)
+	}
+
+	return points, nil
+}
+
+// Prune deletes the prices read before the given time
+func (r *PriceHistoryRepository) Prune(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM price_history WHERE checked_at < $1`, before)
+	if err != nil {
+		log.Printf("Error pruning price history: %v", err)
+		return 0, fmt.Errorf("failed to prune price history: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for price history pruning: %v", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// selectQuery builds a SELECT statement from parts. Conditions are written with "?" placeholders,
+// which are numbered in order when the statement is built, so values always travel as query
+// arguments and never end up in the SQL text. Conditions must not contain a literal "?".
+type selectQuery struct {
+	columns    string
+	from       string
+	conditions []string
+	args       []interface{}
+	orderBy    []string
+	limit      int
+	offset     int
+}
+
+// selectFrom starts a query selecting columns from a table or join expression
+func selectFrom(from, columns string) *selectQuery {
+	return &selectQuery{from: from, columns: columns}
+}
+
+// Where adds a condition, combined with the others using AND, and the values of its placeholders
+func (q *selectQuery) Where(condition string, args ...interface{}) *selectQuery {
+	if count := strings.Count(condition, "?"); count != len(args) {
+		panic(fmt.Sprintf("query condition %q has %d placeholders but %d arguments", condition, count, len(args)))
+	}
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+	return q
+}
+
+// OrderBy adds a sort column; columns must come from a fixed set, never from user input
+func (q *selectQuery) OrderBy(column string, desc bool) *selectQuery {
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	q.orderBy = append(q.orderBy, column+" "+direction)
+	return q
+}
+
+// Page limits the result to limit rows starting at offset; zero values leave the result unbounded
+func (q *selectQuery) Page(limit, offset int) *selectQuery {
+	q.limit = limit
+	q.offset = offset
+	return q
+}
+
+// Build returns the SELECT statement and its arguments
+func (q *selectQuery) Build() (string, []interface{}) {
+	var sql strings.Builder
+	sql.WriteString("SELECT " + q.columns + " FROM " + q.from)
+	sql.WriteString(q.whereClause())
+
+	if len(q.orderBy) > 0 {
+		sql.WriteString(" ORDER BY " + strings.Join(q.orderBy, ", "))
+	}
+	if q.limit > 0 {
+		fmt.Fprintf(&sql, " LIMIT %d", q.limit)
+	}
+	if q.offset > 0 {
+		fmt.Fprintf(&sql, " OFFSET %d", q.offset)
+	}
+
+	return sql.String(), q.args
+}
+
+// BuildCount returns a statement counting every row matching the conditions, ignoring order and paging
+func (q *selectQuery) BuildCount() (string, []interface{}) {
+	return "SELECT COUNT(*) FROM " + q.from + q.whereClause(), q.args
+}
+
+// whereClause joins the conditions and numbers their placeholders
+func (q *selectQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+
+	clause := strings.Join(q.conditions, " AND ")
+	var numbered strings.Builder
+	n := 0
+	for _, r := range clause {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&numbered, "$%d", n)
+			continue
+		}
+		numbered.WriteRune(r)
+	}
+	return " WHERE " + numbered.String()
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/lib/pq"
+)
+
+// ReactionRepository implements the model.ReactionRepository interface
+type ReactionRepository struct {
+	db *DB
+}
+
+// NewReactionRepository creates a new instance of ReactionRepository
+func NewReactionRepository(db *DB) model.ReactionRepository {
+	return &ReactionRepository{
+		db: db,
+	}
+}
+
+// Add records a reaction; reacting twice with the same kind is a no-op
+func (r *ReactionRepository) Add(reaction *model.Reaction) error {
+	query := `
+		INSERT INTO reactions (user_id, target_type, target_id, kind, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, target_type, target_id, kind) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query, reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Kind, reaction.CreatedAt)
+	if err != nil {
+		log.Printf("Error adding %s reaction of user %d to %s %d: %v", reaction.Kind, reaction.UserID, reaction.TargetType, reaction.TargetID, err)
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+
+	return nil
+}
+
+// Remove deletes a user's reaction of a kind; removing a reaction that does not exist is a no-op
+func (r *ReactionRepository) Remove(userID int, targetType model.ReactionTarget, targetID int, kind model.ReactionKind) error {
+	query := `DELETE FROM reactions WHERE user_id = $1 AND target_type = $2 AND target_id = $3 AND kind = $4`
+
+	if _, err := r.db.Exec(query, userID, targetType, targetID, kind); err != nil {
+		log.Printf("Error removing %s reaction of user %d from %s %d: %v", kind, userID, targetType, targetID, err)
+		return fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	return nil
+}
+
+// Counts returns the number of reactions of each kind on the given targets.
+// Targets without reactions are left out of the result.
+func (r *ReactionRepository) Counts(targetType model.ReactionTarget, targetIDs []int) (model.ReactionCounts, error) {
+	counts := make(model.ReactionCounts)
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `
+		SELECT target_id, kind, COUNT(*)
+		FROM reactions
+		WHERE target_type = $1 AND target_id = ANY($2)
+		GROUP BY target_id, kind
+	`
+
+	rows, err := r.db.Query(query, targetType, pq.Array(targetIDs))
+	if err != nil {
+		log.Printf("Error counting %s reactions: %v", targetType, err)
+		return nil, fmt.Errorf("failed to count reactions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		var targetID, count int
+		var kind model.ReactionKind
+		if err := rows.Scan(&targetID, &kind, &count); err != nil {
+			log.Printf("Error scanning reaction count row: %v", err)
+			return nil, fmt.Errorf("failed to scan reaction count: %w", err)
+		}
+		if counts[targetID] == nil {
+			counts[targetID] = make(map[model.ReactionKind]int)
+		}
+		counts[targetID][kind] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over reaction count rows: %v", err)
+		return nil, fmt.Errorf("error iterating over reaction counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// DeleteForWishlist removes the reactions on a wishlist and on its items.
+// Call it before deleting the wishlist, while its items still exist.
+func (r *ReactionRepository) DeleteForWishlist(wishlistID int) error {
+	query := `
+		DELETE FROM reactions
+		WHERE (target_type = 'wishlist' AND target_id = $1)
+			OR (target_type = 'item' AND target_id IN (SELECT id FROM wish_items WHERE wishlist_id = $1))
+	`
+
+	if _, err := r.db.Exec(query, wishlistID); err != nil {
+		log.Printf("Error deleting reactions of wishlist %d: %v", wishlistID, err)
+		return fmt.Errorf("failed to delete reactions: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteForItem removes the reactions on a wish item
+func (r *ReactionRepository) DeleteForItem(itemID int) error {
+	if _, err := r.db.Exec(`DELETE FROM reactions WHERE target_type = 'item' AND target_id = $1`, itemID); err != nil {
+		log.Printf("Error deleting reactions of wish item %d: %v", itemID, err)
+		return fmt.Errorf("failed to delete reactions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// RefreshTokenRepository implements the model.RefreshTokenRepository interface
+type RefreshTokenRepository struct {
+	db *DB
+}
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository
+func NewRefreshTokenRepository(db *DB) model.RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db: db,
+	}
+}
+
+// Create stores a new refresh token
+func (r *RefreshTokenRepository) Create(token *model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, token_hash, expires_at, created_at, session_started_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowWrite(query, token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt, token.SessionStartedAt).Scan(&token.ID)
+	if err != nil {
+		log.Printf("Error creating refresh token: %v", err)
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// GetByHash retrieves a refresh token by its hash
+func (r *RefreshTokenRepository) GetByHash(tokenHash string) (*model.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, revoked_at, created_at, COALESCE(session_started_at, created_at)
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	token := &model.RefreshToken{}
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+		&token.SessionStartedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("refresh token not found")
+		}
+		log.Printf("Error getting refresh token: %v", err)
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Rotate revokes the old refresh token and stores its replacement in one transaction.
+// It returns model.ErrRefreshTokenAlreadyUsed if the old token was revoked concurrently.
+func (r *RefreshTokenRepository) Rotate(oldTokenID int, newToken *model.RefreshToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting refresh token rotation: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back refresh token rotation: %v", rollbackErr)
+		}
+	}()
+
+	result, err := tx.Exec(
+		`UPDATE refresh_tokens SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`,
+		oldTokenID, time.Now().UTC(),
+	)
+	if err != nil {
+		log.Printf("Error revoking refresh token %d: %v", oldTokenID, err)
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.ErrRefreshTokenAlreadyUsed
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO refresh_tokens (user_id, token_hash, expires_at, created_at, session_started_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		newToken.UserID, newToken.TokenHash, newToken.ExpiresAt, newToken.CreatedAt, newToken.SessionStartedAt,
+	).Scan(&newToken.ID)
+	if err != nil {
+		log.Printf("Error storing rotated refresh token: %v", err)
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing refresh token rotation: %v", err)
+		return fmt.Errorf("failed to commit rotation: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke revokes a single refresh token
+func (r *RefreshTokenRepository) Revoke(id int) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.db.Exec(query, id, time.Now().UTC()); err != nil {
+		log.Printf("Error revoking refresh token %d: %v", id, err)
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every active refresh token of a user
+func (r *RefreshTokenRepository) RevokeAllForUser(userID int) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`
+
+	result, err := r.db.Exec(query, userID, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error revoking refresh tokens for user ID %d: %v", userID, err)
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err == nil {
+		log.Printf("Revoked %d refresh tokens for user ID %d", rowsAffected, userID)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+func TestRefreshTokenGetByHash(t *testing.T) {
+	issued := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	revoked := issued.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		revokedAt   driver.Value
+		wantRevoked bool
+	}{
+		{"active token", nil, false},
+		{"replayed token that was rotated away", revoked, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newTestDB(t, 0, fakeStep{row: []driver.Value{
+				int64(1), int64(5), "hash", issued.Add(24 * time.Hour), tt.revokedAt, issued, issued,
+			}})
+
+			token, err := NewRefreshTokenRepository(db).GetByHash("hash")
+			if err != nil {
+				t.Fatalf("GetByHash() error = %v", err)
+			}
+			if token.IsRevoked() != tt.wantRevoked {
+				t.Errorf("IsRevoked() = %t, want %t", token.IsRevoked(), tt.wantRevoked)
+			}
+			if token.UserID != 5 {
+				t.Errorf("UserID = %d, want 5", token.UserID)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenRotate(t *testing.T) {
+	const (
+		revoke = `UPDATE refresh_tokens SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+		insert = `INSERT INTO refresh_tokens (user_id, token_hash, expires_at, created_at, session_started_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	)
+
+	tests := []struct {
+		name       string
+		steps      []fakeStep
+		wantErr    bool
+		wantReused bool
+		wantID     int
+		wantRan    []string
+	}{
+		{
+			name:    "an active token is revoked and replaced",
+			steps:   []fakeStep{{rowsAffected: 1}, {row: []driver.Value{int64(42)}}},
+			wantID:  42,
+			wantRan: []string{"BEGIN", revoke, insert, "COMMIT"},
+		},
+		{
+			name:       "a token already revoked by a concurrent refresh is reported as reused",
+			steps:      []fakeStep{{rowsAffected: 0}},
+			wantErr:    true,
+			wantReused: true,
+			wantRan:    []string{"BEGIN", revoke, "ROLLBACK"},
+		},
+		{
+			name:    "a failed revocation stores no replacement",
+			steps:   []fakeStep{{err: errors.New("boom")}},
+			wantErr: true,
+			wantRan: []string{"BEGIN", revoke, "ROLLBACK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, script := newTestDB(t, 0, tt.steps...)
+
+			replacement := &model.RefreshToken{UserID: 5, TokenHash: "new", ExpiresAt: time.Now().Add(time.Hour)}
+			replacement.BeforeCreate()
+			err := NewRefreshTokenRepository(db).Rotate(1, replacement)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Rotate() error = %v, want error %t", err, tt.wantErr)
+			}
+			if reused := errors.Is(err, model.ErrRefreshTokenAlreadyUsed); reused != tt.wantReused {
+				t.Errorf("Rotate() reported reuse = %t, want %t", reused, tt.wantReused)
+			}
+			if replacement.ID != tt.wantID {
+				t.Errorf("replacement ID = %d, want %d", replacement.ID, tt.wantID)
+			}
+			if !reflect.DeepEqual(script.ran, tt.wantRan) {
+				t.Errorf("statements = %q, want %q", script.ran, tt.wantRan)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// ReminderRepository implements the model.ReminderRepository interface
+type ReminderRepository struct {
+	db *DB
+}
+
+// NewReminderRepository creates a new instance of ReminderRepository
+func NewReminderRepository(db *DB) model.ReminderRepository {
+	return &ReminderRepository{
+		db: db,
+	}
+}
+
+// ListOccasions retrieves the birthdays and important dates of users who are not deleted falling
+// on the day. Outside leap years, February 29 is celebrated on February 28.
+func (r *ReminderRepository) ListOccasions(on time.Time) ([]*model.Occasion, error) {
+	query := `
+		SELECT u.id, u.username, 'birthday', 0, ''
+		FROM users u
+		WHERE u.deleted_at IS NULL AND u.birthday IS NOT NULL
+			AND EXTRACT(MONTH FROM u.birthday) = $1
+			AND (EXTRACT(DAY FROM u.birthday) = $2 OR ($3 AND EXTRACT(DAY FROM u.birthday) = 29))
+		UNION ALL
+		SELECT u.id, u.username, 'important_date', d.id, d.label
+		FROM important_dates d JOIN users u ON u.id = d.user_id
+		WHERE u.deleted_at IS NULL AND d.month = $1 AND (d.day = $2 OR ($3 AND d.day = 29))
+		ORDER BY 1, 4
+	`
+
+	month, day := int(on.Month()), on.Day()
+	leapDay := month == 2 && day == 28 && time.Date(on.Year(), time.February, 29, 0, 0, 0, 0, time.UTC).Month() != time.February
+
+	rows, err := r.db.Query(query, month, day, leapDay)
+	if err != nil {
+		log.Printf("Error listing occasions on %s: %v", on.Format(model.BirthdayLayout), err)
+		return nil, fmt.Errorf("failed to list occasions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var occasions []*model.Occasion
+	for rows.Next() {
+		occasion := &model.Occasion{On: on}
+		if err := rows.Scan(&occasion.UserID, &occasion.Username, &occasion.Kind, &occasion.DateID, &occasion.Label); err != nil {
+			log.Printf("Error scanning occasion row: %v", err)
+			return nil, fmt.Errorf("failed to scan occasion: %w", err)
+		}
+		occasions = append(occasions, occasion)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over occasion rows: %v", err)
+		return nil, fmt.Errorf("error iterating over occasions: %w", err)
+	}
+
+	return occasions, nil
+}
+
+// ListRecipients retrieves the friends of a user, the mutual followers who are not deleted, each
+// with the most recently updated wishlist of the user they may view
+func (r *ReminderRepository) ListRecipients(userID int) ([]*model.ReminderRecipient, error) {
+	query := `
+		SELECT f1.follower_id, (
+			SELECT w.id FROM wishlists w
+			WHERE w.user_id = $1 AND ` + visibleWishlist("f1.follower_id") + `
+			ORDER BY w.updated_at DESC, w.id DESC
+			LIMIT 1
+		)
+		FROM follows f1
+		JOIN follows f2 ON f2.follower_id = f1.followee_id AND f2.followee_id = f1.follower_id
+		JOIN users u ON u.id = f1.follower_id
+		WHERE f1.followee_id = $1 AND u.deleted_at IS NULL
+		ORDER BY f1.follower_id
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		log.Printf("Error listing reminder recipients for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to list reminder recipients: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var recipients []*model.ReminderRecipient
+	for rows.Next() {
+		recipient := &model.ReminderRecipient{}
+		if err := rows.Scan(&recipient.UserID, &recipient.WishlistID); err != nil {
+			log.Printf("Error scanning reminder recipient row: %v", err)
+			return nil, fmt.Errorf("failed to scan reminder recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over reminder recipient rows: %v", err)
+		return nil, fmt.Errorf("error iterating over reminder recipients: %w", err)
+	}
+
+	return recipients, nil
+}
+
+// Claim records that the reminders of an occasion are being sent, reporting false when they
+// already were, so that each occasion is announced once however many instances run the job
+func (r *ReminderRepository) Claim(occasion *model.Occasion) (bool, error) {
+	query := `
+		INSERT INTO date_reminders (user_id, occasion, occurs_on, sent_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, occasion, occurs_on) DO NOTHING
+	`
+
+	result, err := r.db.Exec(query, occasion.UserID, occasion.Key(), occasion.On, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error claiming reminders of %s for user %d: %v", occasion.Key(), occasion.UserID, err)
+		return false, fmt.Errorf("failed to claim reminders: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for reminder claim: %v", err)
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Prune deletes the records of occasions that took place before the given day
+func (r *ReminderRepository) Prune(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM date_reminders WHERE occurs_on < $1`, before)
+	if err != nil {
+		log.Printf("Error pruning date reminders: %v", err)
+		return 0, fmt.Errorf("failed to prune date reminders: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for date reminder pruning: %v", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
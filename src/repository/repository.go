@@ -1,26 +1,117 @@
 package repository
 
-import (
-	"database/sql"
-
-	"github.com/alex-1900/wishlist/src/model"
-)
+import "github.com/alex-1900/wishlist/src/model"
 
 // RepositoryManager manages all repository instances
 type RepositoryManager struct {
-	UserRepo model.UserRepository
+	UserRepo     model.UserRepository
+	WishlistRepo model.WishlistRepository
+	WishItemRepo model.WishItemRepository
+
+	RefreshTokenRepo model.RefreshTokenRepository
+	RevokedTokenRepo model.RevokedTokenRepository
+
+	VerificationCodeRepo model.VerificationCodeRepository
+
+	GroupRepo        model.GroupRepository
+	GroupNoteRepo    model.GroupNoteRepository
+	GroupPollRepo    model.GroupPollRepository
+	FollowRepo       model.FollowRepository
+	ReservationRepo  model.ReservationRepository
+	LoginAttemptRepo model.LoginAttemptRepository
+	NotificationRepo model.NotificationRepository
+	ReactionRepo     model.ReactionRepository
+	BlockRepo        model.BlockRepository
+	SearchRepo       model.SearchRepository
+	SiteSettingsRepo model.SiteSettingsRepository
+	IdentityRepo     model.IdentityRepository
+	SubscriptionRepo model.SubscriptionRepository
+	APIKeyRepo       model.APIKeyRepository
+	SitemapRepo      model.SitemapRepository
+
+	ImportantDateRepo  model.ImportantDateRepository
+	ReminderRepo       model.ReminderRepository
+	PriceHistoryRepo   model.PriceHistoryRepository
+	ContributionRepo   model.ContributionRepository
+	WishlistMemberRepo model.WishlistMemberRepository
+	DemoRepo           model.DemoRepository
+	GiftRepo           model.GiftRepository
+	LoadGenRepo        model.LoadGenRepository
+	WishlistImportRepo model.WishlistImportRepository
 }
 
 // NewRepositoryManager creates a new repository manager with all repositories
-func NewRepositoryManager(db *sql.DB) *RepositoryManager {
+func NewRepositoryManager(db *DB) *RepositoryManager {
 	return &RepositoryManager{
-		UserRepo: NewUserRepository(db),
+		UserRepo:     NewUserRepository(db),
+		WishlistRepo: NewWishlistRepository(db),
+		WishItemRepo: NewWishItemRepository(db),
+
+		RefreshTokenRepo: NewRefreshTokenRepository(db),
+		RevokedTokenRepo: NewRevokedTokenRepository(db),
+
+		VerificationCodeRepo: NewVerificationCodeRepository(db),
+
+		GroupRepo:        NewGroupRepository(db),
+		GroupNoteRepo:    NewGroupNoteRepository(db),
+		GroupPollRepo:    NewGroupPollRepository(db),
+		FollowRepo:       NewFollowRepository(db),
+		ReservationRepo:  NewReservationRepository(db),
+		LoginAttemptRepo: NewLoginAttemptRepository(db),
+		NotificationRepo: NewNotificationRepository(db),
+		ReactionRepo:     NewReactionRepository(db),
+		BlockRepo:        NewBlockRepository(db),
+		SearchRepo:       NewSearchRepository(db),
+		SiteSettingsRepo: NewSiteSettingsRepository(db),
+		IdentityRepo:     NewIdentityRepository(db),
+		SubscriptionRepo: NewSubscriptionRepository(db),
+		APIKeyRepo:       NewAPIKeyRepository(db),
+		SitemapRepo:      NewSitemapRepository(db),
+
+		ImportantDateRepo:  NewImportantDateRepository(db),
+		ReminderRepo:       NewReminderRepository(db),
+		PriceHistoryRepo:   NewPriceHistoryRepository(db),
+		ContributionRepo:   NewContributionRepository(db),
+		WishlistMemberRepo: NewWishlistMemberRepository(db),
+		DemoRepo:           NewDemoRepository(db),
+		GiftRepo:           NewGiftRepository(db),
+		LoadGenRepo:        NewLoadGenRepository(db),
+		WishlistImportRepo: NewWishlistImportRepository(db),
 	}
 }
 
 // Repository interface for easier testing and dependency injection
 type Repository interface {
 	User() model.UserRepository
+	Wishlist() model.WishlistRepository
+	WishItem() model.WishItemRepository
+	RefreshToken() model.RefreshTokenRepository
+	RevokedToken() model.RevokedTokenRepository
+	VerificationCode() model.VerificationCodeRepository
+	Group() model.GroupRepository
+	GroupNote() model.GroupNoteRepository
+	GroupPoll() model.GroupPollRepository
+	Follow() model.FollowRepository
+	Reservation() model.ReservationRepository
+	LoginAttempt() model.LoginAttemptRepository
+	Notification() model.NotificationRepository
+	Reaction() model.ReactionRepository
+	Block() model.BlockRepository
+	Search() model.SearchRepository
+	SiteSettings() model.SiteSettingsRepository
+	Identity() model.IdentityRepository
+	Subscription() model.SubscriptionRepository
+	APIKey() model.APIKeyRepository
+	Sitemap() model.SitemapRepository
+	ImportantDate() model.ImportantDateRepository
+	Reminder() model.ReminderRepository
+	PriceHistory() model.PriceHistoryRepository
+	Contribution() model.ContributionRepository
+	WishlistMember() model.WishlistMemberRepository
+	Demo() model.DemoRepository
+	Gift() model.GiftRepository
+	LoadGen() model.LoadGenRepository
+	WishlistImport() model.WishlistImportRepository
 }
 
 // Ensure RepositoryManager implements the Repository interface
@@ -30,3 +121,148 @@ var _ Repository = (*RepositoryManager)(nil)
 func (rm *RepositoryManager) User() model.UserRepository {
 	return rm.UserRepo
 }
+
+// Wishlist returns the wishlist repository
+func (rm *RepositoryManager) Wishlist() model.WishlistRepository {
+	return rm.WishlistRepo
+}
+
+// WishItem returns the wish item repository
+func (rm *RepositoryManager) WishItem() model.WishItemRepository {
+	return rm.WishItemRepo
+}
+
+// RefreshToken returns the refresh token repository
+func (rm *RepositoryManager) RefreshToken() model.RefreshTokenRepository {
+	return rm.RefreshTokenRepo
+}
+
+// RevokedToken returns the revoked access token repository
+func (rm *RepositoryManager) RevokedToken() model.RevokedTokenRepository {
+	return rm.RevokedTokenRepo
+}
+
+// VerificationCode returns the verification code repository
+func (rm *RepositoryManager) VerificationCode() model.VerificationCodeRepository {
+	return rm.VerificationCodeRepo
+}
+
+// Group returns the group repository
+func (rm *RepositoryManager) Group() model.GroupRepository {
+	return rm.GroupRepo
+}
+
+// GroupNote returns the group note repository
+func (rm *RepositoryManager) GroupNote() model.GroupNoteRepository {
+	return rm.GroupNoteRepo
+}
+
+// GroupPoll returns the group poll repository
+func (rm *RepositoryManager) GroupPoll() model.GroupPollRepository {
+	return rm.GroupPollRepo
+}
+
+// Follow returns the follow repository
+func (rm *RepositoryManager) Follow() model.FollowRepository {
+	return rm.FollowRepo
+}
+
+// Reservation returns the reservation repository
+func (rm *RepositoryManager) Reservation() model.ReservationRepository {
+	return rm.ReservationRepo
+}
+
+// LoginAttempt returns the login attempt repository
+func (rm *RepositoryManager) LoginAttempt() model.LoginAttemptRepository {
+	return rm.LoginAttemptRepo
+}
+
+// Notification returns the notification repository
+func (rm *RepositoryManager) Notification() model.NotificationRepository {
+	return rm.NotificationRepo
+}
+
+// Reaction returns the reaction repository
+func (rm *RepositoryManager) Reaction() model.ReactionRepository {
+	return rm.ReactionRepo
+}
+
+// Block returns the block repository
+func (rm *RepositoryManager) Block() model.BlockRepository {
+	return rm.BlockRepo
+}
+
+// Search returns the search repository
+func (rm *RepositoryManager) Search() model.SearchRepository {
+	return rm.SearchRepo
+}
+
+// SiteSettings returns the site settings repository
+func (rm *RepositoryManager) SiteSettings() model.SiteSettingsRepository {
+	return rm.SiteSettingsRepo
+}
+
+// Identity returns the external identity repository
+func (rm *RepositoryManager) Identity() model.IdentityRepository {
+	return rm.IdentityRepo
+}
+
+// Subscription returns the wishlist subscription repository
+func (rm *RepositoryManager) Subscription() model.SubscriptionRepository {
+	return rm.SubscriptionRepo
+}
+
+// APIKey returns the API key repository
+func (rm *RepositoryManager) APIKey() model.APIKeyRepository {
+	return rm.APIKeyRepo
+}
+
+// Sitemap returns the sitemap repository
+func (rm *RepositoryManager) Sitemap() model.SitemapRepository {
+	return rm.SitemapRepo
+}
+
+// ImportantDate returns the important date repository
+func (rm *RepositoryManager) ImportantDate() model.ImportantDateRepository {
+	return rm.ImportantDateRepo
+}
+
+// Reminder returns the birthday and important date reminder repository
+func (rm *RepositoryManager) Reminder() model.ReminderRepository {
+	return rm.ReminderRepo
+}
+
+// PriceHistory returns the wish item price history repository
+func (rm *RepositoryManager) PriceHistory() model.PriceHistoryRepository {
+	return rm.PriceHistoryRepo
+}
+
+// Contribution returns the group gift contribution repository
+func (rm *RepositoryManager) Contribution() model.ContributionRepository {
+	return rm.ContributionRepo
+}
+
+// WishlistMember returns the wishlist member repository
+func (rm *RepositoryManager) WishlistMember() model.WishlistMemberRepository {
+	return rm.WishlistMemberRepo
+}
+
+// Demo returns the demo sandbox repository
+func (rm *RepositoryManager) Demo() model.DemoRepository {
+	return rm.DemoRepo
+}
+
+// Gift returns the gift history repository
+func (rm *RepositoryManager) Gift() model.GiftRepository {
+	return rm.GiftRepo
+}
+
+// LoadGen returns the repository writing the generated datasets of capacity tests
+func (rm *RepositoryManager) LoadGen() model.LoadGenRepository {
+	return rm.LoadGenRepo
+}
+
+// WishlistImport returns the wishlist import repository
+func (rm *RepositoryManager) WishlistImport() model.WishlistImportRepository {
+	return rm.WishlistImportRepo
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// ReservationRepository implements the model.ReservationRepository interface
+type ReservationRepository struct {
+	db *DB
+}
+
+// NewReservationRepository creates a new instance of ReservationRepository
+func NewReservationRepository(db *DB) model.ReservationRepository {
+	return &ReservationRepository{
+		db: db,
+	}
+}
+
+// Reserve creates or updates the user's reservation of an item. The item row is locked
+// so concurrent givers cannot together reserve more than the quantity still wished for, once the
+// units the owner already received are taken off. A reservation marked purchased is not changed
+// and model.ErrReservationPurchased is returned.
+func (r *ReservationRepository) Reserve(reservation *model.Reservation) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting reservation: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back reservation: %v", rollbackErr)
+		}
+	}()
+
+	var wanted int
+	err = tx.QueryRow(`SELECT quantity - received_quantity FROM wish_items WHERE id = $1 FOR UPDATE`, reservation.ItemID).Scan(&wanted)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("wish item with ID %d not found", reservation.ItemID)
+		}
+		log.Printf("Error locking wish item %d: %v", reservation.ItemID, err)
+		return fmt.Errorf("failed to lock wish item: %w", err)
+	}
+
+	var reservedByOthers int
+	err = tx.QueryRow(
+		`SELECT COALESCE(SUM(quantity), 0) FROM reservations WHERE item_id = $1 AND user_id <> $2`,
+		reservation.ItemID, reservation.UserID,
+	).Scan(&reservedByOthers)
+	if err != nil {
+		log.Printf("Error summing reservations of wish item %d: %v", reservation.ItemID, err)
+		return fmt.Errorf("failed to sum reservations: %w", err)
+	}
+
+	if reservedByOthers+reservation.Quantity > wanted {
+		return model.ErrItemFullyReserved
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO reservations (item_id, user_id, quantity, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (item_id, user_id) DO UPDATE SET quantity = EXCLUDED.quantity
+			WHERE reservations.purchased_at IS NULL
+		RETURNING id, created_at
+	`, reservation.ItemID, reservation.UserID, reservation.Quantity, reservation.CreatedAt).Scan(&reservation.ID, &reservation.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.ErrReservationPurchased
+		}
+		log.Printf("Error reserving wish item %d for user %d: %v", reservation.ItemID, reservation.UserID, err)
+		return fmt.Errorf("failed to reserve wish item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing reservation: %v", err)
+		return fmt.Errorf("failed to commit reservation: %w", err)
+	}
+
+	return nil
+}
+
+// Release removes the user's reservation of an item. A reservation marked purchased is kept and
+// model.ErrReservationPurchased is returned; without a reservation, model.ErrNoReservation is.
+func (r *ReservationRepository) Release(itemID, userID int) error {
+	result, err := r.db.Exec(`DELETE FROM reservations WHERE item_id = $1 AND user_id = $2 AND purchased_at IS NULL`, itemID, userID)
+	if err != nil {
+		log.Printf("Error releasing wish item %d for user %d: %v", itemID, userID, err)
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for reservation release: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		var purchased bool
+		err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM reservations WHERE item_id = $1 AND user_id = $2)`, itemID, userID).Scan(&purchased)
+		if err != nil {
+			log.Printf("Error checking reservation of wish item %d by user %d: %v", itemID, userID, err)
+			return fmt.Errorf("failed to check reservation: %w", err)
+		}
+		if purchased {
+			return model.ErrReservationPurchased
+		}
+		return model.ErrNoReservation
+	}
+
+	return nil
+}
+
+// ListByWishlist retrieves every reservation of the items of a wishlist
+func (r *ReservationRepository) ListByWishlist(wishlistID int) ([]*model.Reservation, error) {
+	query := `
+		SELECT r.id, r.item_id, r.user_id, r.quantity, r.created_at, r.purchased_at
+		FROM reservations r
+		JOIN wish_items i ON i.id = r.item_id
+		WHERE i.wishlist_id = $1
+		ORDER BY r.created_at
+	`
+
+	rows, err := r.db.Query(query, wishlistID)
+	if err != nil {
+		log.Printf("Error listing reservations for wishlist %d: %v", wishlistID, err)
+		return nil, fmt.Errorf("failed to list reservations: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var reservations []*model.Reservation
+	for rows.Next() {
+		reservation := &model.Reservation{}
+		if err := rows.Scan(&reservation.ID, &reservation.ItemID, &reservation.UserID, &reservation.Quantity, &reservation.CreatedAt, &reservation.PurchasedAt); err != nil {
+			log.Printf("Error scanning reservation row: %v", err)
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over reservation rows: %v", err)
+		return nil, fmt.Errorf("error iterating over reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// pickUnclaimedItem selects one item of a wishlist ($1) still wished for, without reservations and within a
+// budget ($2 in minor units of the currency $3, NULL for none). Only items priced in the budget's currency are
+// compared with it; unpriced items always fit, as their price is unknown. Items are drawn at random weighted by
+// priority, so a priority 5 item is five times as likely as a priority 1 item: ordering by -ln(u)/weight is a
+// weighted draw without replacement.
+var pickUnclaimedItem = `
+	SELECT i.id FROM wish_items i
+	WHERE i.wishlist_id = $1
+		AND ($2::bigint IS NULL OR i.price_minor = 0 OR (i.currency = $3 AND i.price_minor <= $2::bigint))
+		AND i.received_quantity < i.quantity
+		AND NOT EXISTS (SELECT 1 FROM reservations r WHERE r.item_id = i.id)
+	ORDER BY -ln(1 - random()) / GREATEST(i.priority, 1)
+	LIMIT 1
+`
+
+// PickUnclaimed returns the ID of a random unclaimed item of a wishlist within the budget,
+// or model.ErrNoUnclaimedItem when there is none
+func (r *ReservationRepository) PickUnclaimed(wishlistID int, budget *currency.Amount) (int, error) {
+	budgetMinor, budgetCurrency := budgetArgs(budget)
+
+	var itemID int
+	if err := r.db.QueryRow(pickUnclaimedItem, wishlistID, budgetMinor, budgetCurrency).Scan(&itemID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, model.ErrNoUnclaimedItem
+		}
+		log.Printf("Error picking an unclaimed item of wishlist %d: %v", wishlistID, err)
+		return 0, fmt.Errorf("failed to pick unclaimed item: %w", err)
+	}
+
+	return itemID, nil
+}
+
+// ClaimRandom picks a random unclaimed item of a wishlist within the budget and reserves one unit
+// of it for the user in the same transaction. Items locked by a concurrent reservation are
+// skipped, and the pick is checked again once locked so two givers never claim the same item.
+func (r *ReservationRepository) ClaimRandom(wishlistID, userID int, budget *currency.Amount) (*model.Reservation, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting random claim: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back random claim: %v", rollbackErr)
+		}
+	}()
+
+	budgetMinor, budgetCurrency := budgetArgs(budget)
+
+	var itemID int
+	err = tx.QueryRow(pickUnclaimedItem+` FOR UPDATE OF i SKIP LOCKED`, wishlistID, budgetMinor, budgetCurrency).Scan(&itemID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrNoUnclaimedItem
+		}
+		log.Printf("Error picking an unclaimed item of wishlist %d: %v", wishlistID, err)
+		return nil, fmt.Errorf("failed to pick unclaimed item: %w", err)
+	}
+
+	// A reservation committed between the pick and the lock is only visible to a new statement
+	var claimed bool
+	if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM reservations WHERE item_id = $1)`, itemID).Scan(&claimed); err != nil {
+		log.Printf("Error checking reservations of wish item %d: %v", itemID, err)
+		return nil, fmt.Errorf("failed to check reservations: %w", err)
+	}
+	if claimed {
+		return nil, model.ErrItemFullyReserved
+	}
+
+	reservation := &model.Reservation{ItemID: itemID, UserID: userID, Quantity: 1}
+	reservation.BeforeCreate()
+
+	err = tx.QueryRow(`
+		INSERT INTO reservations (item_id, user_id, quantity, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, reservation.ItemID, reservation.UserID, reservation.Quantity, reservation.CreatedAt).Scan(&reservation.ID, &reservation.CreatedAt)
+	if err != nil {
+		log.Printf("Error reserving wish item %d for user %d: %v", reservation.ItemID, reservation.UserID, err)
+		return nil, fmt.Errorf("failed to reserve wish item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing random claim: %v", err)
+		return nil, fmt.Errorf("failed to commit reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+// budgetArgs returns the query arguments of a budget, NULL and an empty currency for none
+func budgetArgs(budget *currency.Amount) (*int64, string) {
+	if budget == nil {
+		return nil, ""
+	}
+	return &budget.Minor, budget.Currency
+}
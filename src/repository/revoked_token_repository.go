@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// RevokedTokenRepository implements the model.RevokedTokenRepository interface
+type RevokedTokenRepository struct {
+	db *DB
+}
+
+// NewRevokedTokenRepository creates a new instance of RevokedTokenRepository
+func NewRevokedTokenRepository(db *DB) model.RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		db: db,
+	}
+}
+
+// Revoke adds an access token to the denylist
+func (r *RevokedTokenRepository) Revoke(token *model.RevokedToken) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(query, token.JTI, token.UserID, token.ExpiresAt, token.RevokedAt); err != nil {
+		log.Printf("Error revoking token %s: %v", token.JTI, err)
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	// Opportunistically drop entries that can no longer be presented
+	if _, err := r.PurgeExpired(); err != nil {
+		log.Printf("Error purging expired revoked tokens: %v", err)
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the token with the given JTI has been revoked, or was issued to a
+// user who has since been deleted or whose sessions were ended after it was issued
+func (r *RevokedTokenRepository) IsRevoked(jti string, userID int, issuedAt time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)
+			OR NOT EXISTS(
+				SELECT 1 FROM users
+				WHERE id = $2 AND deleted_at IS NULL
+					AND (sessions_revoked_at IS NULL OR sessions_revoked_at <= $3)
+			)
+	`
+
+	var revoked bool
+	if err := r.db.QueryRow(query, jti, userID, issuedAt).Scan(&revoked); err != nil {
+		log.Printf("Error checking token revocation: %v", err)
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// PurgeExpired deletes denylist entries for tokens that have already expired
+func (r *RevokedTokenRepository) PurgeExpired() (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM revoked_tokens WHERE expires_at < $1`, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge revoked tokens: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/lib/pq"
+)
+
+// SearchRepository implements the model.SearchRepository interface
+type SearchRepository struct {
+	db *DB
+}
+
+// NewSearchRepository creates a new instance of SearchRepository
+func NewSearchRepository(db *DB) model.SearchRepository {
+	return &SearchRepository{
+		db: db,
+	}
+}
+
+// visibleWishlist returns a condition restricting wishlists aliased w to those the viewer may see,
+// following the rules of access.CanViewWishlist: their own, public, friends-only between mutual
+// followers, or shared to one of the viewer's groups, and never across a block. viewer is the
+// placeholder or column holding the viewer's ID.
+func visibleWishlist(viewer string) string {
+	return strings.ReplaceAll(`
+	(w.user_id = {viewer}
+		OR w.visibility = 'public'
+		OR (w.visibility = 'friends' AND EXISTS (
+			SELECT 1 FROM follows f1
+			JOIN follows f2 ON f2.follower_id = f1.followee_id AND f2.followee_id = f1.follower_id
+			WHERE f1.follower_id = w.user_id AND f1.followee_id = {viewer}
+		))
+		OR w.id IN (
+			SELECT gw.wishlist_id FROM group_wishlists gw
+			JOIN group_members gm ON gm.group_id = gw.group_id
+			WHERE gm.user_id = {viewer}
+		))
+	AND NOT EXISTS (
+		SELECT 1 FROM blocks b
+		WHERE b.kind = 'block'
+			AND ((b.blocker_id = w.user_id AND b.blocked_id = {viewer}) OR (b.blocker_id = {viewer} AND b.blocked_id = w.user_id))
+	)`, "{viewer}", viewer)
+}
+
+// searchResults ranks the matches of every type. $1 is the tsquery, $2 the viewer and $3 the types.
+// Users and wishlists are named by their public ID, wishlist_id is the public ID of the wishlist.
+var searchResults = `
+	WITH q AS (SELECT to_tsquery('simple', $1) AS query),
+	results AS (
+		SELECT 'user' AS type, u.id, u.public_id::text AS public_id, u.username AS title, u.username, '' AS wishlist_id, ts_rank(u.search_vector, q.query) AS rank
+		FROM users u CROSS JOIN q
+		WHERE 'user' = ANY($3) AND u.search_vector @@ q.query AND u.deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM blocks b
+				WHERE b.kind = 'block'
+					AND ((b.blocker_id = u.id AND b.blocked_id = $2) OR (b.blocker_id = $2 AND b.blocked_id = u.id))
+			)
+		UNION ALL
+		SELECT 'wishlist', w.id, w.public_id::text, w.title, u.username, w.public_id::text, ts_rank(w.search_vector, q.query)
+		FROM wishlists w JOIN users u ON u.id = w.user_id CROSS JOIN q
+		WHERE 'wishlist' = ANY($3) AND w.search_vector @@ q.query AND u.deleted_at IS NULL AND ` + visibleWishlist("$2") + `
+		UNION ALL
+		SELECT 'item', i.id, '', i.title, u.username, w.public_id::text, ts_rank(i.search_vector, q.query)
+		FROM wish_items i JOIN wishlists w ON w.id = i.wishlist_id JOIN users u ON u.id = w.user_id CROSS JOIN q
+		WHERE 'item' = ANY($3) AND i.search_vector @@ q.query AND u.deleted_at IS NULL AND ` + visibleWishlist("$2") + `
+	)`
+
+// Search retrieves a page of results matching every term, best ranked first
+func (r *SearchRepository) Search(terms []string, types []model.SearchType, viewerID int, opts model.ListOptions) ([]*model.SearchResult, *model.PageInfo, error) {
+	query := prefixQuery(terms)
+	typeNames := make([]string, len(types))
+	for i, t := range types {
+		typeNames[i] = string(t)
+	}
+
+	var total int
+	if err := r.db.QueryRow(searchResults+` SELECT COUNT(*) FROM results`, query, viewerID, pq.Array(typeNames)).Scan(&total); err != nil {
+		log.Printf("Error counting search results: %v", err)
+		return nil, nil, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	rows, err := r.db.Query(
+		searchResults+` SELECT type, id, public_id, title, username, wishlist_id, rank FROM results ORDER BY rank DESC, type, id LIMIT $4 OFFSET $5`,
+		query, viewerID, pq.Array(typeNames), opts.Limit, opts.Offset(),
+	)
+	if err != nil {
+		log.Printf("Error searching: %v", err)
+		return nil, nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var results []*model.SearchResult
+	for rows.Next() {
+		result := &model.SearchResult{}
+		var id int
+		if err := rows.Scan(&result.Type, &id, &result.PublicID, &result.Title, &result.Username, &result.WishlistID, &result.Rank); err != nil {
+			log.Printf("Error scanning search result row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		if result.Type == model.SearchTypeItem {
+			result.ID = id
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over search result rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over search results: %w", err)
+	}
+
+	return results, model.NewPageInfo(opts, total, len(results)), nil
+}
+
+// taggedWishlists counts the items of each wishlist the viewer may see carrying a tag. $1 is the
+// tag, $2 the viewer and $3 the category, empty for every category.
+var taggedWishlists = `
+	WITH tagged AS (
+		SELECT w.id, w.public_id, w.title, u.username, w.updated_at, COUNT(*) AS tagged_items
+		FROM wish_item_tags it
+		JOIN tags t ON t.id = it.tag_id
+		JOIN wish_items i ON i.id = it.item_id
+		JOIN wishlists w ON w.id = i.wishlist_id
+		JOIN users u ON u.id = w.user_id
+		WHERE t.name = $1 AND ($3 = '' OR i.category = $3) AND u.deleted_at IS NULL AND ` + visibleWishlist("$2") + `
+		GROUP BY w.id, u.id
+	)`
+
+// BrowseTag retrieves a page of the wishlists the viewer may see with items carrying the tag, and
+// of the given category when one is set; those with the most tagged items come first
+func (r *SearchRepository) BrowseTag(tag string, category model.WishItemCategory, viewerID int, opts model.ListOptions) ([]*model.TaggedWishlist, *model.PageInfo, error) {
+	var total int
+	if err := r.db.QueryRow(taggedWishlists+` SELECT COUNT(*) FROM tagged`, tag, viewerID, category).Scan(&total); err != nil {
+		log.Printf("Error counting wishlists tagged %q: %v", tag, err)
+		return nil, nil, fmt.Errorf("failed to count tagged wishlists: %w", err)
+	}
+
+	rows, err := r.db.Query(
+		taggedWishlists+` SELECT public_id, title, username, tagged_items FROM tagged ORDER BY tagged_items DESC, updated_at DESC, id LIMIT $4 OFFSET $5`,
+		tag, viewerID, category, opts.Limit, opts.Offset(),
+	)
+	if err != nil {
+		log.Printf("Error browsing wishlists tagged %q: %v", tag, err)
+		return nil, nil, fmt.Errorf("failed to browse tagged wishlists: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var wishlists []*model.TaggedWishlist
+	for rows.Next() {
+		wishlist := &model.TaggedWishlist{}
+		if err := rows.Scan(&wishlist.WishlistID, &wishlist.Title, &wishlist.Username, &wishlist.TaggedItems); err != nil {
+			log.Printf("Error scanning tagged wishlist row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan tagged wishlist: %w", err)
+		}
+		wishlists = append(wishlists, wishlist)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over tagged wishlist rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over tagged wishlists: %w", err)
+	}
+
+	return wishlists, model.NewPageInfo(opts, total, len(wishlists)), nil
+}
+
+// prefixQuery builds a tsquery matching every term as a prefix, e.g. "lego & set" becomes
+// "lego:* & set:*". Terms must only hold letters and digits so they cannot inject tsquery syntax.
+func prefixQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/lib/pq"
+)
+
+// SiteSettingsRepository implements the model.SiteSettingsRepository interface
+type SiteSettingsRepository struct {
+	db *DB
+}
+
+// NewSiteSettingsRepository creates a new instance of SiteSettingsRepository
+func NewSiteSettingsRepository(db *DB) model.SiteSettingsRepository {
+	return &SiteSettingsRepository{
+		db: db,
+	}
+}
+
+// Get retrieves the site settings, falling back to the defaults when the row is missing
+func (r *SiteSettingsRepository) Get() (*model.SiteSettings, error) {
+	query := `SELECT registration_open, allowed_email_domains, denied_email_domains, updated_by, updated_at FROM site_settings WHERE id`
+
+	settings := &model.SiteSettings{}
+	var allowed, denied pq.StringArray
+	err := r.db.QueryRow(query).Scan(&settings.RegistrationOpen, &allowed, &denied, &settings.UpdatedBy, &settings.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.DefaultSiteSettings(), nil
+	}
+	if err != nil {
+		log.Printf("Error getting site settings: %v", err)
+		return nil, fmt.Errorf("failed to get site settings: %w", err)
+	}
+
+	settings.AllowedEmailDomains = append([]string{}, allowed...)
+	settings.DeniedEmailDomains = append([]string{}, denied...)
+	return settings, nil
+}
+
+// Update saves the site settings
+func (r *SiteSettingsRepository) Update(settings *model.SiteSettings) error {
+	query := `
+		INSERT INTO site_settings (id, registration_open, allowed_email_domains, denied_email_domains, updated_by, updated_at)
+		VALUES (TRUE, $1, $2, $3, $4, $5)
+		ON CONFLICT (id)
+		DO UPDATE SET registration_open = EXCLUDED.registration_open, allowed_email_domains = EXCLUDED.allowed_email_domains,
+			denied_email_domains = EXCLUDED.denied_email_domains, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(query, settings.RegistrationOpen, pq.Array(settings.AllowedEmailDomains), pq.Array(settings.DeniedEmailDomains),
+		settings.UpdatedBy, settings.UpdatedAt)
+	if err != nil {
+		log.Printf("Error updating site settings: %v", err)
+		return fmt.Errorf("failed to update site settings: %w", err)
+	}
+
+	return nil
+}
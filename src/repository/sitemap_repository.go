@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// SitemapRepository implements the model.SitemapRepository interface
+type SitemapRepository struct {
+	db *DB
+}
+
+// NewSitemapRepository creates a new instance of SitemapRepository
+func NewSitemapRepository(db *DB) model.SitemapRepository {
+	return &SitemapRepository{
+		db: db,
+	}
+}
+
+// StreamProfiles calls fn for each user with at least one public wishlist; other profiles have
+// nothing to show to search engines, and users who opted out of indexing are left out. A profile counts as modified when the user or one of their
+// public wishlists was. The query timeout does not apply, as the whole table is read.
+func (r *SitemapRepository) StreamProfiles(fn func(*model.SitemapProfile) error) error {
+	query := `
+		SELECT u.username, GREATEST(u.updated_at, MAX(w.updated_at))
+		FROM users u JOIN wishlists w ON w.user_id = u.id
+		WHERE u.deleted_at IS NULL AND NOT u.noindex AND w.visibility = 'public'
+		GROUP BY u.id
+		ORDER BY u.id
+	`
+
+	return r.stream("profiles", query, func(rows *sql.Rows) error {
+		profile := &model.SitemapProfile{}
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&profile.Username, &updatedAt); err != nil {
+			return err
+		}
+		profile.UpdatedAt = updatedAt.Time
+		return fn(profile)
+	})
+}
+
+// StreamWishlists calls fn for each public wishlist of a user who is not deleted and did not opt
+// out of indexing. A wishlist
+// counts as modified when it or one of its items was. The query timeout does not apply, as the
+// whole table is read.
+func (r *SitemapRepository) StreamWishlists(fn func(*model.SitemapWishlist) error) error {
+	query := `
+		SELECT w.public_id, GREATEST(w.updated_at, (SELECT MAX(i.updated_at) FROM wish_items i WHERE i.wishlist_id = w.id))
+		FROM wishlists w JOIN users u ON u.id = w.user_id
+		WHERE u.deleted_at IS NULL AND NOT u.noindex AND w.visibility = 'public'
+		ORDER BY w.id
+	`
+
+	return r.stream("wishlists", query, func(rows *sql.Rows) error {
+		wishlist := &model.SitemapWishlist{}
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&wishlist.PublicID, &updatedAt); err != nil {
+			return err
+		}
+		wishlist.UpdatedAt = updatedAt.Time
+		return fn(wishlist)
+	})
+}
+
+// stream runs query and calls scan for each row, stopping at the first error
+func (r *SitemapRepository) stream(what, query string, scan func(*sql.Rows) error) error {
+	rows, err := r.db.DB.Query(query)
+	if err != nil {
+		log.Printf("Error streaming sitemap %s: %v", what, err)
+		return fmt.Errorf("failed to stream sitemap %s: %w", what, err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			log.Printf("Error reading sitemap %s row: %v", what, err)
+			return fmt.Errorf("failed to read sitemap %s: %w", what, err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over sitemap %s rows: %v", what, err)
+		return fmt.Errorf("error iterating over sitemap %s: %w", what, err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// SubscriptionRepository implements the model.SubscriptionRepository interface
+type SubscriptionRepository struct {
+	db *DB
+}
+
+// NewSubscriptionRepository creates a new instance of SubscriptionRepository
+func NewSubscriptionRepository(db *DB) model.SubscriptionRepository {
+	return &SubscriptionRepository{
+		db: db,
+	}
+}
+
+// subscriptionListSpec describes the sorting and filtering accepted when listing subscriptions
+var subscriptionListSpec = listSpec{
+	sorts: map[string]string{
+		"subscribed_at": "s.created_at",
+		"title":         "w.title",
+	},
+	defaultSort: "subscribed_at",
+	defaultDesc: true,
+	tieBreaker:  "w.id",
+	filters: map[string]string{
+		"title": "w.title ILIKE '%' || ? || '%'",
+	},
+}
+
+// Subscribe subscribes the user to the wishlist, reporting whether the subscription is new;
+// subscribing twice is a no-op
+func (r *SubscriptionRepository) Subscribe(userID, wishlistID int) (bool, error) {
+	query := `
+		INSERT INTO wishlist_subscriptions (user_id, wishlist_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, wishlist_id) DO NOTHING
+	`
+
+	result, err := r.db.Exec(query, userID, wishlistID, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error subscribing user %d to wishlist %d: %v", userID, wishlistID, err)
+		return false, fmt.Errorf("failed to subscribe to wishlist: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for subscription: %v", err)
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Unsubscribe removes the subscription; unsubscribing from a wishlist not subscribed to is a no-op
+func (r *SubscriptionRepository) Unsubscribe(userID, wishlistID int) error {
+	if _, err := r.db.Exec(`DELETE FROM wishlist_subscriptions WHERE user_id = $1 AND wishlist_id = $2`, userID, wishlistID); err != nil {
+		log.Printf("Error unsubscribing user %d from wishlist %d: %v", userID, wishlistID, err)
+		return fmt.Errorf("failed to unsubscribe from wishlist: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser retrieves a page of the wishlists the user is subscribed to. Wishlists the user
+// may no longer view are left out, though the subscription is kept in case access comes back.
+func (r *SubscriptionRepository) ListByUser(userID int, opts model.ListOptions) ([]*model.SubscribedWishlist, *model.PageInfo, error) {
+	q := selectFrom(
+		`wishlist_subscriptions s JOIN wishlists w ON w.id = s.wishlist_id JOIN users u ON u.id = w.user_id`,
+		`w.public_id, w.title, u.id, u.username, s.created_at`,
+	).Where(`s.user_id = ?`, userID).Where("u.deleted_at IS NULL").Where(visibleWishlist("s.user_id"))
+	subscriptionListSpec.apply(q, opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting subscriptions of user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to count subscriptions: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing subscriptions of user %d: %v", userID, err)
+		return nil, nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var wishlists []*model.SubscribedWishlist
+	for rows.Next() {
+		wishlist := &model.SubscribedWishlist{}
+		if err := rows.Scan(&wishlist.WishlistID, &wishlist.Title, &wishlist.OwnerID, &wishlist.OwnerUsername, &wishlist.SubscribedAt); err != nil {
+			log.Printf("Error scanning subscription row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		wishlists = append(wishlists, wishlist)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over subscription rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over subscriptions: %w", err)
+	}
+
+	return wishlists, model.NewPageInfo(opts, total, len(wishlists)), nil
+}
+
+// ListSubscribers returns the IDs of the users subscribed to the wishlist
+func (r *SubscriptionRepository) ListSubscribers(wishlistID int) ([]int, error) {
+	rows, err := r.db.Query(`SELECT user_id FROM wishlist_subscriptions WHERE wishlist_id = $1 ORDER BY user_id`, wishlistID)
+	if err != nil {
+		log.Printf("Error listing subscribers of wishlist %d: %v", wishlistID, err)
+		return nil, fmt.Errorf("failed to list subscribers: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			log.Printf("Error scanning subscriber row: %v", err)
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over subscriber rows: %v", err)
+		return nil, fmt.Errorf("error iterating over subscribers: %w", err)
+	}
+
+	return userIDs, nil
+}
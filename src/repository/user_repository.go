@@ -7,30 +7,58 @@ import (
 	"time"
 
 	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/pii"
 )
 
 // UserRepository implements the model.UserRepository interface
 type UserRepository struct {
-	db *sql.DB
+	db *DB
 }
 
 // NewUserRepository creates a new instance of UserRepository
-func NewUserRepository(db *sql.DB) model.UserRepository {
+func NewUserRepository(db *DB) model.UserRepository {
 	return &UserRepository{
 		db: db,
 	}
 }
 
+// userColumns lists the columns selected for a user
+const userColumns = `id, public_id, username, email, gender, password_hash, email_verified_at, avatar_key, created_at, updated_at, deleted_at, noindex, birthday,
+	avatar_alt, sandbox`
+
+// scanUser scans a user row into a model
+func scanUser(scanner interface{ Scan(...interface{}) error }) (*model.User, error) {
+	user := &model.User{}
+	err := scanner.Scan(
+		&user.ID,
+		&user.PublicID,
+		&user.Username,
+		&user.Email,
+		&user.Gender,
+		&user.PasswordHash,
+		&user.EmailVerifiedAt,
+		&user.AvatarKey,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletedAt,
+		&user.NoIndex,
+		&user.Birthday,
+		&user.AvatarAlt,
+		&user.Sandbox,
+	)
+	return user, err
+}
+
 // Create creates a new user in the database
 func (r *UserRepository) Create(user *model.User) error {
 	query := `
 		INSERT INTO users (username, email, gender, password_hash, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id
+		RETURNING id, public_id
 	`
 
 	var id int
-	err := r.db.QueryRow(
+	err := r.db.QueryRowWrite(
 		query,
 		user.Username,
 		user.Email,
@@ -38,7 +66,7 @@ func (r *UserRepository) Create(user *model.User) error {
 		user.PasswordHash,
 		user.CreatedAt,
 		user.UpdatedAt,
-	).Scan(&id)
+	).Scan(&id, &user.PublicID)
 
 	if err != nil {
 		log.Printf("Error creating user: %v", err)
@@ -53,21 +81,12 @@ func (r *UserRepository) Create(user *model.User) error {
 // GetByID retrieves a user by their ID
 func (r *UserRepository) GetByID(id int) (*model.User, error) {
 	query := `
-		SELECT id, username, email, gender, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	user := &model.User{}
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.Gender,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRow(query, id))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -80,24 +99,39 @@ func (r *UserRepository) GetByID(id int) (*model.User, error) {
 	return user, nil
 }
 
+// GetByPublicID retrieves a user by their public ID
+func (r *UserRepository) GetByPublicID(publicID string) (*model.User, error) {
+	if !model.IsPublicID(publicID) {
+		return nil, fmt.Errorf("user with public ID %q: %w", publicID, model.ErrUserNotFound)
+	}
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE public_id = $1 AND deleted_at IS NULL
+	`
+
+	user, err := scanUser(r.db.QueryRow(query, publicID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user with public ID %q: %w", publicID, model.ErrUserNotFound)
+		}
+		log.Printf("Error getting user by public ID: %v", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
 // GetByUsername retrieves a user by their username
 func (r *UserRepository) GetByUsername(username string) (*model.User, error) {
 	query := `
-		SELECT id, username, email, gender, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE username = $1
+		WHERE username = $1 AND deleted_at IS NULL
 	`
 
-	user := &model.User{}
-	err := r.db.QueryRow(query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.Gender,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRow(query, username))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -113,27 +147,18 @@ func (r *UserRepository) GetByUsername(username string) (*model.User, error) {
 // GetByEmail retrieves a user by their email
 func (r *UserRepository) GetByEmail(email string) (*model.User, error) {
 	query := `
-		SELECT id, username, email, gender, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE email = $1
+		WHERE email = $1 AND deleted_at IS NULL
 	`
 
-	user := &model.User{}
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.Gender,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRow(query, email))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user with email '%s' not found", email)
+			return nil, fmt.Errorf("user with email '%s' not found", pii.Email(email))
 		}
-		log.Printf("Error getting user by email '%s': %v", email, err)
+		log.Printf("Error getting user by email '%s': %v", pii.Email(email), err)
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
@@ -144,8 +169,8 @@ func (r *UserRepository) GetByEmail(email string) (*model.User, error) {
 func (r *UserRepository) Update(user *model.User) error {
 	query := `
 		UPDATE users
-		SET username = $2, email = $3, gender = $4, password_hash = $5, updated_at = $6
-		WHERE id = $1
+		SET username = $2, email = $3, gender = $4, password_hash = $5, email_verified_at = $6, updated_at = $7, noindex = $8, birthday = $9
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	user.BeforeUpdate() // Update the timestamp
@@ -156,7 +181,10 @@ func (r *UserRepository) Update(user *model.User) error {
 		user.Email,
 		user.Gender,
 		user.PasswordHash,
+		user.EmailVerifiedAt,
 		user.UpdatedAt,
+		user.NoIndex,
+		user.Birthday,
 	)
 
 	if err != nil {
@@ -178,11 +206,13 @@ func (r *UserRepository) Update(user *model.User) error {
 	return nil
 }
 
-// Delete deletes a user from the database
+// Delete soft-deletes a user: the row is kept, hidden from every other method, until it is
+// restored or purged. Their sessions end, so access tokens issued before do not work again
+// after a restore.
 func (r *UserRepository) Delete(id int) error {
-	query := `DELETE FROM users WHERE id = $1`
+	query := `UPDATE users SET deleted_at = $2, sessions_revoked_at = $2 WHERE id = $1 AND deleted_at IS NULL`
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.Exec(query, id, time.Now().UTC())
 	if err != nil {
 		log.Printf("Error deleting user with ID %d: %v", id, err)
 		return fmt.Errorf("failed to delete user: %w", err)
@@ -195,25 +225,92 @@ func (r *UserRepository) Delete(id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user with ID %d not found", id)
+		return fmt.Errorf("user with ID %d: %w", id, model.ErrUserNotFound)
 	}
 
 	log.Printf("User with ID %d deleted successfully", id)
 	return nil
 }
 
-// List retrieves all users from the database
-func (r *UserRepository) List() ([]*model.User, error) {
-	query := `
-		SELECT id, username, email, gender, password_hash, created_at, updated_at
-		FROM users
-		ORDER BY created_at DESC
-	`
+// Restore brings back a soft-deleted user, named by their public ID
+func (r *UserRepository) Restore(publicID string) error {
+	if !model.IsPublicID(publicID) {
+		return fmt.Errorf("deleted user with public ID %q not found", publicID)
+	}
+
+	query := `UPDATE users SET deleted_at = NULL, updated_at = $2 WHERE public_id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.Exec(query, publicID, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error restoring user with public ID %s: %v", publicID, err)
+		return fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for user restore: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("deleted user with public ID %q not found", publicID)
+	}
+
+	log.Printf("User with public ID %s restored successfully", publicID)
+	return nil
+}
+
+// Purge permanently removes the users soft-deleted before the cutoff, along with everything
+// they own through the cascading foreign keys, and returns how many were removed
+func (r *UserRepository) Purge(deletedBefore time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM users WHERE deleted_at IS NOT NULL AND deleted_at < $1`, deletedBefore)
+	if err != nil {
+		log.Printf("Error purging users deleted before %s: %v", deletedBefore.Format(time.RFC3339), err)
+		return 0, fmt.Errorf("failed to purge users: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for user purge: %v", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	log.Printf("Purged %d deleted users", purged)
+	return purged, nil
+}
+
+// userListSpec describes the sorting and filtering accepted when listing users
+var userListSpec = listSpec{
+	sorts: map[string]string{
+		"created_at": "created_at",
+		"username":   "username",
+	},
+	defaultSort: "created_at",
+	defaultDesc: true,
+	tieBreaker:  "id",
+	filters: map[string]string{
+		"username": "username ILIKE '%' || ? || '%'",
+		"email":    "email ILIKE '%' || ? || '%'",
+	},
+}
 
-	rows, err := r.db.Query(query)
+// List retrieves a page of users from the database
+func (r *UserRepository) List(opts model.ListOptions) ([]*model.User, *model.PageInfo, error) {
+	q := userListSpec.apply(selectFrom("users", userColumns).Where("deleted_at IS NULL"), opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting users: %v", err)
+		return nil, nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		log.Printf("Error listing users: %v", err)
-		return nil, fmt.Errorf("failed to list users: %w", err)
+		return nil, nil, fmt.Errorf("failed to list users: %w", err)
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
@@ -223,35 +320,62 @@ func (r *UserRepository) List() ([]*model.User, error) {
 
 	var users []*model.User
 	for rows.Next() {
-		user := &model.User{}
-		err := rows.Scan(
-			&user.ID,
-			&user.Username,
-			&user.Email,
-			&user.Gender,
-			&user.PasswordHash,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			log.Printf("Error scanning user row: %v", err)
-			return nil, fmt.Errorf("failed to scan user: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan user: %w", err)
 		}
 		users = append(users, user)
 	}
 
 	if err = rows.Err(); err != nil {
 		log.Printf("Error iterating over user rows: %v", err)
-		return nil, fmt.Errorf("error iterating over users: %w", err)
+		return nil, nil, fmt.Errorf("error iterating over users: %w", err)
 	}
 
 	log.Printf("Retrieved %d users from database", len(users))
-	return users, nil
+	return users, model.NewPageInfo(opts, total, len(users)), nil
 }
 
 // Helper methods for common operations
 
-// ExistsByUsername checks if a user with the given username exists
+// Stream calls fn for each user matching opts as rows are read, without loading the result set
+// into memory. It stops at the first error returned by fn. The query timeout does not apply, as
+// reading the rows lasts as long as the caller takes to consume them.
+func (r *UserRepository) Stream(opts model.ListOptions, fn func(*model.User) error) error {
+	query, args := userListSpec.apply(selectFrom("users", userColumns).Where("deleted_at IS NULL"), opts).Build()
+
+	rows, err := r.db.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("Error streaming users: %v", err)
+		return fmt.Errorf("failed to stream users: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			log.Printf("Error scanning user row: %v", err)
+			return fmt.Errorf("failed to scan user: %w", err)
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over user rows: %v", err)
+		return fmt.Errorf("error iterating over users: %w", err)
+	}
+	return nil
+}
+
+// ExistsByUsername checks if a user with the given username exists. Soft-deleted users keep
+// their username until purged, so they are counted.
 func (r *UserRepository) ExistsByUsername(username string) (bool, error) {
 	query := `SELECT COUNT(*) FROM users WHERE username = $1`
 
@@ -265,7 +389,7 @@ func (r *UserRepository) ExistsByUsername(username string) (bool, error) {
 	return count > 0, nil
 }
 
-// ExistsByEmail checks if a user with the given email exists
+// ExistsByEmail checks if a user with the given email exists, soft-deleted or not
 func (r *UserRepository) ExistsByEmail(email string) (bool, error) {
 	query := `SELECT COUNT(*) FROM users WHERE email = $1`
 
@@ -281,7 +405,7 @@ func (r *UserRepository) ExistsByEmail(email string) (bool, error) {
 
 // GetTotalCount returns the total number of users in the database
 func (r *UserRepository) GetTotalCount() (int, error) {
-	query := `SELECT COUNT(*) FROM users`
+	query := `SELECT COUNT(*) FROM users WHERE deleted_at IS NULL`
 
 	var count int
 	err := r.db.QueryRow(query).Scan(&count)
@@ -298,7 +422,7 @@ func (r *UserRepository) UpdatePassword(userID int, passwordHash string) error {
 	query := `
 		UPDATE users
 		SET password_hash = $2, updated_at = $3
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.Exec(query, userID, passwordHash, time.Now().UTC())
@@ -320,3 +444,88 @@ func (r *UserRepository) UpdatePassword(userID int, passwordHash string) error {
 	log.Printf("Password updated successfully for user ID %d", userID)
 	return nil
 }
+
+// ResetCredentials replaces the password hash of a user and ends their sessions: access tokens
+// issued before now are refused by the denylist check from then on
+func (r *UserRepository) ResetCredentials(userID int, passwordHash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $2, sessions_revoked_at = $3, updated_at = $3
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	// Token iat claims have second precision; tokens issued later in this second stay valid
+	result, err := r.db.Exec(query, userID, passwordHash, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		log.Printf("Error resetting credentials for user ID %d: %v", userID, err)
+		return fmt.Errorf("failed to reset credentials: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for credentials reset: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user with ID %d not found", userID)
+	}
+
+	log.Printf("Credentials reset for user ID %d", userID)
+	return nil
+}
+
+// MarkEmailVerified records that the user's current email address has been verified
+func (r *UserRepository) MarkEmailVerified(userID int) error {
+	query := `
+		UPDATE users
+		SET email_verified_at = $2, updated_at = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, userID, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error marking email verified for user ID %d: %v", userID, err)
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for email verification: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user with ID %d not found", userID)
+	}
+
+	log.Printf("Email verified for user ID %d", userID)
+	return nil
+}
+
+// SetAvatar stores the storage key of a user's avatar and its alternative text; an empty key removes it
+func (r *UserRepository) SetAvatar(userID int, key, alt string) error {
+	query := `
+		UPDATE users
+		SET avatar_key = $2, avatar_alt = $3, updated_at = $4
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, userID, key, alt, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error setting avatar for user ID %d: %v", userID, err)
+		return fmt.Errorf("failed to set avatar: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for avatar update: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user with ID %d not found", userID)
+	}
+
+	return nil
+}
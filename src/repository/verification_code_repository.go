@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// VerificationCodeRepository implements the model.VerificationCodeRepository interface
+type VerificationCodeRepository struct {
+	db *DB
+}
+
+// NewVerificationCodeRepository creates a new instance of VerificationCodeRepository
+func NewVerificationCodeRepository(db *DB) model.VerificationCodeRepository {
+	return &VerificationCodeRepository{
+		db: db,
+	}
+}
+
+// Create stores a new verification code
+func (r *VerificationCodeRepository) Create(code *model.VerificationCode) error {
+	query := `
+		INSERT INTO verification_codes (user_id, email, purpose, code_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, 0, $5, $6)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowWrite(
+		query,
+		code.UserID,
+		code.Email,
+		code.Purpose,
+		code.CodeHash,
+		code.ExpiresAt,
+		code.CreatedAt,
+	).Scan(&code.ID)
+
+	if err != nil {
+		log.Printf("Error creating verification code: %v", err)
+		return fmt.Errorf("failed to create verification code: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestActive retrieves the most recent unconsumed code of a user for a purpose
+func (r *VerificationCodeRepository) GetLatestActive(userID int, purpose model.VerificationPurpose) (*model.VerificationCode, error) {
+	query := `
+		SELECT id, user_id, email, purpose, code_hash, attempts, expires_at, consumed_at, created_at
+		FROM verification_codes
+		WHERE user_id = $1 AND purpose = $2 AND consumed_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	code := &model.VerificationCode{}
+	err := r.db.QueryRow(query, userID, purpose).Scan(
+		&code.ID,
+		&code.UserID,
+		&code.Email,
+		&code.Purpose,
+		&code.CodeHash,
+		&code.Attempts,
+		&code.ExpiresAt,
+		&code.ConsumedAt,
+		&code.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no active verification code for user ID %d", userID)
+		}
+		log.Printf("Error getting verification code for user ID %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get verification code: %w", err)
+	}
+
+	return code, nil
+}
+
+// IncrementAttempts records a failed attempt against a verification code
+func (r *VerificationCodeRepository) IncrementAttempts(id int) error {
+	query := `UPDATE verification_codes SET attempts = attempts + 1 WHERE id = $1`
+
+	if _, err := r.db.Exec(query, id); err != nil {
+		log.Printf("Error incrementing attempts for verification code %d: %v", id, err)
+		return fmt.Errorf("failed to record verification attempt: %w", err)
+	}
+
+	return nil
+}
+
+// Consume marks a verification code as used
+func (r *VerificationCodeRepository) Consume(id int) error {
+	query := `UPDATE verification_codes SET consumed_at = $2 WHERE id = $1 AND consumed_at IS NULL`
+
+	if _, err := r.db.Exec(query, id, time.Now().UTC()); err != nil {
+		log.Printf("Error consuming verification code %d: %v", id, err)
+		return fmt.Errorf("failed to consume verification code: %w", err)
+	}
+
+	return nil
+}
+
+// InvalidateAll consumes every outstanding code of a user for a purpose
+func (r *VerificationCodeRepository) InvalidateAll(userID int, purpose model.VerificationPurpose) error {
+	query := `
+		UPDATE verification_codes
+		SET consumed_at = $3
+		WHERE user_id = $1 AND purpose = $2 AND consumed_at IS NULL
+	`
+
+	if _, err := r.db.Exec(query, userID, purpose, time.Now().UTC()); err != nil {
+		log.Printf("Error invalidating verification codes for user ID %d: %v", userID, err)
+		return fmt.Errorf("failed to invalidate verification codes: %w", err)
+	}
+
+	return nil
+}
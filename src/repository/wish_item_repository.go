@@ -0,0 +1,477 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/lib/pq"
+)
+
+// WishItemRepository implements the model.WishItemRepository interface
+type WishItemRepository struct {
+	db *DB
+}
+
+// NewWishItemRepository creates a new instance of WishItemRepository
+func NewWishItemRepository(db *DB) model.WishItemRepository {
+	return &WishItemRepository{
+		db: db,
+	}
+}
+
+// wishItemColumns lists the columns selected for a wish item from the unaliased wish_items
+// table, its tags included
+const wishItemColumns = `id, wishlist_id, title, description, url, price_minor, currency, priority, quantity, position,
+	preferred_variant, ship_to, gift_wrap, image_key, created_at, updated_at, translations,
+	price_alert_below_minor, price_checked_at, image_alt, received_quantity, category,
+	ARRAY(SELECT t.name FROM wish_item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = wish_items.id ORDER BY t.name)`
+
+// wishItemPrice is the price of a wish item in major units, for sorting and for filters given in
+// them; prices are stored in the minor unit of each item's currency
+var wishItemPrice = "(price_minor::numeric / " + currency.SQLScale("currency") + ")"
+
+// scanWishItem scans a wish item row into a model
+func scanWishItem(scanner interface{ Scan(...interface{}) error }) (*model.WishItem, error) {
+	item := &model.WishItem{}
+	err := scanner.Scan(
+		&item.ID,
+		&item.WishlistID,
+		&item.Title,
+		&item.Description,
+		&item.URL,
+		&item.PriceMinor,
+		&item.Currency,
+		&item.Priority,
+		&item.Quantity,
+		&item.Position,
+		&item.PreferredVariant,
+		&item.ShipTo,
+		&item.GiftWrap,
+		&item.ImageKey,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+		&item.Translations,
+		&item.PriceAlertBelowMinor,
+		&item.PriceCheckedAt,
+		&item.ImageAlt,
+		&item.ReceivedQuantity,
+		&item.Category,
+		(*pq.StringArray)(&item.Tags),
+	)
+	return item, err
+}
+
+// Create adds a new item at the end of its wishlist. The decimal price columns are written too
+// while application versions that read them may still be running.
+func (r *WishItemRepository) Create(item *model.WishItem) error {
+	query := `
+		INSERT INTO wish_items (wishlist_id, title, description, url, price_minor, currency, priority, quantity, position,
+			preferred_variant, ship_to, gift_wrap, created_at, updated_at, translations, price_alert_below_minor, category,
+			price, price_alert_below)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8,
+			(SELECT COALESCE(MAX(position) + 1, 0) FROM wish_items WHERE wishlist_id = $1),
+			$9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, position
+	`
+
+	err := r.db.QueryRowWrite(
+		query,
+		item.WishlistID,
+		item.Title,
+		item.Description,
+		item.URL,
+		item.PriceMinor,
+		item.Currency,
+		item.Priority,
+		item.Quantity,
+		item.PreferredVariant,
+		item.ShipTo,
+		item.GiftWrap,
+		item.CreatedAt,
+		item.UpdatedAt,
+		item.Translations,
+		item.PriceAlertBelowMinor,
+		item.Category,
+		item.Amount().Major(),
+		item.PriceAlertBelow(),
+	).Scan(&item.ID, &item.Position)
+
+	if err != nil {
+		log.Printf("Error creating wish item: %v", err)
+		return fmt.Errorf("failed to create wish item: %w", err)
+	}
+
+	log.Printf("Wish item created successfully with ID: %d", item.ID)
+	return nil
+}
+
+// GetByID retrieves a wish item by its ID
+func (r *WishItemRepository) GetByID(id int) (*model.WishItem, error) {
+	query := `SELECT ` + wishItemColumns + ` FROM wish_items WHERE id = $1`
+
+	item, err := scanWishItem(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("wish item with ID %d not found", id)
+		}
+		log.Printf("Error getting wish item by ID %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get wish item: %w", err)
+	}
+
+	return item, nil
+}
+
+// wishItemListSpec describes the sorting and filtering accepted when listing wish items
+var wishItemListSpec = listSpec{
+	sorts: map[string]string{
+		"position":   "position",
+		"priority":   "priority",
+		"price":      wishItemPrice,
+		"title":      "title",
+		"created_at": "created_at",
+	},
+	defaultSort: "position",
+	tieBreaker:  "id",
+	filters: map[string]string{
+		"title":    "title ILIKE '%' || ? || '%'",
+		"currency": "currency = UPPER(?)",
+		"priority": "priority::text = ?",
+		"category": "category = ?",
+		"tag":      "EXISTS (SELECT 1 FROM wish_item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = wish_items.id AND t.name = LOWER(?))",
+
+		// Price and availability filters for givers; reservations are matched through their
+		// unique (item_id, user_id) index
+		"min_price": wishItemPrice + " >= ?::numeric",
+		"max_price": wishItemPrice + " <= ?::numeric",
+		"available": "(quantity - received_quantity > (SELECT COALESCE(SUM(r.quantity), 0) FROM reservations r WHERE r.item_id = wish_items.id)) = ?::boolean",
+		"unclaimed": "(NOT EXISTS (SELECT 1 FROM reservations r WHERE r.item_id = wish_items.id)) = ?::boolean",
+	},
+}
+
+// ListByWishlist retrieves a page of the items of a wishlist, in display order by default
+func (r *WishItemRepository) ListByWishlist(wishlistID int, opts model.ListOptions) ([]*model.WishItem, *model.PageInfo, error) {
+	q := selectFrom("wish_items", wishItemColumns).Where("wishlist_id = ?", wishlistID)
+	wishItemListSpec.apply(q, opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting wish items for wishlist ID %d: %v", wishlistID, err)
+		return nil, nil, fmt.Errorf("failed to count wish items: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing wish items for wishlist ID %d: %v", wishlistID, err)
+		return nil, nil, fmt.Errorf("failed to list wish items: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var items []*model.WishItem
+	for rows.Next() {
+		item, err := scanWishItem(rows)
+		if err != nil {
+			log.Printf("Error scanning wish item row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan wish item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over wish item rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over wish items: %w", err)
+	}
+
+	return items, model.NewPageInfo(opts, total, len(items)), nil
+}
+
+// Update updates an existing wish item, writing the decimal price columns too like Create
+func (r *WishItemRepository) Update(item *model.WishItem) error {
+	query := `
+		UPDATE wish_items
+		SET title = $2, description = $3, url = $4, price_minor = $5, currency = $6,
+			priority = $7, quantity = $8, preferred_variant = $9, ship_to = $10, gift_wrap = $11,
+			updated_at = $12, translations = $13, price_alert_below_minor = $14, image_alt = $15,
+			received_quantity = $16, category = $17, price = $18, price_alert_below = $19
+		WHERE id = $1
+	`
+
+	item.BeforeUpdate()
+	result, err := r.db.Exec(
+		query,
+		item.ID,
+		item.Title,
+		item.Description,
+		item.URL,
+		item.PriceMinor,
+		item.Currency,
+		item.Priority,
+		item.Quantity,
+		item.PreferredVariant,
+		item.ShipTo,
+		item.GiftWrap,
+		item.UpdatedAt,
+		item.Translations,
+		item.PriceAlertBelowMinor,
+		item.ImageAlt,
+		item.ReceivedQuantity,
+		item.Category,
+		item.Amount().Major(),
+		item.PriceAlertBelow(),
+	)
+	if err != nil {
+		log.Printf("Error updating wish item with ID %d: %v", item.ID, err)
+		return fmt.Errorf("failed to update wish item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for wish item update: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("wish item with ID %d not found", item.ID)
+	}
+
+	log.Printf("Wish item with ID %d updated successfully", item.ID)
+	return nil
+}
+
+// Delete removes a wish item
+func (r *WishItemRepository) Delete(id int) error {
+	query := `DELETE FROM wish_items WHERE id = $1`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		log.Printf("Error deleting wish item with ID %d: %v", id, err)
+		return fmt.Errorf("failed to delete wish item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for wish item deletion: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("wish item with ID %d not found", id)
+	}
+
+	log.Printf("Wish item with ID %d deleted successfully", id)
+	return nil
+}
+
+// Reorder sets the item positions of a wishlist to match the given order.
+// Every item ID must belong to the wishlist; the whole reorder runs in one transaction.
+func (r *WishItemRepository) Reorder(wishlistID int, itemIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting reorder transaction: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back reorder transaction: %v", rollbackErr)
+		}
+	}()
+
+	now := time.Now().UTC()
+	for position, itemID := range itemIDs {
+		result, err := tx.Exec(
+			`UPDATE wish_items SET position = $3, updated_at = $4 WHERE id = $1 AND wishlist_id = $2`,
+			itemID, wishlistID, position, now,
+		)
+		if err != nil {
+			log.Printf("Error reordering wish item %d: %v", itemID, err)
+			return fmt.Errorf("failed to reorder wish items: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("wish item with ID %d not found in wishlist %d", itemID, wishlistID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing reorder transaction: %v", err)
+		return fmt.Errorf("failed to commit reorder: %w", err)
+	}
+
+	log.Printf("Reordered %d items in wishlist %d", len(itemIDs), wishlistID)
+	return nil
+}
+
+// SetImage stores the storage key of a wish item's image and its alternative text; an empty key removes it
+func (r *WishItemRepository) SetImage(itemID int, key, alt string) error {
+	query := `
+		UPDATE wish_items
+		SET image_key = $2, image_alt = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(query, itemID, key, alt, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error setting image for wish item ID %d: %v", itemID, err)
+		return fmt.Errorf("failed to set wish item image: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for wish item image update: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("wish item with ID %d not found", itemID)
+	}
+
+	return nil
+}
+
+// SetTags replaces the tags of a wish item, adding the tags no item carried yet. Tags must be
+// normalized.
+func (r *WishItemRepository) SetTags(itemID int, tags []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting tagging transaction: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back tagging transaction: %v", rollbackErr)
+		}
+	}()
+
+	if _, err := tx.Exec(`DELETE FROM wish_item_tags WHERE item_id = $1`, itemID); err != nil {
+		log.Printf("Error clearing tags of wish item %d: %v", itemID, err)
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+
+	if len(tags) > 0 {
+		_, err := tx.Exec(`INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`, pq.Array(tags))
+		if err != nil {
+			log.Printf("Error adding tags of wish item %d: %v", itemID, err)
+			return fmt.Errorf("failed to add tags: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO wish_item_tags (item_id, tag_id)
+			SELECT $1, id FROM tags WHERE name = ANY($2)
+		`, itemID, pq.Array(tags))
+		if err != nil {
+			log.Printf("Error tagging wish item %d: %v", itemID, err)
+			return fmt.Errorf("failed to tag wish item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing tags of wish item %d: %v", itemID, err)
+		return fmt.Errorf("failed to commit tags: %w", err)
+	}
+
+	return nil
+}
+
+// Stats aggregates the items of a wishlist by priority and, for priced items, by currency.
+// The total value counts every unit wished for.
+func (r *WishItemRepository) Stats(wishlistID int) (*model.WishlistStats, error) {
+	stats := &model.WishlistStats{
+		ByPriority: make(map[int]int),
+		ByCategory: make(map[model.WishItemCategory]int),
+		Currencies: []model.CurrencyStats{},
+	}
+	for priority := model.WishItemMinPriority; priority <= model.WishItemMaxPriority; priority++ {
+		stats.ByPriority[priority] = 0
+	}
+	for _, category := range model.WishItemCategories {
+		stats.ByCategory[category] = 0
+	}
+
+	rows, err := r.db.Query(`
+		SELECT priority, category, COUNT(*), COALESCE(SUM(quantity), 0)
+		FROM wish_items
+		WHERE wishlist_id = $1
+		GROUP BY priority, category
+	`, wishlistID)
+	if err != nil {
+		log.Printf("Error aggregating wish items of wishlist %d by priority and category: %v", wishlistID, err)
+		return nil, fmt.Errorf("failed to aggregate wish items: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		var priority, count, quantity int
+		var category model.WishItemCategory
+		if err := rows.Scan(&priority, &category, &count, &quantity); err != nil {
+			log.Printf("Error scanning wish item priority row: %v", err)
+			return nil, fmt.Errorf("failed to scan wish item aggregate: %w", err)
+		}
+		stats.ByPriority[priority] += count
+		if category == "" {
+			stats.Uncategorized += count
+		} else {
+			stats.ByCategory[category] += count
+		}
+		stats.ItemCount += count
+		stats.TotalQuantity += quantity
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over wish item priority rows: %v", err)
+		return nil, fmt.Errorf("error iterating over wish item aggregates: %w", err)
+	}
+
+	currencyRows, err := r.db.Query(`
+		SELECT currency, COUNT(*), SUM(price_minor * quantity), ROUND(AVG(price_minor))
+		FROM wish_items
+		WHERE wishlist_id = $1 AND price_minor > 0
+		GROUP BY currency
+		ORDER BY currency
+	`, wishlistID)
+	if err != nil {
+		log.Printf("Error aggregating wish items of wishlist %d by currency: %v", wishlistID, err)
+		return nil, fmt.Errorf("failed to aggregate wish items: %w", err)
+	}
+	defer func() {
+		if closeErr := currencyRows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	for currencyRows.Next() {
+		var byCurrency model.CurrencyStats
+		var averageMinor int64
+		if err := currencyRows.Scan(&byCurrency.Currency, &byCurrency.ItemCount, &byCurrency.TotalValueMinor, &averageMinor); err != nil {
+			log.Printf("Error scanning wish item currency row: %v", err)
+			return nil, fmt.Errorf("failed to scan wish item aggregate: %w", err)
+		}
+		byCurrency.TotalValue = currency.FromMinor(byCurrency.TotalValueMinor, byCurrency.Currency)
+		byCurrency.AveragePrice = currency.FromMinor(averageMinor, byCurrency.Currency)
+		stats.Currencies = append(stats.Currencies, byCurrency)
+	}
+
+	if err = currencyRows.Err(); err != nil {
+		log.Printf("Error iterating over wish item currency rows: %v", err)
+		return nil, fmt.Errorf("error iterating over wish item aggregates: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/lib/pq"
+)
+
+// WishlistImportRepository implements the model.WishlistImportRepository interface
+type WishlistImportRepository struct {
+	db *DB
+}
+
+// NewWishlistImportRepository creates a new instance of WishlistImportRepository
+func NewWishlistImportRepository(db *DB) model.WishlistImportRepository {
+	return &WishlistImportRepository{
+		db: db,
+	}
+}
+
+// wishlistImportColumns lists the columns returned for an import
+const wishlistImportColumns = `id, user_id, wishlist_id, source, source_url, payload, status, total_rows,
+	processed_rows, imported_rows, row_errors, error, created_at, started_at, finished_at`
+
+// scanWishlistImport scans a row of wishlistImportColumns
+func scanWishlistImport(row interface{ Scan(...interface{}) error }) (*model.WishlistImport, error) {
+	imp := &model.WishlistImport{}
+	err := row.Scan(
+		&imp.ID,
+		&imp.UserID,
+		&imp.WishlistID,
+		&imp.Source,
+		&imp.SourceURL,
+		&imp.Payload,
+		&imp.Status,
+		&imp.TotalRows,
+		&imp.ProcessedRows,
+		&imp.ImportedRows,
+		&imp.RowErrors,
+		&imp.Error,
+		&imp.CreatedAt,
+		&imp.StartedAt,
+		&imp.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return imp, nil
+}
+
+// Create queues an import. When wishlist is not nil it is a new wishlist created in the same
+// transaction, which the import fills, so no empty wishlist is left behind when the import cannot
+// start. It returns model.ErrImportInProgress when the user has a pending or running import.
+func (r *WishlistImportRepository) Create(imp *model.WishlistImport, wishlist *model.Wishlist) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Printf("Error starting wishlist import creation: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Error rolling back wishlist import creation: %v", rollbackErr)
+		}
+	}()
+
+	if wishlist != nil {
+		if err := tx.QueryRow(insertWishlist, wishlistInsertArgs(wishlist)...).Scan(&wishlist.ID, &wishlist.PublicID); err != nil {
+			log.Printf("Error creating wishlist for import: %v", err)
+			return fmt.Errorf("failed to create wishlist: %w", err)
+		}
+		imp.WishlistID = wishlist.ID
+	}
+
+	query := `
+		INSERT INTO wishlist_imports (user_id, wishlist_id, source, source_url, payload, status,
+			total_rows, row_errors, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	err = tx.QueryRow(
+		query,
+		imp.UserID,
+		imp.WishlistID,
+		imp.Source,
+		imp.SourceURL,
+		imp.Payload,
+		imp.Status,
+		imp.TotalRows,
+		imp.RowErrors,
+		imp.CreatedAt,
+	).Scan(&imp.ID)
+	if err != nil {
+		// idx_wishlist_imports_one_active allows one pending or running import per user
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_wishlist_imports_one_active" {
+			return model.ErrImportInProgress
+		}
+		log.Printf("Error creating wishlist import: %v", err)
+		return fmt.Errorf("failed to create wishlist import: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing wishlist import creation: %v", err)
+		return fmt.Errorf("failed to commit wishlist import: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an import by its ID
+func (r *WishlistImportRepository) GetByID(id int) (*model.WishlistImport, error) {
+	query := `SELECT ` + wishlistImportColumns + ` FROM wishlist_imports WHERE id = $1`
+
+	imp, err := scanWishlistImport(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("wishlist import with ID %d not found", id)
+		}
+		log.Printf("Error getting wishlist import by ID %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get wishlist import: %w", err)
+	}
+
+	return imp, nil
+}
+
+// ClaimNext marks the oldest waiting import running and returns it, or nil when there is none.
+// Imports running since before staleBefore were abandoned by a stopped instance and are claimed
+// again. Instances skip the imports claimed by the others.
+func (r *WishlistImportRepository) ClaimNext(now, staleBefore time.Time) (*model.WishlistImport, error) {
+	query := `
+		UPDATE wishlist_imports SET status = 'running', started_at = $1
+		WHERE id = (
+			SELECT id FROM wishlist_imports
+			WHERE status = 'pending' OR (status = 'running' AND started_at < $2)
+			ORDER BY created_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + wishlistImportColumns
+
+	imp, err := scanWishlistImport(r.db.QueryRowWrite(query, now, staleBefore))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		log.Printf("Error claiming wishlist import: %v", err)
+		return nil, fmt.Errorf("failed to claim wishlist import: %w", err)
+	}
+
+	return imp, nil
+}
+
+// UpdateProgress saves the row counts and row errors of a running import
+func (r *WishlistImportRepository) UpdateProgress(imp *model.WishlistImport) error {
+	query := `
+		UPDATE wishlist_imports
+		SET total_rows = $2, processed_rows = $3, imported_rows = $4, row_errors = $5
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(query, imp.ID, imp.TotalRows, imp.ProcessedRows, imp.ImportedRows, imp.RowErrors)
+	if err != nil {
+		log.Printf("Error updating progress of wishlist import %d: %v", imp.ID, err)
+		return fmt.Errorf("failed to update wishlist import progress: %w", err)
+	}
+	return nil
+}
+
+// Finish saves the final status and counts of an import and drops its payload, which is no
+// longer needed
+func (r *WishlistImportRepository) Finish(imp *model.WishlistImport) error {
+	query := `
+		UPDATE wishlist_imports
+		SET status = $2, error = $3, total_rows = $4, processed_rows = $5, imported_rows = $6,
+			row_errors = $7, finished_at = $8, payload = ''
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(query, imp.ID, imp.Status, imp.Error, imp.TotalRows, imp.ProcessedRows,
+		imp.ImportedRows, imp.RowErrors, imp.FinishedAt)
+	if err != nil {
+		log.Printf("Error finishing wishlist import %d: %v", imp.ID, err)
+		return fmt.Errorf("failed to finish wishlist import: %w", err)
+	}
+	imp.Payload = ""
+	return nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/alex-1900/wishlist/src/model"
+)
+
+// WishlistMemberRepository implements the model.WishlistMemberRepository interface
+type WishlistMemberRepository struct {
+	db *DB
+}
+
+// NewWishlistMemberRepository creates a new instance of WishlistMemberRepository
+func NewWishlistMemberRepository(db *DB) model.WishlistMemberRepository {
+	return &WishlistMemberRepository{
+		db: db,
+	}
+}
+
+// Invite invites a user to share the ownership of a wishlist. Inviting a member again changes
+// their role, without asking them to accept again.
+func (r *WishlistMemberRepository) Invite(member *model.WishlistMember) error {
+	query := `
+		INSERT INTO wishlist_members (wishlist_id, user_id, role, invited_by, invited_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (wishlist_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		RETURNING invited_by, invited_at, accepted_at
+	`
+
+	err := r.db.QueryRowWrite(query, member.WishlistID, member.UserID, member.Role, member.InvitedBy, member.InvitedAt).
+		Scan(&member.InvitedBy, &member.InvitedAt, &member.AcceptedAt)
+	if err != nil {
+		log.Printf("Error inviting user %d to wishlist %d: %v", member.UserID, member.WishlistID, err)
+		return fmt.Errorf("failed to invite wishlist member: %w", err)
+	}
+	return nil
+}
+
+// Accept accepts the user's pending invitation to a wishlist
+func (r *WishlistMemberRepository) Accept(wishlistID, userID int) error {
+	result, err := r.db.Exec(`
+		UPDATE wishlist_members SET accepted_at = CURRENT_TIMESTAMP
+		WHERE wishlist_id = $1 AND user_id = $2 AND accepted_at IS NULL
+	`, wishlistID, userID)
+	if err != nil {
+		log.Printf("Error accepting invitation of user %d to wishlist %d: %v", userID, wishlistID, err)
+		return fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for invitation acceptance: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no pending invitation of user %d to wishlist %d", userID, wishlistID)
+	}
+
+	return nil
+}
+
+// Remove removes a member of a wishlist, or withdraws their pending invitation
+func (r *WishlistMemberRepository) Remove(wishlistID, userID int) error {
+	result, err := r.db.Exec(`DELETE FROM wishlist_members WHERE wishlist_id = $1 AND user_id = $2`, wishlistID, userID)
+	if err != nil {
+		log.Printf("Error removing user %d from wishlist %d: %v", userID, wishlistID, err)
+		return fmt.Errorf("failed to remove wishlist member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for wishlist member removal: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user %d is not a member of wishlist %d", userID, wishlistID)
+	}
+
+	return nil
+}
+
+// GetRole returns the role of an accepted member of a wishlist, empty for anyone else
+func (r *WishlistMemberRepository) GetRole(wishlistID, userID int) (model.WishlistRole, error) {
+	var role model.WishlistRole
+	err := r.db.QueryRow(
+		`SELECT role FROM wishlist_members WHERE wishlist_id = $1 AND user_id = $2 AND accepted_at IS NOT NULL`,
+		wishlistID, userID,
+	).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		log.Printf("Error getting role of user %d on wishlist %d: %v", userID, wishlistID, err)
+		return "", fmt.Errorf("failed to get wishlist role: %w", err)
+	}
+	return role, nil
+}
+
+// ListByWishlist retrieves the members of a wishlist and its pending invitations, in the order
+// they were invited
+func (r *WishlistMemberRepository) ListByWishlist(wishlistID int) ([]*model.WishlistMember, error) {
+	query := `
+		SELECT wm.wishlist_id, wm.user_id, u.username, wm.role, wm.invited_by, wm.invited_at, wm.accepted_at
+		FROM wishlist_members wm
+		JOIN users u ON u.id = wm.user_id
+		WHERE wm.wishlist_id = $1 AND u.deleted_at IS NULL
+		ORDER BY wm.invited_at
+	`
+
+	rows, err := r.db.Query(query, wishlistID)
+	if err != nil {
+		log.Printf("Error listing members of wishlist %d: %v", wishlistID, err)
+		return nil, fmt.Errorf("failed to list wishlist members: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var members []*model.WishlistMember
+	for rows.Next() {
+		member := &model.WishlistMember{}
+		if err := rows.Scan(&member.WishlistID, &member.UserID, &member.Username, &member.Role,
+			&member.InvitedBy, &member.InvitedAt, &member.AcceptedAt); err != nil {
+			log.Printf("Error scanning wishlist member row: %v", err)
+			return nil, fmt.Errorf("failed to scan wishlist member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over wishlist member rows: %v", err)
+		return nil, fmt.Errorf("error iterating over wishlist members: %w", err)
+	}
+
+	return members, nil
+}
+
+// ListInvitations retrieves the pending invitations of a user, the most recent first
+func (r *WishlistMemberRepository) ListInvitations(userID int) ([]*model.WishlistInvitation, error) {
+	query := `
+		SELECT w.public_id, w.title, wm.role, COALESCE(inviter.username, ''), wm.invited_at
+		FROM wishlist_members wm
+		JOIN wishlists w ON w.id = wm.wishlist_id
+		LEFT JOIN users inviter ON inviter.id = wm.invited_by AND inviter.deleted_at IS NULL
+		WHERE wm.user_id = $1 AND wm.accepted_at IS NULL
+		ORDER BY wm.invited_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		log.Printf("Error listing wishlist invitations of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to list wishlist invitations: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	invitations := []*model.WishlistInvitation{}
+	for rows.Next() {
+		invitation := &model.WishlistInvitation{}
+		if err := rows.Scan(&invitation.WishlistID, &invitation.WishlistTitle, &invitation.Role,
+			&invitation.InvitedByUsername, &invitation.InvitedAt); err != nil {
+			log.Printf("Error scanning wishlist invitation row: %v", err)
+			return nil, fmt.Errorf("failed to scan wishlist invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over wishlist invitation rows: %v", err)
+		return nil, fmt.Errorf("error iterating over wishlist invitations: %w", err)
+	}
+
+	return invitations, nil
+}
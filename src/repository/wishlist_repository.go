@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/lib/pq"
+)
+
+// WishlistRepository implements the model.WishlistRepository interface
+type WishlistRepository struct {
+	db *DB
+}
+
+// NewWishlistRepository creates a new instance of WishlistRepository
+func NewWishlistRepository(db *DB) model.WishlistRepository {
+	return &WishlistRepository{
+		db: db,
+	}
+}
+
+// wishlistColumns lists the columns selected for a wishlist
+const wishlistColumns = `id, public_id, user_id, title, visibility, created_at, updated_at, share_token, language, translations,
+	event_type, event_date, archived_at, shipping_address`
+
+// scanWishlist scans a wishlist row into a model
+func scanWishlist(scanner interface{ Scan(...interface{}) error }) (*model.Wishlist, error) {
+	wishlist := &model.Wishlist{}
+	err := scanner.Scan(
+		&wishlist.ID,
+		&wishlist.PublicID,
+		&wishlist.UserID,
+		&wishlist.Title,
+		&wishlist.Visibility,
+		&wishlist.CreatedAt,
+		&wishlist.UpdatedAt,
+		&wishlist.ShareToken,
+		&wishlist.Language,
+		&wishlist.Translations,
+		&wishlist.EventType,
+		&wishlist.EventDate,
+		&wishlist.ArchivedAt,
+		&wishlist.ShippingAddress,
+	)
+	return wishlist, err
+}
+
+// Create creates a new wishlist in the database
+func (r *WishlistRepository) Create(wishlist *model.Wishlist) error {
+	var id int
+	err := r.db.QueryRowWrite(insertWishlist, wishlistInsertArgs(wishlist)...).Scan(&id, &wishlist.PublicID)
+
+	if err != nil {
+		log.Printf("Error creating wishlist: %v", err)
+		return fmt.Errorf("failed to create wishlist: %w", err)
+	}
+
+	wishlist.ID = id
+	log.Printf("Wishlist created successfully with ID: %d", id)
+	return nil
+}
+
+// insertWishlist creates a wishlist from wishlistInsertArgs, returning its ID and public ID
+const insertWishlist = `
+	INSERT INTO wishlists (user_id, title, visibility, created_at, updated_at, language, translations,
+		event_type, event_date, shipping_address)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING id, public_id
+`
+
+// wishlistInsertArgs returns the arguments of insertWishlist
+func wishlistInsertArgs(wishlist *model.Wishlist) []interface{} {
+	return []interface{}{
+		wishlist.UserID,
+		wishlist.Title,
+		wishlist.Visibility,
+		wishlist.CreatedAt,
+		wishlist.UpdatedAt,
+		wishlist.Language,
+		wishlist.Translations,
+		wishlist.EventType,
+		wishlist.EventDate,
+		wishlist.ShippingAddress,
+	}
+}
+
+// GetByID retrieves a wishlist by its ID
+func (r *WishlistRepository) GetByID(id int) (*model.Wishlist, error) {
+	query := `
+		SELECT ` + wishlistColumns + `
+		FROM wishlists
+		WHERE id = $1
+	`
+
+	wishlist, err := scanWishlist(r.db.QueryRow(query, id))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("wishlist with ID %d not found", id)
+		}
+		log.Printf("Error getting wishlist by ID %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get wishlist: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// GetByPublicID retrieves a wishlist by its public ID
+func (r *WishlistRepository) GetByPublicID(publicID string) (*model.Wishlist, error) {
+	if !model.IsPublicID(publicID) {
+		return nil, fmt.Errorf("wishlist with public ID %q not found", publicID)
+	}
+
+	query := `
+		SELECT ` + wishlistColumns + `
+		FROM wishlists
+		WHERE public_id = $1
+	`
+
+	wishlist, err := scanWishlist(r.db.QueryRow(query, publicID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("wishlist with public ID %q not found", publicID)
+		}
+		log.Printf("Error getting wishlist by public ID: %v", err)
+		return nil, fmt.Errorf("failed to get wishlist: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// GetByShareToken retrieves a wishlist by the token of its share link
+func (r *WishlistRepository) GetByShareToken(token string) (*model.Wishlist, error) {
+	query := `
+		SELECT ` + wishlistColumns + `
+		FROM wishlists
+		WHERE share_token = $1
+	`
+
+	wishlist, err := scanWishlist(r.db.QueryRow(query, token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("wishlist with this share token not found")
+		}
+		log.Printf("Error getting wishlist by share token: %v", err)
+		return nil, fmt.Errorf("failed to get wishlist: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// wishlistListSpec describes the sorting and filtering accepted when listing wishlists
+var wishlistListSpec = listSpec{
+	sorts: map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"title":      "title",
+	},
+	defaultSort: "created_at",
+	defaultDesc: true,
+	tieBreaker:  "id",
+	filters: map[string]string{
+		"title":      "title ILIKE '%' || ? || '%'",
+		"visibility": "visibility = ?",
+		"event_type": "event_type = ?",
+		"archived":   "(archived_at IS NOT NULL) = (? = 'true')",
+	},
+}
+
+// ListByUser retrieves a page of wishlists a user created or accepted to share the ownership of
+func (r *WishlistRepository) ListByUser(userID int, opts model.ListOptions) ([]*model.Wishlist, *model.PageInfo, error) {
+	q := selectFrom("wishlists", wishlistColumns).
+		Where(`(user_id = ? OR id IN (
+			SELECT wishlist_id FROM wishlist_members WHERE user_id = ? AND accepted_at IS NOT NULL
+		))`, userID, userID)
+	return r.list(q, opts)
+}
+
+// ListVisibleByUser retrieves a page of the owner's wishlists that carry one of the given
+// visibility levels or are shared to a group the viewer belongs to
+func (r *WishlistRepository) ListVisibleByUser(ownerID, viewerID int, visibilities []model.WishlistVisibility, opts model.ListOptions) ([]*model.Wishlist, *model.PageInfo, error) {
+	levels := make([]string, len(visibilities))
+	for i, visibility := range visibilities {
+		levels[i] = string(visibility)
+	}
+
+	q := selectFrom("wishlists", wishlistColumns).
+		Where("user_id = ?", ownerID).
+		Where(`(visibility = ANY(?) OR id IN (
+			SELECT gw.wishlist_id FROM group_wishlists gw
+			JOIN group_members gm ON gm.group_id = gw.group_id
+			WHERE gm.user_id = ?
+		))`, pq.Array(levels), viewerID)
+	return r.list(q, opts)
+}
+
+// list retrieves a page of the wishlists matching the query's conditions
+func (r *WishlistRepository) list(q *selectQuery, opts model.ListOptions) ([]*model.Wishlist, *model.PageInfo, error) {
+	wishlistListSpec.apply(q, opts)
+
+	var total int
+	countQuery, countArgs := q.BuildCount()
+	if err := r.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		log.Printf("Error counting wishlists: %v", err)
+		return nil, nil, fmt.Errorf("failed to count wishlists: %w", err)
+	}
+
+	query, args := q.Build()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error listing wishlists: %v", err)
+		return nil, nil, fmt.Errorf("failed to list wishlists: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Error closing rows: %v", closeErr)
+		}
+	}()
+
+	var wishlists []*model.Wishlist
+	for rows.Next() {
+		wishlist, err := scanWishlist(rows)
+		if err != nil {
+			log.Printf("Error scanning wishlist row: %v", err)
+			return nil, nil, fmt.Errorf("failed to scan wishlist: %w", err)
+		}
+		wishlists = append(wishlists, wishlist)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating over wishlist rows: %v", err)
+		return nil, nil, fmt.Errorf("error iterating over wishlists: %w", err)
+	}
+
+	return wishlists, model.NewPageInfo(opts, total, len(wishlists)), nil
+}
+
+// Update updates an existing wishlist in the database
+func (r *WishlistRepository) Update(wishlist *model.Wishlist) error {
+	query := `
+		UPDATE wishlists
+		SET title = $2, visibility = $3, updated_at = $4, language = $5, translations = $6,
+			event_type = $7, event_date = $8, archived_at = $9, shipping_address = $10
+		WHERE id = $1
+	`
+
+	wishlist.BeforeUpdate()
+	result, err := r.db.Exec(query, wishlist.ID, wishlist.Title, wishlist.Visibility, wishlist.UpdatedAt, wishlist.Language, wishlist.Translations,
+		wishlist.EventType, wishlist.EventDate, wishlist.ArchivedAt, wishlist.ShippingAddress)
+	if err != nil {
+		log.Printf("Error updating wishlist with ID %d: %v", wishlist.ID, err)
+		return fmt.Errorf("failed to update wishlist: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for wishlist update: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("wishlist with ID %d not found", wishlist.ID)
+	}
+
+	log.Printf("Wishlist with ID %d updated successfully", wishlist.ID)
+	return nil
+}
+
+// SetShareToken replaces the share link token of a wishlist; nil revokes the link
+func (r *WishlistRepository) SetShareToken(id int, token *string) error {
+	result, err := r.db.Exec(`UPDATE wishlists SET share_token = $2 WHERE id = $1`, id, token)
+	if err != nil {
+		log.Printf("Error setting share token of wishlist %d: %v", id, err)
+		return fmt.Errorf("failed to set share token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for share token update: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("wishlist with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// Delete deletes a wishlist from the database
+func (r *WishlistRepository) Delete(id int) error {
+	query := `DELETE FROM wishlists WHERE id = $1`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		log.Printf("Error deleting wishlist with ID %d: %v", id, err)
+		return fmt.Errorf("failed to delete wishlist: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for wishlist deletion: %v", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("wishlist with ID %d not found", id)
+	}
+
+	log.Printf("Wishlist with ID %d deleted successfully", id)
+	return nil
+}
+
+// ArchiveEnded archives the wishlists whose event date is before the given day and that are not
+// archived yet, returning how many were archived
+func (r *WishlistRepository) ArchiveEnded(before time.Time, archivedAt time.Time) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE wishlists SET archived_at = $2
+		WHERE archived_at IS NULL AND event_date < $1
+	`, before, archivedAt)
+	if err != nil {
+		log.Printf("Error archiving wishlists with an event before %s: %v", before.Format(model.EventDateLayout), err)
+		return 0, fmt.Errorf("failed to archive wishlists: %w", err)
+	}
+
+	archived, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error getting rows affected for wishlist archival: %v", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return archived, nil
+}
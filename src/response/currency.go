@@ -0,0 +1,18 @@
+package response
+
+import (
+	"strings"
+
+	"github.com/alex-1900/wishlist/src/currency"
+	"github.com/gin-gonic/gin"
+)
+
+// DisplayCurrency returns the currency the client wants prices converted to, from the
+// display_currency query parameter, or "" when none or no valid ISO 4217 code was given
+func DisplayCurrency(ctx *gin.Context) string {
+	code := strings.ToUpper(ctx.Query("display_currency"))
+	if !currency.IsCode(code) {
+		return ""
+	}
+	return code
+}
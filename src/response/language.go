@@ -0,0 +1,25 @@
+package response
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// Languages returns the languages the client prefers, best first: the one of the lang query
+// parameter, or else those of the Accept-Language header. Responses localized with them vary by
+// Accept-Language, which it tells caches.
+func Languages(ctx *gin.Context) []language.Tag {
+	ctx.Writer.Header().Add("Vary", "Accept-Language")
+
+	if lang := ctx.Query("lang"); lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			return []language.Tag{tag}
+		}
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		return nil
+	}
+	return tags
+}
@@ -0,0 +1,25 @@
+package response
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WishlistLinks links a wishlist response, by the public ID of the wishlist, to its items
+func WishlistLinks(publicID string) Option {
+	return func(ctx *gin.Context, envelope *Envelope) {
+		envelope.Links["view"] = "/view-wishlist?wishlist_id=" + url.QueryEscape(publicID)
+		envelope.Links["items"] = "/list-wish-items?wishlist_id=" + url.QueryEscape(publicID)
+	}
+}
+
+// GroupLinks links a group response to its members, shared wishlists and polls
+func GroupLinks(groupID int) Option {
+	return func(ctx *gin.Context, envelope *Envelope) {
+		envelope.Links["members"] = fmt.Sprintf("/list-group-members?group_id=%d", groupID)
+		envelope.Links["wishlists"] = fmt.Sprintf("/list-group-wishlists?group_id=%d", groupID)
+		envelope.Links["polls"] = fmt.Sprintf("/list-group-polls?group_id=%d", groupID)
+	}
+}
@@ -0,0 +1,62 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NDJSONContentType is the media type of newline-delimited JSON streams
+const NDJSONContentType = "application/x-ndjson"
+
+// WantsNDJSON reports whether the client asked for a newline-delimited JSON stream
+func WantsNDJSON(ctx *gin.Context) bool {
+	return strings.Contains(ctx.GetHeader("Accept"), NDJSONContentType)
+}
+
+// Stream writes one JSON document per line, flushing each line to the client as it is written.
+// Streams carry no envelope: every line is a bare resource.
+type Stream struct {
+	ctx     *gin.Context
+	encoder *json.Encoder
+	started bool
+}
+
+// NDJSON starts a newline-delimited JSON stream; the headers are sent with the first line
+func NDJSON(ctx *gin.Context) *Stream {
+	return &Stream{ctx: ctx, encoder: json.NewEncoder(ctx.Writer)}
+}
+
+// Write encodes value as the next line of the stream
+func (s *Stream) Write(value interface{}) error {
+	s.start()
+	if err := s.encoder.Encode(value); err != nil {
+		return err
+	}
+	s.ctx.Writer.Flush()
+	return nil
+}
+
+// Started reports whether anything has been sent; until then an error response can still be written
+func (s *Stream) Started() bool {
+	return s.started
+}
+
+// Finish ends the stream, sending the headers if no line was written
+func (s *Stream) Finish() {
+	s.start()
+	s.ctx.Writer.Flush()
+}
+
+// start sends the stream headers once
+func (s *Stream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	s.ctx.Header("Content-Type", NDJSONContentType)
+	s.ctx.Status(http.StatusOK)
+	s.ctx.Writer.WriteHeaderNow()
+}
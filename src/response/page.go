@@ -0,0 +1,30 @@
+package response
+
+import (
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/gin-gonic/gin"
+)
+
+// Page adds the page info to the metadata and links to the first and next pages
+func Page(page *model.PageInfo) Option {
+	return func(ctx *gin.Context, envelope *Envelope) {
+		if page == nil {
+			return
+		}
+		Meta("page", page)(ctx, envelope)
+
+		query := ctx.Request.URL.Query()
+		query.Del("cursor")
+		query.Del("page")
+		first := *ctx.Request.URL
+		first.RawQuery = query.Encode()
+		envelope.Links["first"] = first.RequestURI()
+
+		if page.NextCursor != "" {
+			query.Set("cursor", page.NextCursor)
+			next := *ctx.Request.URL
+			next.RawQuery = query.Encode()
+			envelope.Links["next"] = next.RequestURI()
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package response writes successful responses in the single envelope every endpoint uses:
+//
+//	{"data": ..., "meta": {"message": ..., "page": ...}, "links": {"self": ..., "next": ...}}
+//
+// Errors are written by the apperror middleware instead.
+package response
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Envelope is the body of every successful response
+type Envelope struct {
+	Data  interface{}            `json:"data"`
+	Meta  map[string]interface{} `json:"meta,omitempty"`
+	Links map[string]string      `json:"links,omitempty"`
+}
+
+// Option adds metadata or links to an envelope
+type Option func(ctx *gin.Context, envelope *Envelope)
+
+// OK writes a 200 response
+func OK(ctx *gin.Context, data interface{}, opts ...Option) {
+	write(ctx, http.StatusOK, data, opts)
+}
+
+// Created writes a 201 response
+func Created(ctx *gin.Context, data interface{}, opts ...Option) {
+	write(ctx, http.StatusCreated, data, opts)
+}
+
+// Accepted writes a 202 response, for work that goes on in the background
+func Accepted(ctx *gin.Context, data interface{}, opts ...Option) {
+	write(ctx, http.StatusAccepted, data, opts)
+}
+
+// Message adds a human-readable message to the metadata
+func Message(message string) Option {
+	return Meta("message", message)
+}
+
+// Meta adds a metadata entry
+func Meta(key string, value interface{}) Option {
+	return func(ctx *gin.Context, envelope *Envelope) {
+		if envelope.Meta == nil {
+			envelope.Meta = make(map[string]interface{})
+		}
+		envelope.Meta[key] = value
+	}
+}
+
+// Link adds a link to a related resource
+func Link(rel, href string) Option {
+	return func(ctx *gin.Context, envelope *Envelope) {
+		envelope.Links[rel] = href
+	}
+}
+
+// write builds the envelope and writes it
+func write(ctx *gin.Context, status int, data interface{}, opts []Option) {
+	ctx.JSON(status, build(ctx, data, opts))
+}
+
+// build builds the envelope, linking GET responses to themselves
+func build(ctx *gin.Context, data interface{}, opts []Option) *Envelope {
+	envelope := &Envelope{
+		Data:  data,
+		Links: make(map[string]string),
+	}
+	if ctx.Request.Method == http.MethodGet {
+		envelope.Links["self"] = ctx.Request.URL.RequestURI()
+	}
+
+	for _, opt := range opts {
+		opt(ctx, envelope)
+	}
+
+	if len(envelope.Links) == 0 {
+		envelope.Links = nil
+	}
+
+	return envelope
+}
@@ -0,0 +1,17 @@
+package response
+
+import "github.com/gin-gonic/gin"
+
+// Robots tells search engines whether they may index the page built from the response. When
+// they may not, it sends X-Robots-Tag: noindex and sets meta.noindex, so the web app can add
+// the matching robots meta tag to the page it renders. Decide with access.CanIndexProfile and
+// access.CanIndexWishlist.
+func Robots(indexable bool) Option {
+	return func(ctx *gin.Context, envelope *Envelope) {
+		if indexable {
+			return
+		}
+		ctx.Header("X-Robots-Tag", "noindex")
+		Meta("noindex", true)(ctx, envelope)
+	}
+}
@@ -0,0 +1,175 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ErrNotAmazonWishlist is returned for URLs that are not the page of an Amazon wish list
+var ErrNotAmazonWishlist = errors.New("url is not an Amazon wish list")
+
+// AmazonMaxPages is the number of pages of an Amazon wish list read at most, each listing a few
+// dozen items
+const AmazonMaxPages = 20
+
+// amazonCurrencies maps the Amazon stores to the currency their prices are shown in
+var amazonCurrencies = map[string]string{
+	"amazon.com": "USD", "amazon.ca": "CAD", "amazon.com.mx": "MXN", "amazon.com.br": "BRL",
+	"amazon.co.uk": "GBP", "amazon.de": "EUR", "amazon.fr": "EUR", "amazon.it": "EUR",
+	"amazon.es": "EUR", "amazon.nl": "EUR", "amazon.se": "SEK", "amazon.pl": "PLN",
+	"amazon.co.jp": "JPY", "amazon.in": "INR", "amazon.com.au": "AUD", "amazon.sg": "SGD",
+	"amazon.ae": "AED",
+}
+
+// AmazonWishlist is a public Amazon wish list as shown on its pages
+type AmazonWishlist struct {
+	Title string
+	Items []*AmazonItem
+}
+
+// AmazonItem is an item of an Amazon wish list. Price is 0 when the item is unavailable.
+type AmazonItem struct {
+	Title    string
+	URL      string
+	Price    float64
+	Currency string
+}
+
+// amazonPage is what one page of a wish list holds, with the URL of the next page if any
+type amazonPage struct {
+	title string
+	items []*AmazonItem
+	next  string
+}
+
+// FetchAmazonWishlist reads the items of the public Amazon wish list at rawURL, following its
+// pages up to AmazonMaxPages. Amazon does not offer an API for wish lists, so this reads the
+// HTML of the list and stops working whenever Amazon changes it; pages are not cached.
+func (s *Scraper) FetchAmazonWishlist(ctx context.Context, rawURL string) (*AmazonWishlist, error) {
+	target, code, err := parseAmazonWishlistURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist := &AmazonWishlist{}
+	seen := make(map[string]bool)
+	next := target.String()
+	for pages := 0; next != "" && !seen[next] && pages < AmazonMaxPages; pages++ {
+		seen[next] = true
+
+		page, err := s.fetchAmazonPage(ctx, next, code)
+		if err != nil {
+			return nil, err
+		}
+		if wishlist.Title == "" {
+			wishlist.Title = page.title
+		}
+		wishlist.Items = append(wishlist.Items, page.items...)
+		next = page.next
+	}
+
+	if len(wishlist.Items) == 0 {
+		return nil, errors.New("no items found, the wish list may be private or empty")
+	}
+	return wishlist, nil
+}
+
+// CheckAmazonWishlistURL returns ErrInvalidURL or ErrNotAmazonWishlist when rawURL cannot be
+// read by FetchAmazonWishlist, so requests can be refused before any page is fetched
+func CheckAmazonWishlistURL(rawURL string) error {
+	_, _, err := parseAmazonWishlistURL(rawURL)
+	return err
+}
+
+// parseAmazonWishlistURL parses the URL of a wish list, returning it with the currency of its store
+func parseAmazonWishlistURL(rawURL string) (*url.URL, string, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", ErrInvalidURL
+	}
+	if err := validateURL(target); err != nil {
+		return nil, "", err
+	}
+	code, ok := amazonCurrencies[strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(target.Hostname()), "www."), "smile.")]
+	if !ok || !strings.Contains(target.Path, "/wishlist/") {
+		return nil, "", ErrNotAmazonWishlist
+	}
+	target.Fragment = ""
+	return target, code, nil
+}
+
+// fetchAmazonPage reads one page of a wish list
+func (s *Scraper) fetchAmazonPage(ctx context.Context, pageURL, code string) (*amazonPage, error) {
+	resp, err := s.get(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return parseAmazonPage(io.LimitReader(resp.Body, s.config.MaxBodyBytes), resp.Request.URL, code)
+}
+
+// parseAmazonPage extracts the list name, the items and the link to the next page of a wish list
+// page. Each item is an <li> carrying its price in data-price, holding an <a id="itemName_...">
+// link to the product; the next page is in the showMoreUrl hidden input.
+func parseAmazonPage(r io.Reader, base *url.URL, code string) (*amazonPage, error) {
+	page := &amazonPage{}
+	var price float64
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF && len(page.items) == 0 {
+				return nil, fmt.Errorf("failed to read page: %w", err)
+			}
+			return page, nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			attrs := make(map[string]string, len(token.Attr))
+			for _, attr := range token.Attr {
+				attrs[strings.ToLower(attr.Key)] = attr.Val
+			}
+
+			switch {
+			case attrs["id"] == "profile-list-name":
+				if page.title == "" && tokenizer.Next() == html.TextToken {
+					page.title = strings.TrimSpace(string(tokenizer.Text()))
+				}
+			case token.Data == "li" && attrs["data-price"] != "":
+				// Unavailable items have a price of -Infinity
+				price = 0
+				if value, err := strconv.ParseFloat(attrs["data-price"], 64); err == nil && value > 0 && value < 1e12 {
+					price = value
+				}
+			case token.Data == "a" && strings.HasPrefix(attrs["id"], "itemName_"):
+				title := strings.TrimSpace(attrs["title"])
+				if title == "" && tokenizer.Next() == html.TextToken {
+					title = strings.TrimSpace(string(tokenizer.Text()))
+				}
+				item := &AmazonItem{Title: title, Price: price, Currency: code}
+				if ref, err := url.Parse(attrs["href"]); err == nil && attrs["href"] != "" {
+					resolved := base.ResolveReference(ref)
+					// The query only tracks where the click came from
+					resolved.RawQuery = ""
+					item.URL = resolved.String()
+				}
+				if item.Title != "" {
+					page.items = append(page.items, item)
+				}
+				price = 0
+			case token.Data == "input" && attrs["name"] == "showMoreUrl" && attrs["value"] != "":
+				if ref, err := url.Parse(attrs["value"]); err == nil {
+					page.next = base.ResolveReference(ref).String()
+				}
+			}
+		}
+	}
+}
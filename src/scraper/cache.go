@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"sync"
+	"time"
+)
+
+// cache keeps recently fetched metadata in memory so the same URL is not fetched repeatedly
+type cache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	metadata  *Metadata
+	expiresAt time.Time
+}
+
+func newCache(size int, ttl time.Duration) *cache {
+	return &cache{size: size, ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// get returns a copy of the cached metadata of key if it has not expired
+func (c *cache) get(key string) (*Metadata, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	metadata := *entry.metadata
+	return &metadata, true
+}
+
+// put stores metadata, evicting expired entries and then the oldest one when the cache is full
+func (c *cache) put(key string, metadata *Metadata) {
+	if c.ttl <= 0 || c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= c.size {
+		var oldestKey string
+		var oldest time.Time
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+				continue
+			}
+			if oldestKey == "" || entry.expiresAt.Before(oldest) {
+				oldestKey, oldest = k, entry.expiresAt
+			}
+		}
+		if len(c.entries) >= c.size {
+			delete(c.entries, oldestKey)
+		}
+	}
+
+	stored := *metadata
+	c.entries[key] = cacheEntry{metadata: &stored, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,106 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// parse extracts metadata from the <head> of an HTML document. OpenGraph properties win over
+// their plain HTML and Twitter card counterparts.
+func parse(r io.Reader, base *url.URL) (*Metadata, error) {
+	tags := make(map[string]string)
+	var title string
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF && len(tags) == 0 && title == "" {
+				return nil, fmt.Errorf("failed to read page: %w", err)
+			}
+			return buildMetadata(tags, title, base), nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "meta":
+				collectMeta(token, tags)
+			case "title":
+				if title == "" && tokenizer.Next() == html.TextToken {
+					title = strings.TrimSpace(string(tokenizer.Text()))
+				}
+			case "body":
+				// Everything of interest lives in the head
+				return buildMetadata(tags, title, base), nil
+			}
+		}
+	}
+}
+
+// collectMeta records the first value of each meta property or name
+func collectMeta(token html.Token, tags map[string]string) {
+	var key, content string
+	for _, attr := range token.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "property", "name", "itemprop":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(attr.Val))
+			}
+		case "content":
+			content = strings.TrimSpace(attr.Val)
+		}
+	}
+	if key == "" || content == "" {
+		return
+	}
+	if _, seen := tags[key]; !seen {
+		tags[key] = content
+	}
+}
+
+// buildMetadata picks the best value for each field from the collected tags
+func buildMetadata(tags map[string]string, title string, base *url.URL) *Metadata {
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if value := tags[key]; value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	metadata := &Metadata{
+		Title:       first("og:title", "twitter:title", "name"),
+		Description: first("og:description", "twitter:description", "description"),
+		SiteName:    first("og:site_name", "application-name"),
+		Currency:    strings.ToUpper(first("product:price:currency", "og:price:currency", "pricecurrency")),
+	}
+	if metadata.Title == "" {
+		metadata.Title = title
+	}
+	if !currencyCode.MatchString(metadata.Currency) {
+		metadata.Currency = ""
+	}
+
+	if price, err := strconv.ParseFloat(first("product:price:amount", "og:price:amount", "price"), 64); err == nil && price >= 0 {
+		metadata.Price = price
+	}
+
+	if image := first("og:image:secure_url", "og:image", "og:image:url", "twitter:image", "image"); image != "" {
+		if ref, err := url.Parse(image); err == nil {
+			resolved := base.ResolveReference(ref)
+			if resolved.Scheme == "http" || resolved.Scheme == "https" {
+				metadata.Image = resolved.String()
+			}
+		}
+	}
+
+	return metadata
+}
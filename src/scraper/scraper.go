@@ -0,0 +1,209 @@
+// Package scraper reads product metadata (OpenGraph and similar tags) from web pages
+// so wish items added by URL can be pre-filled.
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// Metadata is what a page says about the product it shows
+type Metadata struct {
+	URL         string  `json:"url"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Image       string  `json:"image"`
+	SiteName    string  `json:"site_name"`
+	Price       float64 `json:"price"`
+	Currency    string  `json:"currency"`
+}
+
+// Config holds the fetch limits of a Scraper
+type Config struct {
+	Timeout      time.Duration // whole request, including reading the body
+	MaxBodyBytes int64         // bytes of HTML read before parsing stops
+	CacheTTL     time.Duration // how long fetched metadata is reused, 0 disables caching
+	CacheSize    int           // maximum number of cached pages
+	UserAgent    string
+}
+
+var (
+	// ErrInvalidURL is returned for URLs that are not absolute http or https URLs
+	ErrInvalidURL = errors.New("url must be an absolute http or https URL")
+	// ErrBlockedAddress is returned for URLs resolving to loopback, private, link-local or other
+	// special-purpose addresses
+	ErrBlockedAddress = errors.New("url resolves to a non-public address")
+)
+
+// Scraper fetches pages and extracts their metadata
+type Scraper struct {
+	config Config
+	client *http.Client
+	cache  *cache
+}
+
+// New creates a Scraper; its HTTP client refuses to connect to non-public addresses
+// so user-supplied URLs cannot reach internal services
+func New(config Config) *Scraper {
+	dialer := &net.Dialer{
+		Timeout: config.Timeout,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip, err := netip.ParseAddr(host); err != nil || !isPublicIP(ip) {
+				return ErrBlockedAddress
+			}
+			return nil
+		},
+	}
+
+	return &Scraper{
+		config: config,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext, Proxy: nil},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return errors.New("too many redirects")
+				}
+				return validateURL(req.URL)
+			},
+		},
+		cache: newCache(config.CacheSize, config.CacheTTL),
+	}
+}
+
+// Fetch returns the metadata of the page at rawURL, from the cache when it was fetched recently
+func (s *Scraper) Fetch(ctx context.Context, rawURL string) (*Metadata, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, ErrInvalidURL
+	}
+	if err := validateURL(target); err != nil {
+		return nil, err
+	}
+	target.Fragment = ""
+	key := target.String()
+
+	if metadata, ok := s.cache.get(key); ok {
+		return metadata, nil
+	}
+
+	resp, err := s.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	metadata, err := parse(io.LimitReader(resp.Body, s.config.MaxBodyBytes), resp.Request.URL)
+	if err != nil {
+		return nil, err
+	}
+	metadata.URL = key
+
+	s.cache.put(key, metadata)
+	return metadata, nil
+}
+
+// get requests the HTML page at rawURL, returning the response of a successful request; the
+// caller closes its body
+func (s *Scraper) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	if s.config.UserAgent != "" {
+		req.Header.Set("User-Agent", s.config.UserAgent)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("page returned status %d", resp.StatusCode)
+	}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		resp.Body.Close()
+		return nil, fmt.Errorf("page is %s, not HTML", mediaType)
+	}
+	return resp, nil
+}
+
+// validateURL only lets absolute http and https URLs through
+func validateURL(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+// blockedPrefixes are the special-purpose ranges of the IANA IPv4 and IPv6 registries that are not
+// globally reachable, or that embed or relay to addresses which may not be
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("10.0.0.0/8"),      // private
+	netip.MustParsePrefix("100.64.0.0/10"),   // shared address space, carrier-grade NAT
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local, cloud metadata services
+	netip.MustParsePrefix("172.16.0.0/12"),   // private
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // private
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, and the limited broadcast address
+
+	netip.MustParsePrefix("::/128"),         // unspecified
+	netip.MustParsePrefix("::1/128"),        // loopback
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("100::/64"),       // discard-only
+	netip.MustParsePrefix("2001::/23"),      // IETF protocol assignments, Teredo included
+	netip.MustParsePrefix("2001:db8::/32"),  // documentation
+	netip.MustParsePrefix("2002::/16"),      // 6to4
+	netip.MustParsePrefix("fc00::/7"),       // unique local
+	netip.MustParsePrefix("fe80::/10"),      // link-local
+	netip.MustParsePrefix("fec0::/10"),      // site-local, deprecated
+	netip.MustParsePrefix("ff00::/8"),       // multicast
+}
+
+// nat64Prefix is the well-known NAT64 prefix, whose addresses end with the IPv4 address they reach
+var nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// isPublicIP reports whether ip is a globally routable unicast address. IPv4-mapped and NAT64
+// addresses are judged by the IPv4 address they carry.
+func isPublicIP(ip netip.Addr) bool {
+	ip = ip.WithZone("").Unmap()
+	if nat64Prefix.Contains(ip) {
+		embedded := ip.As16()
+		return isPublicIP(netip.AddrFrom4([4]byte(embedded[12:])))
+	}
+
+	if !ip.IsGlobalUnicast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
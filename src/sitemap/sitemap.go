@@ -0,0 +1,112 @@
+// Package sitemap renders sitemaps following the sitemaps.org protocol and keeps the latest
+// generated one in memory, split into pages.
+package sitemap
+
+import (
+	"encoding/xml"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// MaxURLsPerPage is the number of URLs the protocol allows in a single sitemap
+const MaxURLsPerPage = 50000
+
+// namespace is the XML namespace of sitemaps and sitemap indexes
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is a page listed in a sitemap
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// Snapshot is a generated sitemap split into pages
+type Snapshot struct {
+	Pages       [][]URL
+	GeneratedAt time.Time
+}
+
+// Store holds the latest snapshot; the refresh job replaces it while requests read it
+type Store struct {
+	current atomic.Pointer[Snapshot]
+}
+
+// NewStore creates a Store without a snapshot
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Load returns the latest snapshot, nil until the first one is stored
+func (s *Store) Load() *Snapshot {
+	return s.current.Load()
+}
+
+// Replace splits urls into pages of at most perPage URLs and makes them the latest snapshot.
+// An empty sitemap still has one empty page, so the index always lists at least one sitemap.
+func (s *Store) Replace(urls []URL, perPage int, generatedAt time.Time) {
+	if perPage <= 0 || perPage > MaxURLsPerPage {
+		perPage = MaxURLsPerPage
+	}
+
+	pages := [][]URL{}
+	for start := 0; start < len(urls); start += perPage {
+		pages = append(pages, urls[start:min(start+perPage, len(urls))])
+	}
+	if len(pages) == 0 {
+		pages = append(pages, nil)
+	}
+
+	s.current.Store(&Snapshot{Pages: pages, GeneratedAt: generatedAt})
+}
+
+// entry is a <url> of a sitemap or a <sitemap> of an index
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+// WritePage writes a sitemap listing urls
+func WritePage(w io.Writer, urls []URL) error {
+	set := urlSet{Xmlns: namespace, URLs: make([]entry, len(urls))}
+	for i, url := range urls {
+		set.URLs[i] = newEntry(url)
+	}
+	return write(w, set)
+}
+
+// WriteIndex writes a sitemap index listing the given sitemaps
+func WriteIndex(w io.Writer, sitemaps []URL) error {
+	index := sitemapIndex{Xmlns: namespace, Sitemaps: make([]entry, len(sitemaps))}
+	for i, sitemap := range sitemaps {
+		index.Sitemaps[i] = newEntry(sitemap)
+	}
+	return write(w, index)
+}
+
+func newEntry(url URL) entry {
+	e := entry{Loc: url.Loc}
+	if !url.LastMod.IsZero() {
+		e.LastMod = url.LastMod.UTC().Format(time.RFC3339)
+	}
+	return e
+}
+
+func write(w io.Writer, document interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(document)
+}
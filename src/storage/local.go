@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalConfig holds the settings of the local disk backend
+type LocalConfig struct {
+	Root      string // directory the files are written to
+	URLPrefix string // path the files are served from, e.g. /uploads
+}
+
+// LocalStorage keeps files on the local disk; the app serves them under URLPrefix
+type LocalStorage struct {
+	config LocalConfig
+}
+
+// NewLocalStorage creates a new LocalStorage
+func NewLocalStorage(config LocalConfig) *LocalStorage {
+	config.URLPrefix = "/" + strings.Trim(config.URLPrefix, "/")
+	return &LocalStorage{config: config}
+}
+
+// Root returns the directory files are written to
+func (s *LocalStorage) Root() string {
+	return s.config.Root
+}
+
+// URLPrefix returns the path files are served from
+func (s *LocalStorage) URLPrefix() string {
+	return s.config.URLPrefix
+}
+
+// Put writes the file atomically so readers never see a partial upload
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the file
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// URL returns the path the file is served from
+func (s *LocalStorage) URL(key string) string {
+	return s.config.URLPrefix + "/" + key
+}
+
+// path maps a key onto the disk, refusing keys that would escape the root directory
+func (s *LocalStorage) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.config.Root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alex-1900/wishlist/src/awssig"
+)
+
+// S3Config holds the settings of an S3-compatible bucket (AWS S3, MinIO, R2, ...)
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.eu-west-1.amazonaws.com or https://minio.internal:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool   // address the bucket as endpoint/bucket instead of bucket.endpoint
+	PublicURL       string // base URL files are served from, e.g. a CDN; defaults to the object URL
+}
+
+// S3Storage keeps files in an S3-compatible bucket, signing requests with SigV4
+type S3Storage struct {
+	config S3Config
+	client *http.Client
+}
+
+// NewS3Storage creates a new S3Storage
+func NewS3Storage(config S3Config) *S3Storage {
+	return &S3Storage{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Put uploads the file with a public-read friendly cache header
+func (s *S3Storage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to build S3 request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Cache-Control", "public, max-age=31536000, immutable")
+
+	return s.do(req, data)
+}
+
+// Delete removes the object; S3 reports success for missing objects as well
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build S3 request: %w", err)
+	}
+	return s.do(req, nil)
+}
+
+// URL returns the public URL of the object
+func (s *S3Storage) URL(key string) string {
+	if s.config.PublicURL != "" {
+		return strings.TrimRight(s.config.PublicURL, "/") + "/" + escapeKey(key)
+	}
+	return s.objectURL(key)
+}
+
+// objectURL returns the API URL of the object
+func (s *S3Storage) objectURL(key string) string {
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil || s.config.PathStyle {
+		return strings.TrimRight(s.config.Endpoint, "/") + "/" + s.config.Bucket + "/" + escapeKey(key)
+	}
+	return endpoint.Scheme + "://" + s.config.Bucket + "." + endpoint.Host + "/" + escapeKey(key)
+}
+
+// do signs and sends a request, turning error statuses into errors
+func (s *S3Storage) do(req *http.Request, payload []byte) error {
+	awssig.SignRequest(req, payload, awssig.Credentials{
+		AccessKeyID:     s.config.AccessKeyID,
+		SecretAccessKey: s.config.SecretAccessKey,
+	}, s.config.Region, "s3", time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("S3 %s failed: %w", req.Method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("S3 %s returned status %d: %s", req.Method, resp.StatusCode, detail)
+	}
+	return nil
+}
+
+// escapeKey escapes each segment of a key for use in a URL path
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
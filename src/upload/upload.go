@@ -0,0 +1,49 @@
+// Package upload reads the image uploads of multipart forms for the HTTP handlers
+package upload
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alex-1900/wishlist/src/apperror"
+	"github.com/alex-1900/wishlist/src/imaging"
+	"github.com/alex-1900/wishlist/src/model"
+	"github.com/alex-1900/wishlist/src/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// ReadImage reads and validates the image in the "image" form field, of up to maxKB KB, and the
+// text describing it in the "alt" field. It writes the error response and returns false when the
+// image is missing, too large, not an image or too many pixels to resize, or the alt text is
+// missing or too long.
+func ReadImage(ctx *gin.Context, maxKB int) (*storage.Image, string, bool) {
+	image, err := storage.ReadFormImage(ctx.Writer, ctx.Request, "image", int64(maxKB)*1024)
+	switch {
+	case errors.Is(err, storage.ErrMissingFile):
+		ctx.Error(apperror.BadRequest("An image file is required in the \"image\" field"))
+	case errors.Is(err, storage.ErrTooLarge):
+		ctx.Error(apperror.PayloadTooLarge(fmt.Sprintf("Image must not exceed %d KB", maxKB)))
+	case errors.Is(err, storage.ErrUnsupportedType):
+		ctx.Error(apperror.UnsupportedMediaType(err.Error()))
+	case err != nil:
+		ctx.Error(apperror.Internal("Failed to read upload", err))
+	default:
+		if err := imaging.Check(image.Data, image.ContentType); err != nil {
+			if errors.Is(err, imaging.ErrTooManyPixels) {
+				ctx.Error(apperror.BadRequest(fmt.Sprintf("Image must not exceed %d megapixels", imaging.MaxPixels/1_000_000)))
+			} else {
+				ctx.Error(apperror.BadRequest("Image cannot be read"))
+			}
+			return nil, "", false
+		}
+
+		// The multipart form is parsed by now, so the text field can be read
+		alt, err := model.NormalizeMediaAlt(ctx.PostForm("alt"))
+		if err != nil {
+			ctx.Error(apperror.Validation(err))
+			return nil, "", false
+		}
+		return image, alt, true
+	}
+	return nil, "", false
+}